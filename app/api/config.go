@@ -6,10 +6,29 @@ const (
 	ModelProviderReplicate ModelProvider = "replicate"
 	ModelProviderAnthropic ModelProvider = "anthropic"
 	ModelProviderOpenAI    ModelProvider = "openai"
+	ModelProviderGemini    ModelProvider = "gemini"
 	ModelProviderDefault   ModelProvider = "openai"
 	ModelProviderUnknown   ModelProvider = "unknown"
 )
 
+// Strategies for reducing long outputs to fit in the prompt.
+const (
+	OutputReducerTruncate = "truncate"
+	OutputReducerHeadTail = "headTail"
+	OutputReducerSmart    = "smart"
+	OutputReducerLLM      = "llm"
+)
+
+// DatabaseConfig configures a SQL database.
+type DatabaseConfig struct {
+	// Driver is the database to use; sqlite or postgres. Defaults to sqlite.
+	Driver string `json:"driver,omitempty" yaml:"driver,omitempty"`
+	// DSN is the data source name. For sqlite it is the path of the database file. For postgres it is a connection
+	// URL; e.g. postgres://foyle@db.example.com:5432/foyle. Postgres passwords can be set with the PGPASSWORD
+	// environment variable rather than in the URL.
+	DSN string `json:"dsn,omitempty" yaml:"dsn,omitempty"`
+}
+
 type AgentConfig struct {
 	// Model is the name of the model to use to generate completions
 	Model string `json:"model" yaml:"model"`
@@ -22,6 +41,166 @@ type AgentConfig struct {
 	// EvalMode is whether to run in evaluation mode or not.
 	// In EvalMode logs are specially marked so requests won't be used for training.
 	EvalMode bool `json:"evalMode" yaml:"evalMode"`
+
+	// Stream configures when StreamGenerate generates completions.
+	Stream *StreamConfig `json:"stream,omitempty" yaml:"stream,omitempty"`
+
+	// NumCandidates is the number of candidate completions to generate for each request. The candidates are
+	// deduplicated and ranked; the best one is returned as the suggestion and the rest as alternatives.
+	// Defaults to 1.
+	NumCandidates int `json:"numCandidates,omitempty" yaml:"numCandidates,omitempty"`
+
+	// FIM configures fill in the middle prompts.
+	FIM *FIMConfig `json:"fim,omitempty" yaml:"fim,omitempty"`
+
+	// Tools configures the agent to run read-only commands to gather information before answering.
+	Tools *ToolsConfig `json:"tools,omitempty" yaml:"tools,omitempty"`
+
+	// Router configures falling back to other models and routing requests by size. Model and ModelProvider are
+	// the primary model.
+	Router *RouterConfig `json:"router,omitempty" yaml:"router,omitempty"`
+
+	// Cache configures caching of prompts and responses.
+	Cache *CacheConfig `json:"cache,omitempty" yaml:"cache,omitempty"`
+
+	// StructuredOutput configures asking the model for the cells as JSON rather than parsing its markdown.
+	StructuredOutput *StructuredOutputConfig `json:"structuredOutput,omitempty" yaml:"structuredOutput,omitempty"`
+
+	// Vision configures sending images output by cells to models that support images.
+	Vision *VisionConfig `json:"vision,omitempty" yaml:"vision,omitempty"`
+
+	// OutputReducer configures how outputs that don't fit in the prompt are shortened.
+	OutputReducer *OutputReducerConfig `json:"outputReducer,omitempty" yaml:"outputReducer,omitempty"`
+}
+
+// OutputReducerConfig configures how outputs that don't fit in the prompt are shortened.
+type OutputReducerConfig struct {
+	// Strategy is how outputs are shortened.
+	//   truncate keeps the beginning of the output.
+	//   headTail keeps the beginning and the end of the output.
+	//   smart collapses repeated lines and keeps the beginning, the end and the error and warning lines.
+	//   llm asks a model to summarize outputs longer than MinLLMChars and uses smart for shorter outputs.
+	// Defaults to smart.
+	Strategy string `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+
+	// Model is the model used to summarize outputs with the llm strategy; use a small, cheap model.
+	// Defaults to the agent's model.
+	Model *ModelConfig `json:"model,omitempty" yaml:"model,omitempty"`
+
+	// MinLLMChars is the minimum length of the outputs summarized by the model. Defaults to 20000.
+	MinLLMChars int `json:"minLLMChars,omitempty" yaml:"minLLMChars,omitempty"`
+}
+
+// VisionConfig configures sending images to the model. When enabled the most recent images output by cells in the
+// document are sent as image parts of the request; in the prompt they are replaced by a placeholder. Only enable it
+// for models that support images.
+type VisionConfig struct {
+	// Enabled is whether to send images to the model.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// MaxImages is the maximum number of images to send. Defaults to 3.
+	MaxImages int `json:"maxImages,omitempty" yaml:"maxImages,omitempty"`
+}
+
+// StructuredOutputConfig configures structured output. When enabled the model returns the cells to add by calling
+// a tool whose arguments are validated against a JSON schema. If the completer doesn't support tool calling or the
+// reply isn't valid the agent falls back to parsing markdown. Structured output isn't used when tools are enabled.
+type StructuredOutputConfig struct {
+	// Enabled is whether to ask the model for structured output.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+}
+
+// CacheConfig configures caching of prompts and responses.
+type CacheConfig struct {
+	// PromptCaching marks the static prefix of the prompt (the system prompt, instructions and examples) so
+	// providers that support it can cache the prefix. OpenAI caches prompts automatically; Anthropic only caches
+	// prompts that are marked.
+	PromptCaching bool `json:"promptCaching" yaml:"promptCaching"`
+
+	// Responses enables a local cache of responses keyed by the normalized prompt so requests for identical
+	// documents don't query the model again.
+	Responses bool `json:"responses" yaml:"responses"`
+
+	// MaxEntries is the maximum number of responses to cache. Defaults to 1000.
+	MaxEntries int `json:"maxEntries,omitempty" yaml:"maxEntries,omitempty"`
+
+	// TTLSeconds is how long responses are cached. Defaults to 10 minutes.
+	TTLSeconds int `json:"ttlSeconds,omitempty" yaml:"ttlSeconds,omitempty"`
+}
+
+// ModelConfig identifies a model used to generate completions.
+type ModelConfig struct {
+	// Model is the name of the model
+	Model string `json:"model" yaml:"model"`
+	// ModelProvider is the provider of the model
+	ModelProvider ModelProvider `json:"modelProvider" yaml:"modelProvider"`
+}
+
+// RouterConfig configures how completions are routed across models.
+type RouterConfig struct {
+	// Fallbacks are the models to try, in order, if the model a request was routed to returns an error, times out
+	// or the context length is exceeded.
+	Fallbacks []ModelConfig `json:"fallbacks,omitempty" yaml:"fallbacks,omitempty"`
+
+	// TimeoutSeconds is how long to wait for a model before falling back to the next one. 0 means no timeout.
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"`
+
+	// Small is an optional model, e.g. a cheaper and faster one, used for short requests. If it fails the request
+	// falls back to the primary model.
+	Small *ModelConfig `json:"small,omitempty" yaml:"small,omitempty"`
+
+	// SmallMaxChars is the maximum number of characters in the prompt of a request routed to the small model.
+	SmallMaxChars int `json:"smallMaxChars,omitempty" yaml:"smallMaxChars,omitempty"`
+}
+
+// ToolsConfig configures the tool using agent. When enabled the model can ask the agent to run read-only commands
+// (e.g. kubectl get) and look at their output before suggesting commands. This requires a model provider that
+// supports tool calling.
+type ToolsConfig struct {
+	// Enabled is whether the agent can run commands.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// AllowedCommands are the command prefixes the agent is allowed to run; e.g. "kubectl get". Every command in
+	// a pipeline must match one of the prefixes. Any arguments are allowed after a prefix except for known flags that
+	// write files so only list commands that can't modify anything. If empty a default list of read-only commands
+	// with explicitly allowed flags and arguments is used.
+	AllowedCommands []string `json:"allowedCommands,omitempty" yaml:"allowedCommands,omitempty"`
+
+	// MaxCalls is the maximum number of tool calls per completion. Defaults to 5.
+	MaxCalls int `json:"maxCalls,omitempty" yaml:"maxCalls,omitempty"`
+
+	// TimeoutSeconds is the maximum time a command can run. Defaults to 30 seconds.
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"`
+}
+
+// FIMConfig configures fill in the middle (FIM) prompts. By default only the cells up to the selected cell are
+// included in the prompt. With FIM the cells after the selected cell are included as well so the model can take
+// into account what comes next when editing the middle of a document.
+type FIMConfig struct {
+	// Enabled is whether to include the cells after the selected cell in the prompt.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// MaxSuffixChars is the maximum number of characters of the following cells to include. The suffix counts
+	// against the same budget as the rest of the document. Defaults to a third of the budget.
+	MaxSuffixChars int `json:"maxSuffixChars,omitempty" yaml:"maxSuffixChars,omitempty"`
+
+	// MaxSuffixCells is the maximum number of following cells to include. Defaults to 3.
+	MaxSuffixCells int `json:"maxSuffixCells,omitempty" yaml:"maxSuffixCells,omitempty"`
+}
+
+// StreamConfig configures how completions are triggered by a stream of notebook updates.
+type StreamConfig struct {
+	// DebounceMillis is how long to wait after the most recent update before generating a completion.
+	// Updates that arrive while waiting restart the wait. Executing a cell isn't debounced. 0 disables debouncing.
+	DebounceMillis int `json:"debounceMillis,omitempty" yaml:"debounceMillis,omitempty"`
+
+	// MarkupRequiresNewline only triggers a completion on an edit to a markup cell if the cell ends with a newline;
+	// i.e. once the user has finished a line rather than on every keystroke.
+	MarkupRequiresNewline bool `json:"markupRequiresNewline,omitempty" yaml:"markupRequiresNewline,omitempty"`
+
+	// IgnoreCodeEdits doesn't trigger completions on edits to code cells. Completions are still triggered when the
+	// cell is executed.
+	IgnoreCodeEdits bool `json:"ignoreCodeEdits,omitempty" yaml:"ignoreCodeEdits,omitempty"`
 }
 
 // RAGConfig configures the RAG model
@@ -30,4 +209,7 @@ type RAGConfig struct {
 	Enabled bool `json:"enabled" yaml:"enabled"`
 	// MaxResults is the maximum number of results to return
 	MaxResults int `json:"maxResults" yaml:"maxResults"`
+	// EmbeddingProvider is the provider used to compute the embeddings of examples; openai or gemini.
+	// Defaults to openai. Examples learned with one provider can't be used with the other.
+	EmbeddingProvider ModelProvider `json:"embeddingProvider,omitempty" yaml:"embeddingProvider,omitempty"`
 }
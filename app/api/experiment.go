@@ -20,6 +20,120 @@ type ExperimentSpec struct {
 	// These should be EvalExample protos.
 	EvalDir string `json:"evalDir" yaml:"evalDir"`
 
-	// OutputDB is the path to the file to store the results in.
+	// OutputDB is the path to the file to store the results in. The dataset manifest and the report are written
+	// next to it.
 	OutputDB string `json:"outputDB" yaml:"outputDB"`
+
+	// ResultsDB optionally stores the results in another database; e.g. Postgres. If it is set OutputDB is only
+	// used to name the dataset manifest and the directory of the report. Use a separate database for each
+	// experiment because results are keyed by the id of the example.
+	ResultsDB *DatabaseConfig `json:"resultsDB,omitempty" yaml:"resultsDB,omitempty"`
+
+	// Assertions are user defined assertions to evaluate on each result.
+	Assertions []AssertionSpec `json:"assertions,omitempty" yaml:"assertions,omitempty"`
+
+	// Dataset configures how the examples in EvalDir are turned into the dataset for the experiment.
+	// If not set all examples are evaluated and learned from.
+	Dataset *DatasetSpec `json:"dataset,omitempty" yaml:"dataset,omitempty"`
+}
+
+// DatasetSpec configures deduplication, splitting and sampling of the eval examples.
+// The steps are applied in that order. The resulting assignment of examples is stored in a manifest named after
+// the OutputDB so that resuming an experiment uses the same dataset.
+type DatasetSpec struct {
+	Dedupe *DedupeSpec `json:"dedupe,omitempty" yaml:"dedupe,omitempty"`
+	Split  *SplitSpec  `json:"split,omitempty" yaml:"split,omitempty"`
+	Sample *SampleSpec `json:"sample,omitempty" yaml:"sample,omitempty"`
+}
+
+// DedupeSpec configures removal of near-identical examples.
+type DedupeSpec struct {
+	// Threshold is the normalized similarity (0 to 1) above which two examples are considered duplicates.
+	// Similarity is computed on the selected cell of the input and the expected cell. Defaults to 0.95.
+	Threshold float64 `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+}
+
+const (
+	SplitByTime     = "time"
+	SplitByNotebook = "notebook"
+)
+
+// SplitSpec configures a train/test split.
+// Examples in the train split are replayed so the agent learns from them; examples in the test split are evaluated
+// but never learned from during the run.
+type SplitSpec struct {
+	// By is either "time" or "notebook".
+	// A time split puts the most recent examples in the test split.
+	// A notebook split assigns all examples from the same notebook to the same split.
+	By string `json:"by" yaml:"by"`
+
+	// TestFraction is the fraction of examples (or notebooks) to put in the test split. Defaults to 0.2.
+	TestFraction float64 `json:"testFraction,omitempty" yaml:"testFraction,omitempty"`
+
+	// Cutoff is an optional RFC3339 timestamp for time based splits. Examples at or after the cutoff are in the
+	// test split. If set TestFraction is ignored.
+	Cutoff string `json:"cutoff,omitempty" yaml:"cutoff,omitempty"`
+
+	// Seed is used to randomize notebook based splits.
+	Seed int64 `json:"seed,omitempty" yaml:"seed,omitempty"`
+}
+
+const (
+	StratifyByCommandFamily = "commandFamily"
+	StratifyByNotebook      = "notebook"
+	StratifyByUser          = "user"
+)
+
+// SampleSpec configures sampling of the examples to evaluate. If the dataset is split only the test split is sampled.
+type SampleSpec struct {
+	// Size is the maximum number of examples to evaluate.
+	Size int `json:"size" yaml:"size"`
+
+	// StratifyBy is an optional key to do stratified sampling on; one of "commandFamily", "notebook" or "user".
+	// If empty examples are sampled uniformly at random.
+	StratifyBy string `json:"stratifyBy,omitempty" yaml:"stratifyBy,omitempty"`
+
+	// Seed for the random number generator so samples are reproducible.
+	Seed int64 `json:"seed,omitempty" yaml:"seed,omitempty"`
+}
+
+// AssertionSpec declares a custom assertion to run against each EvalResult.
+// Exactly one of the assertion types should be set.
+type AssertionSpec struct {
+	// Name of the assertion. This is used as the column name when reporting results.
+	Name string `json:"name" yaml:"name"`
+
+	Regex    *RegexAssertion    `json:"regex,omitempty" yaml:"regex,omitempty"`
+	Binaries *BinariesAssertion `json:"binaries,omitempty" yaml:"binaries,omitempty"`
+	MaxCells *MaxCellsAssertion `json:"maxCells,omitempty" yaml:"maxCells,omitempty"`
+	CEL      *CELAssertion      `json:"cel,omitempty" yaml:"cel,omitempty"`
+}
+
+// RegexAssertion checks the generated command against a regular expression.
+type RegexAssertion struct {
+	// Pattern is a regular expression (RE2 syntax) matched against the contents of the generated code cells.
+	Pattern string `json:"pattern" yaml:"pattern"`
+	// Negate inverts the assertion so it passes only if the pattern doesn't match.
+	Negate bool `json:"negate,omitempty" yaml:"negate,omitempty"`
+}
+
+// BinariesAssertion checks which binaries are invoked by the generated commands.
+type BinariesAssertion struct {
+	// Required is a list of binaries that must be invoked by at least one generated command.
+	Required []string `json:"required,omitempty" yaml:"required,omitempty"`
+	// Forbidden is a list of binaries that must not be invoked by any generated command.
+	Forbidden []string `json:"forbidden,omitempty" yaml:"forbidden,omitempty"`
+}
+
+// MaxCellsAssertion checks the number of generated cells doesn't exceed a limit.
+type MaxCellsAssertion struct {
+	// Max is the maximum number of cells.
+	Max int `json:"max" yaml:"max"`
+}
+
+// CELAssertion evaluates a CEL expression over the EvalResult.
+// The result is available in the expression as the variable "result"; the expression must evaluate to a bool.
+// e.g. "size(result.actual_cells) > 0 && result.generate_time_ms < 5000"
+type CELAssertion struct {
+	Expression string `json:"expression" yaml:"expression"`
 }
@@ -1,13 +1,19 @@
 package api
 
 import (
+	"encoding/json"
+
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 )
 
 // LLMUsage defines a standardized structure for LLM usage that can be independent of the model used.
 type LLMUsage struct {
-	// The number of tokens used.
+	// The number of tokens used. This includes tokens read from or written to the provider's prompt cache.
 	InputTokens int `json:"inputTokens"`
+	// The number of input tokens read from the provider's prompt cache.
+	CachedInputTokens int `json:"cachedInputTokens,omitempty"`
+	// The number of input tokens written to the provider's prompt cache.
+	CacheWriteInputTokens int `json:"cacheWriteInputTokens,omitempty"`
 	// The number of tokens generated.
 	OutputTokens int `json:"outputTokens"`
 	// Model used
@@ -16,13 +22,70 @@ type LLMUsage struct {
 	Provider string `json:"provider"`
 }
 
+// LLMCall is a provider independent record of a call to an LLM. Every completer logs one for each call so that
+// post-processing doesn't depend on the provider.
+type LLMCall struct {
+	// Provider of the model
+	Provider ModelProvider `json:"provider"`
+	// Model that was called
+	Model string `json:"model"`
+	// Request is the request sent to the provider
+	Request json.RawMessage `json:"request,omitempty"`
+	// Response is the response returned by the provider. It is empty if the call failed.
+	Response json.RawMessage `json:"response,omitempty"`
+	// Usage of the call
+	Usage LLMUsage `json:"usage"`
+	// LatencyMs is how long the call took in milliseconds
+	LatencyMs int64 `json:"latencyMs"`
+	// Error is the error returned by the provider if the call failed.
+	Error string `json:"error,omitempty"`
+}
+
+// ModelPrice is the price of a model in USD per million tokens.
+type ModelPrice struct {
+	// Provider of the model e.g. openai. If empty the price applies to a model with this name from any provider.
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+	// Model is the name of the model. Versioned model names (e.g. gpt-4o-2024-08-06) match the longest
+	// model name that is a prefix of the name.
+	Model string `json:"model" yaml:"model"`
+	// InputPerMillion is the price in USD of one million input tokens.
+	InputPerMillion float64 `json:"inputPerMillion" yaml:"inputPerMillion"`
+	// OutputPerMillion is the price in USD of one million output tokens.
+	OutputPerMillion float64 `json:"outputPerMillion" yaml:"outputPerMillion"`
+	// CachedInputPerMillion is the price in USD of one million input tokens read from the prompt cache.
+	// Defaults to InputPerMillion.
+	CachedInputPerMillion float64 `json:"cachedInputPerMillion,omitempty" yaml:"cachedInputPerMillion,omitempty"`
+	// CacheWriteInputPerMillion is the price in USD of one million input tokens written to the prompt cache.
+	// Defaults to InputPerMillion.
+	CacheWriteInputPerMillion float64 `json:"cacheWriteInputPerMillion,omitempty" yaml:"cacheWriteInputPerMillion,omitempty"`
+}
+
 func ModelProviderProtoToAPI(provider v1alpha1.ModelProvider) ModelProvider {
 	switch provider {
 	case v1alpha1.ModelProvider_OPEN_AI:
 		return ModelProviderOpenAI
 	case v1alpha1.ModelProvider_ANTHROPIC:
 		return ModelProviderAnthropic
+	case v1alpha1.ModelProvider_REPLICATE:
+		return ModelProviderReplicate
+	case v1alpha1.ModelProvider_GEMINI:
+		return ModelProviderGemini
 	default:
 		return ModelProviderUnknown
 	}
 }
+
+func ModelProviderAPIToProto(provider ModelProvider) v1alpha1.ModelProvider {
+	switch provider {
+	case ModelProviderOpenAI:
+		return v1alpha1.ModelProvider_OPEN_AI
+	case ModelProviderAnthropic:
+		return v1alpha1.ModelProvider_ANTHROPIC
+	case ModelProviderReplicate:
+		return v1alpha1.ModelProvider_REPLICATE
+	case ModelProviderGemini:
+		return v1alpha1.ModelProvider_GEMINI
+	default:
+		return v1alpha1.ModelProvider_MODEL_PROVIDER_UNKNOWN
+	}
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jlewi/foyle/app/pkg/application"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/monogo/helpers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// NewLogsCmd returns a command to manage the logs
+func NewLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Manage the logs and the traces, blocks and sessions built from them.",
+	}
+
+	cmd.AddCommand(NewLogsGCCmd())
+	cmd.AddCommand(NewLogsGetCmd())
+
+	return cmd
+}
+
+// NewLogsGCCmd returns a command to garbage collect the logs
+func NewLogsGCCmd() *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Delete the logs, traces, blocks and sessions that are past the retention period.",
+		Long: `Delete the logs, traces, blocks and sessions that are past the retention period configured in logging.retention.
+
+The databases can only be opened by a single process so stop the server before running gc. The server garbage
+collects in the background when a retention period is configured.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := func() error {
+				app := application.NewApp()
+				if err := app.LoadConfig(cmd); err != nil {
+					return err
+				}
+				if err := app.SetupLogging(false); err != nil {
+					return err
+				}
+				if err := app.OpenDBs(); err != nil {
+					return err
+				}
+				defer helpers.DeferIgnoreError(app.Shutdown)
+
+				report, err := app.CollectGarbage(context.Background(), dryRun)
+				if err != nil {
+					return err
+				}
+
+				verb := "Deleted"
+				if dryRun {
+					verb = "Would delete"
+				}
+				for _, f := range report.RawLogFiles {
+					fmt.Fprintf(os.Stdout, "%s %s\n", verb, f)
+				}
+				fmt.Fprintf(os.Stdout, "%s %d raw log files (%d bytes), %d log entries, %d traces, %d blocks and %d sessions\n", verb, len(report.RawLogFiles), report.RawLogBytes, report.LogEntries, report.Traces, report.Blocks, report.Sessions)
+				return nil
+			}()
+			if err != nil {
+				fmt.Printf("Error garbage collecting logs;\n %+v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "", false, "Report what would be deleted without deleting anything.")
+	return cmd
+}
+
+// NewLogsGetCmd returns a command to print a trace or block
+func NewLogsGetCmd() *cobra.Command {
+	var endpoint string
+	cmd := &cobra.Command{
+		Use:   "get (trace|block) <id>",
+		Short: "Print the trace or block with the given id as JSON.",
+		Long: `Print the trace or block with the given id as JSON.
+
+The traces and blocks databases are read directly when the server isn't running. When the server is running it has
+the databases open so they are read through the server's API instead.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := func() error {
+				app := application.NewApp()
+				if err := app.LoadConfig(cmd); err != nil {
+					return err
+				}
+				if endpoint == "" {
+					endpoint = app.Config.APIBaseURL()
+				}
+				if err := app.OpenDBsReadOnly(endpoint); err != nil {
+					return err
+				}
+				defer helpers.DeferIgnoreError(app.Shutdown)
+
+				var db dbutil.KV
+				var msg proto.Message
+				switch args[0] {
+				case "trace":
+					db = app.TracesDB
+					msg = &logspb.Trace{}
+				case "block":
+					db = app.BlocksDB()
+					msg = &logspb.BlockLog{}
+				default:
+					return errors.Errorf("Invalid kind %s; it must be trace or block", args[0])
+				}
+
+				if err := dbutil.GetProto(db, args[1], msg); err != nil {
+					if errors.Is(err, dbutil.ErrNotFound) {
+						return errors.Errorf("No %s with id %s was found", args[0], args[1])
+					}
+					return err
+				}
+				b, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(msg)
+				if err != nil {
+					return errors.Wrapf(err, "Failed to marshal %s", args[0])
+				}
+				fmt.Fprintln(os.Stdout, string(b))
+				return nil
+			}()
+			if err != nil {
+				fmt.Printf("Error getting %s;\n %+v\n", args[0], err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&endpoint, "endpoint", "", "", "The base URL of the Foyle API used when the server has the databases open. Defaults to the server in the config.")
+	return cmd
+}
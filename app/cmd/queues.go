@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jlewi/foyle/app/pkg/application"
+	"github.com/jlewi/foyle/app/pkg/queue"
+	"github.com/jlewi/monogo/helpers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// NewQueuesCmd returns a command to inspect the analyzer's and learner's work queues
+func NewQueuesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queues",
+		Short: "Inspect the work queues used by the analyzer and learner.",
+		Long: `Inspect the work queues used by the analyzer and learner.
+
+The queues database can only be opened by a single process and, unlike the traces and blocks, the queues can't be
+read through the server's API. Stop the server before running these commands.`,
+	}
+
+	cmd.AddCommand(NewQueuesDeadLettersCmd())
+	cmd.AddCommand(NewQueuesRequeueCmd())
+
+	return cmd
+}
+
+// NewQueuesDeadLettersCmd returns a command to list the items that ran out of attempts
+func NewQueuesDeadLettersCmd() *cobra.Command {
+	var queueName string
+	cmd := &cobra.Command{
+		Use:   "deadletters",
+		Short: "List the items that failed too many times and were moved to the dead letter store.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := func() error {
+				app, err := openQueues(cmd, true)
+				if err != nil {
+					return err
+				}
+				defer helpers.DeferIgnoreError(app.Shutdown)
+
+				items, err := queue.DeadLetters(app.QueuesDB, queueName)
+				if err != nil {
+					return err
+				}
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "QUEUE\tKEY\tATTEMPTS\tDEAD SINCE\tLAST ERROR")
+				for _, item := range items {
+					fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", item.GetQueue(), item.GetKey(), item.GetAttempts(), item.GetDeadLetterTime().AsTime().Format(time.RFC3339), item.GetLastError())
+				}
+				return w.Flush()
+			}()
+			if err != nil {
+				fmt.Printf("Error listing dead letters;\n %+v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&queueName, "queue", "q", "", "The queue to list; e.g. blocks or sessions. If blank all queues are listed.")
+	return cmd
+}
+
+// NewQueuesRequeueCmd returns a command to move dead letters back to their queue
+func NewQueuesRequeueCmd() *cobra.Command {
+	var queueName string
+	cmd := &cobra.Command{
+		Use:   "requeue <key> <key> ...",
+		Short: "Move items from the dead letter store back to their queue. They are processed when the server next starts.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := func() error {
+				if len(args) == 0 {
+					return errors.New("requeue takes at least one argument which should be the key of the item to requeue")
+				}
+				app, err := openQueues(cmd, false)
+				if err != nil {
+					return err
+				}
+				defer helpers.DeferIgnoreError(app.Shutdown)
+
+				for _, key := range args {
+					if err := queue.Requeue(app.QueuesDB, queueName, key); err != nil {
+						return err
+					}
+					fmt.Fprintf(os.Stdout, "Requeued %s in queue %s\n", key, queueName)
+				}
+				return nil
+			}()
+			if err != nil {
+				fmt.Printf("Error requeuing items;\n %+v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&queueName, "queue", "q", "", "The queue the items belong to; e.g. blocks or sessions.")
+	helpers.IgnoreError(cmd.MarkFlagRequired("queue"))
+	return cmd
+}
+
+func openQueues(cmd *cobra.Command, readOnly bool) (*application.App, error) {
+	app := application.NewApp()
+	if err := app.LoadConfig(cmd); err != nil {
+		return nil, err
+	}
+	if err := app.SetupLogging(false); err != nil {
+		return nil, err
+	}
+	if err := app.OpenQueuesDB(readOnly); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
@@ -30,6 +30,9 @@ func NewRootCmd() *cobra.Command {
 	rootCmd.AddCommand(NewServeCmd())
 	rootCmd.AddCommand(NewConfigCmd())
 	rootCmd.AddCommand(NewLLMsCmd())
+	rootCmd.AddCommand(NewLogsCmd())
+	rootCmd.AddCommand(NewQueuesCmd())
+	rootCmd.AddCommand(NewSessionsCmd())
 	rootCmd.AddCommand(NewApplyCmd())
 	rootCmd.AddCommand(NewProtoToJsonCmd())
 	return rootCmd
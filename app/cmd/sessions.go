@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/jlewi/foyle/app/pkg/application"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/logs/logspbconnect"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NewSessionsCmd returns a command to work with sessions
+func NewSessionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "Work with the sessions recorded by the server.",
+	}
+
+	cmd.AddCommand(NewSessionsListCmd())
+
+	return cmd
+}
+
+// NewSessionsListCmd returns a command to list sessions
+func NewSessionsListCmd() *cobra.Command {
+	var endpoint string
+	var output string
+	var start string
+	var end string
+	var status string
+	var executed string
+	req := &logspb.ListSessionsRequest{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List sessions most recent first. Use the flags to filter them.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := func() error {
+				app := application.NewApp()
+				if err := app.LoadConfig(cmd); err != nil {
+					return err
+				}
+				if endpoint == "" {
+					endpoint = app.Config.APIBaseURL()
+				}
+
+				for _, t := range []struct {
+					value string
+					field **timestamppb.Timestamp
+				}{{value: start, field: &req.StartTime}, {value: end, field: &req.EndTime}} {
+					if t.value == "" {
+						continue
+					}
+					parsed, err := time.Parse(time.RFC3339, t.value)
+					if err != nil {
+						return errors.Wrapf(err, "Failed to parse time %s; times must be in RFC3339 format e.g. 2024-01-02T15:04:05Z", t.value)
+					}
+					*t.field = timestamppb.New(parsed)
+				}
+
+				switch strings.ToLower(status) {
+				case "":
+				case "accepted":
+					req.SuggestionStatus = logspb.SuggestionStatus_ACCEPTED
+				case "rejected":
+					req.SuggestionStatus = logspb.SuggestionStatus_REJECTED
+				default:
+					return errors.Errorf("Invalid status %s; status must be accepted or rejected", status)
+				}
+
+				switch strings.ToLower(executed) {
+				case "":
+				case "true":
+					req.Execution = logspb.ExecutionFilter_EXECUTED
+				case "false":
+					req.Execution = logspb.ExecutionFilter_NOT_EXECUTED
+				default:
+					return errors.Errorf("Invalid value for executed %s; it must be true or false", executed)
+				}
+
+				client := logspbconnect.NewSessionsServiceClient(http.DefaultClient, endpoint)
+				resp, err := client.ListSessions(context.Background(), connect.NewRequest(req))
+				if err != nil {
+					return errors.Wrapf(err, "Failed to list sessions; is the server running at %s?", endpoint)
+				}
+
+				switch output {
+				case "json":
+					b, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(resp.Msg)
+					if err != nil {
+						return errors.Wrapf(err, "Failed to marshal sessions")
+					}
+					fmt.Fprintln(os.Stdout, string(b))
+				case "table":
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+					fmt.Fprintln(w, "CONTEXT ID\tSTART\tNOTEBOOK\tINPUT TOKENS\tOUTPUT TOKENS\tCOST (USD)")
+					for _, s := range resp.Msg.GetSessions() {
+						fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%.4f\n", s.GetContextId(), s.GetStartTime().AsTime().Format(time.RFC3339), s.GetFullContext().GetNotebookUri(), s.GetTotalInputTokens(), s.GetTotalOutputTokens(), s.GetTotalCostUsd())
+					}
+					if err := w.Flush(); err != nil {
+						return err
+					}
+					if resp.Msg.GetNextPageToken() != "" {
+						fmt.Fprintf(os.Stdout, "\nMore sessions are available; use --page-token=%s to get them.\n", resp.Msg.GetNextPageToken())
+					}
+				default:
+					return errors.Errorf("Invalid output format %s; it must be table or json", output)
+				}
+				return nil
+			}()
+			if err != nil {
+				fmt.Printf("Error listing sessions;\n %+v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&endpoint, "endpoint", "", "", "The base URL of the Foyle API. Defaults to the server in the config.")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "The output format; table or json.")
+	cmd.Flags().Int32VarP(&req.PageSize, "page-size", "", 25, "The maximum number of sessions to list.")
+	cmd.Flags().StringVarP(&req.PageToken, "page-token", "", "", "The page token returned by the previous call.")
+	cmd.Flags().StringVarP(&start, "start", "", "", "Only list sessions that started at or after this time; e.g. 2024-01-02T15:04:05Z.")
+	cmd.Flags().StringVarP(&end, "end", "", "", "Only list sessions that started before this time.")
+	cmd.Flags().StringVarP(&req.NotebookUri, "notebook", "", "", "Only list sessions for the notebook with this URI.")
+	cmd.Flags().StringVarP(&status, "status", "", "", "Only list sessions in which the completion was accepted or rejected.")
+	cmd.Flags().StringVarP(&executed, "executed", "", "", "If true only list sessions in which a cell was executed; if false only those in which no cell was.")
+	cmd.Flags().Int32VarP(&req.MinInputTokens, "min-input-tokens", "", 0, "Only list sessions with at least this many input tokens.")
+	cmd.Flags().Int32VarP(&req.MaxInputTokens, "max-input-tokens", "", 0, "Only list sessions with at most this many input tokens.")
+	cmd.Flags().Int32VarP(&req.MinOutputTokens, "min-output-tokens", "", 0, "Only list sessions with at least this many output tokens.")
+	cmd.Flags().Int32VarP(&req.MaxOutputTokens, "max-output-tokens", "", 0, "Only list sessions with at most this many output tokens.")
+	cmd.Flags().StringVarP(&req.Query, "query", "q", "", "Only list sessions whose notebook contains this text.")
+	return cmd
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -33,8 +34,12 @@ import (
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap"
 
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/budget"
 	"github.com/jlewi/foyle/app/pkg/config"
 	"github.com/jlewi/foyle/app/pkg/docs"
+	"github.com/jlewi/foyle/app/pkg/events"
+	"github.com/jlewi/foyle/app/pkg/executor"
 	"github.com/jlewi/foyle/app/pkg/logs"
 	"github.com/jlewi/foyle/app/pkg/oai"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
@@ -63,6 +68,28 @@ var (
 		Name: "cells_accepted_total",
 		Help: "Total number of suggested cells accepted broken down by type"},
 	)
+
+	streamCompletionsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "stream_completions_total",
+		Help: "Number of updates and completions in StreamGenerate broken down by what happened to them"},
+		[]string{"status"},
+	)
+)
+
+const (
+	// Values for the status label of streamCompletionsCounter
+	// streamSkipped means the update didn't satisfy the trigger rules.
+	streamSkipped = "skipped"
+	// streamCoalesced means the update replaced a pending update that hadn't been processed yet.
+	streamCoalesced = "coalesced"
+	// streamCancelled means an in-flight completion was cancelled because a newer update arrived.
+	streamCancelled = "cancelled"
+	// streamStale means a completion finished but a newer update arrived so the response was dropped.
+	streamStale = "stale"
+	// streamEmpty means the completion was dropped because it didn't contain any cells.
+	streamEmpty = "empty"
+	// streamSent means the completion was sent to the client.
+	streamSent = "sent"
 )
 
 // Agent is the agent.
@@ -72,9 +99,18 @@ type Agent struct {
 	completer llms.Completer
 	config    config.Config
 	db        *learn.InMemoryExampleDB
+	// tools is nil unless the agent is allowed to run read-only commands.
+	tools *toolRunner
+	// reducer shortens outputs that don't fit in the prompt.
+	reducer docs.OutputReducer
+	// bus is where trace and session events are published; nil if the event pipeline isn't used.
+	bus *events.Bus
 }
 
-func NewAgent(cfg config.Config, completer llms.Completer, inMemoryExampleDB *learn.InMemoryExampleDB) (*Agent, error) {
+// NewAgent creates an agent. summarizer is the completer used to summarize long outputs when the llm output reducer
+// is configured; if it is nil completer is used. bus is where the agent publishes trace and session events; it can
+// be nil in which case traces are only built from the logs.
+func NewAgent(cfg config.Config, completer llms.Completer, summarizer llms.Completer, inMemoryExampleDB *learn.InMemoryExampleDB, bus *events.Bus) (*Agent, error) {
 	if cfg.Agent == nil {
 		return nil, errors.New("Configuration is missing AgentConfig; configuration must define the agent field.")
 	}
@@ -93,19 +129,52 @@ func NewAgent(cfg config.Config, completer llms.Completer, inMemoryExampleDB *le
 		inMemoryExampleDB = nil
 	}
 
+	var tools *toolRunner
+	if cfg.UseTools() {
+		if !llms.SupportsTools(completer) {
+			return nil, errors.New("Tools are enabled but the completer doesn't support tool calling")
+		}
+		e, err := executor.NewExecutor(cfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create executor for tools")
+		}
+		tools = newToolRunner(cfg.GetToolsConfig(), e)
+		log.Info("Tools are enabled", "allowedCommands", cfg.GetToolsConfig().AllowedCommands)
+	}
+
+	if summarizer == nil {
+		summarizer = completer
+	}
+	reducer, err := newOutputReducer(cfg.GetOutputReducerConfig(), summarizer)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Agent{
 		completer: completer,
 		config:    cfg,
 		db:        inMemoryExampleDB,
+		tools:     tools,
+		reducer:   reducer,
+		bus:       bus,
 	}, nil
 }
 
+// withEvents returns a context whose events are published to the agent's bus.
+func (a *Agent) withEvents(ctx context.Context) context.Context {
+	if a.bus == nil {
+		return ctx
+	}
+	return events.WithBus(ctx, a.bus)
+}
+
 func (a *Agent) Generate(ctx context.Context, req *v1alpha1.GenerateRequest) (*v1alpha1.GenerateResponse, error) {
 	span := trace.SpanFromContext(ctx)
 	log := logs.FromContext(ctx)
 	traceId := span.SpanContext().TraceID()
 	log = log.WithValues("traceId", traceId, "evalMode", a.config.EvalMode())
 	ctx = logr.NewContext(ctx, log)
+	ctx = events.WithTrace(a.withEvents(ctx), traceId.String(), a.config.EvalMode())
 
 	var examples []*v1alpha1.Example
 	if a.config.UseRAG() {
@@ -119,20 +188,42 @@ func (a *Agent) Generate(ctx context.Context, req *v1alpha1.GenerateRequest) (*v
 	}
 
 	log.Info("Agent.Generate", zap.Object("request", req))
-	blocks, err := a.completeWithRetries(ctx, req, examples)
+	events.Publish(ctx, &events.Event{GenerateRequest: req})
+	completions, err := a.completeWithRetries(ctx, req, examples)
 	if err != nil {
 		// TODO(jeremy): Should we set a status code?
 		log.Error(err, "Agent.Generate failed to generate completions")
+		events.Publish(ctx, &events.Event{GenerateError: err.Error()})
 		return nil, err
 	}
 
-	postProcessed, err := postProcessBlocks(blocks)
+	candidates, err := rankCandidates(completions, examples)
 	if err != nil {
 		log.Error(err, "Agent.Generate failed to post process blocks")
+		events.Publish(ctx, &events.Event{GenerateError: err.Error()})
 		return nil, err
 	}
 
-	log.Info(logs.Level1Assertion, "assertion", logs.BuildAssertion(v1alpha1.Assertion_AT_LEAST_ONE_BLOCK_POST_PROCESSED, len(postProcessed) > 0))
+	postProcessed := []*v1alpha1.Block{}
+	alternatives := make([]*v1alpha1.Candidate, 0, len(candidates))
+	scores := make([]float64, 0, len(candidates))
+	for i, c := range candidates {
+		scores = append(scores, c.score)
+		if i == 0 {
+			postProcessed = c.blocks
+			continue
+		}
+		if _, err := docs.SetBlockIds(c.blocks); err != nil {
+			log.Error(err, "Agent.Generate, failed to set block ids on alternative", "blocks", c.blocks)
+		}
+		alternatives = append(alternatives, &v1alpha1.Candidate{
+			Blocks: c.blocks,
+			Score:  c.score,
+		})
+	}
+	log.Info("Agent.Generate ranked candidates", "numCompletions", len(completions), "numCandidates", len(candidates), "scores", scores)
+
+	logs.LogAssertion(ctx, logs.BuildAssertion(v1alpha1.Assertion_AT_LEAST_ONE_BLOCK_POST_PROCESSED, len(postProcessed) > 0))
 
 	// Attach block ids to any blocks generated.
 	// N.B. This is kind of a last resort to make sure all blocks have an ID set. In general, we want to set blockIds
@@ -146,34 +237,61 @@ func (a *Agent) Generate(ctx context.Context, req *v1alpha1.GenerateRequest) (*v
 	}
 
 	resp := &v1alpha1.GenerateResponse{
-		Blocks:  postProcessed,
-		TraceId: traceId.String(),
+		Blocks:       postProcessed,
+		TraceId:      traceId.String(),
+		Alternatives: alternatives,
 	}
 
 	log.Info("Agent.Generate returning response", zap.Object("response", resp))
 
 	assertRequestResponse(ctx, req, resp)
+	// N.B. The response is published after the assertions because it ends the trace.
+	events.Publish(ctx, &events.Event{GenerateResponse: resp})
 	return resp, nil
 }
 
-func (a *Agent) completeWithRetries(ctx context.Context, req *v1alpha1.GenerateRequest, examples []*v1alpha1.Example) ([]*v1alpha1.Block, error) {
+// completeWithRetries generates the candidate completions for the request. Each element of the result is the blocks
+// for one completion.
+func (a *Agent) completeWithRetries(ctx context.Context, req *v1alpha1.GenerateRequest, examples []*v1alpha1.Example) ([][]*v1alpha1.Block, error) {
 	log := logs.FromContext(ctx)
 
 	cells := docs.PreprocessDoc(req)
-	t := docs.NewTailer(ctx, cells, MaxDocChars)
+	tailerCtx := ctx
+	if a.reducer != nil {
+		tailerCtx = docs.WithOutputReducer(ctx, a.reducer)
+	}
+	var t *docs.Tailer
+	useFIM := a.config.UseFIM()
+	if useFIM {
+		fimCfg := a.config.GetFIMConfig()
+		maxSuffixChars := fimCfg.MaxSuffixChars
+		if maxSuffixChars <= 0 {
+			maxSuffixChars = MaxDocChars / 3
+		}
+		suffix := docs.SuffixBlocks(req, fimCfg.MaxSuffixCells)
+		t = docs.NewFIMTailer(tailerCtx, cells, suffix, MaxDocChars, maxSuffixChars)
+	} else {
+		t = docs.NewTailer(tailerCtx, cells, MaxDocChars)
+	}
 
 	exampleArgs := make([]Example, 0, len(examples))
 	for _, example := range examples {
-		exampleArgs = append(exampleArgs, Example{
+		exampleArg := Example{
 			Input:  docs.DocToMarkdown(example.Query),
 			Output: docs.BlocksToMarkdown(example.Answer),
-		})
+		}
+		if useFIM && len(example.GetSuffix().GetBlocks()) > 0 {
+			exampleArg.Suffix = docs.DocToMarkdown(example.GetSuffix())
+		}
+		exampleArgs = append(exampleArgs, exampleArg)
 	}
 	for try := 0; try < maxTries; try++ {
 		docText := t.Text()
 		args := promptArgs{
-			Document: docText,
-			Examples: exampleArgs,
+			Document:  docText,
+			Suffix:    t.Suffix(),
+			Examples:  exampleArgs,
+			Languages: languagePrompts(cells),
 		}
 
 		if len(strings.TrimSpace(docText)) == 0 {
@@ -185,7 +303,28 @@ func (a *Agent) completeWithRetries(ctx context.Context, req *v1alpha1.GenerateR
 			return nil, errors.Wrapf(err, "Failed to execute prompt template")
 		}
 
-		blocks, err := a.completer.Complete(ctx, systemPrompt, sb.String())
+		completeCtx := ctx
+		if i := strings.Index(sb.String(), documentHeader); i > 0 {
+			completeCtx = llms.WithCacheablePrefix(ctx, i)
+		}
+		if a.config.UseVision() {
+			if images := imageOutputs(cells, a.config.GetVisionConfig().MaxImages); len(images) > 0 {
+				completeCtx = llms.WithImages(completeCtx, images)
+			}
+		}
+
+		var completions [][]*v1alpha1.Block
+		var err error
+		if a.tools != nil {
+			// N.B. When tools are enabled we only generate a single candidate since each one could run commands.
+			var blocks []*v1alpha1.Block
+			blocks, err = a.completeWithTools(ctx, sb.String())
+			completions = [][]*v1alpha1.Block{blocks}
+		} else if a.config.UseStructuredOutput() {
+			completions, err = a.completeStructured(completeCtx, sb.String(), a.config.GetNumCandidates())
+		} else {
+			completions, err = llms.CompleteN(completeCtx, a.completer, systemPrompt, sb.String(), a.config.GetNumCandidates())
+		}
 
 		if err != nil {
 			if oai.ErrorIs(err, oai.ContextLengthExceededCode) {
@@ -212,7 +351,7 @@ func (a *Agent) completeWithRetries(ctx context.Context, req *v1alpha1.GenerateR
 			assertion.Result = v1alpha1.AssertResult_FAILED
 		}
 
-		log.Info(logs.Level1Assertion, "assertion", assertion)
+		logs.LogAssertion(ctx, assertion)
 
 		assertBlocks := &v1alpha1.Assertion{
 			Name:   v1alpha1.Assertion_AT_LEAST_ONE_BLOCK,
@@ -220,11 +359,15 @@ func (a *Agent) completeWithRetries(ctx context.Context, req *v1alpha1.GenerateR
 			Id:     ulid.GenerateID(),
 		}
 
-		if len(blocks) == 0 {
+		numBlocks := 0
+		for _, blocks := range completions {
+			numBlocks += len(blocks)
+		}
+		if numBlocks == 0 {
 			assertBlocks.Result = v1alpha1.AssertResult_FAILED
 		}
-		log.Info(logs.Level1Assertion, "assertion", assertBlocks)
-		return blocks, nil
+		logs.LogAssertion(ctx, assertBlocks)
+		return completions, nil
 	}
 	err := errors.Errorf("Failed to generate a chat completion after %d tries", maxTries)
 	log.Error(err, "Failed to generate a chat completion", "maxTries", maxTries)
@@ -237,6 +380,8 @@ func (a *Agent) StreamGenerate(ctx context.Context, stream *connect.BidiStream[v
 	traceId := span.SpanContext().TraceID()
 	log = log.WithValues("traceId", traceId, "evalMode", a.config.EvalMode())
 	log.Info("Agent.StreamGenerate")
+	ctx = a.withEvents(ctx)
+	ctx = budget.WithUser(ctx, stream.RequestHeader().Get(UserHeader))
 	notebookUri := ""
 	var selectedCell int32
 	reqCount := 0
@@ -252,9 +397,17 @@ func (a *Agent) StreamGenerate(ctx context.Context, stream *connect.BidiStream[v
 	// lastDoc is the serialized version of the most recent document. It will be non empty if there is a version
 	// of the document awaiting processing.
 	var pendingDoc *v1alpha1.Doc
+	// pendingExecute is true if the pending document was triggered by executing a cell; these aren't debounced.
+	pendingExecute := false
+	// lastUpdate is the time the pending document was last updated; used to debounce updates.
+	var lastUpdate time.Time
+	// cancelInflight cancels the completion currently being generated; nil if there isn't one.
+	var cancelInflight context.CancelFunc
 	mu := &sync.Mutex{}
 
 	state := &streamState{}
+	streamCfg := a.config.GetStreamConfig()
+	debounce := time.Duration(streamCfg.DebounceMillis) * time.Millisecond
 
 	// Start a thread to asynchronously generate completions.
 	// We will generate one completion at a time. pendingDoc is used to enqueue a document to be processed.
@@ -267,6 +420,18 @@ func (a *Agent) StreamGenerate(ctx context.Context, stream *connect.BidiStream[v
 			select {
 			case <-trigger:
 				log.Info("Received trigger signal")
+				// Wait until no updates have arrived for the debounce period.
+				if !waitForDebounce(ctx, debounce, func() (time.Time, bool) {
+					mu.Lock()
+					defer mu.Unlock()
+					return lastUpdate, pendingExecute
+				}) {
+					log.Info("Context cancelled while debouncing")
+					statusChan <- status.New(codes.Canceled, "Stream context canceled")
+					return
+				}
+
+				completionCtx, completionCancel := context.WithCancel(ctx)
 				// TODO(jeremy): I should be this into streamState
 				generateRequest := func() *v1alpha1.GenerateRequest {
 					mu.Lock()
@@ -281,25 +446,53 @@ func (a *Agent) StreamGenerate(ctx context.Context, stream *connect.BidiStream[v
 						SelectedIndex: selectedCell,
 					}
 					pendingDoc = nil
+					pendingExecute = false
+					cancelInflight = completionCancel
 					return r
 				}()
 				if generateRequest == nil {
 					// There is no pending document to process
+					completionCancel()
 					continue
 				}
 
-				response, err := a.createCompletion(ctx, generateRequest, notebookUri, state.getContextID())
+				response, err := a.createCompletion(completionCtx, generateRequest, notebookUri, state.getContextID())
+
+				// superseded is true if a newer document arrived while generating the completion.
+				superseded := func() bool {
+					mu.Lock()
+					defer mu.Unlock()
+					cancelInflight = nil
+					return completionCtx.Err() != nil && ctx.Err() == nil
+				}()
+				completionCancel()
+
+				if superseded {
+					if err != nil {
+						log.Info("Completion cancelled because the document changed")
+						streamCompletionsCounter.WithLabelValues(streamCancelled).Inc()
+					} else {
+						log.Info("Dropping stale completion because the document changed")
+						streamCompletionsCounter.WithLabelValues(streamStale).Inc()
+					}
+					continue
+				}
 
 				if err != nil {
 					log.Error(err, "createCompletion failed")
 					// TODO(jeremy): Instead of terminating the request should we just try to recover on
 					// The next request?
-					statusChan <- status.Newf(codes.Internal, err.Error())
+					code := codes.Internal
+					if isBudgetExceeded(err) {
+						code = codes.ResourceExhausted
+					}
+					statusChan <- status.New(code, err.Error())
 					return
 				}
 
 				if dropResponse(response) {
 					log.V(logs.Debug).Info("Dropping response", zap.Object("response", response))
+					streamCompletionsCounter.WithLabelValues(streamEmpty).Inc()
 					continue
 				}
 
@@ -318,6 +511,7 @@ func (a *Agent) StreamGenerate(ctx context.Context, stream *connect.BidiStream[v
 						statusChan <- status.Newf(codes.Internal, "failed to send response; %v", err)
 						return
 					}
+					streamCompletionsCounter.WithLabelValues(streamSent).Inc()
 				}
 
 			case <-ctx.Done():
@@ -398,6 +592,7 @@ func (a *Agent) StreamGenerate(ctx context.Context, stream *connect.BidiStream[v
 					// n.b. we need to use ZapProto because GetFullContext contains RunMe protos that don't have
 					// zap marshler interface defined.
 					log.Info("Received full context", "contextId", req.GetContextId(), logs.ZapProto("context", req.GetFullContext()))
+					events.Publish(ctx, &events.Event{ContextID: req.GetContextId(), FullContext: req.GetFullContext()})
 					if req.GetFullContext().GetNotebookUri() == "" {
 						return status.Errorf(codes.InvalidArgument, "First request must have a notebookUri")
 					}
@@ -467,7 +662,8 @@ func (a *Agent) StreamGenerate(ctx context.Context, stream *connect.BidiStream[v
 
 			// If we don't want to trigger we continue waiting for the next request but we don't abort
 			// That's because the client will just try to reconnect right now if the stream is aborted.
-			if !shouldTrigger(doc, selectedCell) {
+			if !shouldTrigger(doc, selectedCell, req.GetTrigger(), streamCfg) {
+				streamCompletionsCounter.WithLabelValues(streamSkipped).Inc()
 				continue
 			}
 
@@ -480,6 +676,19 @@ func (a *Agent) StreamGenerate(ctx context.Context, stream *connect.BidiStream[v
 				// We only need to send a trigger if pendingDoc was nil.
 				// If its nonNil then we've already sent a trigger that hasn't been processed yet
 				sendTrigger := pendingDoc == nil
+				if !sendTrigger {
+					streamCompletionsCounter.WithLabelValues(streamCoalesced).Inc()
+				}
+
+				// Any completion being generated is for an older version of the doc so cancel it.
+				if cancelInflight != nil {
+					cancelInflight()
+					cancelInflight = nil
+				}
+				lastUpdate = time.Now()
+				if req.GetTrigger() == v1alpha1.StreamGenerateRequest_CELL_EXECUTE {
+					pendingExecute = true
+				}
 
 				var ok bool
 				pendingDoc, ok = proto.Clone(doc).(*v1alpha1.Doc)
@@ -524,6 +733,7 @@ func (a *Agent) GenerateCells(ctx context.Context, req *connect.Request[v1alpha1
 	log = log.WithValues("traceId", span.SpanContext().TraceID())
 
 	log.Info("Runme.Generate")
+	ctx = budget.WithUser(ctx, req.Header().Get(UserHeader))
 
 	// Convert the request to the agent format
 	doc, err := converters.NotebookToDoc(req.Msg.Notebook)
@@ -552,6 +762,9 @@ func (a *Agent) GenerateCells(ctx context.Context, req *connect.Request[v1alpha1
 			return nil, connect.NewError(connect.CodeDeadlineExceeded, err)
 		}
 		err := errors.Wrapf(err, "Agent.Generate failed; traceId %s", span.SpanContext().TraceID().String())
+		if isBudgetExceeded(err) {
+			return nil, connect.NewError(connect.CodeResourceExhausted, err)
+		}
 		return nil, err
 	}
 
@@ -561,8 +774,14 @@ func (a *Agent) GenerateCells(ctx context.Context, req *connect.Request[v1alpha1
 		log.Error(err, "Failed to convert agent blocks to cells")
 		return nil, err
 	}
+	alternatives, err := candidatesToCells(agentResp.GetAlternatives())
+	if err != nil {
+		log.Error(err, "Failed to convert alternatives to cells")
+		return nil, err
+	}
 	resp := &v1alpha1.GenerateCellsResponse{
-		Cells: cells,
+		Cells:        cells,
+		Alternatives: alternatives,
 	}
 
 	// We need to attach the traceId to the response.
@@ -584,6 +803,7 @@ func (a *Agent) createCompletion(ctx context.Context, generateRequest *v1alpha1.
 	generateTraceId := generateSpan.SpanContext().TraceID()
 	log = log.WithValues("traceId", generateTraceId, "streamTraceId", traceId.String(), "contextId", contextID)
 	generateCtx = logr.NewContext(generateCtx, log)
+	generateCtx = events.WithContextID(generateCtx, contextID)
 	defer generateSpan.End()
 
 	generateResponse, err := a.Generate(generateCtx, generateRequest)
@@ -596,11 +816,17 @@ func (a *Agent) createCompletion(ctx context.Context, generateRequest *v1alpha1.
 		return nil, errors.Wrapf(err, "Failed to convert blocks to cells")
 	}
 
+	alternatives, err := candidatesToCells(generateResponse.GetAlternatives())
+	if err != nil {
+		return nil, err
+	}
+
 	response := &v1alpha1.StreamGenerateResponse{
-		Cells:       cells,
-		NotebookUri: notebookUri,
-		InsertAt:    generateRequest.GetSelectedIndex() + 1,
-		ContextId:   contextID,
+		Cells:        cells,
+		NotebookUri:  notebookUri,
+		InsertAt:     generateRequest.GetSelectedIndex() + 1,
+		ContextId:    contextID,
+		Alternatives: alternatives,
 	}
 
 	return response, nil
@@ -636,6 +862,7 @@ func (a *Agent) GetExample(ctx context.Context, req *connect.Request[v1alpha1.Ge
 
 func (a *Agent) LogEvents(ctx context.Context, req *connect.Request[v1alpha1.LogEventsRequest]) (*connect.Response[v1alpha1.LogEventsResponse], error) {
 	log := logs.FromContext(ctx)
+	ctx = a.withEvents(ctx)
 	tp := tracer()
 	for _, event := range req.Msg.Events {
 		func() {
@@ -651,6 +878,7 @@ func (a *Agent) LogEvents(ctx context.Context, req *connect.Request[v1alpha1.Log
 			}
 			// N.B we can't use zap.Object to log the event because it contains runme protos which don't have the zap marshaler bindings.
 			log.Info("LogEvent", "eventId", event.GetEventId(), "eventType", event.Type, "contextId", event.ContextId, "selectedCellId", event.SelectedId, logs.ZapProto("event", event))
+			events.Publish(ctx, &events.Event{ContextID: event.GetContextId(), LogEvent: event})
 		}()
 	}
 	return connect.NewResponse(&v1alpha1.LogEventsResponse{}), nil
@@ -693,6 +921,12 @@ func postProcessBlocks(blocks []*v1alpha1.Block) ([]*v1alpha1.Block, error) {
 	return results, nil
 }
 
+// isBudgetExceeded returns true if the error is because the LLM budget has been used up.
+func isBudgetExceeded(err error) bool {
+	var exceeded *budget.ExceededError
+	return errors.As(err, &exceeded)
+}
+
 func isOutputTag(contents string) bool {
 	trimmed := strings.TrimSpace(contents)
 	return trimmed == "</output>"
@@ -720,8 +954,55 @@ func (s *streamState) getContextID() string {
 }
 
 // shouldTrigger returns true if the agent should trigger a completion for the current document.
-func shouldTrigger(doc *v1alpha1.Doc, selectedIndex int32) bool {
-	return len(doc.Blocks) != 0
+func shouldTrigger(doc *v1alpha1.Doc, selectedIndex int32, trigger v1alpha1.StreamGenerateRequest_Trigger, cfg api.StreamConfig) bool {
+	if len(doc.Blocks) == 0 {
+		return false
+	}
+
+	// Executing a cell is a strong signal the user is ready for the next cell.
+	if trigger == v1alpha1.StreamGenerateRequest_CELL_EXECUTE {
+		return true
+	}
+
+	if trigger != v1alpha1.StreamGenerateRequest_CELL_TEXT_CHANGE || selectedIndex < 0 || int(selectedIndex) >= len(doc.Blocks) {
+		return true
+	}
+
+	block := doc.Blocks[selectedIndex]
+	switch block.GetKind() {
+	case v1alpha1.BlockKind_MARKUP:
+		if cfg.MarkupRequiresNewline && !strings.HasSuffix(block.GetContents(), "\n") {
+			return false
+		}
+	case v1alpha1.BlockKind_CODE:
+		if cfg.IgnoreCodeEdits {
+			return false
+		}
+	}
+	return true
+}
+
+// waitForDebounce waits until no updates have arrived for the debounce period. getLastUpdate returns the time of the
+// most recent update and whether it should skip the wait. Returns false if the context is cancelled.
+func waitForDebounce(ctx context.Context, debounce time.Duration, getLastUpdate func() (time.Time, bool)) bool {
+	if debounce <= 0 {
+		return true
+	}
+	for {
+		last, immediate := getLastUpdate()
+		if immediate {
+			return true
+		}
+		wait := debounce - time.Since(last)
+		if wait <= 0 {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(wait):
+		}
+	}
 }
 
 // dropResponse returns true if the response should be dropped rather than being sent to the client.
@@ -740,7 +1021,6 @@ func dropResponse(response *v1alpha1.StreamGenerateResponse) bool {
 
 // assertRequestResponse runs some assertions that depend on the generateRequest and the response.
 func assertRequestResponse(ctx context.Context, req *v1alpha1.GenerateRequest, resp *v1alpha1.GenerateResponse) {
-	log := logs.FromContext(ctx)
 	assertMarkupAfterCode := &v1alpha1.Assertion{
 		Name:   v1alpha1.Assertion_MARKUP_AFTER_CODE,
 		Result: v1alpha1.AssertResult_SKIPPED,
@@ -761,5 +1041,5 @@ func assertRequestResponse(ctx context.Context, req *v1alpha1.GenerateRequest, r
 		assertMarkupAfterCode.Result = v1alpha1.AssertResult_FAILED
 	}
 
-	log.Info(logs.Level1Assertion, "assertion", assertMarkupAfterCode)
+	logs.LogAssertion(ctx, assertMarkupAfterCode)
 }
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"testing"
 	"time"
 
@@ -35,7 +36,12 @@ import (
 	"github.com/jlewi/foyle/app/api"
 
 	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/events"
+	"github.com/jlewi/foyle/app/pkg/executor"
+	"github.com/jlewi/foyle/app/pkg/llmcache"
+	"github.com/jlewi/foyle/app/pkg/llms"
 	"github.com/jlewi/foyle/app/pkg/oai"
+	"github.com/jlewi/foyle/app/pkg/replicate"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 	"go.uber.org/zap"
 )
@@ -130,7 +136,7 @@ func Test_Generate(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating completer; %v", err)
 	}
-	agentWithRag, err := NewAgent(*cfg, completer, inMemoryDB)
+	agentWithRag, err := NewAgent(*cfg, completer, nil, inMemoryDB, nil)
 
 	if err != nil {
 		t.Fatalf("Error creating agent; %v", err)
@@ -138,7 +144,7 @@ func Test_Generate(t *testing.T) {
 
 	cfgNoRag := cfg.DeepCopy()
 	cfgNoRag.Agent.RAG.Enabled = false
-	agentNoRag, err := NewAgent(cfgNoRag, completer, nil)
+	agentNoRag, err := NewAgent(cfgNoRag, completer, nil, nil, nil)
 
 	if err != nil {
 		t.Fatalf("Error creating agent; %v", err)
@@ -408,9 +414,31 @@ func Test_ShouldTrigger(t *testing.T) {
 		name          string
 		doc           *v1alpha1.Doc
 		selectedIndex int32
+		trigger       v1alpha1.StreamGenerateRequest_Trigger
+		cfg           api.StreamConfig
 		expected      bool
 	}
 
+	markupDoc := func(contents string) *v1alpha1.Doc {
+		return &v1alpha1.Doc{
+			Blocks: []*v1alpha1.Block{
+				{
+					Contents: contents,
+					Kind:     v1alpha1.BlockKind_MARKUP,
+				},
+			},
+		}
+	}
+
+	codeDoc := &v1alpha1.Doc{
+		Blocks: []*v1alpha1.Block{
+			{
+				Contents: "gcloud builds list",
+				Kind:     v1alpha1.BlockKind_CODE,
+			},
+		},
+	}
+
 	cases := []testCase{
 		{
 			name: "markupcell",
@@ -438,11 +466,49 @@ func Test_ShouldTrigger(t *testing.T) {
 			selectedIndex: 0,
 			expected:      true,
 		},
+		{
+			name:          "empty-doc",
+			doc:           &v1alpha1.Doc{},
+			selectedIndex: 0,
+			expected:      false,
+		},
+		{
+			name:          "markup-no-newline",
+			doc:           markupDoc("Use gcloud to list"),
+			selectedIndex: 0,
+			trigger:       v1alpha1.StreamGenerateRequest_CELL_TEXT_CHANGE,
+			cfg:           api.StreamConfig{MarkupRequiresNewline: true},
+			expected:      false,
+		},
+		{
+			name:          "markup-newline",
+			doc:           markupDoc("Use gcloud to list the builds\n"),
+			selectedIndex: 0,
+			trigger:       v1alpha1.StreamGenerateRequest_CELL_TEXT_CHANGE,
+			cfg:           api.StreamConfig{MarkupRequiresNewline: true},
+			expected:      true,
+		},
+		{
+			name:          "code-edit-ignored",
+			doc:           codeDoc,
+			selectedIndex: 0,
+			trigger:       v1alpha1.StreamGenerateRequest_CELL_TEXT_CHANGE,
+			cfg:           api.StreamConfig{IgnoreCodeEdits: true},
+			expected:      false,
+		},
+		{
+			name:          "code-execute",
+			doc:           codeDoc,
+			selectedIndex: 0,
+			trigger:       v1alpha1.StreamGenerateRequest_CELL_EXECUTE,
+			cfg:           api.StreamConfig{IgnoreCodeEdits: true},
+			expected:      true,
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			actual := shouldTrigger(c.doc, c.selectedIndex)
+			actual := shouldTrigger(c.doc, c.selectedIndex, c.trigger, c.cfg)
 			if actual != c.expected {
 				t.Fatalf("Expected %v but got %v", c.expected, actual)
 			}
@@ -539,6 +605,86 @@ func Test_PostProcessBlocks(t *testing.T) {
 	}
 }
 
+func Test_RankCandidates(t *testing.T) {
+	code := func(contents string) []*v1alpha1.Block {
+		return []*v1alpha1.Block{
+			{
+				Kind:     v1alpha1.BlockKind_CODE,
+				Contents: contents,
+			},
+		}
+	}
+
+	type testCase struct {
+		name        string
+		completions [][]*v1alpha1.Block
+		examples    []*v1alpha1.Example
+		expected    []string
+	}
+
+	cases := []testCase{
+		{
+			name: "dedupe-and-vote",
+			completions: [][]*v1alpha1.Block{
+				code("kubectl get pods"),
+				code("gcloud builds list"),
+				code("gcloud  builds list\n"),
+			},
+			expected: []string{"gcloud builds list", "kubectl get pods"},
+		},
+		{
+			name: "rag-similarity",
+			completions: [][]*v1alpha1.Block{
+				code("kubectl get pods"),
+				code("gcloud builds list"),
+			},
+			examples: []*v1alpha1.Example{
+				{
+					Answer: code("gcloud builds list --limit=10"),
+				},
+			},
+			expected: []string{"gcloud builds list", "kubectl get pods"},
+		},
+		{
+			name: "prefer-code",
+			completions: [][]*v1alpha1.Block{
+				{
+					{
+						Kind:     v1alpha1.BlockKind_MARKUP,
+						Contents: "You should list the pods",
+					},
+				},
+				code("kubectl get pods"),
+			},
+			expected: []string{"kubectl get pods", "You should list the pods"},
+		},
+		{
+			name: "drop-empty",
+			completions: [][]*v1alpha1.Block{
+				code("</output>"),
+				code("kubectl get pods"),
+			},
+			expected: []string{"kubectl get pods"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			candidates, err := rankCandidates(c.completions, c.examples)
+			if err != nil {
+				t.Fatalf("Error ranking candidates; %v", err)
+			}
+			actual := make([]string, 0, len(candidates))
+			for _, candidate := range candidates {
+				actual = append(actual, candidate.blocks[0].Contents)
+			}
+			if d := cmp.Diff(c.expected, actual); d != "" {
+				t.Errorf("Unexpected diff:\n%s", d)
+			}
+		})
+	}
+}
+
 func Test_dropResponse(t *testing.T) {
 	type testCase struct {
 		name     string
@@ -577,3 +723,343 @@ func Test_dropResponse(t *testing.T) {
 		})
 	}
 }
+
+func Test_waitForDebounce(t *testing.T) {
+	debounce := 50 * time.Millisecond
+
+	start := time.Now()
+	if !waitForDebounce(context.Background(), debounce, func() (time.Time, bool) { return start, false }) {
+		t.Fatalf("waitForDebounce should return true")
+	}
+	if elapsed := time.Since(start); elapsed < debounce {
+		t.Errorf("waitForDebounce returned after %v; expected to wait at least %v", elapsed, debounce)
+	}
+
+	// Updates triggered by execution skip the wait.
+	start = time.Now()
+	if !waitForDebounce(context.Background(), time.Hour, func() (time.Time, bool) { return start, true }) {
+		t.Fatalf("waitForDebounce should return true")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if waitForDebounce(ctx, time.Hour, func() (time.Time, bool) { return time.Now(), false }) {
+		t.Errorf("waitForDebounce should return false when the context is cancelled")
+	}
+}
+
+// fakeToolCompleter replays canned replies and records the conversations it was sent.
+type fakeToolCompleter struct {
+	replies  []*llms.Message
+	messages [][]llms.Message
+	// blocks are returned by Complete. If nil Complete returns an error.
+	blocks []*v1alpha1.Block
+}
+
+func (f *fakeToolCompleter) Complete(ctx context.Context, systemPrompt string, message string) ([]*v1alpha1.Block, error) {
+	if f.blocks == nil {
+		return nil, errors.New("Complete shouldn't be called when tools are enabled")
+	}
+	return f.blocks, nil
+}
+
+func (f *fakeToolCompleter) SupportsTools() bool {
+	return true
+}
+
+func (f *fakeToolCompleter) CompleteWithTools(ctx context.Context, systemPrompt string, messages []llms.Message, tools []llms.Tool) (*llms.Message, error) {
+	f.messages = append(f.messages, messages)
+	if len(f.replies) == 0 {
+		return nil, errors.New("No more replies")
+	}
+	reply := f.replies[0]
+	f.replies = f.replies[1:]
+	return reply, nil
+}
+
+func Test_CompleteWithTools(t *testing.T) {
+	e, err := executor.NewExecutor(config.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	completer := &fakeToolCompleter{
+		replies: []*llms.Message{
+			{
+				Role: llms.RoleAssistant,
+				ToolCalls: []llms.ToolCall{
+					{ID: "call1", Name: runCommandTool, Arguments: `{"command": "echo my-cluster"}`},
+					{ID: "call2", Name: runCommandTool, Arguments: `{"command": "kubectl delete pods --all"}`},
+				},
+			},
+			{
+				Role:      llms.RoleAssistant,
+				ToolCalls: []llms.ToolCall{{ID: "call3", Name: runCommandTool, Arguments: `{"command": "echo again"}`}},
+			},
+			{
+				Role:    llms.RoleAssistant,
+				Content: "```bash\nkubectl --context=my-cluster get pods\n```",
+			},
+		},
+	}
+
+	a := &Agent{
+		completer: completer,
+		tools: newToolRunner(api.ToolsConfig{
+			Enabled:         true,
+			AllowedCommands: []string{"echo", "kubectl get"},
+			MaxCalls:        2,
+			TimeoutSeconds:  10,
+		}, e),
+	}
+
+	blocks, err := a.completeWithTools(context.Background(), "list the pods")
+	if err != nil {
+		t.Fatalf("completeWithTools failed: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].GetContents() != "kubectl --context=my-cluster get pods" {
+		t.Errorf("Unexpected blocks: %v", blocks)
+	}
+
+	if len(completer.messages) != 3 {
+		t.Fatalf("Expected 3 calls to the model; got %d", len(completer.messages))
+	}
+
+	// The last conversation contains the results of every tool call.
+	results := map[string]string{}
+	for _, m := range completer.messages[2] {
+		if m.Role == llms.RoleTool {
+			results[m.ToolCallID] = m.Content
+		}
+	}
+	expected := map[string]string{
+		"call1": "exitCode: 0\nstdout:\nmy-cluster\n",
+		"call2": "Error: command \"kubectl delete pods --all\" is not an allowed read-only command",
+		"call3": "The limit of 2 commands has been reached; answer using the information you already have.",
+	}
+	if d := cmp.Diff(expected, results); d != "" {
+		t.Errorf("Unexpected tool results:\n%s", d)
+	}
+}
+
+func Test_NewAgentRequiresTools(t *testing.T) {
+	cfg := config.Config{
+		Agent: &api.AgentConfig{
+			Tools: &api.ToolsConfig{Enabled: true},
+		},
+	}
+
+	// Hide CompleteWithTools so the completer doesn't support tool calling.
+	plain := struct{ llms.Completer }{&fakeToolCompleter{}}
+	cached, err := llmcache.NewCompleter(plain, api.CacheConfig{MaxEntries: 10, TTLSeconds: 60})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	// The cache implements CompleteWithTools so NewAgent has to ask it whether the completer it wraps supports tools.
+	if _, err := NewAgent(cfg, cached, nil, nil, nil); err == nil {
+		t.Errorf("Expected NewAgent to fail when the wrapped completer doesn't support tools")
+	}
+
+	cached, err = llmcache.NewCompleter(&fakeToolCompleter{}, api.CacheConfig{MaxEntries: 10, TTLSeconds: 60})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	if _, err := NewAgent(cfg, cached, nil, nil, nil); err != nil {
+		t.Errorf("Expected NewAgent to succeed when the wrapped completer supports tools; got %v", err)
+	}
+
+	// Replicate uses the OpenAI completer but its models only support tools if it is explicitly configured.
+	replicateCompleter, err := replicate.NewCompleter(cfg, openai.NewClient(""))
+	if err != nil {
+		t.Fatalf("Failed to create Replicate completer: %v", err)
+	}
+	if _, err := NewAgent(cfg, replicateCompleter, nil, nil, nil); err == nil {
+		t.Errorf("Expected NewAgent to fail with a Replicate completer that doesn't support tools")
+	}
+}
+
+func Test_CompleteStructured(t *testing.T) {
+	type testCase struct {
+		name      string
+		completer llms.Completer
+		expected  []*v1alpha1.Block
+	}
+
+	markdownBlocks := []*v1alpha1.Block{
+		{
+			Kind:     v1alpha1.BlockKind_CODE,
+			Contents: "kubectl get pods",
+		},
+	}
+
+	cases := []testCase{
+		{
+			name: "structured",
+			completer: &fakeToolCompleter{
+				replies: []*llms.Message{
+					{
+						Role: llms.RoleAssistant,
+						ToolCalls: []llms.ToolCall{
+							{
+								ID:        "call1",
+								Name:      addCellsTool,
+								Arguments: `{"cells": [{"kind": "code", "language": "bash", "contents": "kubectl get pods\n", "explanation": "List the pods"}]}`,
+							},
+						},
+					},
+				},
+			},
+			expected: []*v1alpha1.Block{
+				{
+					Kind:     v1alpha1.BlockKind_MARKUP,
+					Contents: "List the pods",
+				},
+				{
+					Kind:     v1alpha1.BlockKind_CODE,
+					Language: "bash",
+					Contents: "kubectl get pods",
+				},
+			},
+		},
+		{
+			name: "invalid-kind-falls-back-to-content",
+			completer: &fakeToolCompleter{
+				replies: []*llms.Message{
+					{
+						Role:    llms.RoleAssistant,
+						Content: "```bash\nkubectl get pods\n```",
+						ToolCalls: []llms.ToolCall{
+							{ID: "call1", Name: addCellsTool, Arguments: `{"cells": [{"kind": "table", "contents": "x"}]}`},
+						},
+					},
+				},
+			},
+			expected: []*v1alpha1.Block{
+				{
+					Kind:     v1alpha1.BlockKind_CODE,
+					Language: "bash",
+					Contents: "kubectl get pods",
+				},
+			},
+		},
+		{
+			name: "unknown-field-falls-back-to-complete",
+			completer: &fakeToolCompleter{
+				replies: []*llms.Message{
+					{
+						Role: llms.RoleAssistant,
+						ToolCalls: []llms.ToolCall{
+							{ID: "call1", Name: addCellsTool, Arguments: `{"cells": [{"kind": "code", "contents": "ls", "output": "x"}]}`},
+						},
+					},
+				},
+				blocks: markdownBlocks,
+			},
+			expected: markdownBlocks,
+		},
+		{
+			name: "no-tool-support",
+			completer: &fakeToolCompleter{
+				blocks: markdownBlocks,
+			},
+			expected: markdownBlocks,
+		},
+	}
+
+	opts := cmpopts.IgnoreUnexported(v1alpha1.Block{})
+	// Blocks parsed from markdown also get outputs and metadata which we don't care about here.
+	ignoreFields := cmpopts.IgnoreFields(v1alpha1.Block{}, "Id", "Outputs", "Metadata")
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			completer := c.completer
+			if c.name == "no-tool-support" {
+				// Hide CompleteWithTools so the completer doesn't support tool calling.
+				completer = struct{ llms.Completer }{c.completer}
+			}
+			a := &Agent{
+				completer: completer,
+			}
+
+			completions, err := a.completeStructured(context.Background(), "list the pods", 1)
+			if err != nil {
+				t.Fatalf("completeStructured failed: %v", err)
+			}
+			if len(completions) != 1 {
+				t.Fatalf("Expected 1 completion; got %d", len(completions))
+			}
+			if d := cmp.Diff(c.expected, completions[0], opts, ignoreFields); d != "" {
+				t.Errorf("Unexpected blocks:\n%s", d)
+			}
+		})
+	}
+}
+
+func Test_CompleteStructuredAssertion(t *testing.T) {
+	valid := &llms.Message{
+		Role:      llms.RoleAssistant,
+		ToolCalls: []llms.ToolCall{{ID: "call1", Name: addCellsTool, Arguments: `{"cells": [{"kind": "code", "contents": "ls"}]}`}},
+	}
+	invalid := &llms.Message{
+		Role:    llms.RoleAssistant,
+		Content: "```bash\nls\n```",
+	}
+
+	type testCase struct {
+		name     string
+		replies  []*llms.Message
+		expected v1alpha1.AssertResult
+		detail   string
+	}
+
+	cases := []testCase{
+		{
+			name:     "all-structured",
+			replies:  []*llms.Message{valid, valid},
+			expected: v1alpha1.AssertResult_PASSED,
+		},
+		{
+			name:     "one-fallback",
+			replies:  []*llms.Message{valid, invalid},
+			expected: v1alpha1.AssertResult_FAILED,
+			detail:   "Fell back to parsing markdown for 1 of 2 candidates",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			bus := events.NewBus(10)
+			var assertions []*v1alpha1.Assertion
+			bus.Subscribe(func(ctx context.Context, e *events.Event) {
+				if e.Assertion != nil {
+					assertions = append(assertions, e.Assertion)
+				}
+			})
+			bus.Run(context.Background())
+
+			a := &Agent{
+				completer: &fakeToolCompleter{replies: c.replies},
+			}
+			completions, err := a.completeStructured(events.WithBus(context.Background(), bus), "list the files", 2)
+			if err != nil {
+				t.Fatalf("completeStructured failed: %v", err)
+			}
+			if err := bus.Shutdown(context.Background()); err != nil {
+				t.Fatalf("Failed to shutdown bus: %v", err)
+			}
+			if len(completions) != 2 {
+				t.Fatalf("Expected 2 completions; got %d", len(completions))
+			}
+
+			// There is one assertion for the request rather than one per candidate.
+			if len(assertions) != 1 {
+				t.Fatalf("Expected 1 assertion; got %d", len(assertions))
+			}
+			if assertions[0].GetName() != v1alpha1.Assertion_STRUCTURED_OUTPUT || assertions[0].GetResult() != c.expected {
+				t.Errorf("Expected %v STRUCTURED_OUTPUT assertion; got %v", c.expected, assertions[0])
+			}
+			if !strings.HasPrefix(assertions[0].GetDetail(), c.detail) {
+				t.Errorf("Expected detail to start with %q; got %q", c.detail, assertions[0].GetDetail())
+			}
+		})
+	}
+}
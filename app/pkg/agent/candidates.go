@@ -0,0 +1,142 @@
+package agent
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/agnivade/levenshtein"
+	"github.com/jlewi/foyle/app/pkg/docs"
+	"github.com/jlewi/foyle/app/pkg/runme/converters"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Weights used to combine the signals into the score for a candidate.
+	// ragWeight weights how similar the candidate is to the answers of the examples retrieved by RAG.
+	ragWeight = 0.5
+	// voteWeight weights the fraction of the generated candidates that were identical to the candidate.
+	voteWeight = 0.4
+	// codeWeight rewards candidates that contain a code block since those are the ones users can execute.
+	codeWeight = 0.1
+)
+
+// candidate is a single completion generated for a request.
+type candidate struct {
+	blocks []*v1alpha1.Block
+	// votes is the number of generated completions that were duplicates of this candidate.
+	votes int
+	score float64
+}
+
+// rankCandidates post processes the completions, removes duplicates and sorts them from best to worst.
+// Completions that are empty after post processing are dropped.
+func rankCandidates(completions [][]*v1alpha1.Block, examples []*v1alpha1.Example) ([]*candidate, error) {
+	candidates := make([]*candidate, 0, len(completions))
+	byKey := make(map[string]*candidate)
+	for _, blocks := range completions {
+		postProcessed, err := postProcessBlocks(blocks)
+		if err != nil {
+			return nil, err
+		}
+		if len(postProcessed) == 0 {
+			continue
+		}
+		key := candidateKey(postProcessed)
+		if c, ok := byKey[key]; ok {
+			c.votes++
+			continue
+		}
+		c := &candidate{
+			blocks: postProcessed,
+			votes:  1,
+		}
+		byKey[key] = c
+		candidates = append(candidates, c)
+	}
+
+	answers := make([]string, 0, len(examples))
+	for _, example := range examples {
+		answers = append(answers, normalizeText(docs.BlocksToMarkdown(example.GetAnswer())))
+	}
+
+	for _, c := range candidates {
+		c.score = scoreCandidate(c, len(completions), answers)
+	}
+
+	// Use a stable sort so that ties are broken by the order the completions were generated in.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	return candidates, nil
+}
+
+// scoreCandidate scores a candidate; higher is better.
+func scoreCandidate(c *candidate, total int, answers []string) float64 {
+	score := 0.0
+	if total > 0 {
+		score += voteWeight * float64(c.votes) / float64(total)
+	}
+
+	text := normalizeText(docs.BlocksToMarkdown(c.blocks))
+	best := 0.0
+	for _, answer := range answers {
+		if s := textSimilarity(text, answer); s > best {
+			best = s
+		}
+	}
+	score += ragWeight * best
+
+	for _, b := range c.blocks {
+		if b.GetKind() == v1alpha1.BlockKind_CODE {
+			score += codeWeight
+			break
+		}
+	}
+	return score
+}
+
+// candidateKey returns a key such that candidates which only differ in whitespace have the same key.
+func candidateKey(blocks []*v1alpha1.Block) string {
+	var sb strings.Builder
+	for _, b := range blocks {
+		sb.WriteString(b.GetKind().String())
+		sb.WriteString(":")
+		sb.WriteString(normalizeText(b.GetContents()))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// normalizeText collapses all whitespace to single spaces.
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// textSimilarity returns the normalized levenshtein similarity of two strings; 1 means the strings are identical.
+func textSimilarity(a, b string) float64 {
+	maxLen := len(a)
+	if len(b) > maxLen {
+		maxLen = len(b)
+	}
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein.ComputeDistance(a, b))/float64(maxLen)
+}
+
+// candidatesToCells converts the alternatives in a GenerateResponse to runme cells.
+func candidatesToCells(alternatives []*v1alpha1.Candidate) ([]*v1alpha1.CellCandidate, error) {
+	results := make([]*v1alpha1.CellCandidate, 0, len(alternatives))
+	for _, alt := range alternatives {
+		cells, err := converters.BlocksToCells(alt.GetBlocks())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to convert candidate blocks to cells")
+		}
+		results = append(results, &v1alpha1.CellCandidate{
+			Cells: cells,
+			Score: alt.GetScore(),
+		})
+	}
+	return results, nil
+}
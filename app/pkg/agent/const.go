@@ -2,4 +2,7 @@ package agent
 
 const (
 	TraceIDHeader = "Foyle-Trace-ID"
+	// UserHeader identifies the user a request should be attributed to when enforcing budgets.
+	// The header is trusted; the server doesn't authenticate users.
+	UserHeader = "Foyle-User"
 )
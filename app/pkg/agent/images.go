@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"strings"
+
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+)
+
+// imageMimeTypes are the types of images supported by all the providers that support images.
+var imageMimeTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// imageOutputs returns up to maxImages of the most recent images output by the blocks, in the order they appear
+// in the document.
+func imageOutputs(blocks []*v1alpha1.Block, maxImages int) []llms.Image {
+	images := make([]llms.Image, 0, maxImages)
+	for i := len(blocks) - 1; i >= 0 && len(images) < maxImages; i-- {
+		outputs := blocks[i].GetOutputs()
+		for j := len(outputs) - 1; j >= 0 && len(images) < maxImages; j-- {
+			items := outputs[j].GetItems()
+			for k := len(items) - 1; k >= 0 && len(images) < maxImages; k-- {
+				item := items[k]
+				mime := strings.ToLower(item.GetMime())
+				if !imageMimeTypes[mime] || len(item.GetData()) == 0 {
+					continue
+				}
+				images = append(images, llms.Image{MimeType: mime, Data: item.GetData()})
+			}
+		}
+	}
+
+	// Reverse the images so they are in the order they appear in the document.
+	for i, j := 0, len(images)-1; i < j; i, j = i+1, j-1 {
+		images[i], images[j] = images[j], images[i]
+	}
+	return images
+}
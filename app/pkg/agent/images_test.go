@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+)
+
+func Test_imageOutputs(t *testing.T) {
+	imageBlock := func(mime string, data string) *v1alpha1.Block {
+		return &v1alpha1.Block{
+			Kind: v1alpha1.BlockKind_CODE,
+			Outputs: []*v1alpha1.BlockOutput{
+				{
+					Items: []*v1alpha1.BlockOutputItem{
+						{Mime: "text/plain", TextData: "some text"},
+						{Mime: mime, Data: []byte(data)},
+					},
+				},
+			},
+		}
+	}
+
+	blocks := []*v1alpha1.Block{
+		imageBlock("image/png", "first"),
+		{Kind: v1alpha1.BlockKind_MARKUP, Contents: "some markup"},
+		imageBlock("image/tiff", "unsupported"),
+		imageBlock("image/jpeg", "second"),
+		imageBlock("image/PNG", "third"),
+	}
+
+	expected := []llms.Image{
+		{MimeType: "image/jpeg", Data: []byte("second")},
+		{MimeType: "image/png", Data: []byte("third")},
+	}
+	if d := cmp.Diff(expected, imageOutputs(blocks, 2)); d != "" {
+		t.Errorf("Unexpected images (-want +got):\n%s", d)
+	}
+}
@@ -3,6 +3,9 @@ package agent
 import (
 	_ "embed"
 	"text/template"
+
+	"github.com/jlewi/foyle/app/pkg/docs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 )
 
 const (
@@ -10,6 +13,10 @@ const (
 markdown documents to deploy and operate software. Your job is to help users with tasks related to building, deploying,
 and operating software. You should interpret any questions or commands in that context. You job is to suggest
 commands the user can execute to accomplish their goals.`
+
+	// documentHeader precedes the document in prompt.tmpl. Everything before it (the instructions and examples)
+	// doesn't depend on the document so it is the prefix of the prompt that can be cached.
+	documentHeader = "Here's the actual document containing the problem or task to be solved:"
 )
 
 //go:embed prompt.tmpl
@@ -17,14 +24,39 @@ var promptTemplateString string
 
 var (
 	promptTemplate = template.Must(template.New("prompt").Parse(promptTemplateString))
+
+	// languageGuidance is added to the prompt for each language that is used in the document.
+	// N.B. The guidance comes after the document so it doesn't change the prefix of the prompt that can be cached.
+	languageGuidance = map[string]string{
+		docs.PYTHONLANG: "The document contains python code blocks. Python code blocks are run with a Python interpreter; write complete scripts that print their results.",
+		docs.SQLLANG:    "The document contains sql code blocks. SQL code blocks are run against the database configured for the notebook; write a single query and limit the number of rows it returns.",
+		docs.JQLANG:     "The document contains jq code blocks. jq code blocks contain only the jq filter; to run jq on the output of a command use a bash code block with a pipe.",
+	}
 )
 
+// languagePrompts returns the guidance for the languages of the code blocks.
+func languagePrompts(blocks []*v1alpha1.Block) []string {
+	prompts := make([]string, 0, len(languageGuidance))
+	for _, lang := range docs.CodeLanguages(blocks) {
+		if guidance, ok := languageGuidance[lang]; ok {
+			prompts = append(prompts, guidance)
+		}
+	}
+	return prompts
+}
+
 type Example struct {
 	Input  string
 	Output string
+	// Suffix is the text that followed the output; only set for fill in the middle prompts.
+	Suffix string
 }
 
 type promptArgs struct {
 	Document string
+	// Suffix is the text following the selected cell; only set for fill in the middle prompts.
+	Suffix   string
 	Examples []Example
+	// Languages is the guidance for the languages used in the document.
+	Languages []string
 }
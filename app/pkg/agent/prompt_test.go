@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 )
 
 func Test_Prompt(t *testing.T) {
@@ -39,6 +40,27 @@ func Test_Prompt(t *testing.T) {
 			},
 			expectedFile: "examples.txt",
 		},
+		{
+			args: promptArgs{
+				Document: "blah blah",
+				Suffix:   "what comes next",
+				Examples: []Example{
+					{
+						Input:  "input1",
+						Output: "output1",
+						Suffix: "suffix1",
+					},
+				},
+			},
+			expectedFile: "fim.txt",
+		},
+		{
+			args: promptArgs{
+				Document:  "blah blah",
+				Languages: languagePrompts([]*v1alpha1.Block{{Kind: v1alpha1.BlockKind_CODE, Language: "python3"}}),
+			},
+			expectedFile: "languages.txt",
+		},
 	}
 
 	updateExpected := (os.Getenv("UPDATE_EXPECTED") != "")
@@ -78,3 +100,19 @@ func Test_Prompt(t *testing.T) {
 		})
 	}
 }
+
+func Test_PromptDocumentHeader(t *testing.T) {
+	// The cacheable prefix of the prompt is found by looking for documentHeader so it needs to be kept in sync
+	// with the template.
+	var sb strings.Builder
+	if err := promptTemplate.Execute(&sb, promptArgs{Document: "some document"}); err != nil {
+		t.Fatalf("Failed to execute prompt template: %v", err)
+	}
+	i := strings.Index(sb.String(), documentHeader)
+	if i <= 0 {
+		t.Fatalf("The prompt doesn't contain the documentHeader %q", documentHeader)
+	}
+	if strings.Contains(sb.String()[:i], "some document") {
+		t.Errorf("The cacheable prefix of the prompt shouldn't contain the document")
+	}
+}
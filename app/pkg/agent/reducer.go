@@ -0,0 +1,129 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/docs"
+	"github.com/jlewi/foyle/app/pkg/events"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	summarizePrompt = `You summarize the output of commands that were run in a notebook so the output can be used as context
+when suggesting the next commands. Keep the information needed to decide what to do next: errors and warnings, the
+names and status of resources, counts and any values the user is likely to need. Reply with plain text; don't
+suggest any commands.`
+
+	// maxSummarizeInputChars is the maximum number of characters of an output sent to the model. Longer outputs are
+	// reduced with the fallback reducer first.
+	maxSummarizeInputChars = 50000
+
+	summarizedMessage = "<...the output was summarized by a model...>\n"
+
+	summaryCacheSize = 100
+	summaryCacheTTL  = 30 * time.Minute
+)
+
+// llmReducer asks a model to summarize long outputs. Shorter outputs and summaries that are still too long are
+// reduced by the fallback reducer. Summaries are cached because the same document is sent on every edit.
+type llmReducer struct {
+	completer llms.Completer
+	fallback  docs.OutputReducer
+	minChars  int
+	cache     *expirable.LRU[string, string]
+}
+
+// NewLLMReducer creates a reducer that uses the completer to summarize outputs of at least minChars characters.
+func NewLLMReducer(completer llms.Completer, minChars int) (docs.OutputReducer, error) {
+	if completer == nil {
+		return nil, errors.New("Completer is required")
+	}
+	return &llmReducer{
+		completer: completer,
+		fallback:  &docs.SmartReducer{},
+		minChars:  minChars,
+		cache:     expirable.NewLRU[string, string](summaryCacheSize, nil, summaryCacheTTL),
+	}, nil
+}
+
+func (r *llmReducer) Name() string {
+	return api.OutputReducerLLM
+}
+
+func (r *llmReducer) Reduce(ctx context.Context, output string, maxLength int) (string, error) {
+	if len(output) < r.minChars {
+		return r.fallback.Reduce(ctx, output, maxLength)
+	}
+
+	key := summaryKey(output, maxLength)
+	if summary, ok := r.cache.Get(key); ok {
+		return summary, nil
+	}
+
+	input := output
+	if len(input) > maxSummarizeInputChars {
+		reduced, err := r.fallback.Reduce(ctx, input, maxSummarizeInputChars)
+		if err != nil {
+			return "", err
+		}
+		input = reduced
+	}
+
+	// The call is logged and published without the traceId of the request. Otherwise the analyzer would mistake it
+	// for the call that generated the completion.
+	traceId := trace.SpanFromContext(ctx).SpanContext().TraceID()
+	sCtx := logr.NewContext(ctx, zapr.NewLogger(zap.L()).WithValues("summarizedForTraceId", traceId))
+	sCtx = events.WithTrace(sCtx, "", false)
+	blocks, err := r.completer.Complete(sCtx, summarizePrompt, input)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to summarize the output")
+	}
+
+	contents := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		if c := strings.TrimSpace(b.GetContents()); c != "" {
+			contents = append(contents, c)
+		}
+	}
+	if len(contents) == 0 {
+		return "", errors.New("The model returned an empty summary")
+	}
+
+	summary := summarizedMessage + strings.Join(contents, "\n")
+	if len(summary) > maxLength {
+		summary, err = r.fallback.Reduce(ctx, summary, maxLength)
+		if err != nil {
+			return "", err
+		}
+	}
+	r.cache.Add(key, summary)
+	return summary, nil
+}
+
+func summaryKey(output string, maxLength int) string {
+	h := sha256.New()
+	h.Write([]byte(output))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.Itoa(maxLength)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// newOutputReducer creates the reducer configured for the agent. summarizer is used by the llm strategy.
+func newOutputReducer(cfg api.OutputReducerConfig, summarizer llms.Completer) (docs.OutputReducer, error) {
+	if cfg.Strategy == api.OutputReducerLLM {
+		return NewLLMReducer(summarizer, cfg.MinLLMChars)
+	}
+	return docs.NewOutputReducer(cfg.Strategy)
+}
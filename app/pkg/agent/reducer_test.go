@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+)
+
+type countingCompleter struct {
+	calls int
+}
+
+func (c *countingCompleter) Complete(ctx context.Context, systemPrompt string, message string) ([]*v1alpha1.Block, error) {
+	c.calls++
+	return []*v1alpha1.Block{{Kind: v1alpha1.BlockKind_MARKUP, Contents: "The build failed because a file is missing."}}, nil
+}
+
+func Test_LLMReducer(t *testing.T) {
+	completer := &countingCompleter{}
+	r, err := NewLLMReducer(completer, 100)
+	if err != nil {
+		t.Fatalf("Failed to create reducer: %v", err)
+	}
+
+	long := strings.Repeat("compiling\n", 20) + "error: missing file"
+	for i := 0; i < 2; i++ {
+		summary, err := r.Reduce(context.Background(), long, 200)
+		if err != nil {
+			t.Fatalf("Reduce failed: %v", err)
+		}
+		if summary != summarizedMessage+"The build failed because a file is missing." {
+			t.Errorf("Unexpected summary %q", summary)
+		}
+	}
+	if completer.calls != 1 {
+		t.Errorf("Expected the summary to be cached; completer was called %d times", completer.calls)
+	}
+
+	// Short outputs aren't sent to the model.
+	if _, err := r.Reduce(context.Background(), "line 1\nline 2\nline 3", 10); err != nil {
+		t.Fatalf("Reduce failed: %v", err)
+	}
+	if completer.calls != 1 {
+		t.Errorf("Expected short outputs to be reduced without the model; completer was called %d times", completer.calls)
+	}
+}
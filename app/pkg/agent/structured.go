@@ -0,0 +1,224 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jlewi/foyle/app/pkg/docs"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+const (
+	addCellsTool = "add_cells"
+
+	structuredPrompt = `
+Return the cells to add to the document by calling the add_cells tool rather than by replying with markdown.
+Use a markup cell for markdown and a code cell for the commands the user should execute. Use the explanation of
+a code cell to briefly explain what the commands do.`
+
+	cellKindMarkup = "markup"
+	cellKindCode   = "code"
+)
+
+var (
+	// addCellsParameters is the JSON schema of the cells returned by the model. parseCells validates the
+	// arguments against it.
+	addCellsParameters = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "cells": {
+      "type": "array",
+      "minItems": 1,
+      "items": {
+        "type": "object",
+        "properties": {
+          "kind": {
+            "type": "string",
+            "enum": ["markup", "code"],
+            "description": "markup for markdown; code for commands the user should execute"
+          },
+          "language": {
+            "type": "string",
+            "description": "The language of a code cell; e.g. bash, python, sql or jq"
+          },
+          "contents": {
+            "type": "string",
+            "description": "The markdown or the commands"
+          },
+          "explanation": {
+            "type": "string",
+            "description": "A short explanation of what the commands in a code cell do"
+          }
+        },
+        "required": ["kind", "contents"],
+        "additionalProperties": false
+      }
+    }
+  },
+  "required": ["cells"],
+  "additionalProperties": false
+}`)
+)
+
+type structuredCell struct {
+	Kind        string `json:"kind"`
+	Language    string `json:"language,omitempty"`
+	Contents    string `json:"contents"`
+	Explanation string `json:"explanation,omitempty"`
+}
+
+type addCellsArgs struct {
+	Cells []structuredCell `json:"cells"`
+}
+
+// completeStructured generates n candidate completions by asking the model to call the add_cells tool. If the
+// completer doesn't support tool calling or the reply isn't valid we fall back to parsing markdown. A
+// STRUCTURED_OUTPUT assertion records whether every candidate was parsed from structured output.
+func (a *Agent) completeStructured(ctx context.Context, message string, n int) ([][]*v1alpha1.Block, error) {
+	log := logs.FromContext(ctx)
+	if n < 1 {
+		n = 1
+	}
+	tc, ok := a.completer.(llms.ToolCompleter)
+	if !ok || !tc.SupportsTools() {
+		logStructuredAssertion(ctx, n, n, errors.New("the completer doesn't support tool calling"))
+		return llms.CompleteN(ctx, a.completer, systemPrompt, message, n)
+	}
+
+	tools := []llms.Tool{
+		{
+			Name:        addCellsTool,
+			Description: "Add cells to the document.",
+			Parameters:  addCellsParameters,
+		},
+	}
+	messages := []llms.Message{
+		{
+			Role:    llms.RoleUser,
+			Content: message,
+		},
+	}
+
+	completions := make([][]*v1alpha1.Block, 0, n)
+	// fallbacks is the number of candidates that weren't parsed from structured output and fallbackErr the reason
+	// the first one wasn't.
+	fallbacks := 0
+	var fallbackErr error
+	for i := 0; i < n; i++ {
+		reply, err := tc.CompleteWithTools(ctx, systemPrompt+structuredPrompt, messages, tools)
+		if err != nil {
+			if errors.Is(err, llms.ErrToolsNotSupported) {
+				if fallbackErr == nil {
+					fallbackErr = err
+				}
+				logStructuredAssertion(ctx, n, fallbacks+n-i, fallbackErr)
+				rest, err := llms.CompleteN(ctx, a.completer, systemPrompt, message, n-i)
+				if err != nil {
+					return nil, err
+				}
+				return append(completions, rest...), nil
+			}
+			return nil, err
+		}
+
+		blocks, err := replyToCells(reply)
+		if err != nil {
+			log.Info("Falling back to parsing markdown", "err", err.Error())
+			fallbacks++
+			if fallbackErr == nil {
+				fallbackErr = err
+			}
+			blocks, err = a.structuredFallback(ctx, message, reply)
+			if err != nil {
+				return nil, err
+			}
+		}
+		completions = append(completions, blocks)
+	}
+	logStructuredAssertion(ctx, n, fallbacks, fallbackErr)
+	return completions, nil
+}
+
+// structuredFallback parses the reply as markdown. If the reply doesn't contain any text we ask the model again
+// for markdown.
+func (a *Agent) structuredFallback(ctx context.Context, message string, reply *llms.Message) ([]*v1alpha1.Block, error) {
+	if strings.TrimSpace(reply.Content) != "" {
+		return replyToBlocks(ctx, reply.Content)
+	}
+	return a.completer.Complete(ctx, systemPrompt, message)
+}
+
+// replyToCells returns the blocks for the cells in the model's call to add_cells.
+func replyToCells(reply *llms.Message) ([]*v1alpha1.Block, error) {
+	for _, call := range reply.ToolCalls {
+		if call.Name != addCellsTool {
+			continue
+		}
+		return parseCells(call.Arguments)
+	}
+	return nil, errors.Errorf("the model didn't call %s", addCellsTool)
+}
+
+// parseCells validates the arguments of add_cells against addCellsParameters and converts the cells to blocks.
+// The explanation of a code cell is added as a markup block before the code so the blocks have the same shape
+// as those parsed from markdown.
+func parseCells(arguments string) ([]*v1alpha1.Block, error) {
+	args := &addCellsArgs{}
+	d := json.NewDecoder(bytes.NewReader([]byte(arguments)))
+	d.DisallowUnknownFields()
+	if err := d.Decode(args); err != nil {
+		return nil, errors.Wrapf(err, "the arguments of %s aren't valid", addCellsTool)
+	}
+	if len(args.Cells) == 0 {
+		return nil, errors.Errorf("the arguments of %s don't contain any cells", addCellsTool)
+	}
+
+	blocks := make([]*v1alpha1.Block, 0, len(args.Cells))
+	for i, c := range args.Cells {
+		if strings.TrimSpace(c.Contents) == "" {
+			return nil, errors.Errorf("cell %d is empty", i)
+		}
+		switch c.Kind {
+		case cellKindMarkup:
+			blocks = append(blocks, &v1alpha1.Block{
+				Kind:     v1alpha1.BlockKind_MARKUP,
+				Contents: c.Contents,
+			})
+		case cellKindCode:
+			if strings.TrimSpace(c.Explanation) != "" {
+				blocks = append(blocks, &v1alpha1.Block{
+					Kind:     v1alpha1.BlockKind_MARKUP,
+					Contents: c.Explanation,
+				})
+			}
+			blocks = append(blocks, &v1alpha1.Block{
+				Kind:     v1alpha1.BlockKind_CODE,
+				Language: c.Language,
+				Contents: strings.TrimSuffix(c.Contents, "\n"),
+			})
+		default:
+			return nil, errors.Errorf("cell %d has kind %q; kind must be %s or %s", i, c.Kind, cellKindMarkup, cellKindCode)
+		}
+	}
+
+	if _, err := docs.SetBlockIds(blocks); err != nil {
+		return nil, errors.Wrapf(err, "Failed to set block ids")
+	}
+	return blocks, nil
+}
+
+// logStructuredAssertion logs a single assertion for the n candidates of a request. It passes only if none of
+// them fell back to parsing markdown. err is the reason the first candidate that fell back did.
+func logStructuredAssertion(ctx context.Context, n int, fallbacks int, err error) {
+	assertion := logs.BuildAssertion(v1alpha1.Assertion_STRUCTURED_OUTPUT, fallbacks == 0)
+	if fallbacks > 0 {
+		assertion.Detail = fmt.Sprintf("Fell back to parsing markdown for %d of %d candidates: %v", fallbacks, n, err)
+	}
+	logs.LogAssertion(ctx, assertion)
+}
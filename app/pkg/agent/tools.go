@@ -0,0 +1,239 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/docs"
+	"github.com/jlewi/foyle/app/pkg/events"
+	"github.com/jlewi/foyle/app/pkg/executor"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	"github.com/jlewi/foyle/app/pkg/logs/matchers"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	runCommandTool = "run_command"
+
+	// maxToolOutputChars is the maximum number of characters of a command's output returned to the model.
+	maxToolOutputChars = 2000
+
+	toolsPrompt = `
+Before suggesting commands you can call the run_command tool to run read-only commands that gather information
+about the user's environment; e.g. to find the names of resources or check their status. Only the following
+commands are allowed: %s.
+Only call the tool when the information would change your suggestion. Once you have the information you need,
+respond with the markdown containing your suggestion.`
+
+	// Values for the status label of toolCallsCounter
+	toolCallOK       = "ok"
+	toolCallRejected = "rejected"
+	toolCallError    = "error"
+	toolCallLimit    = "limit"
+)
+
+var (
+	toolCallsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "agent_tool_calls_total",
+		Help: "Number of tool calls made by the agent broken down by status"},
+		[]string{"status"},
+	)
+
+	runCommandParameters = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "command": {
+      "type": "string",
+      "description": "The read-only command to run; e.g. kubectl get pods -n default"
+    }
+  },
+  "required": ["command"]
+}`)
+)
+
+// toolRunner runs the tools the model calls.
+type toolRunner struct {
+	executor *executor.Executor
+	policy   *executor.CommandPolicy
+	config   api.ToolsConfig
+}
+
+func newToolRunner(cfg api.ToolsConfig, e *executor.Executor) *toolRunner {
+	return &toolRunner{
+		executor: e,
+		policy:   executor.NewCommandPolicy(cfg.AllowedCommands),
+		config:   cfg,
+	}
+}
+
+type runCommandArgs struct {
+	Command string `json:"command"`
+}
+
+// completeWithTools generates a completion letting the model run read-only commands before it answers.
+func (a *Agent) completeWithTools(ctx context.Context, message string) ([]*v1alpha1.Block, error) {
+	log := logs.FromContext(ctx)
+	tc, ok := a.completer.(llms.ToolCompleter)
+	if !ok || !tc.SupportsTools() {
+		return nil, errors.New("Tools are enabled but the completer doesn't support tool calling")
+	}
+
+	prompt := systemPrompt + fmt.Sprintf(toolsPrompt, strings.Join(a.tools.policy.Commands(), ", "))
+	tools := []llms.Tool{
+		{
+			Name:        runCommandTool,
+			Description: "Run a read-only shell command and return its exit code and output.",
+			Parameters:  runCommandParameters,
+		},
+	}
+
+	messages := []llms.Message{
+		{
+			Role:    llms.RoleUser,
+			Content: message,
+		},
+	}
+
+	numCalls := 0
+	// We allow one extra round after the limit is reached so the model can answer using the results it has.
+	for round := 0; round <= a.tools.config.MaxCalls; round++ {
+		reply, err := tc.CompleteWithTools(ctx, prompt, messages, tools)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(reply.ToolCalls) == 0 {
+			return replyToBlocks(ctx, reply.Content)
+		}
+
+		messages = append(messages, *reply)
+		for _, call := range reply.ToolCalls {
+			var output string
+			if numCalls >= a.tools.config.MaxCalls {
+				toolCallsCounter.WithLabelValues(toolCallLimit).Inc()
+				output = fmt.Sprintf("The limit of %d commands has been reached; answer using the information you already have.", a.tools.config.MaxCalls)
+			} else {
+				output = a.runTool(ctx, call)
+			}
+			numCalls++
+			messages = append(messages, llms.Message{
+				Role:       llms.RoleTool,
+				Content:    output,
+				ToolCallID: call.ID,
+			})
+		}
+	}
+
+	err := errors.Errorf("The model didn't produce an answer after %d tool calls", numCalls)
+	log.Error(err, "Failed to generate a completion with tools")
+	return nil, err
+}
+
+// runTool runs a tool call and returns the result to send to the model. Errors are returned to the model as the
+// result so it can try something else. Every call is logged as a ToolSpan.
+func (a *Agent) runTool(ctx context.Context, call llms.ToolCall) string {
+	log := logs.FromContext(ctx)
+	ctx, otelSpan := tracer().Start(ctx, "RunTool", trace.WithAttributes(attribute.String("tool", call.Name)))
+	defer otelSpan.End()
+
+	toolSpan := &logspb.ToolSpan{
+		Name:      call.Name,
+		Arguments: call.Arguments,
+		StartTime: timestamppb.Now(),
+	}
+	defer func() {
+		toolSpan.EndTime = timestamppb.Now()
+		log.Info("Agent tool call", "tool", call.Name, "command", toolSpan.Command, "allowed", toolSpan.Allowed, logs.ZapProto(matchers.ToolSpanField, toolSpan))
+		events.Publish(ctx, &events.Event{Span: &logspb.Span{Data: &logspb.Span_Tool{Tool: toolSpan}}})
+	}()
+
+	if call.Name != runCommandTool {
+		toolCallsCounter.WithLabelValues(toolCallError).Inc()
+		toolSpan.Error = fmt.Sprintf("unknown tool %s", call.Name)
+		toolSpan.Output = "Error: " + toolSpan.Error
+		return toolSpan.Output
+	}
+
+	args := &runCommandArgs{}
+	if err := json.Unmarshal([]byte(call.Arguments), args); err != nil {
+		toolCallsCounter.WithLabelValues(toolCallError).Inc()
+		toolSpan.Error = fmt.Sprintf("invalid arguments: %v", err)
+		toolSpan.Output = "Error: " + toolSpan.Error
+		return toolSpan.Output
+	}
+	toolSpan.Command = args.Command
+
+	runCtx, cancel := context.WithTimeout(ctx, time.Duration(a.tools.config.TimeoutSeconds)*time.Second)
+	defer cancel()
+	result, err := a.tools.executor.RunAllowed(runCtx, args.Command, a.tools.policy)
+	if err != nil {
+		notAllowed := &executor.NotAllowedError{}
+		if errors.As(err, &notAllowed) {
+			toolCallsCounter.WithLabelValues(toolCallRejected).Inc()
+		} else {
+			toolCallsCounter.WithLabelValues(toolCallError).Inc()
+			toolSpan.Allowed = true
+		}
+		toolSpan.Error = err.Error()
+		toolSpan.Output = "Error: " + err.Error()
+		return toolSpan.Output
+	}
+
+	toolCallsCounter.WithLabelValues(toolCallOK).Inc()
+	toolSpan.Allowed = true
+	toolSpan.ExitCode = int32(result.ExitCode)
+	toolSpan.Output = formatCommandResult(result)
+	return toolSpan.Output
+}
+
+// formatCommandResult formats the result of a command for the model. Long outputs are truncated; we keep the
+// beginning since that usually contains headers and the most relevant rows.
+func formatCommandResult(r *executor.CommandResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("exitCode: %d\n", r.ExitCode))
+	if r.StdOut != "" {
+		sb.WriteString("stdout:\n" + r.StdOut + "\n")
+	}
+	if r.StdErr != "" {
+		sb.WriteString("stderr:\n" + r.StdErr + "\n")
+	}
+	out := sb.String()
+	if len(out) > maxToolOutputChars {
+		out = out[:maxToolOutputChars] + "\n<...output was truncated...>"
+	}
+	return out
+}
+
+// replyToBlocks parses the final answer of the model into blocks.
+func replyToBlocks(ctx context.Context, content string) ([]*v1alpha1.Block, error) {
+	log := logs.FromContext(ctx)
+	if strings.TrimSpace(content) == "" {
+		return []*v1alpha1.Block{}, nil
+	}
+	blocks, err := docs.MarkdownToBlocks(content)
+	if err != nil {
+		log.Error(err, "Failed to parse markdown to blocks", "markdown", content)
+		blocks = []*v1alpha1.Block{
+			{
+				Kind:     v1alpha1.BlockKind_MARKUP,
+				Contents: content,
+			},
+		}
+	}
+	if _, err := docs.SetBlockIds(blocks); err != nil {
+		return nil, errors.Wrapf(err, "Failed to set block ids")
+	}
+	return blocks, nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/jlewi/foyle/app/pkg/logs/matchers"
+	"github.com/jlewi/foyle/app/pkg/queue"
 
 	"github.com/jlewi/foyle/app/pkg/runme/converters"
 	parserv1 "github.com/stateful/runme/v3/pkg/api/gen/proto/go/runme/parser/v1"
@@ -24,9 +25,9 @@ import (
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap"
 
-	"github.com/cockroachdb/pebble"
 	"github.com/jlewi/foyle/app/api"
 	"github.com/jlewi/foyle/app/pkg/dbutil"
+	"github.com/jlewi/foyle/app/pkg/llms"
 	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -49,11 +50,18 @@ const (
 	// to a particular trace. We don't use the field "traceId" because these log entries aren't actually part of the
 	// trace.
 	traceField = "targetTraceId"
+
+	// blockQueueName is the name of the durable queue of blocks to update.
+	blockQueueName = "blocks"
 )
 
 // Analyzer is responsible for analyzing logs and building traces. It does this in a streaming fashion so that
 // traces get built in "realtime".
 //
+// When the Analyzer is subscribed to an events.Bus (see Subscribe) traces, blocks and sessions are built from the
+// events published by the agent and the logs are only processed to backfill the entries written before the
+// subscription.
+//
 // The Analyzer is multi-threaded. One potential pitfall is we have multiple writers trying to update the same
 // key. This would result in a last-write win situation with the last write potentially overwriting the changes
 // by the other writer. To avoid this, we use WorkQueue's to schedule updates to each key. The workqueue
@@ -65,15 +73,15 @@ const (
 // its updated and invoke handleLogFileEvents. So if handleLogFileEvents updates the log file then we get constant
 // reprocessing. We use a rateLimitingQueue to ensure that we don't process the same file too quickly.
 type Analyzer struct {
-	tracesDB  *pebble.DB
+	tracesDB  dbutil.KV
 	blocksDB  *dbutil.LockingDB[*logspb.BlockLog]
 	rawLogsDB *dbutil.LockingDB[*logspb.LogEntries]
 	// queue for log file processing
 	queue workqueue.RateLimitingInterface
 
-	// Queue for block log processing
-	// TODO(jeremy): We should really use a durable queue backed by files
-	blockQueue workqueue.DelayingInterface
+	// Queue for block log processing. It is durable so blocks that haven't been processed when the analyzer shuts
+	// down are processed when it restarts.
+	blockQueue *queue.Queue
 
 	watcher *fsnotify.Watcher
 
@@ -90,10 +98,24 @@ type Analyzer struct {
 	signalBlockDone chan<- string
 
 	sessBuilder *sessionBuilder
+
+	// pricing is used to compute the cost of the LLM calls in a trace.
+	pricing *llms.PriceTable
+
+	// liveSince is when the analyzer subscribed to the event bus; zero if it didn't. Log entries written after
+	// liveSince are skipped because they were already processed as events.
+	liveSince time.Time
+	// pendingTraces are the traces built from events whose response hasn't been published yet.
+	pendingTraces map[string]*pendingTrace
+	// lastFlush is the time of the last event that checked for stale pending traces.
+	lastFlush time.Time
+	// exporter exports the traces built from events; nil if traces aren't exported.
+	exporter TraceExporter
 }
 
-// NewAnalyzer creates a new Analyzer.
-func NewAnalyzer(logOffsetsFile string, maxDelay time.Duration, rawLogsDB *dbutil.LockingDB[*logspb.LogEntries], tracesDB *pebble.DB, blocksDB *dbutil.LockingDB[*logspb.BlockLog], sessions *SessionsManager) (*Analyzer, error) {
+// NewAnalyzer creates a new Analyzer. exporter is optional; if it is set the traces built from events are exported
+// to it. queuesDB is the database backing the block queue; if it is nil the queue is kept in memory.
+func NewAnalyzer(logOffsetsFile string, maxDelay time.Duration, rawLogsDB *dbutil.LockingDB[*logspb.LogEntries], tracesDB dbutil.KV, blocksDB *dbutil.LockingDB[*logspb.BlockLog], sessions *SessionsManager, pricing *llms.PriceTable, exporter TraceExporter, queuesDB dbutil.KV) (*Analyzer, error) {
 	logOffsets, err := initOffsets(logOffsetsFile)
 	if err != nil {
 		return nil, err
@@ -117,20 +139,28 @@ func NewAnalyzer(logOffsetsFile string, maxDelay time.Duration, rawLogsDB *dbuti
 	baseDelay := time.Second
 	fileQueue := workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay))
 
-	sessBuilder, err := NewSessionBuilder(sessions)
+	sessBuilder, err := NewSessionBuilder(sessions, pricing)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to create session builder")
 	}
 
+	blockQueue, err := queue.New(queuesDB, blockQueueName, queue.DefaultOptions())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create block queue")
+	}
+
 	return &Analyzer{
 		logOffsetsFile: logOffsetsFile,
 		rawLogsDB:      rawLogsDB,
 		tracesDB:       tracesDB,
 		blocksDB:       blocksDB,
 		queue:          fileQueue,
-		blockQueue:     workqueue.NewDelayingQueue(),
+		blockQueue:     blockQueue,
 		logFileOffsets: logOffsets,
 		sessBuilder:    sessBuilder,
+		pricing:        pricing,
+		pendingTraces:  make(map[string]*pendingTrace),
+		exporter:       exporter,
 	}, nil
 }
 
@@ -158,10 +188,6 @@ type fileItem struct {
 	active bool
 }
 
-type blockItem struct {
-	id string
-}
-
 // PostSessionEvent interface for functions to post session events.
 type PostSessionEvent func(session *logspb.Session) error
 
@@ -178,8 +204,9 @@ func (a *Analyzer) Run(ctx context.Context, logDirs []string, learnNotifier Post
 
 	// Enqueue an item to process each file
 	for i, f := range jsonFiles {
-		// Only the last file should be active.
-		active := i == len(jsonFiles)-1
+		// Only the last file should be active. If the analyzer is subscribed to the event bus no file is active;
+		// the logs are only processed once to backfill the entries written before the events.
+		active := i == len(jsonFiles)-1 && a.liveSince.IsZero()
 		a.queue.Add(fileItem{path: f, active: active})
 	}
 
@@ -289,6 +316,11 @@ func (a *Analyzer) processLogFile(ctx context.Context, path string) error {
 
 			lastLogTime = entry.Time()
 
+			// Skip entries that were already processed as events.
+			if !a.liveSince.IsZero() && !lastLogTime.Before(a.liveSince) {
+				continue
+			}
+
 			// Add the entry to a session if it should be.
 			a.sessBuilder.processLogEntry(entry, a.learnNotifier)
 
@@ -448,6 +480,12 @@ func (a *Analyzer) processLogEvent(ctx context.Context, entry *api.LogEntry) {
 		log.Error(errors.New("Failed to decode event"), "Failed to decode LogEvent", "entry", entry)
 		return
 	}
+	a.updateBlocksFromLogEvent(ctx, event)
+}
+
+// updateBlocksFromLogEvent updates the blocks with the execution or the status of the suggestion in the event.
+func (a *Analyzer) updateBlocksFromLogEvent(ctx context.Context, event *v1alpha1.LogEvent) {
+	log := logs.FromContext(ctx)
 	log = log.WithValues("eventId", event.GetEventId())
 	switch event.Type {
 	case v1alpha1.LogEventType_EXECUTE:
@@ -540,7 +578,7 @@ func (a *Analyzer) buildTrace(ctx context.Context, tid string) error {
 
 	// Now combine the entries for the trace
 	log.Info("Combining entries for trace", traceField, tid, "numEntries", len(logEntries))
-	trace, err := combineEntriesForTrace(ctx, logEntries)
+	trace, err := combineEntriesForTrace(ctx, logEntries, a.pricing)
 	if err != nil {
 		log.Error(err, "Error combining entries for trace", traceField, tid)
 		return err
@@ -552,7 +590,13 @@ func (a *Analyzer) buildTrace(ctx context.Context, tid string) error {
 		log.V(logs.Debug).Info("Entries for trace are currently skipped", traceField, tid)
 		return nil
 	}
+	return a.saveTrace(ctx, tid, trace)
+}
 
+// saveTrace writes the trace and updates the blocks it generated. It is used both when traces are built from the
+// logs and from events published on the event bus.
+func (a *Analyzer) saveTrace(ctx context.Context, tid string, trace *logspb.Trace) error {
+	log := logs.FromContext(ctx)
 	if err := dbutil.SetProto(a.tracesDB, tid, trace); err != nil {
 		return err
 	}
@@ -563,7 +607,7 @@ func (a *Analyzer) buildTrace(ctx context.Context, tid string) error {
 		bids := make([]string, 0, 10)
 		switch t := trace.Data.(type) {
 		case *logspb.Trace_Generate:
-			for _, oBlock := range t.Generate.Response.GetBlocks() {
+			for _, oBlock := range generatedBlocks(t.Generate.Response) {
 				bid := oBlock.GetId()
 				if bid == "" {
 					continue
@@ -590,9 +634,9 @@ func (a *Analyzer) buildTrace(ctx context.Context, tid string) error {
 
 	// Enqueue the block updates
 	for _, delta := range blockIds {
-		a.blockQueue.Add(blockItem{
-			id: delta,
-		})
+		if err := a.blockQueue.Add(delta); err != nil {
+			return errors.Wrapf(err, "Failed to enqueue block %s", delta)
+		}
 	}
 
 	return nil
@@ -656,30 +700,25 @@ func findLogFiles(ctx context.Context, logsDir string) ([]string, error) {
 func (a *Analyzer) handleBlockEvents(ctx context.Context) {
 	log := logs.FromContext(ctx)
 	for {
-		item, shutdown := a.blockQueue.Get()
+		blockID, shutdown := a.blockQueue.Get()
 		if shutdown {
 			a.handleBlocksIsDone.Done()
 			return
 		}
 		func() {
-			defer a.blockQueue.Done(item)
-			// N.B. We need to enqueue concrete types because the workqueue uses the value as the key.
-			// If we use pointers then we would be using the address as the key and we will end up treating the same
-			// values as different keys which would result in multiple workers processing the same item.
-			blockItem, ok := item.(blockItem)
-			if !ok {
-				log.Error(errors.New("Failed to cast item to blockItem"), "Failed to cast item to blockItem")
-				return
-			}
-
-			err := a.blocksDB.ReadModifyWrite(blockItem.id, func(block *logspb.BlockLog) error {
+			err := a.blocksDB.ReadModifyWrite(blockID, func(block *logspb.BlockLog) error {
 				return buildBlockLog(ctx, block, a.tracesDB)
 			})
 			if err != nil {
-				log.Error(err, "Error processing block", "blockId", blockItem.id)
+				log.Error(err, "Error processing block", "blockId", blockID)
+				if err := a.blockQueue.Retry(blockID, err); err != nil {
+					log.Error(err, "Failed to retry block", "blockId", blockID)
+				}
+			} else if err := a.blockQueue.Done(blockID); err != nil {
+				log.Error(err, "Failed to mark block as done", "blockId", blockID)
 			}
 			if a.signalBlockDone != nil {
-				a.signalBlockDone <- blockItem.id
+				a.signalBlockDone <- blockID
 			}
 		}()
 	}
@@ -687,7 +726,7 @@ func (a *Analyzer) handleBlockEvents(ctx context.Context) {
 
 // buildBlockLog updates blocklogs given a generate trace.
 // Since a single generate trace can generate multiple blocks, its a one to many operation.
-func buildBlockLog(ctx context.Context, block *logspb.BlockLog, tracesDB *pebble.DB) error {
+func buildBlockLog(ctx context.Context, block *logspb.BlockLog, tracesDB dbutil.KV) error {
 	log := logs.FromContext(ctx)
 	log = log.WithValues("blockId", block.Id)
 	log.Info("Building block log")
@@ -715,13 +754,24 @@ func buildBlockLog(ctx context.Context, block *logspb.BlockLog, tracesDB *pebble
 				block.EvalMode = true
 			}
 
-			// Find the actual block
+			// Find the actual block. The block could belong to the top ranked candidate or one of the alternatives
+			// the user cycled to; we record which so we can tell which alternatives get accepted.
 			for _, b := range genTrace.Generate.Response.GetBlocks() {
 				if b.GetId() == block.GetId() {
 					block.GeneratedBlock = b
+					block.CandidateRank = 0
 					return
 				}
 			}
+			for i, alt := range genTrace.Generate.Response.GetAlternatives() {
+				for _, b := range alt.GetBlocks() {
+					if b.GetId() == block.GetId() {
+						block.GeneratedBlock = b
+						block.CandidateRank = int32(i + 1)
+						return
+					}
+				}
+			}
 			if block.GeneratedBlock == nil {
 				log.Error(errors.New("Failed to find generated block"), "Error finding generated block", "blockId", block.GetId())
 			}
@@ -731,7 +781,17 @@ func buildBlockLog(ctx context.Context, block *logspb.BlockLog, tracesDB *pebble
 	return nil
 }
 
-func combineEntriesForTrace(ctx context.Context, entries []*api.LogEntry) (*logspb.Trace, error) {
+// generatedBlocks returns all the blocks in the response including the blocks in the alternatives.
+func generatedBlocks(resp *v1alpha1.GenerateResponse) []*v1alpha1.Block {
+	blocks := make([]*v1alpha1.Block, 0, len(resp.GetBlocks()))
+	blocks = append(blocks, resp.GetBlocks()...)
+	for _, alt := range resp.GetAlternatives() {
+		blocks = append(blocks, alt.GetBlocks()...)
+	}
+	return blocks
+}
+
+func combineEntriesForTrace(ctx context.Context, entries []*api.LogEntry, pricing *llms.PriceTable) (*logspb.Trace, error) {
 	// First sort the entries by timestamp.
 	sort.Slice(entries, func(i, j int) bool {
 		return entries[i].Time().Before(entries[j].Time())
@@ -741,7 +801,7 @@ func combineEntriesForTrace(ctx context.Context, entries []*api.LogEntry) (*logs
 	for _, logEntry := range entries {
 		function := logEntry.Function()
 		if strings.HasSuffix(function, "agent.(*Agent).Generate") {
-			return combineGenerateTrace(ctx, entries)
+			return combineGenerateTrace(ctx, entries, pricing)
 		}
 
 		if strings.HasSuffix(function, "agent.(*Agent).StreamGenerate") {
@@ -753,7 +813,7 @@ func combineEntriesForTrace(ctx context.Context, entries []*api.LogEntry) (*logs
 	return nil, errors.New("Failed to identify trace type")
 }
 
-func combineGenerateTrace(ctx context.Context, entries []*api.LogEntry) (*logspb.Trace, error) {
+func combineGenerateTrace(ctx context.Context, entries []*api.LogEntry, pricing *llms.PriceTable) (*logspb.Trace, error) {
 	log := logs.FromContext(ctx)
 	gTrace := &logspb.GenerateTrace{}
 	trace := &logspb.Trace{
@@ -788,6 +848,17 @@ func combineGenerateTrace(ctx context.Context, entries []*api.LogEntry) (*logspb
 			continue
 		}
 
+		if usage, ok := getLLMUsage(e); ok {
+			trace.InputTokens += int32(usage.InputTokens)
+			trace.OutputTokens += int32(usage.OutputTokens)
+			trace.CachedInputTokens += int32(usage.CachedInputTokens)
+			cost, _ := pricing.Cost(*usage)
+			trace.CostUsd += cost
+		} else if matchers.IsLLMUsage(e.Function()) {
+			log.Error(errors.New("Failed to decode usage"), "Failed to decode LLMUsage", "entry", e)
+			continue
+		}
+
 		if gTrace.Request == nil && strings.HasSuffix(e.Function(), "agent.(*Agent).Generate") {
 			raw := e.Request()
 			if raw != nil {
@@ -14,10 +14,10 @@ import (
 	"github.com/go-logr/zapr"
 	"github.com/jlewi/foyle/app/pkg/logs"
 
-	"github.com/cockroachdb/pebble"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/jlewi/foyle/app/pkg/dbutil"
+	"github.com/jlewi/foyle/app/pkg/llms"
 	"github.com/jlewi/foyle/app/pkg/testutil"
 	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
@@ -47,6 +47,7 @@ func Test_BuildBlockLog(t *testing.T) {
 	traces := make(map[string]*logspb.Trace)
 
 	const bid1 = "g123output1"
+	const bidAlt = "g123alt1"
 	genTrace := &logspb.Trace{
 		Id:        "g123",
 		StartTime: timeMustParse(time.RFC3339, "2021-01-01T00:00:00Z"),
@@ -69,6 +70,16 @@ func Test_BuildBlockLog(t *testing.T) {
 							Contents: "outcell",
 						},
 					},
+					Alternatives: []*v1alpha1.Candidate{
+						{
+							Blocks: []*v1alpha1.Block{
+								{
+									Id:       bidAlt,
+									Contents: "altcell",
+								},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -123,6 +134,21 @@ func Test_BuildBlockLog(t *testing.T) {
 			},
 			traces: traces,
 		},
+		{
+			name: "alternative",
+			block: &logspb.BlockLog{
+				Id:         bidAlt,
+				GenTraceId: genTrace.Id,
+			},
+			expected: &logspb.BlockLog{
+				Id:             bidAlt,
+				GenTraceId:     genTrace.Id,
+				Doc:            genTrace.GetGenerate().Request.Doc,
+				GeneratedBlock: genTrace.GetGenerate().Response.Alternatives[0].Blocks[0],
+				CandidateRank:  1,
+			},
+			traces: traces,
+		},
 		{
 			name: "eval_mode",
 			block: &logspb.BlockLog{
@@ -144,7 +170,7 @@ func Test_BuildBlockLog(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create temp dir: %v", err)
 	}
-	tracesDB, err := pebble.Open(tracesDBDir, &pebble.Options{})
+	tracesDB, err := dbutil.OpenPebbleKV(tracesDBDir, false)
 	if err != nil {
 		t.Errorf("could not open traces database %s", tracesDBDir)
 	}
@@ -211,7 +237,7 @@ func Test_Analyzer(t *testing.T) {
 	tracesDBDir := filepath.Join(oDir, "traces")
 	blocksDBDir := filepath.Join(oDir, "blocks")
 
-	rawDB, err := pebble.Open(rawLogsDBDir, &pebble.Options{})
+	rawDB, err := dbutil.OpenPebbleKV(rawLogsDBDir, false)
 	if err != nil {
 		t.Fatalf("could not open blocks database %s", blocksDBDir)
 	}
@@ -219,7 +245,7 @@ func Test_Analyzer(t *testing.T) {
 
 	lockingRawDB := NewLockingEntriesDB(rawDB)
 
-	blocksDB, err := pebble.Open(blocksDBDir, &pebble.Options{})
+	blocksDB, err := dbutil.OpenPebbleKV(blocksDBDir, false)
 	if err != nil {
 		t.Fatalf("could not open blocks database %s", blocksDBDir)
 	}
@@ -227,7 +253,7 @@ func Test_Analyzer(t *testing.T) {
 
 	lockingBlocksDB := NewLockingBlocksDB(blocksDB)
 
-	tracesDB, err := pebble.Open(tracesDBDir, &pebble.Options{})
+	tracesDB, err := dbutil.OpenPebbleKV(tracesDBDir, false)
 	if err != nil {
 		t.Fatalf("could not open blocks database %s", blocksDBDir)
 	}
@@ -243,7 +269,7 @@ func Test_Analyzer(t *testing.T) {
 		t.Fatalf("Failed to open database: %v; error: %+v", sessionsPath, err)
 	}
 
-	sessionsManager, err := NewSessionsManager(db)
+	sessionsManager, err := NewSessionsManager(NewStore(db, dbutil.SQLite))
 	if err != nil {
 		t.Fatalf("Failed to create sessions manager: %v", err)
 	}
@@ -263,7 +289,7 @@ func Test_Analyzer(t *testing.T) {
 	}
 
 	logOffsetsFile := filepath.Join(rawDir, "log_offsets.json")
-	a, err := NewAnalyzer(logOffsetsFile, 3*time.Second, lockingRawDB, tracesDB, lockingBlocksDB, sessionsManager)
+	a, err := NewAnalyzer(logOffsetsFile, 3*time.Second, lockingRawDB, tracesDB, lockingBlocksDB, sessionsManager, llms.NewPriceTable(nil), nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to create analyzer: %v", err)
 	}
@@ -516,7 +542,7 @@ func Test_CombineGenerateEntries(t *testing.T) {
 					entries = append(entries, e)
 				}
 			}
-			trace, err := combineGenerateTrace(context.Background(), entries)
+			trace, err := combineGenerateTrace(context.Background(), entries, llms.NewPriceTable(nil))
 			if err != nil {
 				t.Fatalf("combineGenerateTrace failed: %+v", err)
 			}
@@ -8,7 +8,6 @@ import (
 	"connectrpc.com/connect"
 	"github.com/jlewi/foyle/app/pkg/logs"
 
-	"github.com/cockroachdb/pebble"
 	"github.com/go-logr/zapr"
 	"github.com/jlewi/foyle/app/pkg/config"
 	"github.com/jlewi/foyle/app/pkg/dbutil"
@@ -20,13 +19,13 @@ import (
 // CrudHandler is a handler for CRUD operations on log entries
 type CrudHandler struct {
 	cfg      config.Config
-	blocksDB *pebble.DB
-	tracesDB *pebble.DB
+	blocksDB dbutil.KV
+	tracesDB dbutil.KV
 	analyzer *Analyzer
 	location *time.Location
 }
 
-func NewCrudHandler(cfg config.Config, blocksDB *pebble.DB, tracesDB *pebble.DB, analyzer *Analyzer) (*CrudHandler, error) {
+func NewCrudHandler(cfg config.Config, blocksDB dbutil.KV, tracesDB dbutil.KV, analyzer *Analyzer) (*CrudHandler, error) {
 	// Load the PST time zone
 	location, err := time.LoadLocation("America/Los_Angeles")
 	if err != nil {
@@ -50,7 +49,7 @@ func (h *CrudHandler) GetTrace(ctx context.Context, request *connect.Request[log
 	trace := &logspb.Trace{}
 	err := dbutil.GetProto(h.tracesDB, getReq.GetId(), trace)
 	if err != nil {
-		if errors.Is(err, pebble.ErrNotFound) {
+		if errors.Is(err, dbutil.ErrNotFound) {
 			return nil, connect.NewError(connect.CodeNotFound, errors.Wrapf(err, "Failed to get trace with id %s", getReq.GetId()))
 		} else {
 			log := logs.FromContext(ctx)
@@ -104,7 +103,7 @@ func (h *CrudHandler) GetBlockLog(ctx context.Context, request *connect.Request[
 
 	bLog := &logspb.BlockLog{}
 	if err := dbutil.GetProto(h.blocksDB, getReq.GetId(), bLog); err != nil {
-		if errors.Is(err, pebble.ErrNotFound) {
+		if errors.Is(err, dbutil.ErrNotFound) {
 			return nil, connect.NewError(connect.CodeNotFound, errors.Wrapf(err, "No BlockLog with id %s was found", getReq.GetId()))
 		} else {
 			log.Error(err, "Failed to read block with id", "id", getReq.GetId())
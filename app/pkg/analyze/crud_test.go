@@ -9,13 +9,12 @@ import (
 	"github.com/jlewi/foyle/app/pkg/dbutil"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 
-	"github.com/cockroachdb/pebble"
 	"github.com/jlewi/foyle/app/pkg/config"
 	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 	"github.com/pkg/errors"
 )
 
-func populateDB(db *pebble.DB) error {
+func populateDB(db dbutil.KV) error {
 	block := &logspb.BlockLog{
 		Id:         "test-id",
 		GenTraceId: "sometrace",
@@ -24,7 +23,7 @@ func populateDB(db *pebble.DB) error {
 	return dbutil.SetProto(db, block.GetId(), block)
 }
 
-func populateTraceDB(db *pebble.DB) error {
+func populateTraceDB(db dbutil.KV) error {
 	trace := &logspb.Trace{
 		Id: "test-trace",
 		Data: &logspb.Trace_Generate{
@@ -56,7 +55,7 @@ func createCrudHandler() (*CrudHandler, error) {
 		},
 	}
 
-	db, err := pebble.Open(cfg.GetBlocksDBDir(), &pebble.Options{})
+	db, err := dbutil.OpenPebbleKV(cfg.GetBlocksDBDir(), false)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to open DB")
 	}
@@ -65,7 +64,7 @@ func createCrudHandler() (*CrudHandler, error) {
 		return nil, errors.Wrapf(err, "Failed to populate DB")
 	}
 
-	tracesDB, err := pebble.Open(cfg.GetTracesDBDir(), &pebble.Options{})
+	tracesDB, err := dbutil.OpenPebbleKV(cfg.GetTracesDBDir(), false)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to open DB")
 	}
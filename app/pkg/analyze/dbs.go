@@ -3,7 +3,6 @@ package analyze
 import (
 	"context"
 
-	"github.com/cockroachdb/pebble"
 	"github.com/jlewi/foyle/app/pkg/dbutil"
 	"github.com/jlewi/foyle/app/pkg/logs"
 	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
@@ -13,7 +12,7 @@ import (
 )
 
 // NewLockingBlocksDB helper function to create a new LockingDB for BlockLog.
-func NewLockingBlocksDB(db *pebble.DB) *dbutil.LockingDB[*logspb.BlockLog] {
+func NewLockingBlocksDB(db dbutil.KV) *dbutil.LockingDB[*logspb.BlockLog] {
 	return dbutil.NewLockingDB[*logspb.BlockLog](db, newBlock, getBlockVersion, setBlockVersion)
 }
 
@@ -30,7 +29,7 @@ func setBlockVersion(block *logspb.BlockLog, version string) {
 }
 
 // NewLockingEntriesDB helper function to create a new LockingDB for LogEntries.
-func NewLockingEntriesDB(db *pebble.DB) *dbutil.LockingDB[*logspb.LogEntries] {
+func NewLockingEntriesDB(db dbutil.KV) *dbutil.LockingDB[*logspb.LogEntries] {
 	return dbutil.NewLockingDB[*logspb.LogEntries](db, newLogEntries, getLogEntriesVersion, setLogEntriesVersion)
 }
 
@@ -0,0 +1,171 @@
+package analyze
+
+import (
+	"context"
+	"time"
+
+	"github.com/jlewi/foyle/app/pkg/events"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// pendingTraceTimeout is how long a trace can be pending before it is flushed. A trace whose request panicked or
+	// was cancelled never gets a response so without it the trace would be kept in memory forever.
+	pendingTraceTimeout = 10 * time.Minute
+	// flushInterval is how often the pending traces are checked for traces that timed out.
+	flushInterval = time.Minute
+)
+
+// pendingTrace is a trace built from events whose response hasn't been published yet.
+type pendingTrace struct {
+	trace *logspb.Trace
+	// added is the time of the event that started the trace.
+	added time.Time
+}
+
+// Subscribe subscribes the analyzer to the events published on the bus. Traces, blocks and sessions are then
+// updated as soon as events are published and the logs are only processed to backfill the entries written before
+// Subscribe was called. Subscribe must be called before Run and the bus should be started after Run.
+func (a *Analyzer) Subscribe(bus *events.Bus) {
+	a.liveSince = time.Now()
+	bus.Subscribe(a.handleEvent)
+}
+
+// handleEvent processes an event published on the bus. The bus delivers events one at a time so pendingTraces
+// doesn't need to be locked.
+func (a *Analyzer) handleEvent(ctx context.Context, e *events.Event) {
+	log := logs.FromContext(ctx)
+	a.flushStaleTraces(ctx, e.Time)
+	switch {
+	case e.LogEvent != nil:
+		a.updateBlocksFromLogEvent(ctx, e.LogEvent)
+		a.sessBuilder.addLogEvent(e.LogEvent, e.Time, a.learnNotifier)
+	case e.FullContext != nil:
+		if e.ContextID != "" {
+			a.sessBuilder.setFullContext(e.ContextID, e.FullContext)
+		}
+	case e.GenerateResponse != nil || e.GenerateError != "":
+		trace := a.endTrace(e)
+		if trace == nil {
+			return
+		}
+		if err := a.saveTrace(ctx, trace.Id, trace); err != nil {
+			log.Error(err, "Error saving trace", traceField, trace.Id)
+		}
+		// N.B. Only traces built from events are exported. Traces built from the logs are rebuilt every time a new
+		// log entry for the trace is processed so they would be exported more than once.
+		if a.exporter != nil {
+			if err := a.exporter.Export(ctx, trace); err != nil {
+				log.Error(err, "Error exporting trace", traceField, trace.Id)
+			}
+		}
+	default:
+		if e.LLMCall != nil && e.ContextID != "" {
+			a.sessBuilder.addUsage(e.ContextID, e.LLMCall.Usage)
+		}
+		if e.GenerateRequest != nil && e.ContextID != "" && e.TraceID != "" {
+			a.sessBuilder.addGenerateTrace(e.ContextID, e.TraceID)
+		}
+		if e.Span.GetRag() != nil && e.ContextID != "" {
+			a.sessBuilder.setRAGEnabled(e.ContextID)
+		}
+		a.addToTrace(e)
+	}
+}
+
+// addToTrace adds the request, span, LLM call or assertion in the event to the pending trace.
+func (a *Analyzer) addToTrace(e *events.Event) {
+	trace := a.getPendingTrace(e)
+	if trace == nil {
+		return
+	}
+	switch {
+	case e.GenerateRequest != nil:
+		trace.GetGenerate().Request = e.GenerateRequest
+		trace.StartTime = timestamppb.New(e.Time)
+	case e.Span != nil:
+		trace.Spans = append(trace.Spans, e.Span)
+	case e.Assertion != nil:
+		trace.Assertions = append(trace.Assertions, e.Assertion)
+	case e.LLMCall != nil:
+		usage := e.LLMCall.Usage
+		trace.InputTokens += int32(usage.InputTokens)
+		trace.OutputTokens += int32(usage.OutputTokens)
+		trace.CachedInputTokens += int32(usage.CachedInputTokens)
+		cost, _ := a.pricing.Cost(usage)
+		trace.CostUsd += cost
+		trace.Spans = append(trace.Spans, llmCallToSpan(e.LLMCall))
+	}
+}
+
+// endTrace removes the trace from the pending traces and returns it. The spans are combined the same way as when
+// the trace is built from the logs.
+func (a *Analyzer) endTrace(e *events.Event) *logspb.Trace {
+	trace := a.getPendingTrace(e)
+	if trace == nil {
+		return nil
+	}
+	delete(a.pendingTraces, e.TraceID)
+
+	if e.GenerateResponse != nil {
+		trace.GetGenerate().Response = e.GenerateResponse
+	}
+	trace.EndTime = timestamppb.New(e.Time)
+	combineSpans(trace)
+	dedupeAssertions(trace)
+	return trace
+}
+
+// getPendingTrace returns the trace the event belongs to creating it if necessary. It returns nil if the event isn't
+// part of a trace.
+func (a *Analyzer) getPendingTrace(e *events.Event) *logspb.Trace {
+	if e.TraceID == "" {
+		return nil
+	}
+	pending, ok := a.pendingTraces[e.TraceID]
+	if !ok {
+		pending = &pendingTrace{
+			trace: &logspb.Trace{
+				Id: e.TraceID,
+				Data: &logspb.Trace_Generate{
+					Generate: &logspb.GenerateTrace{},
+				},
+				Spans:      make([]*logspb.Span, 0, 10),
+				Assertions: make([]*v1alpha1.Assertion, 0),
+			},
+			added: e.Time,
+		}
+		a.pendingTraces[e.TraceID] = pending
+	}
+	if e.EvalMode {
+		pending.trace.EvalMode = true
+	}
+	return pending.trace
+}
+
+// flushStaleTraces saves the traces that have been pending for longer than pendingTraceTimeout and removes them
+// from the pending traces. It runs on the bus's goroutine when events are handled, at most once per flushInterval.
+// The traces are saved without a response so the requests that never finished can still be inspected.
+func (a *Analyzer) flushStaleTraces(ctx context.Context, now time.Time) {
+	if now.Sub(a.lastFlush) < flushInterval {
+		return
+	}
+	a.lastFlush = now
+
+	log := logs.FromContext(ctx)
+	for tid, pending := range a.pendingTraces {
+		if now.Sub(pending.added) < pendingTraceTimeout {
+			continue
+		}
+		delete(a.pendingTraces, tid)
+		log.Info("Flushing trace that didn't end", traceField, tid, "added", pending.added)
+		combineSpans(pending.trace)
+		dedupeAssertions(pending.trace)
+		if err := a.saveTrace(ctx, tid, pending.trace); err != nil {
+			log.Error(err, "Error saving trace", traceField, tid)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package analyze
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	"github.com/jlewi/foyle/app/pkg/events"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/jlewi/monogo/helpers"
+)
+
+func Test_AnalyzerEvents(t *testing.T) {
+	oDir := t.TempDir()
+
+	openDB := func(name string) dbutil.KV {
+		db, err := dbutil.OpenPebbleKV(filepath.Join(oDir, name), false)
+		if err != nil {
+			t.Fatalf("could not open %s database: %v", name, err)
+		}
+		return db
+	}
+	rawDB := openDB("rawlogs")
+	defer helpers.DeferIgnoreError(rawDB.Close)
+	blocksDB := openDB("blocks")
+	defer helpers.DeferIgnoreError(blocksDB.Close)
+	tracesDB := openDB("traces")
+	defer helpers.DeferIgnoreError(tracesDB.Close)
+
+	db, err := sql.Open(SQLLiteDriver, filepath.Join(oDir, "sessions.sqllite3"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %+v", err)
+	}
+	sessionsManager, err := NewSessionsManager(NewStore(db, dbutil.SQLite))
+	if err != nil {
+		t.Fatalf("Failed to create sessions manager: %v", err)
+	}
+
+	a, err := NewAnalyzer(filepath.Join(oDir, "log_offsets.json"), 3*time.Second, NewLockingEntriesDB(rawDB), tracesDB, NewLockingBlocksDB(blocksDB), sessionsManager, llms.NewPriceTable(nil), nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+	blockProcessed := make(chan string, 10)
+	a.signalBlockDone = blockProcessed
+
+	bus := events.NewBus(10)
+	a.Subscribe(bus)
+	fakeNotifier := &fakeNotifier{}
+	if err := a.Run(context.Background(), []string{oDir}, fakeNotifier.PostSession); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	bus.Run(context.Background())
+
+	const (
+		traceID   = "trace1"
+		contextID = "context1"
+		blockID   = "block1"
+	)
+	ctx := events.WithContextID(events.WithBus(context.Background(), bus), contextID)
+	events.Publish(ctx, &events.Event{LogEvent: &v1alpha1.LogEvent{Type: v1alpha1.LogEventType_SESSION_START, ContextId: contextID, EventId: "event1"}})
+
+	traceCtx := events.WithTrace(ctx, traceID, false)
+	events.Publish(traceCtx, &events.Event{GenerateRequest: &v1alpha1.GenerateRequest{
+		Doc: &v1alpha1.Doc{Blocks: []*v1alpha1.Block{{Kind: v1alpha1.BlockKind_MARKUP, Contents: "list pods"}}},
+	}})
+	events.Publish(traceCtx, &events.Event{LLMCall: &api.LLMCall{
+		Provider: api.ModelProviderOpenAI,
+		Model:    "gpt-4o",
+		Usage:    api.LLMUsage{InputTokens: 100, OutputTokens: 20},
+	}})
+	events.Publish(traceCtx, &events.Event{Assertion: &v1alpha1.Assertion{Id: "a1", Name: v1alpha1.Assertion_NON_EMPTY_DOC, Result: v1alpha1.AssertResult_PASSED}})
+	events.Publish(traceCtx, &events.Event{GenerateResponse: &v1alpha1.GenerateResponse{
+		TraceId: traceID,
+		Blocks:  []*v1alpha1.Block{{Id: blockID, Kind: v1alpha1.BlockKind_CODE, Contents: "kubectl get pods"}},
+	}})
+	events.Publish(ctx, &events.Event{LogEvent: &v1alpha1.LogEvent{Type: v1alpha1.LogEventType_SESSION_END, ContextId: contextID, EventId: "event2"}})
+
+	waitForBlock(t, blockID, 1, blockProcessed)
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Failed to shutdown bus: %v", err)
+	}
+	if err := a.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Failed to shutdown analyzer: %v", err)
+	}
+
+	trace := &logspb.Trace{}
+	if err := dbutil.GetProto(tracesDB, traceID, trace); err != nil {
+		t.Fatalf("Failed to get trace %s: %+v", traceID, err)
+	}
+	if trace.GetGenerate().GetRequest() == nil || trace.GetGenerate().GetResponse() == nil {
+		t.Errorf("Expected the trace to have the request and the response")
+	}
+	if trace.InputTokens != 100 || trace.OutputTokens != 20 {
+		t.Errorf("Expected 100 input and 20 output tokens; got %d and %d", trace.InputTokens, trace.OutputTokens)
+	}
+	if len(trace.Spans) != 1 || trace.Spans[0].GetLlm().GetModel() != "gpt-4o" {
+		t.Errorf("Expected the trace to have a single LLM span; got %v", trace.Spans)
+	}
+	if len(trace.Assertions) != 1 {
+		t.Errorf("Expected the trace to have 1 assertion; got %d", len(trace.Assertions))
+	}
+
+	block := &logspb.BlockLog{}
+	if err := dbutil.GetProto(blocksDB, blockID, block); err != nil {
+		t.Fatalf("Failed to get block %s: %+v", blockID, err)
+	}
+	if block.GenTraceId != traceID {
+		t.Errorf("Expected GenTraceId %s; got %s", traceID, block.GenTraceId)
+	}
+	if block.GeneratedBlock == nil || block.Doc == nil {
+		t.Errorf("Expected GeneratedBlock and Doc to be set")
+	}
+
+	session, err := sessionsManager.Get(context.Background(), contextID)
+	if err != nil {
+		t.Fatalf("Failed to get session %s: %+v", contextID, err)
+	}
+	if len(session.GenerateTraceIds) != 1 || session.GenerateTraceIds[0] != traceID {
+		t.Errorf("Expected the session to have trace %s; got %v", traceID, session.GenerateTraceIds)
+	}
+	if session.TotalInputTokens != 100 {
+		t.Errorf("Expected the session to have 100 input tokens; got %d", session.TotalInputTokens)
+	}
+	if fakeNotifier.counts[contextID] != 1 {
+		t.Errorf("Expected the session to be posted once; got %d", fakeNotifier.counts[contextID])
+	}
+}
+
+func Test_FlushStaleTraces(t *testing.T) {
+	tracesDB := dbutil.NewMemKV()
+	a := &Analyzer{
+		tracesDB:      tracesDB,
+		pendingTraces: make(map[string]*pendingTrace),
+	}
+
+	start := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	// stale never gets a response; e.g. because the request panicked.
+	a.handleEvent(ctx, &events.Event{Time: start, TraceID: "stale", GenerateRequest: &v1alpha1.GenerateRequest{}})
+	a.handleEvent(ctx, &events.Event{Time: start.Add(pendingTraceTimeout - time.Minute), TraceID: "active", GenerateRequest: &v1alpha1.GenerateRequest{}})
+
+	if _, ok := a.pendingTraces["stale"]; !ok {
+		t.Fatalf("Expected the trace to be pending until it times out")
+	}
+
+	a.handleEvent(ctx, &events.Event{Time: start.Add(pendingTraceTimeout + time.Second), TraceID: "active", Assertion: &v1alpha1.Assertion{Id: "a1"}})
+
+	if _, ok := a.pendingTraces["stale"]; ok {
+		t.Errorf("Expected the stale trace to be removed from the pending traces")
+	}
+	if _, ok := a.pendingTraces["active"]; !ok {
+		t.Errorf("Expected the active trace to still be pending")
+	}
+
+	trace := &logspb.Trace{}
+	if err := dbutil.GetProto(tracesDB, "stale", trace); err != nil {
+		t.Fatalf("Expected the stale trace to be saved: %+v", err)
+	}
+	if trace.GetGenerate().GetRequest() == nil || trace.GetGenerate().GetResponse() != nil {
+		t.Errorf("Expected the stale trace to have the request and no response; got %v", trace)
+	}
+}
@@ -23,7 +23,7 @@ ORDER BY time DESC
 SELECT COUNT(*) FROM results;
 
 -- name: CountErrors :one
-SELECT COUNT(*) FROM results WHERE json_extract(proto_json, '$.error') IS NOT NULL;
+SELECT COUNT(*) FROM results WHERE error IS NOT NULL;
 
 -- name: CountByCellsMatchResult :many
-SELECT json_extract(proto_json, '$.cellsMatchResult') as match_result, COUNT(*) as count FROM results GROUP BY match_result;
\ No newline at end of file
+SELECT cells_match_result as match_result, COUNT(*) as count FROM results GROUP BY match_result;
\ No newline at end of file
@@ -7,15 +7,16 @@ package fsql
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
 const countByCellsMatchResult = `-- name: CountByCellsMatchResult :many
-SELECT json_extract(proto_json, '$.cellsMatchResult') as match_result, COUNT(*) as count FROM results GROUP BY match_result
+SELECT cells_match_result as match_result, COUNT(*) as count FROM results GROUP BY match_result
 `
 
 type CountByCellsMatchResultRow struct {
-	MatchResult interface{}
+	MatchResult sql.NullString
 	Count       int64
 }
 
@@ -43,7 +44,7 @@ func (q *Queries) CountByCellsMatchResult(ctx context.Context) ([]CountByCellsMa
 }
 
 const countErrors = `-- name: CountErrors :one
-SELECT COUNT(*) FROM results WHERE json_extract(proto_json, '$.error') IS NOT NULL
+SELECT COUNT(*) FROM results WHERE error IS NOT NULL
 `
 
 func (q *Queries) CountErrors(ctx context.Context) (int64, error) {
@@ -66,19 +67,25 @@ func (q *Queries) CountResults(ctx context.Context) (int64, error) {
 }
 
 const getResult = `-- name: GetResult :one
-SELECT id, time, proto_json FROM results
+SELECT id, time, proto_json, error, cells_match_result FROM results
 WHERE id = ?
 `
 
 func (q *Queries) GetResult(ctx context.Context, id string) (Result, error) {
 	row := q.db.QueryRowContext(ctx, getResult, id)
 	var i Result
-	err := row.Scan(&i.ID, &i.Time, &i.ProtoJson)
+	err := row.Scan(
+		&i.ID,
+		&i.Time,
+		&i.ProtoJson,
+		&i.Error,
+		&i.CellsMatchResult,
+	)
 	return i, err
 }
 
 const listResults = `-- name: ListResults :many
-SELECT id, time, proto_json FROM results
+SELECT id, time, proto_json, error, cells_match_result FROM results
 WHERE (?1 = '' OR time < ?1)
 ORDER BY time DESC
     LIMIT ?2
@@ -101,7 +108,13 @@ func (q *Queries) ListResults(ctx context.Context, arg ListResultsParams) ([]Res
 	var items []Result
 	for rows.Next() {
 		var i Result
-		if err := rows.Scan(&i.ID, &i.Time, &i.ProtoJson); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Time,
+			&i.ProtoJson,
+			&i.Error,
+			&i.CellsMatchResult,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
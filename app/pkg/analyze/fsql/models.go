@@ -5,13 +5,16 @@
 package fsql
 
 import (
+	"database/sql"
 	"time"
 )
 
 type Result struct {
-	ID        string
-	Time      time.Time
-	ProtoJson string
+	ID               string
+	Time             time.Time
+	ProtoJson        string
+	Error            sql.NullString
+	CellsMatchResult sql.NullString
 }
 
 type Session struct {
@@ -24,4 +27,14 @@ type Session struct {
 	TotalOutputTokens int64
 	NumGenerateTraces int64
 	Proto             []byte
+	TotalCostUsd      float64
+	NotebookUri       string
+	SuggestionStatus  string
+	Executed          int64
+	CellText          string
+	Model             string
+	RagEnabled        int64
+	SecondsToAccept   float64
+	ExecuteFailed     int64
+	ProtoJson         string
 }
@@ -0,0 +1,34 @@
+-- These are the Postgres versions of the queries in ../eval_query.sql.
+
+-- name: UpdateResult :exec
+INSERT INTO results
+(id, time, proto_json)
+VALUES
+($1, $2, $3)
+ON CONFLICT (id) DO UPDATE SET
+    time = EXCLUDED.time,
+    proto_json = EXCLUDED.proto_json;
+
+-- name: GetResult :one
+SELECT * FROM results
+WHERE id = $1;
+
+
+-- name: ListResults :many
+-- This queries for results.
+-- Results are listed in descending order of time (most recent first) because the primary use is for resuming
+-- in the evaluator
+SELECT * FROM results
+WHERE (sqlc.narg(cursor)::timestamptz IS NULL OR time < sqlc.narg(cursor)::timestamptz)
+ORDER BY time DESC
+    LIMIT sqlc.arg(page_size)::bigint;
+
+-- name: CountResults :one
+-- Count the total number of results
+SELECT COUNT(*) FROM results;
+
+-- name: CountErrors :one
+SELECT COUNT(*) FROM results WHERE error IS NOT NULL;
+
+-- name: CountByCellsMatchResult :many
+SELECT cells_match_result as match_result, COUNT(*) as count FROM results GROUP BY match_result;
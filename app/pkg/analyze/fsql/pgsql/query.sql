@@ -0,0 +1,104 @@
+-- These are the Postgres versions of the queries in ../query.sql. The queries must have the same names and
+-- return the same columns so the Postgres queries can implement fsql.Querier.
+-- N.B. Unlike SQLite, Postgres needs to know the types of the parameters so they are cast. Optional time filters
+-- are NULL rather than the empty string when they aren't set.
+
+-- name: ListSessions :many
+SELECT * FROM sessions
+ORDER BY startTime desc limit 25;
+
+-- name: ListSessionsForExamples :many
+-- This queries for sessions that we will use for Examples.
+--- This should be sessions that have a selectedKind of Code
+SELECT * FROM sessions
+WHERE (sqlc.arg(cursor)::text = '' OR contextId < sqlc.arg(cursor)::text) and selectedKind = 'CELL_KIND_CODE'
+ORDER BY contextId DESC
+    LIMIT sqlc.arg(page_size)::bigint;
+
+-- name: GetSession :one
+SELECT * FROM sessions
+WHERE contextID = $1;
+
+-- name: SearchSessions :many
+-- SearchSessions lists the sessions matching the filters. Filters set to their zero value are ignored.
+-- N.B. ILIKE is used because LIKE is case insensitive in SQLite.
+SELECT * FROM sessions
+WHERE (sqlc.arg(cursor)::text = '' OR contextID < sqlc.arg(cursor)::text)
+  AND (sqlc.narg(start_time)::timestamptz IS NULL OR startTime >= sqlc.narg(start_time)::timestamptz)
+  AND (sqlc.narg(end_time)::timestamptz IS NULL OR startTime < sqlc.narg(end_time)::timestamptz)
+  AND (sqlc.arg(notebook_uri)::text = '' OR notebook_uri = sqlc.arg(notebook_uri)::text)
+  AND (sqlc.arg(suggestion_status)::text = '' OR suggestion_status = sqlc.arg(suggestion_status)::text)
+  AND (sqlc.arg(executed)::bigint = -1 OR executed = sqlc.arg(executed)::bigint)
+  AND (sqlc.arg(min_input_tokens)::bigint = 0 OR total_input_tokens >= sqlc.arg(min_input_tokens)::bigint)
+  AND (sqlc.arg(max_input_tokens)::bigint = 0 OR total_input_tokens <= sqlc.arg(max_input_tokens)::bigint)
+  AND (sqlc.arg(min_output_tokens)::bigint = 0 OR total_output_tokens >= sqlc.arg(min_output_tokens)::bigint)
+  AND (sqlc.arg(max_output_tokens)::bigint = 0 OR total_output_tokens <= sqlc.arg(max_output_tokens)::bigint)
+  AND (sqlc.arg(query)::text = '' OR cell_text ILIKE '%' || sqlc.arg(query)::text || '%' ESCAPE '\')
+ORDER BY contextID DESC
+    LIMIT sqlc.arg(page_size)::bigint;
+
+-- name: SessionStats :many
+-- SessionStats aggregates the sessions matching the filters grouped by the dimension. The dimension is the name
+-- of a StatsDimension; sessions are grouped by the UTC day they started if it isn't one of the other values.
+SELECT CAST(CASE sqlc.arg(dimension)::text
+    WHEN 'NOTEBOOK' THEN notebook_uri
+    WHEN 'MODEL' THEN model
+    WHEN 'RAG' THEN CASE rag_enabled WHEN 1 THEN 'enabled' ELSE 'disabled' END
+    ELSE to_char(startTime AT TIME ZONE 'UTC', 'YYYY-MM-DD')
+  END AS TEXT) AS value,
+  COUNT(*) AS num_sessions,
+  COUNT(*) FILTER (WHERE num_generate_traces > 0) AS num_suggested,
+  COUNT(*) FILTER (WHERE suggestion_status = 'ACCEPTED') AS num_accepted,
+  COUNT(*) FILTER (WHERE suggestion_status = 'REJECTED') AS num_rejected,
+  COUNT(*) FILTER (WHERE num_generate_traces > 0 AND executed = 1) AS num_suggested_executed,
+  CAST(SUM(executed) AS BIGINT) AS num_executed,
+  CAST(SUM(execute_failed) AS BIGINT) AS num_execute_failed,
+  CAST(SUM(total_input_tokens) AS BIGINT) AS total_input_tokens,
+  CAST(SUM(total_output_tokens) AS BIGINT) AS total_output_tokens,
+  CAST(SUM(CASE WHEN suggestion_status = 'ACCEPTED' THEN seconds_to_accept ELSE 0 END) AS DOUBLE PRECISION) AS total_seconds_to_accept,
+  COUNT(*) FILTER (WHERE suggestion_status = 'ACCEPTED' AND seconds_to_accept > 0) AS num_timed_accepts
+FROM sessions
+WHERE (sqlc.narg(start_time)::timestamptz IS NULL OR startTime >= sqlc.narg(start_time)::timestamptz)
+  AND (sqlc.narg(end_time)::timestamptz IS NULL OR startTime < sqlc.narg(end_time)::timestamptz)
+  AND (sqlc.arg(notebook_uri)::text = '' OR notebook_uri = sqlc.arg(notebook_uri)::text)
+GROUP BY value
+ORDER BY value;
+
+-- name: ListSessionProtos :many
+-- ListSessionProtos returns the proto of every session. It is used to backfill columns computed from the proto.
+SELECT contextID, proto FROM sessions;
+
+-- name: UpdateSession :exec
+INSERT INTO sessions
+(contextID, startTime, endTime, selectedId, selectedKind, total_input_tokens, total_output_tokens, num_generate_traces, proto, total_cost_usd, notebook_uri, suggestion_status, executed, cell_text, model, rag_enabled, seconds_to_accept, execute_failed, proto_json)
+VALUES
+($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+ON CONFLICT (contextID) DO UPDATE SET
+    startTime = EXCLUDED.startTime,
+    endTime = EXCLUDED.endTime,
+    selectedId = EXCLUDED.selectedId,
+    selectedKind = EXCLUDED.selectedKind,
+    total_input_tokens = EXCLUDED.total_input_tokens,
+    total_output_tokens = EXCLUDED.total_output_tokens,
+    num_generate_traces = EXCLUDED.num_generate_traces,
+    proto = EXCLUDED.proto,
+    total_cost_usd = EXCLUDED.total_cost_usd,
+    notebook_uri = EXCLUDED.notebook_uri,
+    suggestion_status = EXCLUDED.suggestion_status,
+    executed = EXCLUDED.executed,
+    cell_text = EXCLUDED.cell_text,
+    model = EXCLUDED.model,
+    rag_enabled = EXCLUDED.rag_enabled,
+    seconds_to_accept = EXCLUDED.seconds_to_accept,
+    execute_failed = EXCLUDED.execute_failed,
+    proto_json = EXCLUDED.proto_json;
+
+-- name: CountSessionsBefore :one
+-- Count the sessions that started before the given time.
+SELECT COUNT(*) FROM sessions
+WHERE startTime < $1;
+
+-- name: DeleteSessionsBefore :execrows
+-- Delete the sessions that started before the given time.
+DELETE FROM sessions
+WHERE startTime < $1;
@@ -0,0 +1,64 @@
+-- This is the Postgres schema sqlc generates code from. The tables are created and changed by the Postgres
+-- migrations in analyze/migrations.go; when you add a migration update this file to match the schema it produces.
+-- The tables are the same as the SQLite tables in ../schema.sql. The column types are chosen so that sqlc generates
+-- the same Go types for both databases; e.g. BIGINT rather than INT and DOUBLE PRECISION rather than REAL.
+CREATE TABLE IF NOT EXISTS sessions (
+    contextID VARCHAR(255) PRIMARY KEY,
+    -- protobufs can't have null timestamps so no point allowing nulls
+    startTime TIMESTAMPTZ NOT NULL,
+    endTime TIMESTAMPTZ NOT NULL,
+    -- The selectedId is the id of the cell that is selected in the session.
+    selectedID VARCHAR(255) NOT NULL,
+    -- SelectedKind is the kind of the selected cell; i.e. Markdown or Code.
+    selectedKind VARCHAR(255) NOT NULL,
+
+    -- Total number of input and output tokens
+    total_input_tokens BIGINT NOT NULL,
+    total_output_tokens BIGINT NOT NULL,
+
+    -- Number of generate traces is the number of generations for this particular session
+    num_generate_traces BIGINT NOT NULL,
+    proto BYTEA,
+
+    -- Total cost in USD of the LLM calls in the session.
+    total_cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+
+    -- The columns below are used to search sessions. They are computed from the proto.
+    notebook_uri VARCHAR(1024) NOT NULL DEFAULT '',
+    suggestion_status VARCHAR(255) NOT NULL DEFAULT '',
+    executed BIGINT NOT NULL DEFAULT 0,
+    cell_text TEXT NOT NULL DEFAULT '',
+
+    -- The columns below are used to compute statistics. They are also computed from the proto.
+    model VARCHAR(255) NOT NULL DEFAULT '',
+    rag_enabled BIGINT NOT NULL DEFAULT 0,
+    seconds_to_accept DOUBLE PRECISION NOT NULL DEFAULT 0,
+    execute_failed BIGINT NOT NULL DEFAULT 0,
+
+    -- The JSON serialization of the proto so that SQL queries can use values in it with the JSON operators.
+    proto_json JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_start_time ON sessions (startTime);
+CREATE INDEX IF NOT EXISTS idx_sessions_notebook_uri ON sessions (notebook_uri);
+CREATE INDEX IF NOT EXISTS idx_sessions_suggestion_status ON sessions (suggestion_status);
+CREATE INDEX IF NOT EXISTS idx_sessions_executed ON sessions (executed);
+CREATE INDEX IF NOT EXISTS idx_sessions_model ON sessions (model);
+
+-- Results contains evaluation results
+CREATE TABLE IF NOT EXISTS results (
+    id VARCHAR(255) PRIMARY KEY,
+    -- time is the time of the evaluation example
+    time TIMESTAMPTZ NOT NULL,
+
+    -- The JSON serialization of the proto.
+    proto_json JSONB NOT NULL,
+
+    -- Generated columns for the fields of the proto that are commonly queried.
+    -- The proto is serialized with default values so error is NULL rather than empty if there was no error.
+    error TEXT GENERATED ALWAYS AS (NULLIF(proto_json ->> 'error', '')) STORED,
+    cells_match_result TEXT GENERATED ALWAYS AS (proto_json ->> 'cellsMatchResult') STORED
+);
+
+CREATE INDEX IF NOT EXISTS idx_results_time ON results (time);
+CREATE INDEX IF NOT EXISTS idx_results_cells_match_result ON results (cells_match_result);
@@ -16,9 +16,70 @@ ORDER BY contextId DESC
 SELECT * FROM sessions
 WHERE contextID = ?;
 
+-- name: SearchSessions :many
+-- SearchSessions lists the sessions matching the filters. Filters set to their zero value are ignored.
+-- Sessions are ordered by contextID; since contextIds are ULIDs the most recent sessions are first and the last
+-- contextId of a page is the cursor for the next page.
+SELECT * FROM sessions
+WHERE (:cursor = '' OR contextID < :cursor)
+  AND (:start_time = '' OR startTime >= :start_time)
+  AND (:end_time = '' OR startTime < :end_time)
+  AND (:notebook_uri = '' OR notebook_uri = :notebook_uri)
+  AND (:suggestion_status = '' OR suggestion_status = :suggestion_status)
+  AND (:executed = -1 OR executed = :executed)
+  AND (:min_input_tokens = 0 OR total_input_tokens >= :min_input_tokens)
+  AND (:max_input_tokens = 0 OR total_input_tokens <= :max_input_tokens)
+  AND (:min_output_tokens = 0 OR total_output_tokens >= :min_output_tokens)
+  AND (:max_output_tokens = 0 OR total_output_tokens <= :max_output_tokens)
+  AND (:query = '' OR cell_text LIKE '%' || :query || '%' ESCAPE '\')
+ORDER BY contextID DESC
+    LIMIT :page_size;
+
+-- name: SessionStats :many
+-- SessionStats aggregates the sessions matching the filters grouped by the dimension. The dimension is the name
+-- of a StatsDimension; sessions are grouped by the UTC day they started if it isn't one of the other values.
+-- N.B. startTime is stored as a string starting with the date so the first 10 characters are the day.
+SELECT CAST(CASE :dimension
+    WHEN 'NOTEBOOK' THEN notebook_uri
+    WHEN 'MODEL' THEN model
+    WHEN 'RAG' THEN CASE rag_enabled WHEN 1 THEN 'enabled' ELSE 'disabled' END
+    ELSE substr(startTime, 1, 10)
+  END AS TEXT) AS value,
+  COUNT(*) AS num_sessions,
+  CAST(SUM(num_generate_traces > 0) AS INTEGER) AS num_suggested,
+  CAST(SUM(suggestion_status = 'ACCEPTED') AS INTEGER) AS num_accepted,
+  CAST(SUM(suggestion_status = 'REJECTED') AS INTEGER) AS num_rejected,
+  CAST(SUM(num_generate_traces > 0 AND executed = 1) AS INTEGER) AS num_suggested_executed,
+  CAST(SUM(executed) AS INTEGER) AS num_executed,
+  CAST(SUM(execute_failed) AS INTEGER) AS num_execute_failed,
+  CAST(SUM(total_input_tokens) AS INTEGER) AS total_input_tokens,
+  CAST(SUM(total_output_tokens) AS INTEGER) AS total_output_tokens,
+  CAST(SUM(CASE WHEN suggestion_status = 'ACCEPTED' THEN seconds_to_accept ELSE 0 END) AS REAL) AS total_seconds_to_accept,
+  CAST(SUM(suggestion_status = 'ACCEPTED' AND seconds_to_accept > 0) AS INTEGER) AS num_timed_accepts
+FROM sessions
+WHERE (:start_time = '' OR startTime >= :start_time)
+  AND (:end_time = '' OR startTime < :end_time)
+  AND (:notebook_uri = '' OR notebook_uri = :notebook_uri)
+GROUP BY value
+ORDER BY value;
+
+-- name: ListSessionProtos :many
+-- ListSessionProtos returns the proto of every session. It is used to backfill columns computed from the proto.
+SELECT contextID, proto FROM sessions;
+
 -- name: UpdateSession :exec
 INSERT OR REPLACE INTO sessions 
-(contextID, startTime, endTime, selectedId, selectedKind, total_input_tokens, total_output_tokens, num_generate_traces, proto)
+(contextID, startTime, endTime, selectedId, selectedKind, total_input_tokens, total_output_tokens, num_generate_traces, proto, total_cost_usd, notebook_uri, suggestion_status, executed, cell_text, model, rag_enabled, seconds_to_accept, execute_failed, proto_json)
 VALUES 
-(?, ?, ?, ?, ?, ?, ?, ?, ?);
+(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+
+-- name: CountSessionsBefore :one
+-- Count the sessions that started before the given time.
+SELECT COUNT(*) FROM sessions
+WHERE startTime < ?;
 
+-- name: DeleteSessionsBefore :execrows
+-- Delete the sessions that started before the given time.
+DELETE FROM sessions
+WHERE startTime < ?;
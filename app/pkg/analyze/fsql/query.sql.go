@@ -10,8 +10,35 @@ import (
 	"time"
 )
 
+const countSessionsBefore = `-- name: CountSessionsBefore :one
+SELECT COUNT(*) FROM sessions
+WHERE startTime < ?
+`
+
+// Count the sessions that started before the given time.
+func (q *Queries) CountSessionsBefore(ctx context.Context, starttime time.Time) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSessionsBefore, starttime)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteSessionsBefore = `-- name: DeleteSessionsBefore :execrows
+DELETE FROM sessions
+WHERE startTime < ?
+`
+
+// Delete the sessions that started before the given time.
+func (q *Queries) DeleteSessionsBefore(ctx context.Context, starttime time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteSessionsBefore, starttime)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getSession = `-- name: GetSession :one
-SELECT contextid, starttime, endtime, selectedid, selectedkind, total_input_tokens, total_output_tokens, num_generate_traces, proto FROM sessions
+SELECT contextid, starttime, endtime, selectedid, selectedkind, total_input_tokens, total_output_tokens, num_generate_traces, proto, total_cost_usd, notebook_uri, suggestion_status, executed, cell_text, model, rag_enabled, seconds_to_accept, execute_failed, proto_json FROM sessions
 WHERE contextID = ?
 `
 
@@ -28,12 +55,55 @@ func (q *Queries) GetSession(ctx context.Context, contextid string) (Session, er
 		&i.TotalOutputTokens,
 		&i.NumGenerateTraces,
 		&i.Proto,
+		&i.TotalCostUsd,
+		&i.NotebookUri,
+		&i.SuggestionStatus,
+		&i.Executed,
+		&i.CellText,
+		&i.Model,
+		&i.RagEnabled,
+		&i.SecondsToAccept,
+		&i.ExecuteFailed,
+		&i.ProtoJson,
 	)
 	return i, err
 }
 
+const listSessionProtos = `-- name: ListSessionProtos :many
+SELECT contextID, proto FROM sessions
+`
+
+type ListSessionProtosRow struct {
+	Contextid string
+	Proto     []byte
+}
+
+// ListSessionProtos returns the proto of every session. It is used to backfill columns computed from the proto.
+func (q *Queries) ListSessionProtos(ctx context.Context) ([]ListSessionProtosRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSessionProtos)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSessionProtosRow
+	for rows.Next() {
+		var i ListSessionProtosRow
+		if err := rows.Scan(&i.Contextid, &i.Proto); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSessions = `-- name: ListSessions :many
-SELECT contextid, starttime, endtime, selectedid, selectedkind, total_input_tokens, total_output_tokens, num_generate_traces, proto FROM sessions
+SELECT contextid, starttime, endtime, selectedid, selectedkind, total_input_tokens, total_output_tokens, num_generate_traces, proto, total_cost_usd, notebook_uri, suggestion_status, executed, cell_text, model, rag_enabled, seconds_to_accept, execute_failed, proto_json FROM sessions
 ORDER BY startTime desc limit 25
 `
 
@@ -58,6 +128,16 @@ func (q *Queries) ListSessions(ctx context.Context) ([]Session, error) {
 			&i.TotalOutputTokens,
 			&i.NumGenerateTraces,
 			&i.Proto,
+			&i.TotalCostUsd,
+			&i.NotebookUri,
+			&i.SuggestionStatus,
+			&i.Executed,
+			&i.CellText,
+			&i.Model,
+			&i.RagEnabled,
+			&i.SecondsToAccept,
+			&i.ExecuteFailed,
+			&i.ProtoJson,
 		); err != nil {
 			return nil, err
 		}
@@ -73,7 +153,7 @@ func (q *Queries) ListSessions(ctx context.Context) ([]Session, error) {
 }
 
 const listSessionsForExamples = `-- name: ListSessionsForExamples :many
-SELECT contextid, starttime, endtime, selectedid, selectedkind, total_input_tokens, total_output_tokens, num_generate_traces, proto FROM sessions
+SELECT contextid, starttime, endtime, selectedid, selectedkind, total_input_tokens, total_output_tokens, num_generate_traces, proto, total_cost_usd, notebook_uri, suggestion_status, executed, cell_text, model, rag_enabled, seconds_to_accept, execute_failed, proto_json FROM sessions
 WHERE (?1 = '' OR contextId < ?1) and selectedKind = 'CELL_KIND_CODE'
 ORDER BY contextId DESC
     LIMIT ?2
@@ -105,6 +185,199 @@ func (q *Queries) ListSessionsForExamples(ctx context.Context, arg ListSessionsF
 			&i.TotalOutputTokens,
 			&i.NumGenerateTraces,
 			&i.Proto,
+			&i.TotalCostUsd,
+			&i.NotebookUri,
+			&i.SuggestionStatus,
+			&i.Executed,
+			&i.CellText,
+			&i.Model,
+			&i.RagEnabled,
+			&i.SecondsToAccept,
+			&i.ExecuteFailed,
+			&i.ProtoJson,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchSessions = `-- name: SearchSessions :many
+SELECT contextid, starttime, endtime, selectedid, selectedkind, total_input_tokens, total_output_tokens, num_generate_traces, proto, total_cost_usd, notebook_uri, suggestion_status, executed, cell_text, model, rag_enabled, seconds_to_accept, execute_failed, proto_json FROM sessions
+WHERE (?1 = '' OR contextID < ?1)
+  AND (?2 = '' OR startTime >= ?2)
+  AND (?3 = '' OR startTime < ?3)
+  AND (?4 = '' OR notebook_uri = ?4)
+  AND (?5 = '' OR suggestion_status = ?5)
+  AND (?6 = -1 OR executed = ?6)
+  AND (?7 = 0 OR total_input_tokens >= ?7)
+  AND (?8 = 0 OR total_input_tokens <= ?8)
+  AND (?9 = 0 OR total_output_tokens >= ?9)
+  AND (?10 = 0 OR total_output_tokens <= ?10)
+  AND (?11 = '' OR cell_text LIKE '%' || ?11 || '%' ESCAPE '\')
+ORDER BY contextID DESC
+    LIMIT ?12
+`
+
+type SearchSessionsParams struct {
+	Cursor           interface{}
+	StartTime        interface{}
+	EndTime          interface{}
+	NotebookUri      interface{}
+	SuggestionStatus interface{}
+	Executed         interface{}
+	MinInputTokens   interface{}
+	MaxInputTokens   interface{}
+	MinOutputTokens  interface{}
+	MaxOutputTokens  interface{}
+	Query            interface{}
+	PageSize         int64
+}
+
+// SearchSessions lists the sessions matching the filters. Filters set to their zero value are ignored.
+// Sessions are ordered by contextID; since contextIds are ULIDs the most recent sessions are first and the last
+// contextId of a page is the cursor for the next page.
+func (q *Queries) SearchSessions(ctx context.Context, arg SearchSessionsParams) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, searchSessions,
+		arg.Cursor,
+		arg.StartTime,
+		arg.EndTime,
+		arg.NotebookUri,
+		arg.SuggestionStatus,
+		arg.Executed,
+		arg.MinInputTokens,
+		arg.MaxInputTokens,
+		arg.MinOutputTokens,
+		arg.MaxOutputTokens,
+		arg.Query,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Session
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.Contextid,
+			&i.Starttime,
+			&i.Endtime,
+			&i.Selectedid,
+			&i.Selectedkind,
+			&i.TotalInputTokens,
+			&i.TotalOutputTokens,
+			&i.NumGenerateTraces,
+			&i.Proto,
+			&i.TotalCostUsd,
+			&i.NotebookUri,
+			&i.SuggestionStatus,
+			&i.Executed,
+			&i.CellText,
+			&i.Model,
+			&i.RagEnabled,
+			&i.SecondsToAccept,
+			&i.ExecuteFailed,
+			&i.ProtoJson,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sessionStats = `-- name: SessionStats :many
+SELECT CAST(CASE ?1
+    WHEN 'NOTEBOOK' THEN notebook_uri
+    WHEN 'MODEL' THEN model
+    WHEN 'RAG' THEN CASE rag_enabled WHEN 1 THEN 'enabled' ELSE 'disabled' END
+    ELSE substr(startTime, 1, 10)
+  END AS TEXT) AS value,
+  COUNT(*) AS num_sessions,
+  CAST(SUM(num_generate_traces > 0) AS INTEGER) AS num_suggested,
+  CAST(SUM(suggestion_status = 'ACCEPTED') AS INTEGER) AS num_accepted,
+  CAST(SUM(suggestion_status = 'REJECTED') AS INTEGER) AS num_rejected,
+  CAST(SUM(num_generate_traces > 0 AND executed = 1) AS INTEGER) AS num_suggested_executed,
+  CAST(SUM(executed) AS INTEGER) AS num_executed,
+  CAST(SUM(execute_failed) AS INTEGER) AS num_execute_failed,
+  CAST(SUM(total_input_tokens) AS INTEGER) AS total_input_tokens,
+  CAST(SUM(total_output_tokens) AS INTEGER) AS total_output_tokens,
+  CAST(SUM(CASE WHEN suggestion_status = 'ACCEPTED' THEN seconds_to_accept ELSE 0 END) AS REAL) AS total_seconds_to_accept,
+  CAST(SUM(suggestion_status = 'ACCEPTED' AND seconds_to_accept > 0) AS INTEGER) AS num_timed_accepts
+FROM sessions
+WHERE (?2 = '' OR startTime >= ?2)
+  AND (?3 = '' OR startTime < ?3)
+  AND (?4 = '' OR notebook_uri = ?4)
+GROUP BY value
+ORDER BY value
+`
+
+type SessionStatsParams struct {
+	Dimension   interface{}
+	StartTime   interface{}
+	EndTime     interface{}
+	NotebookUri interface{}
+}
+
+type SessionStatsRow struct {
+	Value                string
+	NumSessions          int64
+	NumSuggested         int64
+	NumAccepted          int64
+	NumRejected          int64
+	NumSuggestedExecuted int64
+	NumExecuted          int64
+	NumExecuteFailed     int64
+	TotalInputTokens     int64
+	TotalOutputTokens    int64
+	TotalSecondsToAccept float64
+	NumTimedAccepts      int64
+}
+
+// SessionStats aggregates the sessions matching the filters grouped by the dimension. The dimension is the name
+// of a StatsDimension; sessions are grouped by the UTC day they started if it isn't one of the other values.
+// N.B. startTime is stored as a string starting with the date so the first 10 characters are the day.
+func (q *Queries) SessionStats(ctx context.Context, arg SessionStatsParams) ([]SessionStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, sessionStats,
+		arg.Dimension,
+		arg.StartTime,
+		arg.EndTime,
+		arg.NotebookUri,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SessionStatsRow
+	for rows.Next() {
+		var i SessionStatsRow
+		if err := rows.Scan(
+			&i.Value,
+			&i.NumSessions,
+			&i.NumSuggested,
+			&i.NumAccepted,
+			&i.NumRejected,
+			&i.NumSuggestedExecuted,
+			&i.NumExecuted,
+			&i.NumExecuteFailed,
+			&i.TotalInputTokens,
+			&i.TotalOutputTokens,
+			&i.TotalSecondsToAccept,
+			&i.NumTimedAccepts,
 		); err != nil {
 			return nil, err
 		}
@@ -121,9 +394,9 @@ func (q *Queries) ListSessionsForExamples(ctx context.Context, arg ListSessionsF
 
 const updateSession = `-- name: UpdateSession :exec
 INSERT OR REPLACE INTO sessions 
-(contextID, startTime, endTime, selectedId, selectedKind, total_input_tokens, total_output_tokens, num_generate_traces, proto)
+(contextID, startTime, endTime, selectedId, selectedKind, total_input_tokens, total_output_tokens, num_generate_traces, proto, total_cost_usd, notebook_uri, suggestion_status, executed, cell_text, model, rag_enabled, seconds_to_accept, execute_failed, proto_json)
 VALUES 
-(?, ?, ?, ?, ?, ?, ?, ?, ?)
+(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type UpdateSessionParams struct {
@@ -136,6 +409,16 @@ type UpdateSessionParams struct {
 	TotalOutputTokens int64
 	NumGenerateTraces int64
 	Proto             []byte
+	TotalCostUsd      float64
+	NotebookUri       string
+	SuggestionStatus  string
+	Executed          int64
+	CellText          string
+	Model             string
+	RagEnabled        int64
+	SecondsToAccept   float64
+	ExecuteFailed     int64
+	ProtoJson         string
 }
 
 func (q *Queries) UpdateSession(ctx context.Context, arg UpdateSessionParams) error {
@@ -149,6 +432,16 @@ func (q *Queries) UpdateSession(ctx context.Context, arg UpdateSessionParams) er
 		arg.TotalOutputTokens,
 		arg.NumGenerateTraces,
 		arg.Proto,
+		arg.TotalCostUsd,
+		arg.NotebookUri,
+		arg.SuggestionStatus,
+		arg.Executed,
+		arg.CellText,
+		arg.Model,
+		arg.RagEnabled,
+		arg.SecondsToAccept,
+		arg.ExecuteFailed,
+		arg.ProtoJson,
 	)
 	return err
 }
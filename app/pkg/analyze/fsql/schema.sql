@@ -1,5 +1,5 @@
--- TODO(jeremy): Should we add the notebookURI as a column so we can query for
--- sesions associated with a particular notebook. We could also add the selectedCellId as a column.
+-- This is the schema sqlc generates code from. The tables are created and changed by the migrations in
+-- analyze/migrations.go; when you add a migration update this file to match the schema it produces.
 -- N.B. Column names should be snake case. This is because sqlc will map snake case to camel case in the generated Go c
 -- code. So we will get camelCase member names consistent with GoLang style.
 -- See: https://docs.sqlc.dev/en/stable/howto/rename.html
@@ -20,10 +20,43 @@ CREATE TABLE IF NOT EXISTS sessions (
     -- Number of generate traces is the number of generations for this particular session
 
     num_generate_traces INT NOT NULL,
-    -- TODO(jeremy): Should we store the proto in JSON format so that we can run SQL queries on values in it?
-    proto BLOB
+    proto BLOB,
+
+    -- Total cost in USD of the LLM calls in the session.
+    -- N.B. This column is after proto because it is added with ALTER TABLE to databases created before it existed
+    -- and ALTER TABLE always appends columns.
+    total_cost_usd REAL NOT NULL DEFAULT 0,
+
+    -- The columns below are used to search sessions. They are computed from the proto.
+    -- The URI of the notebook the session belongs to.
+    notebook_uri VARCHAR(1024) NOT NULL DEFAULT '',
+    -- The name of the SuggestionStatus enum; i.e. whether the completion was accepted or rejected.
+    suggestion_status VARCHAR(255) NOT NULL DEFAULT '',
+    -- 1 if a cell was executed during the session and 0 otherwise.
+    executed INT NOT NULL DEFAULT 0,
+    -- The contents of the notebook's cells; used for text search.
+    cell_text TEXT NOT NULL DEFAULT '',
+
+    -- The columns below are used to compute statistics. They are also computed from the proto.
+    -- The model that produced the last completion.
+    model VARCHAR(255) NOT NULL DEFAULT '',
+    -- 1 if examples were retrieved to include in the prompt and 0 otherwise.
+    rag_enabled INT NOT NULL DEFAULT 0,
+    -- The number of seconds from the start of the session to the completion being accepted; 0 if it wasn't.
+    seconds_to_accept REAL NOT NULL DEFAULT 0,
+    -- 1 if a cell execution failed during the session and 0 otherwise.
+    execute_failed INT NOT NULL DEFAULT 0,
+
+    -- The JSON serialization of the proto so that SQL queries can use values in it with the JSON functions.
+    proto_json TEXT NOT NULL DEFAULT '{}'
 );
 
+CREATE INDEX IF NOT EXISTS idx_sessions_start_time ON sessions (startTime);
+CREATE INDEX IF NOT EXISTS idx_sessions_notebook_uri ON sessions (notebook_uri);
+CREATE INDEX IF NOT EXISTS idx_sessions_suggestion_status ON sessions (suggestion_status);
+CREATE INDEX IF NOT EXISTS idx_sessions_executed ON sessions (executed);
+CREATE INDEX IF NOT EXISTS idx_sessions_model ON sessions (model);
+
 -- Results contains evaluation results
 CREATE TABLE IF NOT EXISTS results (
     id VARCHAR(255) PRIMARY KEY,
@@ -32,5 +65,13 @@ CREATE TABLE IF NOT EXISTS results (
     time TIMESTAMP NOT NULL,
 
     -- The JSON serialization of the proto.
-    proto_json TEXT NOT NULL
+    proto_json TEXT NOT NULL,
+
+    -- Generated columns for the fields of the proto that are commonly queried.
+    -- The proto is serialized with default values so error is NULL rather than empty if there was no error.
+    error TEXT GENERATED ALWAYS AS (NULLIF(json_extract(proto_json, '$.error'), '')) VIRTUAL,
+    cells_match_result TEXT GENERATED ALWAYS AS (json_extract(proto_json, '$.cellsMatchResult')) VIRTUAL
 );
+
+CREATE INDEX IF NOT EXISTS idx_results_time ON results (time);
+CREATE INDEX IF NOT EXISTS idx_results_cells_match_result ON results (cells_match_result);
@@ -0,0 +1,348 @@
+package analyze
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	gcDeleted = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gc_deleted_total",
+			Help: "Number of raw log files, log entries, traces, blocks and sessions deleted by garbage collection",
+		},
+		[]string{"kind"},
+	)
+)
+
+// GCReport summarizes what garbage collection deleted or, for a dry run, what it would delete.
+type GCReport struct {
+	DryRun bool
+	// RawLogFiles are the raw log files deleted.
+	RawLogFiles []string
+	// RawLogBytes is the total size of the raw log files deleted.
+	RawLogBytes int64
+	LogEntries  int
+	Traces      int
+	Blocks      int
+	Sessions    int64
+}
+
+// GarbageCollector deletes the raw logs and the traces, blocks and sessions built from them once they are older than
+// the retention period. Raw log files are only deleted once the analyzer's watermark has moved past them so logs
+// that haven't been processed are never lost.
+type GarbageCollector struct {
+	retention    config.RetentionConfig
+	rawLogDir    string
+	watermark    func() *logspb.LogsWaterMark
+	logEntriesDB dbutil.KV
+	tracesDB     dbutil.KV
+	blocksDB     dbutil.KV
+	sessions     *SessionsManager
+
+	stop   chan struct{}
+	isDone sync.WaitGroup
+}
+
+// NewGarbageCollector creates a garbage collector. watermark should return the analyzer's current watermark; when
+// the analyzer isn't running use WatermarkFromFile.
+func NewGarbageCollector(retention config.RetentionConfig, rawLogDir string, watermark func() *logspb.LogsWaterMark, logEntriesDB dbutil.KV, tracesDB dbutil.KV, blocksDB dbutil.KV, sessions *SessionsManager) (*GarbageCollector, error) {
+	if watermark == nil {
+		return nil, errors.New("watermark function must be non nil")
+	}
+	return &GarbageCollector{
+		retention:    retention,
+		rawLogDir:    rawLogDir,
+		watermark:    watermark,
+		logEntriesDB: logEntriesDB,
+		tracesDB:     tracesDB,
+		blocksDB:     blocksDB,
+		sessions:     sessions,
+		stop:         make(chan struct{}),
+	}, nil
+}
+
+// WatermarkFromFile returns a function that returns the watermark saved in the log offsets file.
+func WatermarkFromFile(logOffsetsFile string) (func() *logspb.LogsWaterMark, error) {
+	w, err := initOffsets(logOffsetsFile)
+	if err != nil {
+		return nil, err
+	}
+	return func() *logspb.LogsWaterMark {
+		return w
+	}, nil
+}
+
+// Start garbage collects every retention interval until Shutdown is called.
+func (g *GarbageCollector) Start(ctx context.Context) {
+	g.isDone.Add(1)
+	go g.loop(ctx)
+}
+
+func (g *GarbageCollector) loop(ctx context.Context) {
+	log := logs.FromContext(ctx)
+	defer g.isDone.Done()
+	ticker := time.NewTicker(g.retention.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.stop:
+			return
+		case <-ticker.C:
+			report, err := g.Collect(ctx, false)
+			if err != nil {
+				log.Error(err, "Garbage collection failed")
+				continue
+			}
+			log.Info("Garbage collection done", "rawLogFiles", len(report.RawLogFiles), "rawLogBytes", report.RawLogBytes, "logEntries", report.LogEntries, "traces", report.Traces, "blocks", report.Blocks, "sessions", report.Sessions)
+		}
+	}
+}
+
+// Shutdown stops the garbage collector and waits for a collection in progress to finish.
+func (g *GarbageCollector) Shutdown(ctx context.Context) error {
+	log := logs.FromContext(ctx)
+	log.Info("Shutting down garbage collector")
+	close(g.stop)
+	g.isDone.Wait()
+	log.Info("Garbage collector shutdown")
+	return nil
+}
+
+// Collect deletes everything that is past the retention period. If dryRun is true nothing is deleted and the report
+// lists what would have been deleted.
+func (g *GarbageCollector) Collect(ctx context.Context, dryRun bool) (*GCReport, error) {
+	report := &GCReport{DryRun: dryRun}
+
+	if err := g.collectRawLogs(ctx, report); err != nil {
+		return report, err
+	}
+
+	if g.retention.MaxAge <= 0 {
+		return report, nil
+	}
+	cutoff := time.Now().Add(-g.retention.MaxAge)
+
+	if err := g.collectTraces(ctx, cutoff, report); err != nil {
+		return report, err
+	}
+
+	if g.sessions != nil {
+		n, err := g.sessions.DeleteBefore(ctx, cutoff, dryRun)
+		if err != nil {
+			return report, err
+		}
+		report.Sessions = n
+		if !dryRun {
+			gcDeleted.WithLabelValues("session").Add(float64(n))
+		}
+	}
+	return report, nil
+}
+
+type rawLogFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// collectRawLogs deletes the raw log files that are older than the max age or, when the directory is over the size
+// limit, the oldest files until it is under the limit. Only files before the watermark are deleted.
+func (g *GarbageCollector) collectRawLogs(ctx context.Context, report *GCReport) error {
+	log := logs.FromContext(ctx)
+	if g.rawLogDir == "" {
+		return nil
+	}
+	// The watermark records the resolved path of the file.
+	dir, err := filepath.EvalSymlinks(g.rawLogDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Failed to resolve raw log directory %s", g.rawLogDir)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read raw log directory %s", dir)
+	}
+
+	files := make([]rawLogFile, 0, len(entries))
+	var totalSize int64
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".json" && ext != ".jsonl") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return errors.Wrapf(err, "Failed to stat %s", e.Name())
+		}
+		files = append(files, rawLogFile{path: filepath.Join(dir, e.Name()), size: info.Size(), modTime: info.ModTime()})
+		totalSize += info.Size()
+	}
+	// The file names include the time the file was created so sorting them puts the oldest first.
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].path < files[j].path
+	})
+
+	watermark := g.watermark()
+	maxBytes := int64(g.retention.MaxRawLogsMB) * 1024 * 1024
+	cutoff := time.Now().Add(-g.retention.MaxAge)
+	for _, f := range files {
+		if f.path >= watermark.GetFile() {
+			// The file hasn't been fully processed yet; the files after it haven't either.
+			break
+		}
+		expired := g.retention.MaxAge > 0 && f.modTime.Before(cutoff)
+		overSize := maxBytes > 0 && totalSize > maxBytes
+		if !expired && !overSize {
+			continue
+		}
+		if !report.DryRun {
+			if err := os.Remove(f.path); err != nil {
+				return errors.Wrapf(err, "Failed to delete raw log file %s", f.path)
+			}
+			gcDeleted.WithLabelValues("raw_log_file").Inc()
+		}
+		log.V(logs.Debug).Info("Deleting raw log file", "file", f.path, "dryRun", report.DryRun)
+		report.RawLogFiles = append(report.RawLogFiles, f.path)
+		report.RawLogBytes += f.size
+		totalSize -= f.size
+	}
+	return nil
+}
+
+// collectTraces deletes the traces that ended before the cutoff along with their log entries and the blocks they
+// generated. The blocks are deleted first so that if collection is interrupted no block refers to a deleted trace.
+func (g *GarbageCollector) collectTraces(ctx context.Context, cutoff time.Time, report *GCReport) error {
+	if g.tracesDB == nil {
+		return nil
+	}
+	expired := make(map[string]bool)
+	err := forEachProto(g.tracesDB, func() *logspb.Trace { return &logspb.Trace{} }, func(key string, trace *logspb.Trace) {
+		t := trace.GetEndTime()
+		if t == nil {
+			t = trace.GetStartTime()
+		}
+		// Keep traces we can't date rather than guess.
+		if t != nil && t.AsTime().Before(cutoff) {
+			expired[key] = true
+		}
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to scan traces")
+	}
+
+	if g.blocksDB != nil {
+		blocks := make([]string, 0)
+		err := forEachProto(g.blocksDB, func() *logspb.BlockLog { return &logspb.BlockLog{} }, func(key string, block *logspb.BlockLog) {
+			if expired[block.GetGenTraceId()] {
+				blocks = append(blocks, key)
+			}
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Failed to scan blocks")
+		}
+		report.Blocks = len(blocks)
+		if err := deleteKeys(ctx, g.blocksDB, blocks, report.DryRun); err != nil {
+			return errors.Wrapf(err, "Failed to delete blocks")
+		}
+		if !report.DryRun {
+			gcDeleted.WithLabelValues("block").Add(float64(len(blocks)))
+		}
+	}
+
+	traces := make([]string, 0, len(expired))
+	for id := range expired {
+		traces = append(traces, id)
+	}
+	sort.Strings(traces)
+
+	if g.logEntriesDB != nil {
+		// Log entries are keyed by trace id.
+		entries := make([]string, 0, len(traces))
+		for _, id := range traces {
+			_, err := g.logEntriesDB.Get([]byte(id))
+			if errors.Is(err, dbutil.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "Failed to read log entries for trace %s", id)
+			}
+			entries = append(entries, id)
+		}
+		report.LogEntries = len(entries)
+		if err := deleteKeys(ctx, g.logEntriesDB, entries, report.DryRun); err != nil {
+			return errors.Wrapf(err, "Failed to delete log entries")
+		}
+		if !report.DryRun {
+			gcDeleted.WithLabelValues("log_entries").Add(float64(len(entries)))
+		}
+	}
+
+	report.Traces = len(traces)
+	if err := deleteKeys(ctx, g.tracesDB, traces, report.DryRun); err != nil {
+		return errors.Wrapf(err, "Failed to delete traces")
+	}
+	if !report.DryRun {
+		gcDeleted.WithLabelValues("trace").Add(float64(len(traces)))
+	}
+	return nil
+}
+
+// forEachProto calls visit with every value in the database. Values that can't be unmarshaled are skipped.
+func forEachProto[T proto.Message](db dbutil.KV, newProto func() T, visit func(key string, value T)) error {
+	return db.Scan(nil, func(key []byte, b []byte) error {
+		value := newProto()
+		if err := proto.Unmarshal(b, value); err != nil {
+			return nil
+		}
+		visit(string(key), value)
+		return nil
+	})
+}
+
+// deleteKeys deletes the keys in a single batch and then, if the store supports it, compacts the range they span so
+// the space is reclaimed.
+func deleteKeys(ctx context.Context, db dbutil.KV, keys []string, dryRun bool) error {
+	if dryRun || len(keys) == 0 {
+		return nil
+	}
+	sorted := append([]string{}, keys...)
+	sort.Strings(sorted)
+
+	toDelete := make([][]byte, 0, len(sorted))
+	for _, k := range sorted {
+		toDelete = append(toDelete, []byte(k))
+	}
+	if err := db.Delete(toDelete...); err != nil {
+		return errors.Wrapf(err, "Failed to delete keys")
+	}
+
+	compacter, ok := db.(dbutil.Compacter)
+	if !ok {
+		return nil
+	}
+	// Compact's end key is exclusive.
+	end := sorted[len(sorted)-1] + "\x00"
+	if err := compacter.Compact([]byte(sorted[0]), []byte(end)); err != nil {
+		// The keys are deleted; compaction only reclaims the space so don't fail.
+		log := logs.FromContext(ctx)
+		log.Error(err, "Failed to compact database after deleting keys")
+	}
+	return nil
+}
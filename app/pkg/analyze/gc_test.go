@@ -0,0 +1,180 @@
+package analyze
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/monogo/helpers"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func Test_GarbageCollector(t *testing.T) {
+	oDir := t.TempDir()
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+
+	openDB := func(name string) dbutil.KV {
+		db, err := dbutil.OpenPebbleKV(filepath.Join(oDir, name), false)
+		if err != nil {
+			t.Fatalf("could not open %s database: %v", name, err)
+		}
+		return db
+	}
+	logEntriesDB := openDB("logEntries")
+	defer helpers.DeferIgnoreError(logEntriesDB.Close)
+	tracesDB := openDB("traces")
+	defer helpers.DeferIgnoreError(tracesDB.Close)
+	blocksDB := openDB("blocks")
+	defer helpers.DeferIgnoreError(blocksDB.Close)
+
+	db, err := sql.Open(SQLLiteDriver, filepath.Join(oDir, "sessions.sqllite3"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %+v", err)
+	}
+	defer helpers.DeferIgnoreError(db.Close)
+	sessions, err := NewSessionsManager(NewStore(db, dbutil.SQLite))
+	if err != nil {
+		t.Fatalf("Failed to create sessions manager: %v", err)
+	}
+
+	for id, start := range map[string]time.Time{"oldTrace": old, "newTrace": now} {
+		trace := &logspb.Trace{Id: id, StartTime: timestamppb.New(start), EndTime: timestamppb.New(start.Add(time.Second))}
+		if err := dbutil.SetProto(tracesDB, id, trace); err != nil {
+			t.Fatalf("Failed to write trace: %v", err)
+		}
+		if err := dbutil.SetProto(logEntriesDB, id, &logspb.LogEntries{Lines: []string{"{}"}}); err != nil {
+			t.Fatalf("Failed to write log entries: %v", err)
+		}
+		block := &logspb.BlockLog{Id: id + "-block", GenTraceId: id}
+		if err := dbutil.SetProto(blocksDB, block.Id, block); err != nil {
+			t.Fatalf("Failed to write block: %v", err)
+		}
+		if err := sessions.Update(context.Background(), id+"-session", func(s *logspb.Session) error {
+			s.ContextId = id + "-session"
+			s.StartTime = timestamppb.New(start)
+			s.EndTime = timestamppb.New(start.Add(time.Minute))
+			return nil
+		}); err != nil {
+			t.Fatalf("Failed to write session: %v", err)
+		}
+	}
+
+	rawDir := filepath.Join(oDir, "raw")
+	if err := os.MkdirAll(rawDir, 0755); err != nil {
+		t.Fatalf("Failed to create raw log dir: %v", err)
+	}
+	// The watermark is on the last file so the first three have been processed.
+	// The first file is expired and the second is removed to get under the size limit. The last file is expired but
+	// hasn't been fully processed so it is kept.
+	rawFiles := []struct {
+		name    string
+		size    int
+		modTime time.Time
+	}{
+		{name: "foyle.logs.2024-01-01T00:00:00.json", size: 10, modTime: old},
+		{name: "foyle.logs.2024-01-02T00:00:00.json", size: 1024 * 1024, modTime: now},
+		{name: "foyle.logs.2024-01-03T00:00:00.json", size: 10, modTime: now},
+		{name: "foyle.logs.2024-01-04T00:00:00.json", size: 512 * 1024, modTime: old},
+	}
+	for _, f := range rawFiles {
+		p := filepath.Join(rawDir, f.name)
+		if err := os.WriteFile(p, make([]byte, f.size), 0644); err != nil {
+			t.Fatalf("Failed to write raw log file: %v", err)
+		}
+		if err := os.Chtimes(p, f.modTime, f.modTime); err != nil {
+			t.Fatalf("Failed to set mod time: %v", err)
+		}
+	}
+	resolvedDir, err := filepath.EvalSymlinks(rawDir)
+	if err != nil {
+		t.Fatalf("Failed to resolve raw log dir: %v", err)
+	}
+	watermark := func() *logspb.LogsWaterMark {
+		return &logspb.LogsWaterMark{File: filepath.Join(resolvedDir, rawFiles[3].name)}
+	}
+
+	retention := config.RetentionConfig{MaxAge: 24 * time.Hour, MaxRawLogsMB: 1}
+	gc, err := NewGarbageCollector(retention, rawDir, watermark, logEntriesDB, tracesDB, blocksDB, sessions)
+	if err != nil {
+		t.Fatalf("Failed to create garbage collector: %v", err)
+	}
+
+	expected := &GCReport{
+		DryRun: true,
+		RawLogFiles: []string{
+			filepath.Join(resolvedDir, rawFiles[0].name),
+			filepath.Join(resolvedDir, rawFiles[1].name),
+		},
+		RawLogBytes: 10 + 1024*1024,
+		LogEntries:  1,
+		Traces:      1,
+		Blocks:      1,
+		Sessions:    1,
+	}
+
+	// A dry run shouldn't delete anything.
+	report, err := gc.Collect(context.Background(), true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %+v", err)
+	}
+	if d := cmp.Diff(expected, report); d != "" {
+		t.Errorf("Unexpected dry run report:\n%s", d)
+	}
+	if _, err := os.Stat(filepath.Join(rawDir, rawFiles[0].name)); err != nil {
+		t.Errorf("Dry run deleted a raw log file: %v", err)
+	}
+
+	report, err = gc.Collect(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Collect failed: %+v", err)
+	}
+	expected.DryRun = false
+	if d := cmp.Diff(expected, report); d != "" {
+		t.Errorf("Unexpected report:\n%s", d)
+	}
+
+	remaining, err := os.ReadDir(rawDir)
+	if err != nil {
+		t.Fatalf("Failed to read raw log dir: %v", err)
+	}
+	names := make([]string, 0, len(remaining))
+	for _, e := range remaining {
+		names = append(names, e.Name())
+	}
+	if d := cmp.Diff([]string{rawFiles[2].name, rawFiles[3].name}, names); d != "" {
+		t.Errorf("Unexpected raw log files:\n%s", d)
+	}
+
+	for _, c := range []struct {
+		db      dbutil.KV
+		key     string
+		present bool
+	}{
+		{db: tracesDB, key: "oldTrace", present: false},
+		{db: tracesDB, key: "newTrace", present: true},
+		{db: logEntriesDB, key: "oldTrace", present: false},
+		{db: logEntriesDB, key: "newTrace", present: true},
+		{db: blocksDB, key: "oldTrace-block", present: false},
+		{db: blocksDB, key: "newTrace-block", present: true},
+	} {
+		_, err := c.db.Get([]byte(c.key))
+		if present := err == nil; present != c.present {
+			t.Errorf("Key %s: expected present=%v; got err %v", c.key, c.present, err)
+		}
+	}
+
+	if _, err := sessions.Get(context.Background(), "oldTrace-session"); err == nil {
+		t.Errorf("Expected the old session to be deleted")
+	}
+	if _, err := sessions.Get(context.Background(), "newTrace-session"); err != nil {
+		t.Errorf("Expected the new session to be kept; got %v", err)
+	}
+}
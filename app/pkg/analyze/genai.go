@@ -0,0 +1,308 @@
+package analyze
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/jlewi/foyle/app/pkg/docs"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attributes and events from the OpenTelemetry semantic conventions for generative AI.
+// https://opentelemetry.io/docs/specs/semconv/gen-ai/
+const (
+	genAIOperationName     = attribute.Key("gen_ai.operation.name")
+	genAISystem            = attribute.Key("gen_ai.system")
+	genAIRequestModel      = attribute.Key("gen_ai.request.model")
+	genAIUsageInputTokens  = attribute.Key("gen_ai.usage.input_tokens")
+	genAIUsageOutputTokens = attribute.Key("gen_ai.usage.output_tokens")
+	genAIToolName          = attribute.Key("gen_ai.tool.name")
+	genAIPrompt            = attribute.Key("gen_ai.prompt")
+	genAICompletion        = attribute.Key("gen_ai.completion")
+
+	genAIPromptEvent     = "gen_ai.content.prompt"
+	genAICompletionEvent = "gen_ai.content.completion"
+
+	genAIOperationChat        = "chat"
+	genAIOperationExecuteTool = "execute_tool"
+)
+
+// Foyle specific attributes for the parts of the trace that aren't covered by the semantic conventions.
+const (
+	foyleTraceID           = attribute.Key("foyle.trace_id")
+	foyleEvalMode          = attribute.Key("foyle.eval_mode")
+	foyleCostUSD           = attribute.Key("foyle.cost_usd")
+	foyleCachedInputTokens = attribute.Key("foyle.usage.cached_input_tokens")
+	foyleNumBlocks         = attribute.Key("foyle.response.num_blocks")
+	foyleNumAlternatives   = attribute.Key("foyle.response.num_alternatives")
+	foyleRoute             = attribute.Key("foyle.llm.route")
+	foyleAttempts          = attribute.Key("foyle.llm.attempts")
+	foyleToolCommand       = attribute.Key("foyle.tool.command")
+	foyleToolAllowed       = attribute.Key("foyle.tool.allowed")
+	foyleToolExitCode      = attribute.Key("foyle.tool.exit_code")
+	foyleRAGQuery          = attribute.Key("foyle.rag.query")
+	foyleRAGNumResults     = attribute.Key("foyle.rag.num_results")
+	foyleRAGExampleID      = attribute.Key("foyle.rag.example_id")
+	foyleRAGScore          = attribute.Key("foyle.rag.score")
+	foyleRAGDocument       = attribute.Key("foyle.rag.document")
+	foyleBlockID           = attribute.Key("foyle.block_id")
+	foyleReducerStrategy   = attribute.Key("foyle.reduction.strategy")
+	foyleOriginalLength    = attribute.Key("foyle.reduction.original_length")
+	foyleReducedLength     = attribute.Key("foyle.reduction.reduced_length")
+	foyleAssertionName     = attribute.Key("foyle.assertion.name")
+	foyleAssertionResult   = attribute.Key("foyle.assertion.result")
+
+	retrievedDocumentEvent = "foyle.rag.result"
+	assertionEvent         = "foyle.assertion"
+)
+
+// TraceExporter exports the traces built by the Analyzer to another system.
+type TraceExporter interface {
+	Export(ctx context.Context, trace *logspb.Trace) error
+}
+
+// GenAIExporter exports traces as OpenTelemetry spans following the GenAI semantic conventions. The spans use
+// Foyle's trace id so they can be correlated with the spans Foyle emits while handling requests.
+type GenAIExporter struct {
+	provider       *sdktrace.TracerProvider
+	tracer         trace.Tracer
+	includeContent bool
+}
+
+// NewGenAIExporter creates an exporter that sends the spans to exporter; e.g. an OTLP exporter. If includeContent
+// is true the prompts, completions and retrieved examples are added to the spans.
+func NewGenAIExporter(exporter sdktrace.SpanExporter, serviceName string, includeContent bool) (*GenAIExporter, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", serviceName)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create OTEL resource")
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithIDGenerator(&traceIDGenerator{}),
+	)
+	return &GenAIExporter{
+		provider:       provider,
+		tracer:         provider.Tracer("github.com/jlewi/foyle/app/pkg/analyze"),
+		includeContent: includeContent,
+	}, nil
+}
+
+// Export emits the spans for the trace.
+func (e *GenAIExporter) Export(ctx context.Context, t *logspb.Trace) error {
+	gen := t.GetGenerate()
+	if gen == nil {
+		return errors.Errorf("Trace %s isn't a generate trace; only generate traces can be exported", t.GetId())
+	}
+
+	end := time.Now()
+	if t.GetEndTime() != nil {
+		end = t.GetEndTime().AsTime()
+	}
+	start := end
+	if t.GetStartTime() != nil {
+		start = t.GetStartTime().AsTime()
+	}
+	if end.Before(start) {
+		end = start
+	}
+
+	ctx = withTraceID(ctx, t.GetId())
+	ctx, root := e.tracer.Start(ctx, "generate", trace.WithTimestamp(start), trace.WithAttributes(
+		foyleTraceID.String(t.GetId()),
+		foyleEvalMode.Bool(t.GetEvalMode()),
+		foyleCostUSD.Float64(t.GetCostUsd()),
+		foyleNumBlocks.Int(len(gen.GetResponse().GetBlocks())),
+		foyleNumAlternatives.Int(len(gen.GetResponse().GetAlternatives())),
+	))
+	if gen.GetResponse() == nil {
+		root.SetStatus(codes.Error, "generate didn't return a response")
+	}
+	for _, a := range t.GetAssertions() {
+		root.AddEvent(assertionEvent, trace.WithTimestamp(start), trace.WithAttributes(
+			foyleAssertionName.String(a.GetName().String()),
+			foyleAssertionResult.String(a.GetResult().String()),
+		))
+	}
+
+	numLLMSpans := 0
+	for _, s := range t.GetSpans() {
+		if s.GetLlm() != nil {
+			numLLMSpans++
+		}
+	}
+	// Usage is only recorded for the trace as a whole so it can only be attributed to the call when there is one.
+	if numLLMSpans > 1 {
+		root.SetAttributes(
+			genAIUsageInputTokens.Int64(int64(t.GetInputTokens())),
+			genAIUsageOutputTokens.Int64(int64(t.GetOutputTokens())),
+			foyleCachedInputTokens.Int64(int64(t.GetCachedInputTokens())),
+		)
+	}
+
+	for _, s := range t.GetSpans() {
+		switch {
+		case s.GetLlm() != nil:
+			e.exportLLMSpan(ctx, t, s.GetLlm(), numLLMSpans == 1, start, end)
+		case s.GetRag() != nil:
+			e.exportRAGSpan(ctx, s.GetRag(), start)
+		case s.GetTool() != nil:
+			e.exportToolSpan(ctx, s.GetTool(), start)
+		case s.GetReduction() != nil:
+			e.exportReductionSpan(ctx, s.GetReduction(), start)
+		}
+	}
+	root.End(trace.WithTimestamp(end))
+	return nil
+}
+
+// exportLLMSpan exports a call to the model. If includeUsage is true the usage of the trace is attributed to the call.
+func (e *GenAIExporter) exportLLMSpan(ctx context.Context, t *logspb.Trace, llm *logspb.LLMSpan, includeUsage bool, start time.Time, end time.Time) {
+	spanEnd := end
+	if llm.GetLatencyMs() > 0 {
+		spanEnd = start.Add(time.Duration(llm.GetLatencyMs()) * time.Millisecond)
+		if spanEnd.After(end) {
+			spanEnd = end
+		}
+	}
+	_, span := e.tracer.Start(ctx, fmt.Sprintf("%s %s", genAIOperationChat, llm.GetModel()), trace.WithTimestamp(start), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		genAIOperationName.String(genAIOperationChat),
+		genAISystem.String(genAISystemName(llm.GetProvider())),
+		genAIRequestModel.String(llm.GetModel()),
+	))
+	if includeUsage {
+		span.SetAttributes(
+			genAIUsageInputTokens.Int64(int64(t.GetInputTokens())),
+			genAIUsageOutputTokens.Int64(int64(t.GetOutputTokens())),
+			foyleCachedInputTokens.Int64(int64(t.GetCachedInputTokens())),
+		)
+	}
+	if llm.GetRoute() != "" {
+		span.SetAttributes(foyleRoute.String(llm.GetRoute()), foyleAttempts.Int64(int64(llm.GetAttempts())))
+	}
+	if e.includeContent {
+		span.AddEvent(genAIPromptEvent, trace.WithTimestamp(start), trace.WithAttributes(genAIPrompt.String(llm.GetRequestJson())))
+		span.AddEvent(genAICompletionEvent, trace.WithTimestamp(spanEnd), trace.WithAttributes(genAICompletion.String(llm.GetResponseJson())))
+	}
+	if llm.GetResponseJson() == "" {
+		span.SetStatus(codes.Error, "the model didn't return a response")
+	}
+	span.End(trace.WithTimestamp(spanEnd))
+}
+
+// exportRAGSpan exports the examples retrieved to include in the prompt.
+func (e *GenAIExporter) exportRAGSpan(ctx context.Context, rag *logspb.RAGSpan, start time.Time) {
+	_, span := e.tracer.Start(ctx, "retrieve examples", trace.WithTimestamp(start), trace.WithAttributes(
+		foyleRAGNumResults.Int(len(rag.GetResults())),
+	))
+	if e.includeContent && rag.GetQuery() != "" {
+		span.SetAttributes(foyleRAGQuery.String(rag.GetQuery()))
+	}
+	for _, r := range rag.GetResults() {
+		attrs := []attribute.KeyValue{
+			foyleRAGExampleID.String(r.GetExample().GetId()),
+			foyleRAGScore.Float64(r.GetScore()),
+		}
+		if e.includeContent {
+			attrs = append(attrs, foyleRAGDocument.String(docs.DocToMarkdown(r.GetExample().GetQuery())))
+		}
+		span.AddEvent(retrievedDocumentEvent, trace.WithTimestamp(start), trace.WithAttributes(attrs...))
+	}
+	span.End(trace.WithTimestamp(start))
+}
+
+// exportToolSpan exports a tool call made by the model.
+func (e *GenAIExporter) exportToolSpan(ctx context.Context, tool *logspb.ToolSpan, start time.Time) {
+	toolStart := start
+	if tool.GetStartTime() != nil {
+		toolStart = tool.GetStartTime().AsTime()
+	}
+	toolEnd := toolStart
+	if tool.GetEndTime() != nil {
+		toolEnd = tool.GetEndTime().AsTime()
+	}
+	_, span := e.tracer.Start(ctx, fmt.Sprintf("%s %s", genAIOperationExecuteTool, tool.GetName()), trace.WithTimestamp(toolStart), trace.WithAttributes(
+		genAIOperationName.String(genAIOperationExecuteTool),
+		genAIToolName.String(tool.GetName()),
+		foyleToolCommand.String(tool.GetCommand()),
+		foyleToolAllowed.Bool(tool.GetAllowed()),
+		foyleToolExitCode.Int64(int64(tool.GetExitCode())),
+	))
+	if tool.GetError() != "" {
+		span.SetStatus(codes.Error, tool.GetError())
+	}
+	span.End(trace.WithTimestamp(toolEnd))
+}
+
+// exportReductionSpan exports the reduction of an output to fit in the prompt.
+func (e *GenAIExporter) exportReductionSpan(ctx context.Context, r *logspb.OutputReductionSpan, start time.Time) {
+	_, span := e.tracer.Start(ctx, "reduce output", trace.WithTimestamp(start), trace.WithAttributes(
+		foyleBlockID.String(r.GetBlockId()),
+		foyleReducerStrategy.String(r.GetStrategy()),
+		foyleOriginalLength.Int64(int64(r.GetOriginalLength())),
+		foyleReducedLength.Int64(int64(r.GetReducedLength())),
+	))
+	if r.GetError() != "" {
+		span.SetStatus(codes.Error, r.GetError())
+	}
+	span.End(trace.WithTimestamp(start))
+}
+
+// Shutdown flushes the spans that haven't been exported yet.
+func (e *GenAIExporter) Shutdown(ctx context.Context) error {
+	return e.provider.Shutdown(ctx)
+}
+
+// genAISystemName returns the value of gen_ai.system for the provider.
+func genAISystemName(p v1alpha1.ModelProvider) string {
+	switch p {
+	case v1alpha1.ModelProvider_OPEN_AI:
+		return "openai"
+	case v1alpha1.ModelProvider_ANTHROPIC:
+		return "anthropic"
+	case v1alpha1.ModelProvider_GEMINI:
+		return "gcp.gemini"
+	case v1alpha1.ModelProvider_REPLICATE:
+		return "replicate"
+	default:
+		return "_OTHER"
+	}
+}
+
+type traceIDKey struct{}
+
+// withTraceID returns a context telling traceIDGenerator to use Foyle's trace id for the root span. Foyle's trace ids
+// are the ids of the OTEL traces of the requests.
+func withTraceID(ctx context.Context, id string) context.Context {
+	tid, err := trace.TraceIDFromHex(id)
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, traceIDKey{}, tid)
+}
+
+// traceIDGenerator generates random ids except for the trace id of root spans which is taken from the context.
+type traceIDGenerator struct{}
+
+func (g *traceIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	tid, ok := ctx.Value(traceIDKey{}).(trace.TraceID)
+	if !ok {
+		_, _ = rand.Read(tid[:])
+	}
+	return tid, g.NewSpanID(ctx, tid)
+}
+
+func (g *traceIDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	sid := trace.SpanID{}
+	_, _ = rand.Read(sid[:])
+	return sid
+}
@@ -0,0 +1,174 @@
+package analyze
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	collectortracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// otlpReceiver is an in-process OTLP/HTTP collector that records the spans it receives.
+type otlpReceiver struct {
+	mu    sync.Mutex
+	spans []*tracepb.Span
+}
+
+func (r *otlpReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	export := &collectortracepb.ExportTraceServiceRequest{}
+	if err := proto.Unmarshal(body, export); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rs := range export.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			r.spans = append(r.spans, ss.GetSpans()...)
+		}
+	}
+	raw, err := proto.Marshal(&collectortracepb.ExportTraceServiceResponse{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(raw)
+}
+
+func spanAttributes(s *tracepb.Span) map[string]string {
+	attrs := make(map[string]string)
+	for _, kv := range s.GetAttributes() {
+		v := kv.GetValue()
+		switch {
+		case v.GetStringValue() != "":
+			attrs[kv.GetKey()] = v.GetStringValue()
+		case v.GetIntValue() != 0:
+			attrs[kv.GetKey()] = strconv.FormatInt(v.GetIntValue(), 10)
+		}
+	}
+	return attrs
+}
+
+func Test_GenAIExporter(t *testing.T) {
+	receiver := &otlpReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	otlpExporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpoint(strings.TrimPrefix(server.URL, "http://")), otlptracehttp.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to create OTLP exporter: %v", err)
+	}
+	exporter, err := NewGenAIExporter(otlpExporter, "foyle-test", true)
+	if err != nil {
+		t.Fatalf("Failed to create GenAI exporter: %v", err)
+	}
+
+	const traceID = "0123456789abcdef0123456789abcdef"
+	start := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	trace := &logspb.Trace{
+		Id:           traceID,
+		StartTime:    timestamppb.New(start),
+		EndTime:      timestamppb.New(start.Add(2 * time.Second)),
+		InputTokens:  100,
+		OutputTokens: 20,
+		Data: &logspb.Trace_Generate{
+			Generate: &logspb.GenerateTrace{
+				Request:  &v1alpha1.GenerateRequest{},
+				Response: &v1alpha1.GenerateResponse{Blocks: []*v1alpha1.Block{{Id: "b1"}}},
+			},
+		},
+		Spans: []*logspb.Span{
+			{
+				Data: &logspb.Span_Rag{Rag: &logspb.RAGSpan{Results: []*v1alpha1.RAGResult{{Example: &v1alpha1.Example{Id: "e1"}, Score: 0.9}}}},
+			},
+			{
+				Data: &logspb.Span_Llm{Llm: &logspb.LLMSpan{Provider: v1alpha1.ModelProvider_OPEN_AI, Model: "gpt-4o", RequestJson: `{"prompt":"hi"}`, ResponseJson: `{"text":"ls"}`, LatencyMs: 1500}},
+			},
+			{
+				Data: &logspb.Span_Tool{Tool: &logspb.ToolSpan{Name: "run_command", Command: "ls", Allowed: true, StartTime: timestamppb.New(start), EndTime: timestamppb.New(start.Add(time.Second))}},
+			},
+		},
+	}
+
+	if err := exporter.Export(context.Background(), trace); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if err := exporter.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+
+	spans := make(map[string]*tracepb.Span)
+	for _, s := range receiver.spans {
+		spans[s.GetName()] = s
+	}
+	for _, name := range []string{"generate", "retrieve examples", "chat gpt-4o", "execute_tool run_command"} {
+		s, ok := spans[name]
+		if !ok {
+			t.Fatalf("Missing span %q; got %v", name, receiver.spans)
+		}
+		// All the spans should use Foyle's trace id so they can be correlated with the request.
+		if got := hex.EncodeToString(s.GetTraceId()); got != traceID {
+			t.Errorf("Span %q has trace id %s; want %s", name, got, traceID)
+		}
+	}
+
+	root := spans["generate"]
+	if len(root.GetParentSpanId()) != 0 {
+		t.Errorf("Expected generate to be the root span")
+	}
+	chat := spans["chat gpt-4o"]
+	if string(chat.GetParentSpanId()) != string(root.GetSpanId()) {
+		t.Errorf("Expected the chat span to be a child of the generate span")
+	}
+	if d := time.Duration(chat.GetEndTimeUnixNano() - chat.GetStartTimeUnixNano()); d != 1500*time.Millisecond {
+		t.Errorf("Expected the chat span to last 1.5s; got %v", d)
+	}
+
+	expected := map[string]string{
+		"gen_ai.operation.name":      "chat",
+		"gen_ai.system":              "openai",
+		"gen_ai.request.model":       "gpt-4o",
+		"gen_ai.usage.input_tokens":  "100",
+		"gen_ai.usage.output_tokens": "20",
+	}
+	actual := spanAttributes(chat)
+	for k := range actual {
+		if _, ok := expected[k]; !ok {
+			delete(actual, k)
+		}
+	}
+	if d := cmp.Diff(expected, actual); d != "" {
+		t.Errorf("Unexpected chat span attributes:\n%s", d)
+	}
+
+	events := make([]string, 0, 2)
+	for _, e := range chat.GetEvents() {
+		events = append(events, e.GetName())
+	}
+	if d := cmp.Diff([]string{"gen_ai.content.prompt", "gen_ai.content.completion"}, events); d != "" {
+		t.Errorf("Unexpected chat span events:\n%s", d)
+	}
+}
@@ -51,28 +51,35 @@ func readLLMLog(ctx context.Context, traceId string, logFile string) (*logspb.Ge
 		if entry.TraceID() != traceId {
 			continue
 		}
-		isMatch := false
+		if call, ok := getLLMCall(entry); ok {
+			provider = call.Provider
+			resp.RequestJson = string(call.Request)
+			resp.ResponseJson = string(call.Response)
+		} else {
+			// Older logs logged the request and response from the completer.
+			isMatch := false
 
-		if matchers.IsOAIComplete(entry.Function()) {
-			provider = api.ModelProviderOpenAI
-			isMatch = true
-		}
+			if matchers.IsOAIComplete(entry.Function()) {
+				provider = api.ModelProviderOpenAI
+				isMatch = true
+			}
 
-		if strings.HasSuffix(entry.Function(), "anthropic.(*Completer).Complete") {
-			provider = api.ModelProviderAnthropic
-			isMatch = true
-		}
+			if strings.HasSuffix(entry.Function(), "anthropic.(*Completer).Complete") {
+				provider = api.ModelProviderAnthropic
+				isMatch = true
+			}
 
-		// If tis not a matching request ignore it.
-		if !isMatch {
-			continue
-		}
-		if reqBytes := entry.Request(); reqBytes != nil {
-			resp.RequestJson = string(reqBytes)
-		}
+			// If tis not a matching request ignore it.
+			if !isMatch {
+				continue
+			}
+			if reqBytes := entry.Request(); reqBytes != nil {
+				resp.RequestJson = string(reqBytes)
+			}
 
-		if resBytes := entry.Response(); resBytes != nil {
-			resp.ResponseJson = string(resBytes)
+			if resBytes := entry.Response(); resBytes != nil {
+				resp.ResponseJson = string(resBytes)
+			}
 		}
 
 		// Since we have read the request and response less
@@ -0,0 +1,221 @@
+package analyze
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jlewi/foyle/app/pkg/analyze/fsql"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// sessionsComponent and resultsComponent identify the migrations in the schema_migrations table.
+	sessionsComponent = "sessions"
+	resultsComponent  = "results"
+)
+
+// N.B. fsql/schema.sql and fsql/pgsql/schema.sql are the schemas sqlc generates code from. They must match the
+// schemas created by applying all the migrations; Test_MigrationsMatchSchema checks that they do. To change the
+// schema add a SQLite and a Postgres migration and update both schema files. Never change a migration that has been
+// released.
+
+// sessionsMigrations are the migrations for the sessions table.
+var sessionsMigrations = []dbutil.Migration{
+	{
+		Version:     1,
+		Description: "Create the sessions table",
+		Up: dbutil.Exec(`CREATE TABLE IF NOT EXISTS sessions (
+    contextID VARCHAR(255) PRIMARY KEY,
+    startTime TIMESTAMP NOT NULL,
+    endTime TIMESTAMP NOT NULL,
+    selectedID VARCHAR(255) NOT NULL,
+    selectedKind VARCHAR(255) NOT NULL,
+    total_input_tokens INT NOT NULL,
+    total_output_tokens INT NOT NULL,
+    num_generate_traces INT NOT NULL,
+    proto BLOB
+);`),
+	},
+	{
+		Version:     2,
+		Description: "Add the cost of the session",
+		Up:          dbutil.AddColumns("sessions", dbutil.Column{Name: "total_cost_usd", Definition: "REAL NOT NULL DEFAULT 0"}),
+	},
+	{
+		Version:     3,
+		Description: "Add columns to search sessions and compute statistics",
+		Up: dbutil.Steps(
+			dbutil.AddColumns("sessions",
+				dbutil.Column{Name: "notebook_uri", Definition: "VARCHAR(1024) NOT NULL DEFAULT ''"},
+				dbutil.Column{Name: "suggestion_status", Definition: "VARCHAR(255) NOT NULL DEFAULT ''"},
+				dbutil.Column{Name: "executed", Definition: "INT NOT NULL DEFAULT 0"},
+				dbutil.Column{Name: "cell_text", Definition: "TEXT NOT NULL DEFAULT ''"},
+				dbutil.Column{Name: "model", Definition: "VARCHAR(255) NOT NULL DEFAULT ''"},
+				dbutil.Column{Name: "rag_enabled", Definition: "INT NOT NULL DEFAULT 0"},
+				dbutil.Column{Name: "seconds_to_accept", Definition: "REAL NOT NULL DEFAULT 0"},
+				dbutil.Column{Name: "execute_failed", Definition: "INT NOT NULL DEFAULT 0"},
+			),
+			dbutil.Exec(
+				"CREATE INDEX IF NOT EXISTS idx_sessions_start_time ON sessions (startTime);",
+				"CREATE INDEX IF NOT EXISTS idx_sessions_notebook_uri ON sessions (notebook_uri);",
+				"CREATE INDEX IF NOT EXISTS idx_sessions_suggestion_status ON sessions (suggestion_status);",
+				"CREATE INDEX IF NOT EXISTS idx_sessions_executed ON sessions (executed);",
+				"CREATE INDEX IF NOT EXISTS idx_sessions_model ON sessions (model);",
+			),
+			backfillSearchColumns,
+		),
+	},
+	{
+		Version:     4,
+		Description: "Store the session proto as JSON",
+		Up: dbutil.Steps(
+			dbutil.AddColumns("sessions", dbutil.Column{Name: "proto_json", Definition: "TEXT NOT NULL DEFAULT '{}'"}),
+			backfillProtoJSON,
+		),
+	},
+}
+
+// resultsMigrations are the migrations for the eval results table.
+var resultsMigrations = []dbutil.Migration{
+	{
+		Version:     1,
+		Description: "Create the results table",
+		Up: dbutil.Exec(`CREATE TABLE IF NOT EXISTS results (
+    id VARCHAR(255) PRIMARY KEY,
+    time TIMESTAMP NOT NULL,
+    proto_json TEXT NOT NULL
+);`),
+	},
+	{
+		Version:     2,
+		Description: "Add generated columns for the error and the cells match result",
+		Up: dbutil.Steps(
+			dbutil.AddColumns("results",
+				dbutil.Column{Name: "error", Definition: "TEXT GENERATED ALWAYS AS (NULLIF(json_extract(proto_json, '$.error'), '')) VIRTUAL"},
+				dbutil.Column{Name: "cells_match_result", Definition: "TEXT GENERATED ALWAYS AS (json_extract(proto_json, '$.cellsMatchResult')) VIRTUAL"},
+			),
+			dbutil.Exec(
+				"CREATE INDEX IF NOT EXISTS idx_results_time ON results (time);",
+				"CREATE INDEX IF NOT EXISTS idx_results_cells_match_result ON results (cells_match_result);",
+			),
+		),
+	},
+}
+
+// pgSessionsMigrations are the migrations for the sessions table in Postgres. Postgres was supported after the
+// SQLite schema had evolved so the first migration creates the current schema.
+var pgSessionsMigrations = []dbutil.Migration{
+	{
+		Version:     1,
+		Description: "Create the sessions table",
+		Up: dbutil.Exec(`CREATE TABLE IF NOT EXISTS sessions (
+    contextID VARCHAR(255) PRIMARY KEY,
+    startTime TIMESTAMPTZ NOT NULL,
+    endTime TIMESTAMPTZ NOT NULL,
+    selectedID VARCHAR(255) NOT NULL,
+    selectedKind VARCHAR(255) NOT NULL,
+    total_input_tokens BIGINT NOT NULL,
+    total_output_tokens BIGINT NOT NULL,
+    num_generate_traces BIGINT NOT NULL,
+    proto BYTEA,
+    total_cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+    notebook_uri VARCHAR(1024) NOT NULL DEFAULT '',
+    suggestion_status VARCHAR(255) NOT NULL DEFAULT '',
+    executed BIGINT NOT NULL DEFAULT 0,
+    cell_text TEXT NOT NULL DEFAULT '',
+    model VARCHAR(255) NOT NULL DEFAULT '',
+    rag_enabled BIGINT NOT NULL DEFAULT 0,
+    seconds_to_accept DOUBLE PRECISION NOT NULL DEFAULT 0,
+    execute_failed BIGINT NOT NULL DEFAULT 0,
+    proto_json JSONB NOT NULL DEFAULT '{}'
+);`,
+			"CREATE INDEX IF NOT EXISTS idx_sessions_start_time ON sessions (startTime);",
+			"CREATE INDEX IF NOT EXISTS idx_sessions_notebook_uri ON sessions (notebook_uri);",
+			"CREATE INDEX IF NOT EXISTS idx_sessions_suggestion_status ON sessions (suggestion_status);",
+			"CREATE INDEX IF NOT EXISTS idx_sessions_executed ON sessions (executed);",
+			"CREATE INDEX IF NOT EXISTS idx_sessions_model ON sessions (model);",
+		),
+	},
+}
+
+// pgResultsMigrations are the migrations for the eval results table in Postgres.
+var pgResultsMigrations = []dbutil.Migration{
+	{
+		Version:     1,
+		Description: "Create the results table",
+		Up: dbutil.Exec(`CREATE TABLE IF NOT EXISTS results (
+    id VARCHAR(255) PRIMARY KEY,
+    time TIMESTAMPTZ NOT NULL,
+    proto_json JSONB NOT NULL,
+    error TEXT GENERATED ALWAYS AS (NULLIF(proto_json ->> 'error', '')) STORED,
+    cells_match_result TEXT GENERATED ALWAYS AS (proto_json ->> 'cellsMatchResult') STORED
+);`,
+			"CREATE INDEX IF NOT EXISTS idx_results_time ON results (time);",
+			"CREATE INDEX IF NOT EXISTS idx_results_cells_match_result ON results (cells_match_result);",
+		),
+	},
+}
+
+// MigrateSessionsDB brings the schema of the sessions table up to date.
+func MigrateSessionsDB(ctx context.Context, store *Store) error {
+	return store.migrate(ctx, sessionsComponent, sessionsMigrations, pgSessionsMigrations)
+}
+
+// MigrateResultsDB brings the schema of the eval results table up to date.
+// N.B. The results migrations are here because the results and sessions share the fsql package and its schema.
+func MigrateResultsDB(ctx context.Context, store *Store) error {
+	return store.migrate(ctx, resultsComponent, resultsMigrations, pgResultsMigrations)
+}
+
+// backfillSearchColumns computes the search columns of the existing sessions from their protos.
+// N.B. Backfills only write the columns their migration added because later migrations haven't been applied yet.
+func backfillSearchColumns(ctx context.Context, tx *sql.Tx) error {
+	return forEachSessionProto(ctx, tx, func(contextID string, session *logspb.Session) error {
+		row, err := protoToRow(session)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE sessions SET notebook_uri = ?, suggestion_status = ?, executed = ?, cell_text = ?,
+    model = ?, rag_enabled = ?, seconds_to_accept = ?, execute_failed = ? WHERE contextID = ?`,
+			row.NotebookUri, row.SuggestionStatus, row.Executed, row.CellText, row.Model, row.RagEnabled, row.SecondsToAccept, row.ExecuteFailed, contextID)
+		return errors.Wrapf(err, "Failed to backfill session %s", contextID)
+	})
+}
+
+// backfillProtoJSON serializes the protos of the existing sessions to JSON.
+func backfillProtoJSON(ctx context.Context, tx *sql.Tx) error {
+	return forEachSessionProto(ctx, tx, func(contextID string, session *logspb.Session) error {
+		b, err := protojson.Marshal(session)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to serialize session %s to JSON", contextID)
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE sessions SET proto_json = ? WHERE contextID = ?", string(b), contextID)
+		return errors.Wrapf(err, "Failed to backfill session %s", contextID)
+	})
+}
+
+// forEachSessionProto calls update with each session in the table. Sessions that can't be deserialized are skipped.
+func forEachSessionProto(ctx context.Context, tx *sql.Tx, update func(contextID string, session *logspb.Session) error) error {
+	log := logs.FromContext(ctx)
+	rows, err := fsql.New(tx).ListSessionProtos(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to list sessions to backfill")
+	}
+	log.Info("Backfilling sessions", "numSessions", len(rows))
+	for _, r := range rows {
+		session := &logspb.Session{}
+		if err := proto.Unmarshal(r.Proto, session); err != nil {
+			log.Error(err, "Failed to deserialize session; it won't be backfilled", "contextId", r.Contextid)
+			continue
+		}
+		if err := update(r.Contextid, session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+)
+
+// Test_MigrationsMatchSchema checks that the schemas sqlc generates code from match the schemas created by the
+// migrations.
+func Test_MigrationsMatchSchema(t *testing.T) {
+	schemas := map[dbutil.Dialect]string{
+		dbutil.SQLite:   filepath.Join("fsql", "schema.sql"),
+		dbutil.Postgres: filepath.Join("fsql", "pgsql", "schema.sql"),
+	}
+	for _, dialect := range []dbutil.Dialect{dbutil.SQLite, dbutil.Postgres} {
+		t.Run(string(dialect), func(t *testing.T) {
+			ddl, err := os.ReadFile(schemas[dialect])
+			if err != nil {
+				t.Fatalf("Failed to read schema: %v", err)
+			}
+			fromSchema := newTestStore(t, dialect)
+			if _, err := fromSchema.DB().Exec(string(ddl)); err != nil {
+				t.Fatalf("Failed to create schema: %v", err)
+			}
+
+			migrated := newTestStore(t, dialect)
+			// Apply the migrations twice to check applying them again is a no-op.
+			for i := 0; i < 2; i++ {
+				if err := MigrateSessionsDB(context.Background(), migrated); err != nil {
+					t.Fatalf("Failed to migrate sessions: %+v", err)
+				}
+				if err := MigrateResultsDB(context.Background(), migrated); err != nil {
+					t.Fatalf("Failed to migrate results: %+v", err)
+				}
+			}
+
+			for _, table := range []string{"sessions", "results"} {
+				if d := cmp.Diff(describeTable(t, fromSchema, table), describeTable(t, migrated, table)); d != "" {
+					t.Errorf("The schema doesn't match the migrations for table %s:\n%s", table, d)
+				}
+			}
+
+			expected := len(sessionsMigrations)
+			if dialect == dbutil.Postgres {
+				expected = len(pgSessionsMigrations)
+			}
+			version, err := dbutil.MigrationVersion(context.Background(), migrated.DB(), dialect, sessionsComponent)
+			if err != nil {
+				t.Fatalf("Failed to get version: %v", err)
+			}
+			if version != expected {
+				t.Errorf("Expected sessions version %d; got %d", expected, version)
+			}
+		})
+	}
+}
+
+// describeTable returns a description of the columns and indexes of the table.
+func describeTable(t *testing.T, store *Store, table string) []string {
+	columnsQuery := fmt.Sprintf("SELECT name, type, \"notnull\", COALESCE(dflt_value, ''), pk, hidden FROM pragma_table_xinfo('%s')", table)
+	indexesQuery := "SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL ORDER BY name"
+	if store.Dialect() == dbutil.Postgres {
+		columnsQuery = fmt.Sprintf(`SELECT column_name, data_type, is_nullable, COALESCE(column_default, ''), is_generated,
+    COALESCE(generation_expression, '') FROM information_schema.columns WHERE table_name = '%s' ORDER BY ordinal_position`, table)
+		indexesQuery = "SELECT indexname FROM pg_indexes WHERE tablename = ? ORDER BY indexname"
+	}
+
+	desc := make([]string, 0, 30)
+	rows, err := store.DB().Query(columnsQuery)
+	if err != nil {
+		t.Fatalf("Failed to describe table %s: %v", table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		values := make([]string, 6)
+		if err := rows.Scan(&values[0], &values[1], &values[2], &values[3], &values[4], &values[5]); err != nil {
+			t.Fatalf("Failed to read columns: %v", err)
+		}
+		desc = append(desc, "column "+strings.Join(values, " "))
+	}
+
+	indexes, err := store.DB().Query(dbutil.Rebind(store.Dialect(), indexesQuery), table)
+	if err != nil {
+		t.Fatalf("Failed to list indexes: %v", err)
+	}
+	defer indexes.Close()
+	for indexes.Next() {
+		var name string
+		if err := indexes.Scan(&name); err != nil {
+			t.Fatalf("Failed to read indexes: %v", err)
+		}
+		desc = append(desc, "index "+name)
+	}
+	return desc
+}
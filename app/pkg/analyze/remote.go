@@ -0,0 +1,88 @@
+package analyze
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/logs/logspbconnect"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	remoteTimeout = 30 * time.Second
+)
+
+// remoteKV is a read only dbutil.KV that reads the values from the LogsService of a running server. Pebble databases
+// can only be opened by one process so commands use it to read the traces and blocks while the server is running.
+// Only Get is supported because the LogsService only looks up values by key.
+type remoteKV struct {
+	name string
+	get  func(ctx context.Context, key string) (proto.Message, error)
+}
+
+var _ dbutil.KV = (*remoteKV)(nil)
+
+// NewRemoteTracesDB returns a read only KV of the traces stored by the server.
+func NewRemoteTracesDB(client logspbconnect.LogsServiceClient) dbutil.KV {
+	return &remoteKV{
+		name: "traces",
+		get: func(ctx context.Context, key string) (proto.Message, error) {
+			resp, err := client.GetTrace(ctx, connect.NewRequest(&logspb.GetTraceRequest{Id: key}))
+			if err != nil {
+				return nil, err
+			}
+			return resp.Msg.GetTrace(), nil
+		},
+	}
+}
+
+// NewRemoteBlocksDB returns a read only KV of the blocks stored by the server.
+func NewRemoteBlocksDB(client logspbconnect.LogsServiceClient) dbutil.KV {
+	return &remoteKV{
+		name: "blocks",
+		get: func(ctx context.Context, key string) (proto.Message, error) {
+			resp, err := client.GetBlockLog(ctx, connect.NewRequest(&logspb.GetBlockLogRequest{Id: key}))
+			if err != nil {
+				return nil, err
+			}
+			return resp.Msg.GetBlockLog(), nil
+		},
+	}
+}
+
+func (r *remoteKV) Get(key []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteTimeout)
+	defer cancel()
+	msg, err := r.get(ctx, string(key))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			return nil, errors.Wrapf(dbutil.ErrNotFound, "key %s", string(key))
+		}
+		return nil, errors.Wrapf(err, "Failed to get key %s from the %s on the server", string(key), r.name)
+	}
+	return proto.Marshal(msg)
+}
+
+func (r *remoteKV) Set(key []byte, value []byte) error {
+	return errors.Wrapf(dbutil.ErrReadOnly, "the %s on the server can't be modified", r.name)
+}
+
+func (r *remoteKV) Delete(keys ...[]byte) error {
+	return errors.Wrapf(dbutil.ErrReadOnly, "the %s on the server can't be modified", r.name)
+}
+
+func (r *remoteKV) Apply(batch *dbutil.Batch) error {
+	return errors.Wrapf(dbutil.ErrReadOnly, "the %s on the server can't be modified", r.name)
+}
+
+func (r *remoteKV) Scan(prefix []byte, visit func(key []byte, value []byte) error) error {
+	return errors.Errorf("Scanning the %s on the server isn't supported; stop the server to read the database directly", r.name)
+}
+
+func (r *remoteKV) Close() error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package analyze
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/logs/logspbconnect"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func Test_RemoteKV(t *testing.T) {
+	tracesDB := dbutil.NewMemKV()
+	blocksDB := dbutil.NewMemKV()
+
+	trace := &logspb.Trace{Id: "test-trace"}
+	if err := dbutil.SetProto(tracesDB, trace.GetId(), trace); err != nil {
+		t.Fatalf("Failed to set trace: %+v", err)
+	}
+	block := &logspb.BlockLog{Id: "test-block", GenTraceId: trace.GetId()}
+	if err := dbutil.SetProto(blocksDB, block.GetId(), block); err != nil {
+		t.Fatalf("Failed to set block: %+v", err)
+	}
+
+	handler, err := NewCrudHandler(config.Config{}, blocksDB, tracesDB, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %+v", err)
+	}
+	path, h := logspbconnect.NewLogsServiceHandler(handler)
+	mux := http.NewServeMux()
+	mux.Handle(path, h)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := logspbconnect.NewLogsServiceClient(server.Client(), server.URL)
+	remoteTraces := NewRemoteTracesDB(client)
+	remoteBlocks := NewRemoteBlocksDB(client)
+
+	gotTrace := &logspb.Trace{}
+	if err := dbutil.GetProto(remoteTraces, trace.GetId(), gotTrace); err != nil {
+		t.Fatalf("Failed to get trace: %+v", err)
+	}
+	if d := cmp.Diff(trace, gotTrace, protocmp.Transform()); d != "" {
+		t.Errorf("Unexpected trace:\n%s", d)
+	}
+
+	gotBlock := &logspb.BlockLog{}
+	if err := dbutil.GetProto(remoteBlocks, block.GetId(), gotBlock); err != nil {
+		t.Fatalf("Failed to get block: %+v", err)
+	}
+	if d := cmp.Diff(block, gotBlock, protocmp.Transform()); d != "" {
+		t.Errorf("Unexpected block:\n%s", d)
+	}
+
+	if _, err := remoteTraces.Get([]byte("missing")); !errors.Is(err, dbutil.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound; got %v", err)
+	}
+	if err := remoteBlocks.Set([]byte("b"), []byte("value")); !errors.Is(err, dbutil.ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly; got %v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/gemini"
 	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 	"github.com/pkg/errors"
 	"github.com/sashabaranov/go-openai"
@@ -69,7 +70,8 @@ func RenderRequestHTML(jsonValue string, provider api.ModelProvider) (string, er
 
 	var data *TemplateData
 	switch provider {
-	case api.ModelProviderOpenAI:
+	// Replicate serves models using an OpenAI compatible API.
+	case api.ModelProviderOpenAI, api.ModelProviderReplicate:
 		req := &openai.ChatCompletionRequest{}
 		if err := json.Unmarshal([]byte(jsonValue), req); err != nil {
 			return "", errors.Wrapf(err, "failed to unmarshal request to openai.ChatCompletionRequest; json: %s", jsonValue)
@@ -81,6 +83,12 @@ func RenderRequestHTML(jsonValue string, provider api.ModelProvider) (string, er
 			return "", errors.Wrapf(err, "failed to unmarshal request to anthropic.MessagesRequest; json: %s", jsonValue)
 		}
 		data = anthropicRequestToTemplateData(req)
+	case api.ModelProviderGemini:
+		req := &gemini.GenerateContentRequest{}
+		if err := json.Unmarshal([]byte(jsonValue), req); err != nil {
+			return "", errors.Wrapf(err, "failed to unmarshal request to gemini.GenerateContentRequest; json: %s", jsonValue)
+		}
+		data = geminiRequestToTemplateData(req)
 	default:
 		return fmt.Sprintf("<html><body><h1>Unsupported provider: %v</h1></body></html>", provider), nil
 	}
@@ -100,7 +108,8 @@ func RenderResponseHTML(jsonValue string, provider api.ModelProvider) (string, e
 
 	var data *ResponseTemplateData
 	switch provider {
-	case api.ModelProviderOpenAI:
+	// Replicate serves models using an OpenAI compatible API.
+	case api.ModelProviderOpenAI, api.ModelProviderReplicate:
 		req := &openai.ChatCompletionResponse{}
 		if err := json.Unmarshal([]byte(jsonValue), req); err != nil {
 			return "", errors.Wrapf(err, "failed to unmarshal request to openai.ChatCompletionResponse; json: %s", jsonValue)
@@ -112,6 +121,12 @@ func RenderResponseHTML(jsonValue string, provider api.ModelProvider) (string, e
 			return "", errors.Wrapf(err, "failed to unmarshal request to anthropic.MessagesRequest; json: %s", jsonValue)
 		}
 		data = anthropicResponseToTemplateData(req)
+	case api.ModelProviderGemini:
+		resp := &gemini.GenerateContentResponse{}
+		if err := json.Unmarshal([]byte(jsonValue), resp); err != nil {
+			return "", errors.Wrapf(err, "failed to unmarshal response to gemini.GenerateContentResponse; json: %s", jsonValue)
+		}
+		data = geminiResponseToTemplateData(resp)
 	default:
 		return fmt.Sprintf("<html><body><h1>Unsupported provider: %v</h1></body></html>", provider), nil
 	}
@@ -259,6 +274,64 @@ func oaiResponseToTemplateData(resp *openai.ChatCompletionResponse) *ResponseTem
 	return data
 }
 
+func geminiRequestToTemplateData(request *gemini.GenerateContentRequest) *TemplateData {
+	data := &TemplateData{
+		Model:    request.Model,
+		Messages: make([]Message, 0, len(request.Contents)),
+	}
+	if c := request.GenerationConfig; c != nil {
+		data.Tokens = c.MaxOutputTokens
+		if c.Temperature != nil {
+			data.Temperature = float64(*c.Temperature)
+		}
+	}
+	if request.SystemInstruction != nil {
+		data.System = request.SystemInstruction.Text()
+	}
+
+	md := converter()
+	for _, content := range request.Contents {
+		data.Messages = append(data.Messages, Message{
+			Role:    content.Role,
+			Content: markdownToHTML(md, content.Text()),
+		})
+	}
+	return data
+}
+
+func geminiResponseToTemplateData(resp *gemini.GenerateContentResponse) *ResponseTemplateData {
+	data := &ResponseTemplateData{
+		Model:        resp.ModelVersion,
+		InputTokens:  resp.UsageMetadata.PromptTokenCount,
+		OutputTokens: resp.UsageMetadata.CandidatesTokenCount,
+		Messages:     make([]Message, 0, len(resp.Candidates)),
+	}
+
+	if len(resp.Candidates) > 0 {
+		data.StopReason = resp.Candidates[0].FinishReason
+		data.Role = resp.Candidates[0].Content.Role
+	}
+
+	md := converter()
+	for _, c := range resp.Candidates {
+		data.Messages = append(data.Messages, Message{
+			Content: markdownToHTML(md, c.Content.Text()),
+		})
+	}
+	return data
+}
+
+// markdownToHTML converts the markdown in an LLM request or response to HTML.
+func markdownToHTML(md goldmark.Markdown, content string) template.HTML {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(escapePromptTags(content)), &buf); err != nil {
+		log := zapr.NewLogger(zap.L())
+		log.Error(err, "Failed to convert markdown to HTML")
+		buf.WriteString(fmt.Sprintf("Failed to convert markdown to HTML: error %+v", err))
+	}
+	return template.HTML(buf.String())
+}
+
 func converter() goldmark.Markdown {
 	md := goldmark.New()
 	return md
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/gemini"
 
 	"github.com/liushuangls/go-anthropic/v2"
 	"github.com/pkg/browser"
@@ -32,6 +34,11 @@ func TestRenderAnthropicRequest(t *testing.T) {
 			fname:    "anthropic_request.json",
 			provider: api.ModelProviderAnthropic,
 		},
+		{
+			name:     "gemini",
+			fname:    "gemini_request.json",
+			provider: api.ModelProviderGemini,
+		},
 	}
 
 	cwd, err := os.Getwd()
@@ -132,6 +139,39 @@ func TestRenderAnthropicResponse(t *testing.T) {
 	}
 }
 
+func TestRenderGeminiResponse(t *testing.T) {
+	resp := &gemini.GenerateContentResponse{
+		ModelVersion: "gemini-1.5-flash-002",
+		Candidates: []gemini.Candidate{
+			{
+				Content: gemini.Content{
+					Role:  gemini.RoleModel,
+					Parts: []gemini.Part{{Text: "This is the response message"}},
+				},
+				FinishReason: "STOP",
+			},
+		},
+		UsageMetadata: gemini.UsageMetadata{
+			PromptTokenCount:     103,
+			CandidatesTokenCount: 105,
+		},
+	}
+	jsonData, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+
+	result, err := RenderResponseHTML(string(jsonData), api.ModelProviderGemini)
+	if err != nil {
+		t.Fatalf("Failed to render response: %v", err)
+	}
+	for _, want := range []string{"This is the response message", "gemini-1.5-flash-002", "STOP"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected the rendered response to contain %q; got:\n%s", want, result)
+		}
+	}
+}
+
 func Test_escapePrompt(t *testing.T) {
 	type testCase struct {
 		name     string
@@ -2,12 +2,15 @@ package analyze
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"google.golang.org/protobuf/proto"
 
 	"github.com/go-logr/zapr"
 	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/app/pkg/logs"
 	"github.com/jlewi/foyle/app/pkg/logs/matchers"
 	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
@@ -19,11 +22,13 @@ import (
 // sessionBuilder build sessions by processing log entries.
 type sessionBuilder struct {
 	sessions *SessionsManager
+	pricing  *llms.PriceTable
 }
 
-func NewSessionBuilder(sessions *SessionsManager) (*sessionBuilder, error) {
+func NewSessionBuilder(sessions *SessionsManager, pricing *llms.PriceTable) (*sessionBuilder, error) {
 	return &sessionBuilder{
 		sessions: sessions,
+		pricing:  pricing,
 	}, nil
 }
 
@@ -37,7 +42,7 @@ func (p *sessionBuilder) processLogEntry(entry *api.LogEntry, notifier PostSessi
 		p.processLogEvent(entry, notifier)
 	}
 
-	if matchers.IsLLMUsage(entry.Function()) {
+	if _, ok := entry.Get(matchers.LLMCallField); ok || matchers.IsLLMUsage(entry.Function()) {
 		p.processLLMUsage(entry)
 	}
 
@@ -48,6 +53,12 @@ func (p *sessionBuilder) processLogEntry(entry *api.LogEntry, notifier PostSessi
 	if matchers.IsStreamGenerate(entry.Function()) {
 		p.processStreamGenerate(entry)
 	}
+
+	if strings.Contains(entry.Function(), getExamplesFunction) {
+		if contextId, ok := entry.GetString("contextId"); ok {
+			p.setRAGEnabled(contextId)
+		}
+	}
 }
 
 func (p *sessionBuilder) processLogEvent(entry *api.LogEntry, notifier PostSessionEvent) {
@@ -58,7 +69,13 @@ func (p *sessionBuilder) processLogEvent(entry *api.LogEntry, notifier PostSessi
 		log.Error(errors.New("Failed to decode event"), "Failed to decode LogEvent", "entry", entry)
 		return
 	}
+	p.addLogEvent(event, entry.Time(), notifier)
+}
 
+// addLogEvent updates the session with the log event. It is used both when processing logs and events published
+// on the event bus.
+func (p *sessionBuilder) addLogEvent(event *v1alpha1.LogEvent, eventTime time.Time, notifier PostSessionEvent) {
+	log := zapr.NewLogger(zap.L())
 	// Update the session with the log event
 	if event.GetContextId() == "" {
 		log.Error(errors.New("LogEvent missing ContextId"), "LogEvent missing ContextId", "event", event)
@@ -67,7 +84,7 @@ func (p *sessionBuilder) processLogEvent(entry *api.LogEntry, notifier PostSessi
 
 	var session *logspb.Session
 	updateFunc := func(s *logspb.Session) error {
-		err := updateSessionFromEvent(event, entry.Time(), s)
+		err := updateSessionFromEvent(event, eventTime, s)
 		// Make a copy of the updated session because we will process it down below
 		session = s
 		return err
@@ -87,8 +104,8 @@ func (p *sessionBuilder) processLogEvent(entry *api.LogEntry, notifier PostSessi
 
 func (p *sessionBuilder) processLLMUsage(entry *api.LogEntry) {
 	log := zapr.NewLogger(zap.L())
-	usage := &api.LLMUsage{}
-	if !entry.GetStruct("usage", usage) {
+	usage, ok := getLLMUsage(entry)
+	if !ok {
 		log.Error(errors.New("Failed to decode usage"), "Failed to decode LLMUsage", "entry", entry)
 		return
 	}
@@ -97,9 +114,19 @@ func (p *sessionBuilder) processLLMUsage(entry *api.LogEntry) {
 		log.Error(errors.New("Failed to handle LLMUsage log entry"), "LLMUsage is missing contextId", "entry", entry, "contextId", contextId)
 		return
 	}
+	p.addUsage(contextId, *usage)
+}
+
+// addUsage adds the usage of an LLM and its cost to the session.
+func (p *sessionBuilder) addUsage(contextId string, usage api.LLMUsage) {
+	log := zapr.NewLogger(zap.L())
+	cost, ok := p.pricing.Cost(usage)
+	if !ok {
+		log.V(logs.Debug).Info("No price for model; cost will not be tracked", "provider", usage.Provider, "model", usage.Model)
+	}
 
 	updateFunc := func(s *logspb.Session) error {
-		return updateSessionFromUsage(*usage, s)
+		return updateSessionFromUsage(usage, cost, s)
 	}
 
 	if err := p.sessions.Update(context.Background(), contextId, updateFunc); err != nil {
@@ -120,7 +147,12 @@ func (p *sessionBuilder) processGenerate(entry *api.LogEntry) {
 		return
 
 	}
+	p.addGenerateTrace(contextId, traceId)
+}
 
+// addGenerateTrace adds the id of a generate trace to the session.
+func (p *sessionBuilder) addGenerateTrace(contextId string, traceId string) {
+	log := zapr.NewLogger(zap.L())
 	updateFunc := func(s *logspb.Session) error {
 		if s.GenerateTraceIds == nil {
 			s.GenerateTraceIds = make([]string, 0, 5)
@@ -135,7 +167,6 @@ func (p *sessionBuilder) processGenerate(entry *api.LogEntry) {
 }
 
 func (p *sessionBuilder) processStreamGenerate(entry *api.LogEntry) {
-	log := zapr.NewLogger(zap.L())
 	contextId, ok := entry.GetString("contextId")
 	if !ok {
 		return
@@ -144,7 +175,12 @@ func (p *sessionBuilder) processStreamGenerate(entry *api.LogEntry) {
 	if ok := entry.GetProto("context", fullContext); !ok {
 		return
 	}
+	p.setFullContext(contextId, fullContext)
+}
 
+// setFullContext sets the context sent by the frontend at the start of the stream.
+func (p *sessionBuilder) setFullContext(contextId string, fullContext *v1alpha1.FullContext) {
+	log := zapr.NewLogger(zap.L())
 	updateFunc := func(s *logspb.Session) error {
 		s.FullContext = fullContext
 		return nil
@@ -155,10 +191,27 @@ func (p *sessionBuilder) processStreamGenerate(entry *api.LogEntry) {
 	}
 }
 
-// updateSessionFromUsage updates the session with the usage
-func updateSessionFromUsage(usage api.LLMUsage, s *logspb.Session) error {
+// setRAGEnabled records that examples were retrieved to include in the prompt for a completion in the session.
+func (p *sessionBuilder) setRAGEnabled(contextId string) {
+	log := zapr.NewLogger(zap.L())
+	updateFunc := func(s *logspb.Session) error {
+		s.RagEnabled = true
+		return nil
+	}
+
+	if err := p.sessions.Update(context.Background(), contextId, updateFunc); err != nil {
+		log.Error(err, "Failed to update session", "contextId", contextId)
+	}
+}
+
+// updateSessionFromUsage updates the session with the usage and its cost in USD
+func updateSessionFromUsage(usage api.LLMUsage, cost float64, s *logspb.Session) error {
 	s.TotalInputTokens = s.GetTotalInputTokens() + int32(usage.InputTokens)
 	s.TotalOutputTokens = s.GetTotalOutputTokens() + int32(usage.OutputTokens)
+	s.TotalCostUsd = s.GetTotalCostUsd() + cost
+	if usage.Model != "" {
+		s.Model = usage.Model
+	}
 	return nil
 }
 
@@ -185,6 +238,8 @@ func updateSessionFromEvent(event *v1alpha1.LogEvent, eventTime time.Time, s *lo
 		s.StartTime = timestamppb.New(eventTime)
 	} else if event.Type == v1alpha1.LogEventType_SESSION_END {
 		s.EndTime = timestamppb.New(eventTime)
+	} else if event.Type == v1alpha1.LogEventType_ACCEPTED {
+		s.AcceptTime = timestamppb.New(eventTime)
 	}
 	s.LogEvents = append(s.LogEvents, event)
 	return nil
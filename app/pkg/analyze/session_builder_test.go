@@ -11,12 +11,14 @@ import (
 
 	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 
+	"github.com/jlewi/foyle/app/pkg/dbutil"
 	"github.com/jlewi/foyle/app/pkg/logs/matchers"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/jlewi/foyle/app/api"
 	config "github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/llms"
 	"github.com/jlewi/foyle/app/pkg/logs"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 	"github.com/pkg/errors"
@@ -54,12 +56,12 @@ func setup() (testTuple, error) {
 	if err != nil {
 		return testTuple{}, errors.Wrapf(err, "Failed to open database")
 	}
-	sessions, err := NewSessionsManager(db)
+	sessions, err := NewSessionsManager(NewStore(db, dbutil.SQLite))
 	if err != nil {
 		return testTuple{}, errors.Wrapf(err, "Failed to create session manager")
 	}
 
-	p, err := NewSessionBuilder(sessions)
+	p, err := NewSessionBuilder(sessions, llms.NewPriceTable(nil))
 
 	if err != nil {
 		return testTuple{}, err
@@ -178,6 +180,7 @@ func Test_processLLMUsage(t *testing.T) {
 	type testCase struct {
 		name     string
 		usage    *api.LLMUsage
+		cost     float64
 		session  *logspb.Session
 		expected *logspb.Session
 	}
@@ -210,13 +213,47 @@ func Test_processLLMUsage(t *testing.T) {
 				TotalOutputTokens: 40,
 			},
 		},
+		{
+			name: "cost",
+			usage: &api.LLMUsage{
+				InputTokens:  10,
+				OutputTokens: 20,
+			},
+			cost: 0.5,
+			session: &logspb.Session{
+				TotalInputTokens:  10,
+				TotalOutputTokens: 20,
+				TotalCostUsd:      0.25,
+			},
+			expected: &logspb.Session{
+				TotalInputTokens:  20,
+				TotalOutputTokens: 40,
+				TotalCostUsd:      0.75,
+			},
+		},
+		{
+			name: "model",
+			usage: &api.LLMUsage{
+				InputTokens:  10,
+				OutputTokens: 20,
+				Model:        "gpt-4o",
+			},
+			session: &logspb.Session{
+				Model: "gpt-3.5-turbo",
+			},
+			expected: &logspb.Session{
+				TotalInputTokens:  10,
+				TotalOutputTokens: 20,
+				Model:             "gpt-4o",
+			},
+		},
 	}
 
 	opts := cmpopts.IgnoreUnexported(logspb.Session{})
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			if err := updateSessionFromUsage(*c.usage, c.session); err != nil {
+			if err := updateSessionFromUsage(*c.usage, c.cost, c.session); err != nil {
 				t.Fatalf("Failed to update session: %v", err)
 			}
 
@@ -3,10 +3,10 @@ package analyze
 import (
 	"context"
 	"database/sql"
-	_ "embed"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -25,17 +25,22 @@ import (
 	parserv1 "github.com/stateful/runme/v3/pkg/api/gen/proto/go/runme/parser/v1"
 
 	"github.com/jlewi/foyle/app/pkg/analyze/fsql"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
 	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	_ "modernc.org/sqlite"
 )
 
-//go:embed fsql/schema.sql
-var ddl string
-
 const (
 	SQLLiteDriver = "sqlite"
+
+	// defaultSessionsPageSize is the number of sessions ListSessions returns if the page size isn't set.
+	defaultSessionsPageSize = 25
+	// maxSessionsPageSize is the largest page size ListSessions allows.
+	maxSessionsPageSize = 1000
 )
 
 var (
@@ -58,43 +63,48 @@ var (
 	})
 )
 
-// GetDDL return the DDL for the database.
-// This is a hack because the DDL statements for the sessions and eval results tables are in the same file and package.
-// The Evaluator needs to be able to get the DDL in order to create the eval results table. We should clean this up
-func GetDDL() string {
-	return ddl
-}
-
 // SessionUpdater is a function that updates a session.
 type SessionUpdater func(session *logspb.Session) error
 
 // SessionsManager manages the database containing sessions.
 type SessionsManager struct {
-	queries *fsql.Queries
-	db      *sql.DB
+	queries fsql.Querier
+	store   *Store
 	// Keep track of the number of concurrent calls to Update.
 	// This is intended to try to track down why SQLITE_BUSY errors is so frequent.
 	activeUpdates atomic.Int32
 }
 
-func NewSessionsManager(db *sql.DB) (*SessionsManager, error) {
-	// create tables
-	// TODO(jeremy): I think this creates the evalresults table as well because we don't separate the DDL statements.
-	if _, err := db.ExecContext(context.TODO(), ddl); err != nil {
+func NewSessionsManager(store *Store) (*SessionsManager, error) {
+	if err := MigrateSessionsDB(context.TODO(), store); err != nil {
 		return nil, err
 	}
 
+	if store.Dialect() == dbutil.SQLite {
+		if err := configureSQLite(store.DB()); err != nil {
+			return nil, err
+		}
+	}
+
+	return &SessionsManager{
+		queries: store.Queries(store.DB()),
+		store:   store,
+	}, nil
+}
+
+// configureSQLite sets the options of the SQLite database that reduce contention.
+func configureSQLite(db *sql.DB) error {
 	// Set busy_timeout using PRAGMA. This is to deal with frequent sqlite busy errors when deployed on
 	// Azure.
 	// This is in milliseconds
 	if _, err := db.Exec("PRAGMA busy_timeout = 10000;"); err != nil {
-		return nil, errors.Wrapf(err, "Failed to set busy timeout for the database")
+		return errors.Wrapf(err, "Failed to set busy timeout for the database")
 	}
 	log := zapr.NewLogger(zap.L())
 	log.Info("sqlite busy_timeout set", "timeout", 5000)
 
 	if _, err := db.Exec("PRAGMA busy_timeout = 10000;"); err != nil {
-		return nil, errors.Wrapf(err, "Failed to set busy timeout for the database")
+		return errors.Wrapf(err, "Failed to set busy timeout for the database")
 	}
 
 	// Activate WAL mode. This hopefully helps with SQLITE_BUSY errors and contention by using a separate file
@@ -104,16 +114,9 @@ func NewSessionsManager(db *sql.DB) (*SessionsManager, error) {
 	output, err := db.Exec("PRAGMA journal_mode=WAL;")
 	log.Info("Set journal mode to WAL", "output", output)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Failed to set journal mode to WAL")
+		return errors.Wrapf(err, "Failed to set journal mode to WAL")
 	}
-
-	// Create the dbtx from the actual database
-	queries := fsql.New(db)
-
-	return &SessionsManager{
-		queries: queries,
-		db:      db,
-	}, nil
+	return nil
 }
 
 // Get retrieves a session with the given contextID.
@@ -165,12 +168,12 @@ func (db *SessionsManager) Update(ctx context.Context, contextID string, updateF
 	activeUpdatesGauge.Set(float64(numActive))
 	sessCounter.WithLabelValues("start").Inc()
 
-	// Wrap the updates in a retry loop. This is intended to deal with SQLITE_BUSY errors and other possible sources
-	// of contention
+	// Wrap the updates in a retry loop. This is intended to deal with SQLITE_BUSY errors, Postgres serialization
+	// failures and other possible sources of contention
 	b := backoff.NewExponentialBackOff(backoff.WithMaxElapsedTime(5*time.Minute), backoff.WithMaxInterval(30*time.Second))
 	for {
 		err := func() error {
-			tx, err := db.db.BeginTx(ctx, &sql.TxOptions{})
+			tx, err := db.store.BeginTx(ctx)
 			if err != nil {
 				// See https://go.dev/doc/database/execute-transactions We do not to issue a rollback if BeginTx fails
 				sessCounter.WithLabelValues("failedstart").Inc()
@@ -185,7 +188,7 @@ func (db *SessionsManager) Update(ctx context.Context, contextID string, updateF
 				}
 			}()
 
-			queries := db.queries.WithTx(tx)
+			queries := db.store.Queries(tx)
 			// Read the record
 			sessRow, err := queries.GetSession(ctx, contextID)
 
@@ -222,17 +225,7 @@ func (db *SessionsManager) Update(ctx context.Context, contextID string, updateF
 				return errors.WithStack(errors.Errorf("contextID in session doesn't match contextID. Update was called with contextID: %v but session has contextID: %v", contextID, newRow.Contextid))
 			}
 
-			update := fsql.UpdateSessionParams{
-				Contextid:         contextID,
-				Proto:             newRow.Proto,
-				Starttime:         newRow.Starttime,
-				Endtime:           newRow.Endtime,
-				Selectedid:        newRow.Selectedid,
-				Selectedkind:      newRow.Selectedkind,
-				TotalInputTokens:  newRow.TotalInputTokens,
-				TotalOutputTokens: newRow.TotalOutputTokens,
-				NumGenerateTraces: newRow.NumGenerateTraces,
-			}
+			update := rowToUpdate(contextID, newRow)
 
 			sessCounter.WithLabelValues("callupdatesession").Inc()
 			if err := queries.UpdateSession(ctx, update); err != nil {
@@ -266,6 +259,25 @@ func (db *SessionsManager) Update(ctx context.Context, contextID string, updateF
 	}
 }
 
+// DeleteBefore deletes the sessions that started before the given time and returns the number deleted. If dryRun is
+// true the sessions are only counted.
+func (db *SessionsManager) DeleteBefore(ctx context.Context, t time.Time, dryRun bool) (int64, error) {
+	if dryRun {
+		count, err := db.queries.CountSessionsBefore(ctx, t.UTC())
+		if err != nil {
+			logDBErrors(ctx, err)
+			return 0, errors.Wrapf(err, "Failed to count sessions before %v", t)
+		}
+		return count, nil
+	}
+	count, err := db.queries.DeleteSessionsBefore(ctx, t.UTC())
+	if err != nil {
+		logDBErrors(ctx, err)
+		return 0, errors.Wrapf(err, "Failed to delete sessions before %v", t)
+	}
+	return count, nil
+}
+
 func (m *SessionsManager) GetSession(ctx context.Context, request *connect.Request[logspb.GetSessionRequest]) (*connect.Response[logspb.GetSessionResponse], error) {
 	log := logs.FromContext(ctx)
 
@@ -284,10 +296,16 @@ func (m *SessionsManager) GetSession(ctx context.Context, request *connect.Reque
 	}), nil
 }
 
+// ListSessions lists the sessions matching the filters in the request most recent first. The next_page_token in the
+// response is the contextId of the last session; it is empty once there are no more sessions.
 func (m *SessionsManager) ListSessions(ctx context.Context, request *connect.Request[logspb.ListSessionsRequest]) (*connect.Response[logspb.ListSessionsResponse], error) {
 	log := logs.FromContext(ctx)
 	queries := m.queries
-	dbSessions, err := queries.ListSessions(ctx)
+	params, err := searchParams(request.Msg)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	dbSessions, err := queries.SearchSessions(ctx, params)
 	if err != nil {
 		log.Error(err, "Failed to list sessions")
 		return nil, connect.NewError(connect.CodeInternal, errors.Wrapf(err, "Failed to  list sessions"))
@@ -296,6 +314,9 @@ func (m *SessionsManager) ListSessions(ctx context.Context, request *connect.Req
 	resp := &logspb.ListSessionsResponse{
 		Sessions: make([]*logspb.Session, 0, len(dbSessions)),
 	}
+	if int64(len(dbSessions)) == params.PageSize {
+		resp.NextPageToken = dbSessions[len(dbSessions)-1].Contextid
+	}
 	for _, s := range dbSessions {
 		sess := &logspb.Session{}
 		if err := proto.Unmarshal(s.Proto, sess); err != nil {
@@ -386,6 +407,93 @@ func (m *SessionsManager) DumpExamples(ctx context.Context, request *connect.Req
 	}
 }
 
+// timeParam returns the value to bind for an optional time filter; "" if the time isn't set.
+// The driver stores timestamps as strings. Binding a time.Time formats it the same way so for UTC times the
+// string comparison orders them correctly.
+func timeParam(t *timestamppb.Timestamp) interface{} {
+	if t == nil {
+		return ""
+	}
+	return t.AsTime().UTC()
+}
+
+// searchParams converts the request into the parameters of the SearchSessions query. Filters that aren't set are
+// mapped to the zero values the query ignores.
+func searchParams(req *logspb.ListSessionsRequest) (fsql.SearchSessionsParams, error) {
+	pageSize := int64(req.GetPageSize())
+	if pageSize < 0 {
+		return fsql.SearchSessionsParams{}, errors.Errorf("page_size must be non-negative; got %d", pageSize)
+	}
+	if pageSize == 0 {
+		pageSize = defaultSessionsPageSize
+	}
+	if pageSize > maxSessionsPageSize {
+		pageSize = maxSessionsPageSize
+	}
+
+	suggestionStatus := ""
+	if req.GetSuggestionStatus() != logspb.SuggestionStatus_SuggestionStatusUnknown {
+		suggestionStatus = req.GetSuggestionStatus().String()
+	}
+
+	executed := int64(-1)
+	switch req.GetExecution() {
+	case logspb.ExecutionFilter_EXECUTED:
+		executed = 1
+	case logspb.ExecutionFilter_NOT_EXECUTED:
+		executed = 0
+	}
+
+	return fsql.SearchSessionsParams{
+		Cursor:           req.GetPageToken(),
+		StartTime:        timeParam(req.GetStartTime()),
+		EndTime:          timeParam(req.GetEndTime()),
+		NotebookUri:      req.GetNotebookUri(),
+		SuggestionStatus: suggestionStatus,
+		Executed:         executed,
+		MinInputTokens:   int64(req.GetMinInputTokens()),
+		MaxInputTokens:   int64(req.GetMaxInputTokens()),
+		MinOutputTokens:  int64(req.GetMinOutputTokens()),
+		MaxOutputTokens:  int64(req.GetMaxOutputTokens()),
+		Query:            escapeLike(req.GetQuery()),
+		PageSize:         pageSize,
+	}, nil
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern so the query is matched literally. The queries use \ as the
+// escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike escapes s so it can be used in a LIKE pattern with ESCAPE '\'.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// rowToUpdate returns the parameters to write the row.
+func rowToUpdate(contextID string, row *fsql.Session) fsql.UpdateSessionParams {
+	return fsql.UpdateSessionParams{
+		Contextid:         contextID,
+		Proto:             row.Proto,
+		Starttime:         row.Starttime,
+		Endtime:           row.Endtime,
+		Selectedid:        row.Selectedid,
+		Selectedkind:      row.Selectedkind,
+		TotalInputTokens:  row.TotalInputTokens,
+		TotalOutputTokens: row.TotalOutputTokens,
+		NumGenerateTraces: row.NumGenerateTraces,
+		TotalCostUsd:      row.TotalCostUsd,
+		NotebookUri:       row.NotebookUri,
+		SuggestionStatus:  row.SuggestionStatus,
+		Executed:          row.Executed,
+		CellText:          row.CellText,
+		Model:             row.Model,
+		RagEnabled:        row.RagEnabled,
+		SecondsToAccept:   row.SecondsToAccept,
+		ExecuteFailed:     row.ExecuteFailed,
+		ProtoJson:         row.ProtoJson,
+	}
+}
+
 // protoToRow converts from the proto representation of a session to the database row representation.
 //
 // TODO(jeremy): I think it would be better to make the return type fsql.UpdateSessionParams. Right now the only
@@ -397,6 +505,10 @@ func protoToRow(session *logspb.Session) (*fsql.Session, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to serialize session")
 	}
+	protoJSON, err := protojson.Marshal(session)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to serialize session to JSON")
+	}
 
 	selectedId := ""
 	selectedKind := ""
@@ -411,6 +523,39 @@ func protoToRow(session *logspb.Session) (*fsql.Session, error) {
 		}
 	}
 
+	// The last accept or reject event determines whether the suggestion was accepted.
+	suggestionStatus := logspb.SuggestionStatus_SuggestionStatusUnknown
+	var executed int64
+	var executeFailed int64
+	for _, e := range session.GetLogEvents() {
+		switch e.GetType() {
+		case v1alpha1.LogEventType_ACCEPTED:
+			suggestionStatus = logspb.SuggestionStatus_ACCEPTED
+		case v1alpha1.LogEventType_REJECTED:
+			suggestionStatus = logspb.SuggestionStatus_REJECTED
+		case v1alpha1.LogEventType_EXECUTE:
+			executed = 1
+			if e.GetExecuteStatus() == v1alpha1.LogEvent_FAILED {
+				executeFailed = 1
+			}
+		}
+	}
+
+	var secondsToAccept float64
+	if suggestionStatus == logspb.SuggestionStatus_ACCEPTED && session.GetAcceptTime() != nil && session.GetStartTime() != nil {
+		secondsToAccept = session.GetAcceptTime().AsTime().Sub(session.GetStartTime().AsTime()).Seconds()
+	}
+
+	var ragEnabled int64
+	if session.GetRagEnabled() {
+		ragEnabled = 1
+	}
+
+	cellText := make([]string, 0, len(session.GetFullContext().GetNotebook().GetCells()))
+	for _, cell := range session.GetFullContext().GetNotebook().GetCells() {
+		cellText = append(cellText, cell.GetValue())
+	}
+
 	return &fsql.Session{
 		Contextid:         session.ContextId,
 		Starttime:         session.StartTime.AsTime(),
@@ -421,6 +566,16 @@ func protoToRow(session *logspb.Session) (*fsql.Session, error) {
 		TotalInputTokens:  int64(session.TotalInputTokens),
 		TotalOutputTokens: int64(session.TotalOutputTokens),
 		NumGenerateTraces: int64(len(session.GenerateTraceIds)),
+		TotalCostUsd:      session.TotalCostUsd,
+		NotebookUri:       session.GetFullContext().GetNotebookUri(),
+		SuggestionStatus:  suggestionStatus.String(),
+		Executed:          executed,
+		CellText:          strings.Join(cellText, "\n"),
+		Model:             session.GetModel(),
+		RagEnabled:        ragEnabled,
+		SecondsToAccept:   secondsToAccept,
+		ExecuteFailed:     executeFailed,
+		ProtoJson:         string(protoJSON),
 	}, nil
 }
 
@@ -460,14 +615,18 @@ func getExampleFromSession(s *logspb.Session) (*v1alpha1.EvalExample, error) {
 	}
 
 	// Rebuild the context. For a LogExecuteEvent the FullContext will contain the entire notebook
-	// and the selectedID and selected cell should be the cell that is being executed. But we only want to use
-	// All the cells before the current cell
+	// and the selectedID and selected cell should be the cell that is being executed. We remove the executed cell
+	// since that is what we want to predict. We keep the cells after it so the example can be used to evaluate
+	// fill in the middle prompts; the agent ignores them unless fill in the middle is enabled.
 
 	newContext := proto.Clone(s.GetFullContext()).(*v1alpha1.FullContext)
-	// Only the cells up to the executed cell should be included
-	newContext.Notebook.Cells = newContext.Notebook.Cells[:executeEvent.SelectedIndex]
-	// Set the selected cell to the last one in the notebook.
-	newContext.Selected = int32(len(newContext.Notebook.Cells) - 1)
+	cells := newContext.Notebook.Cells
+	if int(executeEvent.SelectedIndex) >= len(cells) {
+		return nil, errors.Errorf("Selected cell %v is out of bounds; the notebook has %v cells", executeEvent.SelectedIndex, len(cells))
+	}
+	newContext.Notebook.Cells = append(cells[:executeEvent.SelectedIndex], cells[executeEvent.SelectedIndex+1:]...)
+	// Set the selected cell to the cell preceding the executed cell.
+	newContext.Selected = executeEvent.SelectedIndex - 1
 
 	// We need to get the actual cell that was executed from the execute event because the context won't be up todate.
 	// The executedCell should be the last one in the event. Some additional context might be sent
@@ -9,9 +9,12 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/jlewi/foyle/app/pkg/analyze/fsql"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
 	"github.com/jlewi/foyle/app/pkg/runme/converters"
 	parserv1 "github.com/stateful/runme/v3/pkg/api/gen/proto/go/runme/parser/v1"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -22,54 +25,46 @@ import (
 )
 
 func Test_SessionsCRUD(t *testing.T) {
-	dir, err := temp.CreateTempDir("sessionsDBTest")
-	if err != nil {
-		t.Fatalf("Error creating temp dir: %v", err)
-	}
+	forEachDialect(t, func(t *testing.T, store *Store) {
+		m, err := NewSessionsManager(store)
+		if err != nil {
+			t.Fatalf("Error creating SessionsManager: %v", err)
+		}
 
-	dbPath := filepath.Join(dir.Name, "sessions.db")
-	db, err := sql.Open(SQLLiteDriver, dbPath)
-	if err != nil {
-		t.Fatalf("Error opening database: %v", err)
-	}
-	m, err := NewSessionsManager(db)
-	if err != nil {
-		t.Fatalf("Error creating SessionsManager: %v", err)
-	}
+		cid := "1"
 
-	cid := "1"
-
-	expected := &logspb.Session{
-		ContextId: cid,
-		StartTime: timestamppb.New(time.Now()),
-		EndTime:   timestamppb.New(time.Now().Add(time.Hour)),
-		LogEvents: []*v1alpha1.LogEvent{
-			{
-				Type: v1alpha1.LogEventType_ACCEPTED,
+		expected := &logspb.Session{
+			ContextId: cid,
+			StartTime: timestamppb.New(time.Now()),
+			EndTime:   timestamppb.New(time.Now().Add(time.Hour)),
+			LogEvents: []*v1alpha1.LogEvent{
+				{
+					Type: v1alpha1.LogEventType_ACCEPTED,
+				},
 			},
-		},
-	}
+		}
 
-	if err := m.Update(context.Background(), cid, func(s *logspb.Session) error {
-		s.ContextId = cid
-		s.StartTime = expected.StartTime
-		s.EndTime = expected.EndTime
-		s.LogEvents = expected.LogEvents
-		return nil
-	}); err != nil {
-		t.Fatalf("Error updating session: %v", err)
-	}
+		if err := m.Update(context.Background(), cid, func(s *logspb.Session) error {
+			s.ContextId = cid
+			s.StartTime = expected.StartTime
+			s.EndTime = expected.EndTime
+			s.LogEvents = expected.LogEvents
+			return nil
+		}); err != nil {
+			t.Fatalf("Error updating session: %v", err)
+		}
 
-	// TODO(jeremy): Try to read the session back and verify it was written correctly.
-	actual, err := m.Get(context.Background(), cid)
-	if err != nil {
-		t.Fatalf("Error getting session: %v", err)
-	}
+		// TODO(jeremy): Try to read the session back and verify it was written correctly.
+		actual, err := m.Get(context.Background(), cid)
+		if err != nil {
+			t.Fatalf("Error getting session: %v", err)
+		}
 
-	comparer := cmpopts.IgnoreUnexported(logspb.Session{}, v1alpha1.LogEvent{}, timestamppb.Timestamp{})
-	if d := cmp.Diff(actual, expected, comparer); d != "" {
-		t.Fatalf("Unexpected diff between expected and actual session:\n%v", d)
-	}
+		comparer := cmpopts.IgnoreUnexported(logspb.Session{}, v1alpha1.LogEvent{}, timestamppb.Timestamp{})
+		if d := cmp.Diff(actual, expected, comparer); d != "" {
+			t.Fatalf("Unexpected diff between expected and actual session:\n%v", d)
+		}
+	})
 }
 
 var (
@@ -92,7 +87,7 @@ var (
 					},
 					{
 						Kind:  parserv1.CellKind_CELL_KIND_MARKUP,
-						Value: "This cell should not be in the response because it is after the executed cell",
+						Value: "This cell is after the executed cell; it is kept as the suffix",
 					},
 				},
 			},
@@ -134,6 +129,10 @@ func Test_getExampleFromSession(t *testing.T) {
 								Kind:  parserv1.CellKind_CELL_KIND_MARKUP,
 								Value: "This is cell 1",
 							},
+							{
+								Kind:  parserv1.CellKind_CELL_KIND_MARKUP,
+								Value: "This cell is after the executed cell; it is kept as the suffix",
+							},
 						},
 					},
 					Selected: 0,
@@ -167,42 +166,40 @@ func Test_DumpExamples(t *testing.T) {
 	// This test verifies the logic to dump the examples from the database.
 	// It doesn't test the logic to convert sessions to examples because that is tested via the Test_getExampleFromSession
 	// unittest
-	dir, err := temp.CreateTempDir("sessionsDBTest")
-	if err != nil {
-		t.Fatalf("Error creating temp dir: %v", err)
-	}
+	forEachDialect(t, func(t *testing.T, store *Store) {
+		dir, err := temp.CreateTempDir("sessionsDBTest")
+		if err != nil {
+			t.Fatalf("Error creating temp dir: %v", err)
+		}
 
-	db, err := sql.Open(SQLLiteDriver, filepath.Join(dir.Name, "sessions.db"))
-	if err != nil {
-		t.Fatalf("Error opening database: %v", err)
-	}
-	manager, err := NewSessionsManager(db)
+		manager, err := NewSessionsManager(store)
 
-	if err != nil {
-		t.Fatalf("Error creating SessionsManager: %v", err)
-	}
-	// Write some sessions to the database
-	if err := manager.Update(context.Background(), session1.ContextId, func(s *logspb.Session) error {
-		s.FullContext = session1.FullContext
-		s.LogEvents = session1.LogEvents
-		s.ContextId = session1.ContextId
-		return nil
-	}); err != nil {
-		t.Fatalf("Error writing sessions to the DB: %v", err)
-	}
+		if err != nil {
+			t.Fatalf("Error creating SessionsManager: %v", err)
+		}
+		// Write some sessions to the database
+		if err := manager.Update(context.Background(), session1.ContextId, func(s *logspb.Session) error {
+			s.FullContext = session1.FullContext
+			s.LogEvents = session1.LogEvents
+			s.ContextId = session1.ContextId
+			return nil
+		}); err != nil {
+			t.Fatalf("Error writing sessions to the DB: %v", err)
+		}
 
-	request := &logspb.DumpExamplesRequest{
-		Output: filepath.Join(dir.Name, "examples"),
-	}
-	resp, err := manager.DumpExamples(context.Background(), connect.NewRequest(request))
+		request := &logspb.DumpExamplesRequest{
+			Output: filepath.Join(dir.Name, "examples"),
+		}
+		resp, err := manager.DumpExamples(context.Background(), connect.NewRequest(request))
 
-	if err != nil {
-		t.Fatalf("Error dumping examples: %v", err)
-	}
-	if resp.Msg.GetNumExamples() != 1 {
-		t.Fatalf("Expected 1 example but got %v", resp.Msg.GetNumExamples())
-	}
-	t.Logf("Dumped examples to: %v", request.GetOutput())
+		if err != nil {
+			t.Fatalf("Error dumping examples: %v", err)
+		}
+		if resp.Msg.GetNumExamples() != 1 {
+			t.Fatalf("Expected 1 example but got %v", resp.Msg.GetNumExamples())
+		}
+		t.Logf("Dumped examples to: %v", request.GetOutput())
+	})
 }
 
 func Test_protoToRow(t *testing.T) {
@@ -231,12 +228,15 @@ func Test_protoToRow(t *testing.T) {
 			name:    "Basic",
 			session: session1,
 			expected: &fsql.Session{
-				Contextid:    "1",
-				Starttime:    session1.GetStartTime().AsTime(),
-				Endtime:      session1.GetEndTime().AsTime(),
-				Selectedid:   "0123345-id",
-				Selectedkind: "CELL_KIND_CODE",
-				Proto:        sess1Bytes,
+				Contextid:        "1",
+				Starttime:        session1.GetStartTime().AsTime(),
+				Endtime:          session1.GetEndTime().AsTime(),
+				Selectedid:       "0123345-id",
+				Selectedkind:     "CELL_KIND_CODE",
+				Proto:            sess1Bytes,
+				SuggestionStatus: "SuggestionStatusUnknown",
+				Executed:         1,
+				CellText:         "This is cell 1\nThis should not be the answer\nThis cell is after the executed cell; it is kept as the suffix",
 			},
 		},
 		{
@@ -249,6 +249,7 @@ func Test_protoToRow(t *testing.T) {
 				TotalInputTokens:  11,
 				TotalOutputTokens: 10,
 				NumGenerateTraces: 2,
+				SuggestionStatus:  "SuggestionStatusUnknown",
 			},
 		},
 	}
@@ -267,10 +268,274 @@ func Test_protoToRow(t *testing.T) {
 				}
 				c.expected.Proto = b
 			}
+			// protojson deliberately doesn't produce stable output so check the JSON by deserializing it.
 			comparer := cmpopts.IgnoreUnexported(fsql.Session{}, time.Time{})
-			if d := cmp.Diff(actual, c.expected, comparer); d != "" {
+			if d := cmp.Diff(actual, c.expected, comparer, cmpopts.IgnoreFields(fsql.Session{}, "ProtoJson")); d != "" {
 				t.Fatalf("Unexpected diff between expected and actual session:\n%v", d)
 			}
+			fromJSON := &logspb.Session{}
+			if err := protojson.Unmarshal([]byte(actual.ProtoJson), fromJSON); err != nil {
+				t.Fatalf("Error unmarshalling proto_json: %v", err)
+			}
+			if d := cmp.Diff(c.session, fromJSON, protocmp.Transform()); d != "" {
+				t.Errorf("Unexpected diff in proto_json:\n%v", d)
+			}
 		})
 	}
 }
+
+func Test_SessionsAddCostColumn(t *testing.T) {
+	dir, err := temp.CreateTempDir("sessionsDBMigrate")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %v", err)
+	}
+
+	db, err := sql.Open(SQLLiteDriver, filepath.Join(dir.Name, "sessions.db"))
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+
+	// Create the table as it was before the total_cost_usd column was added.
+	oldSchema := `CREATE TABLE sessions (
+    contextID VARCHAR(255) PRIMARY KEY,
+    startTime TIMESTAMP NOT NULL,
+    endTime TIMESTAMP NOT NULL,
+    selectedID VARCHAR(255) NOT NULL,
+    selectedKind VARCHAR(255) NOT NULL,
+    total_input_tokens INT NOT NULL,
+    total_output_tokens INT NOT NULL,
+    num_generate_traces INT NOT NULL,
+    proto BLOB
+);`
+	if _, err := db.Exec(oldSchema); err != nil {
+		t.Fatalf("Error creating old schema: %v", err)
+	}
+
+	m, err := NewSessionsManager(NewStore(db, dbutil.SQLite))
+	if err != nil {
+		t.Fatalf("Error creating SessionsManager: %v", err)
+	}
+
+	cid := "1"
+	if err := m.Update(context.Background(), cid, func(s *logspb.Session) error {
+		s.ContextId = cid
+		s.TotalCostUsd = 0.25
+		return nil
+	}); err != nil {
+		t.Fatalf("Error updating session: %v", err)
+	}
+
+	row, err := m.queries.GetSession(context.Background(), cid)
+	if err != nil {
+		t.Fatalf("Error getting session: %v", err)
+	}
+	if row.TotalCostUsd != 0.25 {
+		t.Errorf("Unexpected total_cost_usd; got %v, want %v", row.TotalCostUsd, 0.25)
+	}
+}
+
+func Test_ListSessionsFilters(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, store *Store) {
+		m, err := NewSessionsManager(store)
+		if err != nil {
+			t.Fatalf("Error creating SessionsManager: %v", err)
+		}
+
+		start := timeMustParse(time.RFC3339, "2024-01-01T00:00:00Z").AsTime()
+		sessions := []*logspb.Session{
+			{
+				ContextId:        "01A",
+				StartTime:        timestamppb.New(start),
+				TotalInputTokens: 100,
+				FullContext: &v1alpha1.FullContext{
+					NotebookUri: "file:///notebook1.md",
+					Notebook:    &parserv1.Notebook{Cells: []*parserv1.Cell{{Value: "List the Pods"}}},
+				},
+				LogEvents: []*v1alpha1.LogEvent{{Type: v1alpha1.LogEventType_ACCEPTED}, {Type: v1alpha1.LogEventType_EXECUTE}},
+			},
+			{
+				ContextId:        "01B",
+				StartTime:        timestamppb.New(start.Add(time.Hour)),
+				TotalInputTokens: 2000,
+				FullContext: &v1alpha1.FullContext{
+					NotebookUri: "file:///notebook2.md",
+					Notebook:    &parserv1.Notebook{Cells: []*parserv1.Cell{{Value: "gcloud builds list --filter=limit_percent>90%"}}},
+				},
+				LogEvents: []*v1alpha1.LogEvent{{Type: v1alpha1.LogEventType_REJECTED}},
+			},
+			{
+				ContextId:        "01C",
+				StartTime:        timestamppb.New(start.Add(2 * time.Hour)),
+				TotalInputTokens: 300,
+				FullContext: &v1alpha1.FullContext{
+					NotebookUri: "file:///notebook1.md",
+					Notebook:    &parserv1.Notebook{Cells: []*parserv1.Cell{{Value: "kubectl get pods"}}},
+				},
+			},
+		}
+		for _, s := range sessions {
+			s := s
+			if err := m.Update(context.Background(), s.ContextId, func(session *logspb.Session) error {
+				proto.Merge(session, s)
+				return nil
+			}); err != nil {
+				t.Fatalf("Error updating session: %v", err)
+			}
+		}
+
+		type testCase struct {
+			name     string
+			request  *logspb.ListSessionsRequest
+			expected []string
+		}
+
+		cases := []testCase{
+			{
+				name:     "all",
+				request:  &logspb.ListSessionsRequest{},
+				expected: []string{"01C", "01B", "01A"},
+			},
+			{
+				name: "time-range",
+				request: &logspb.ListSessionsRequest{
+					StartTime: timestamppb.New(start.Add(30 * time.Minute)),
+					EndTime:   timestamppb.New(start.Add(90 * time.Minute)),
+				},
+				expected: []string{"01B"},
+			},
+			{
+				name:     "notebook",
+				request:  &logspb.ListSessionsRequest{NotebookUri: "file:///notebook1.md"},
+				expected: []string{"01C", "01A"},
+			},
+			{
+				name:     "accepted",
+				request:  &logspb.ListSessionsRequest{SuggestionStatus: logspb.SuggestionStatus_ACCEPTED},
+				expected: []string{"01A"},
+			},
+			{
+				name:     "not-executed",
+				request:  &logspb.ListSessionsRequest{Execution: logspb.ExecutionFilter_NOT_EXECUTED},
+				expected: []string{"01C", "01B"},
+			},
+			{
+				name:     "tokens",
+				request:  &logspb.ListSessionsRequest{MinInputTokens: 200, MaxInputTokens: 1000},
+				expected: []string{"01C"},
+			},
+			{
+				name:     "query",
+				request:  &logspb.ListSessionsRequest{Query: "pods"},
+				expected: []string{"01C", "01A"},
+			},
+			{
+				// Wildcards in the query are matched literally.
+				name:     "query-percent",
+				request:  &logspb.ListSessionsRequest{Query: "%"},
+				expected: []string{"01B"},
+			},
+			{
+				name:     "query-underscore",
+				request:  &logspb.ListSessionsRequest{Query: "t_p"},
+				expected: []string{"01B"},
+			},
+			{
+				name:     "query-backslash",
+				request:  &logspb.ListSessionsRequest{Query: `\%`},
+				expected: []string{},
+			},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				resp, err := m.ListSessions(context.Background(), connect.NewRequest(c.request))
+				if err != nil {
+					t.Fatalf("Error listing sessions: %v", err)
+				}
+				actual := make([]string, 0, len(resp.Msg.Sessions))
+				for _, s := range resp.Msg.Sessions {
+					actual = append(actual, s.ContextId)
+				}
+				if d := cmp.Diff(c.expected, actual); d != "" {
+					t.Errorf("Unexpected sessions:\n%v", d)
+				}
+			})
+		}
+
+		// Page through the sessions.
+		actual := make([]string, 0, len(sessions))
+		token := ""
+		for {
+			resp, err := m.ListSessions(context.Background(), connect.NewRequest(&logspb.ListSessionsRequest{PageSize: 2, PageToken: token}))
+			if err != nil {
+				t.Fatalf("Error listing sessions: %v", err)
+			}
+			for _, s := range resp.Msg.Sessions {
+				actual = append(actual, s.ContextId)
+			}
+			token = resp.Msg.NextPageToken
+			if token == "" {
+				break
+			}
+		}
+		if d := cmp.Diff([]string{"01C", "01B", "01A"}, actual); d != "" {
+			t.Errorf("Unexpected sessions when paging:\n%v", d)
+		}
+	})
+}
+
+func Test_SessionsBackfillSearchColumns(t *testing.T) {
+	db, err := sql.Open(SQLLiteDriver, filepath.Join(t.TempDir(), "sessions.db"))
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the table as it was before the search columns were added.
+	oldSchema := `CREATE TABLE sessions (
+    contextID VARCHAR(255) PRIMARY KEY,
+    startTime TIMESTAMP NOT NULL,
+    endTime TIMESTAMP NOT NULL,
+    selectedID VARCHAR(255) NOT NULL,
+    selectedKind VARCHAR(255) NOT NULL,
+    total_input_tokens INT NOT NULL,
+    total_output_tokens INT NOT NULL,
+    num_generate_traces INT NOT NULL,
+    proto BLOB,
+    total_cost_usd REAL NOT NULL DEFAULT 0
+);`
+	if _, err := db.Exec(oldSchema); err != nil {
+		t.Fatalf("Error creating old schema: %v", err)
+	}
+	session := &logspb.Session{
+		ContextId:   "1",
+		FullContext: &v1alpha1.FullContext{NotebookUri: "file:///notebook.md"},
+	}
+	b, err := proto.Marshal(session)
+	if err != nil {
+		t.Fatalf("Error marshalling session: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO sessions VALUES (?, ?, ?, '', '', 0, 0, 0, ?, 0)", "1", time.Now(), time.Now(), b); err != nil {
+		t.Fatalf("Error inserting session: %v", err)
+	}
+
+	m, err := NewSessionsManager(NewStore(db, dbutil.SQLite))
+	if err != nil {
+		t.Fatalf("Error creating SessionsManager: %v", err)
+	}
+	resp, err := m.ListSessions(context.Background(), connect.NewRequest(&logspb.ListSessionsRequest{NotebookUri: "file:///notebook.md"}))
+	if err != nil {
+		t.Fatalf("Error listing sessions: %v", err)
+	}
+	if len(resp.Msg.Sessions) != 1 {
+		t.Errorf("Expected the session to be backfilled; got %d sessions", len(resp.Msg.Sessions))
+	}
+
+	var uri string
+	if err := db.QueryRow("SELECT json_extract(proto_json, '$.fullContext.notebookUri') FROM sessions WHERE contextID = '1'").Scan(&uri); err != nil {
+		t.Fatalf("Error querying proto_json: %v", err)
+	}
+	if uri != "file:///notebook.md" {
+		t.Errorf("Expected proto_json to be backfilled; got notebookUri %q", uri)
+	}
+}
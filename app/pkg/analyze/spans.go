@@ -5,24 +5,101 @@ import (
 	"strings"
 
 	"github.com/jlewi/foyle/app/pkg/logs/matchers"
-	"google.golang.org/protobuf/proto"
 
 	"github.com/jlewi/foyle/app/api"
 	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 )
 
+// getExamplesFunction is the function that logs the examples retrieved for RAG.
+const getExamplesFunction = "learn.(*InMemoryExampleDB).GetExamples"
+
 func logEntryToSpan(ctx context.Context, e *api.LogEntry) *logspb.Span {
-	if strings.Contains(e.Function(), "learn.(*InMemoryExampleDB).GetExamples") {
+	if strings.Contains(e.Function(), getExamplesFunction) {
 		return logEntryToRAGSpan(ctx, e)
 	}
 
+	if call, ok := getLLMCall(e); ok {
+		return llmCallToSpan(call)
+	}
+
 	if matchers.IsOAIComplete(e.Function()) || matchers.IsAnthropicComplete(e.Function()) {
 		return logEntryToLLMSpan(ctx, e)
 	}
+
+	routeSpan := &logspb.LLMSpan{}
+	if e.GetProto(matchers.LLMRouteField, routeSpan) {
+		// The model chosen by the router is combined with the request and response by combineSpans.
+		return &logspb.Span{
+			Data: &logspb.Span_Llm{
+				Llm: routeSpan,
+			},
+		}
+	}
+
+	toolSpan := &logspb.ToolSpan{}
+	if e.GetProto(matchers.ToolSpanField, toolSpan) {
+		return &logspb.Span{
+			Data: &logspb.Span_Tool{
+				Tool: toolSpan,
+			},
+		}
+	}
+
+	reductionSpan := &logspb.OutputReductionSpan{}
+	if e.GetProto(matchers.OutputReductionField, reductionSpan) {
+		return &logspb.Span{
+			Data: &logspb.Span_Reduction{
+				Reduction: reductionSpan,
+			},
+		}
+	}
 	return nil
 }
 
+// getLLMCall returns the LLMCall logged by a completer if the entry has one.
+func getLLMCall(e *api.LogEntry) (*api.LLMCall, bool) {
+	if _, ok := e.Get(matchers.LLMCallField); !ok {
+		return nil, false
+	}
+	call := &api.LLMCall{}
+	if !e.GetStruct(matchers.LLMCallField, call) {
+		return nil, false
+	}
+	return call, true
+}
+
+// getLLMUsage returns the usage of an LLM if the entry records one. Usage is part of the LLMCall logged by
+// completers; older logs recorded it with logs.LogLLMUsage.
+func getLLMUsage(e *api.LogEntry) (*api.LLMUsage, bool) {
+	if call, ok := getLLMCall(e); ok {
+		return &call.Usage, true
+	}
+	if !matchers.IsLLMUsage(e.Function()) {
+		return nil, false
+	}
+	usage := &api.LLMUsage{}
+	if !e.GetStruct("usage", usage) {
+		return nil, false
+	}
+	return usage, true
+}
+
+func llmCallToSpan(call *api.LLMCall) *logspb.Span {
+	return &logspb.Span{
+		Data: &logspb.Span_Llm{
+			Llm: &logspb.LLMSpan{
+				Provider:     api.ModelProviderAPIToProto(call.Provider),
+				Model:        call.Model,
+				RequestJson:  string(call.Request),
+				ResponseJson: string(call.Response),
+				LatencyMs:    call.LatencyMs,
+			},
+		},
+	}
+}
+
+// logEntryToLLMSpan converts the request and response logged by completers before they logged an LLMCall.
 func logEntryToLLMSpan(ctx context.Context, e *api.LogEntry) *logspb.Span {
 	provider := v1alpha1.ModelProvider_MODEL_PROVIDER_UNKNOWN
 	if matchers.IsOAIComplete(e.Function()) {
@@ -98,7 +175,7 @@ func combineSpans(trace *logspb.Trace) {
 	trace.Spans = make([]*logspb.Span, 0, len(oldSpans))
 
 	var ragSpan *logspb.RAGSpan
-	var llmSpan *logspb.LLMSpan
+	var llmSpans []*logspb.LLMSpan
 
 	for _, s := range oldSpans {
 		if s.GetRag() != nil {
@@ -108,11 +185,7 @@ func combineSpans(trace *logspb.Trace) {
 				ragSpan = combineRAGSpans(ragSpan, s.GetRag())
 			}
 		} else if s.GetLlm() != nil {
-			if llmSpan == nil {
-				llmSpan = s.GetLlm()
-			} else {
-				llmSpan = combineLLMSpans(llmSpan, s.GetLlm())
-			}
+			llmSpans = combineLLMSpans(llmSpans, s.GetLlm())
 		} else {
 			trace.Spans = append(trace.Spans, s)
 		}
@@ -126,7 +199,7 @@ func combineSpans(trace *logspb.Trace) {
 		})
 	}
 
-	if llmSpan != nil {
+	for _, llmSpan := range llmSpans {
 		trace.Spans = append(trace.Spans, &logspb.Span{
 			Data: &logspb.Span_Llm{
 				Llm: llmSpan,
@@ -151,8 +224,33 @@ func combineRAGSpans(a, b *logspb.RAGSpan) *logspb.RAGSpan {
 	return span
 }
 
-// combine two LLMSpans
-func combineLLMSpans(a, b *logspb.LLMSpan) *logspb.LLMSpan {
-	proto.Merge(a, b)
-	return a
+// combineLLMSpans adds span to spans. Each call to an LLM has its own span; e.g. when the agent uses tools there is
+// a span for every round of the conversation. Spans that are only part of a call are merged into the span of the
+// call they belong to; i.e. the response of older logs which logged the request and response on separate lines and
+// the model chosen by the router which is logged after the call it routed.
+func combineLLMSpans(spans []*logspb.LLMSpan, span *logspb.LLMSpan) []*logspb.LLMSpan {
+	if len(spans) == 0 {
+		return append(spans, span)
+	}
+	last := spans[len(spans)-1]
+
+	isRoute := span.GetRoute() != "" && span.GetRequestJson() == "" && span.GetResponseJson() == ""
+	if isRoute && last.GetRoute() == "" {
+		if last.Model == "" {
+			last.Model = span.GetModel()
+		}
+		last.Route = span.GetRoute()
+		last.Attempts = span.GetAttempts()
+		return spans
+	}
+
+	isResponse := span.GetRequestJson() == "" && span.GetResponseJson() != ""
+	if isResponse && last.GetRequestJson() != "" && last.GetResponseJson() == "" {
+		last.ResponseJson = span.GetResponseJson()
+		if last.Provider == v1alpha1.ModelProvider_MODEL_PROVIDER_UNKNOWN {
+			last.Provider = span.GetProvider()
+		}
+		return spans
+	}
+	return append(spans, span)
 }
@@ -3,14 +3,20 @@ package analyze
 import (
 	"context"
 	"encoding/json"
+	"os"
 	"testing"
 
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"github.com/jlewi/foyle/app/pkg/logs"
 	"github.com/jlewi/foyle/app/pkg/logs/matchers"
 	"github.com/sashabaranov/go-openai"
+	"go.uber.org/zap"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/gemini"
 	"github.com/jlewi/foyle/app/pkg/testutil"
 	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
@@ -58,6 +64,49 @@ func Test_logEntryToSpan(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "RouteSpan",
+			logLine: `{"severity":"info","time":1717094160.1880581,"caller":"router/completer.go:150","function":"github.com/jlewi/foyle/app/pkg/router.(*Completer).route","message":"Router completion","traceId":"3fe82dae88bca105b92aee98c7f48228","model":"claude-3-5-sonnet-20240620","provider":"anthropic","route":"fallback","attempts":2,"llmRoute":{"model":"claude-3-5-sonnet-20240620","route":"fallback","attempts":2}}`,
+			expected: &logspb.Span{
+				Data: &logspb.Span_Llm{
+					Llm: &logspb.LLMSpan{
+						Model:    "claude-3-5-sonnet-20240620",
+						Route:    "fallback",
+						Attempts: 2,
+					},
+				},
+			},
+		},
+		{
+			name:    "ToolSpan",
+			logLine: `{"severity":"info","time":1717094160.1880581,"caller":"agent/tools.go:161","function":"github.com/jlewi/foyle/app/pkg/agent.(*Agent).runTool.func1","message":"Agent tool call","traceId":"3fe82dae88bca105b92aee98c7f48228","tool":"run_command","command":"kubectl get pods","allowed":true,"toolSpan":{"name":"run_command","arguments":"{\"command\":\"kubectl get pods\"}","command":"kubectl get pods","allowed":true,"exitCode":1,"output":"exitCode: 1\n"}}`,
+			expected: &logspb.Span{
+				Data: &logspb.Span_Tool{
+					Tool: &logspb.ToolSpan{
+						Name:      "run_command",
+						Arguments: `{"command":"kubectl get pods"}`,
+						Command:   "kubectl get pods",
+						Allowed:   true,
+						ExitCode:  1,
+						Output:    "exitCode: 1\n",
+					},
+				},
+			},
+		},
+		{
+			name:    "OutputReductionSpan",
+			logLine: `{"severity":"info","time":1717094160.1880581,"caller":"docs/tailer.go:90","function":"github.com/jlewi/foyle/app/pkg/docs.NewFIMTailer","message":"Reduced output","traceId":"3fe82dae88bca105b92aee98c7f48228","blockId":"01J","strategy":"smart","outputReduction":{"blockId":"01J","strategy":"smart","originalLength":12000,"reducedLength":4000}}`,
+			expected: &logspb.Span{
+				Data: &logspb.Span_Reduction{
+					Reduction: &logspb.OutputReductionSpan{
+						BlockId:        "01J",
+						Strategy:       "smart",
+						OriginalLength: 12000,
+						ReducedLength:  4000,
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -67,7 +116,7 @@ func Test_logEntryToSpan(t *testing.T) {
 				t.Fatalf("Failed to unmarshal log line: %v", err)
 			}
 			span := logEntryToSpan(context.Background(), e)
-			if d := cmp.Diff(tc.expected, span, cmpopts.IgnoreUnexported(logspb.Span{}, logspb.RAGSpan{}, v1alpha1.RAGResult{}, v1alpha1.Example{}), testutil.DocComparer); d != "" {
+			if d := cmp.Diff(tc.expected, span, cmpopts.IgnoreUnexported(logspb.Span{}, logspb.RAGSpan{}, logspb.LLMSpan{}, logspb.ToolSpan{}, logspb.OutputReductionSpan{}, v1alpha1.RAGResult{}, v1alpha1.Example{}), testutil.DocComparer); d != "" {
 				t.Fatalf("Unexpected diff:\n%v", d)
 			}
 		})
@@ -154,8 +203,8 @@ func Test_logEntrytoLLMSpan(t *testing.T) {
 				t.Fatalf("Expected LLM span")
 			}
 
-			if span.GetLlm().GetProvider() != v1alpha1.ModelProvider_OPEN_AI {
-				t.Fatalf("Expected OpenAI provider")
+			if span.GetLlm().GetProvider() != tc.expected.GetLlm().GetProvider() {
+				t.Fatalf("Expected provider %v; got %v", tc.expected.GetLlm().GetProvider(), span.GetLlm().GetProvider())
 			}
 
 			// The JSON serialization of the proto isn't deterministic so we can't use cmp.diff to evaluate the response
@@ -249,14 +298,132 @@ func Test_CombineSpans(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "llm-call-per-round",
+			trace: &logspb.Trace{
+				Spans: []*logspb.Span{
+					{Data: &logspb.Span_Llm{Llm: &logspb.LLMSpan{Model: "gpt-4o", RequestJson: "request1", ResponseJson: "response1"}}},
+					{Data: &logspb.Span_Llm{Llm: &logspb.LLMSpan{Model: "gpt-4o", Route: "primary", Attempts: 1}}},
+					{Data: &logspb.Span_Tool{Tool: &logspb.ToolSpan{Command: "ls"}}},
+					{Data: &logspb.Span_Llm{Llm: &logspb.LLMSpan{Model: "gpt-4o", RequestJson: "request2", ResponseJson: "response2"}}},
+					{Data: &logspb.Span_Llm{Llm: &logspb.LLMSpan{Model: "gpt-4o", Route: "primary", Attempts: 1}}},
+				},
+			},
+			expected: &logspb.Trace{
+				Spans: []*logspb.Span{
+					{Data: &logspb.Span_Tool{Tool: &logspb.ToolSpan{Command: "ls"}}},
+					{Data: &logspb.Span_Llm{Llm: &logspb.LLMSpan{Model: "gpt-4o", RequestJson: "request1", ResponseJson: "response1", Route: "primary", Attempts: 1}}},
+					{Data: &logspb.Span_Llm{Llm: &logspb.LLMSpan{Model: "gpt-4o", RequestJson: "request2", ResponseJson: "response2", Route: "primary", Attempts: 1}}},
+				},
+			},
+		},
+		{
+			name: "legacy-request-and-response",
+			trace: &logspb.Trace{
+				Spans: []*logspb.Span{
+					{Data: &logspb.Span_Llm{Llm: &logspb.LLMSpan{Provider: v1alpha1.ModelProvider_OPEN_AI, RequestJson: "request"}}},
+					{Data: &logspb.Span_Llm{Llm: &logspb.LLMSpan{Provider: v1alpha1.ModelProvider_OPEN_AI, ResponseJson: "response"}}},
+					{Data: &logspb.Span_Llm{Llm: &logspb.LLMSpan{Model: "gpt-4o", Route: "fallback", Attempts: 2}}},
+				},
+			},
+			expected: &logspb.Trace{
+				Spans: []*logspb.Span{
+					{Data: &logspb.Span_Llm{Llm: &logspb.LLMSpan{Provider: v1alpha1.ModelProvider_OPEN_AI, Model: "gpt-4o", RequestJson: "request", ResponseJson: "response", Route: "fallback", Attempts: 2}}},
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			combineSpans(tc.trace)
-			if d := cmp.Diff(tc.expected, tc.trace, cmpopts.IgnoreUnexported(logspb.Trace{}, logspb.Span{}, logspb.RAGSpan{}, v1alpha1.RAGResult{}, v1alpha1.Example{}), testutil.DocComparer); d != "" {
+			if d := cmp.Diff(tc.expected, tc.trace, cmpopts.IgnoreUnexported(logspb.Trace{}, logspb.Span{}, logspb.RAGSpan{}, logspb.LLMSpan{}, logspb.ToolSpan{}, v1alpha1.RAGResult{}, v1alpha1.Example{}), testutil.DocComparer); d != "" {
 				t.Fatalf("Unexpected diff:\n%v", d)
 			}
 		})
 	}
 }
+
+func Test_LLMCall(t *testing.T) {
+	// Log an LLMCall and make sure the analyzer can turn it into a span and usage without knowing the provider.
+	oFile, err := os.CreateTemp("", "llmcall.json")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	outputName := oFile.Name()
+	defer os.Remove(outputName)
+	if err := oFile.Close(); err != nil {
+		t.Fatalf("Failed to close file: %v", err)
+	}
+
+	c := zap.NewProductionConfig()
+	c.OutputPaths = []string{outputName}
+	logger, err := c.Build()
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	ctx := logr.NewContext(context.Background(), zapr.NewLogger(logger))
+
+	request := gemini.GenerateContentRequest{
+		Contents: []gemini.Content{{Role: gemini.RoleUser, Parts: []gemini.Part{{Text: "list the pods"}}}},
+	}
+	response := &gemini.GenerateContentResponse{
+		Candidates: []gemini.Candidate{{Content: gemini.Content{Role: gemini.RoleModel, Parts: []gemini.Part{{Text: "kubectl get pods"}}}}},
+	}
+	usage := api.LLMUsage{
+		InputTokens:  10,
+		OutputTokens: 5,
+		Model:        "gemini-1.5-flash",
+		Provider:     string(api.ModelProviderGemini),
+	}
+	logs.LogLLMCall(ctx, api.LLMCall{
+		Provider:  api.ModelProviderGemini,
+		Model:     "gemini-1.5-flash",
+		Usage:     usage,
+		LatencyMs: 250,
+	}, request, response)
+	_ = logger.Sync()
+
+	b, err := os.ReadFile(outputName)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	entry := &api.LogEntry{}
+	if err := json.Unmarshal(b, entry); err != nil {
+		t.Fatalf("Failed to unmarshal log entry: %v", err)
+	}
+
+	span := logEntryToSpan(ctx, entry)
+	if span.GetLlm() == nil {
+		t.Fatalf("Expected LLM span; got %v", span)
+	}
+	llm := span.GetLlm()
+	if llm.GetProvider() != v1alpha1.ModelProvider_GEMINI {
+		t.Errorf("Expected provider GEMINI; got %v", llm.GetProvider())
+	}
+	if llm.GetModel() != "gemini-1.5-flash" {
+		t.Errorf("Expected model gemini-1.5-flash; got %v", llm.GetModel())
+	}
+	if llm.GetLatencyMs() != 250 {
+		t.Errorf("Expected latency 250; got %v", llm.GetLatencyMs())
+	}
+
+	actualRequest := gemini.GenerateContentRequest{}
+	if err := json.Unmarshal([]byte(llm.GetRequestJson()), &actualRequest); err != nil {
+		t.Fatalf("Failed to unmarshal request: %v", err)
+	}
+	if d := cmp.Diff(request, actualRequest); d != "" {
+		t.Errorf("Unexpected diff in request:\n%s", d)
+	}
+	if _, err := RenderResponseHTML(llm.GetResponseJson(), api.ModelProviderProtoToAPI(llm.GetProvider())); err != nil {
+		t.Errorf("Failed to render response: %v", err)
+	}
+
+	actualUsage, ok := getLLMUsage(entry)
+	if !ok {
+		t.Fatalf("Expected usage")
+	}
+	if d := cmp.Diff(usage, *actualUsage); d != "" {
+		t.Errorf("Unexpected diff in usage:\n%s", d)
+	}
+}
@@ -0,0 +1,82 @@
+package analyze
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/jlewi/foyle/app/pkg/analyze/fsql"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/pkg/errors"
+)
+
+// StatsService computes statistics about how completions are used from the sessions database.
+type StatsService struct {
+	sessions *SessionsManager
+}
+
+// NewStatsService creates a StatsService that reads the sessions managed by sessions.
+func NewStatsService(sessions *SessionsManager) (*StatsService, error) {
+	if sessions == nil {
+		return nil, errors.New("sessions must be non nil")
+	}
+	return &StatsService{sessions: sessions}, nil
+}
+
+// GetSessionStats returns the statistics for the sessions matching the request grouped by the requested dimension.
+func (s *StatsService) GetSessionStats(ctx context.Context, request *connect.Request[logspb.GetSessionStatsRequest]) (*connect.Response[logspb.GetSessionStatsResponse], error) {
+	log := logs.FromContext(ctx)
+	req := request.Msg
+	dimension := req.GetDimension()
+	if dimension == logspb.StatsDimension_STATS_DIMENSION_UNSPECIFIED {
+		dimension = logspb.StatsDimension_DAY
+	}
+
+	rows, err := s.sessions.queries.SessionStats(ctx, fsql.SessionStatsParams{
+		Dimension:   dimension.String(),
+		StartTime:   timeParam(req.GetStartTime()),
+		EndTime:     timeParam(req.GetEndTime()),
+		NotebookUri: req.GetNotebookUri(),
+	})
+	if err != nil {
+		log.Error(err, "Failed to compute session stats")
+		return nil, connect.NewError(connect.CodeInternal, errors.Wrapf(err, "Failed to compute session stats"))
+	}
+
+	resp := &logspb.GetSessionStatsResponse{
+		Stats: make([]*logspb.SessionStats, 0, len(rows)),
+	}
+	for _, r := range rows {
+		resp.Stats = append(resp.Stats, rowToStats(r))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// rowToStats converts the counts computed by the database into SessionStats and computes the rates.
+func rowToStats(r fsql.SessionStatsRow) *logspb.SessionStats {
+	return &logspb.SessionStats{
+		Value:                 r.Value,
+		NumSessions:           r.NumSessions,
+		NumSuggested:          r.NumSuggested,
+		NumAccepted:           r.NumAccepted,
+		NumRejected:           r.NumRejected,
+		NumSuggestedExecuted:  r.NumSuggestedExecuted,
+		NumExecuted:           r.NumExecuted,
+		NumExecuteFailed:      r.NumExecuteFailed,
+		TotalInputTokens:      r.TotalInputTokens,
+		TotalOutputTokens:     r.TotalOutputTokens,
+		AcceptanceRate:        ratio(float64(r.NumAccepted), r.NumSuggested),
+		ExecutionRate:         ratio(float64(r.NumSuggestedExecuted), r.NumSuggested),
+		FailureRate:           ratio(float64(r.NumExecuteFailed), r.NumExecuted),
+		MeanSecondsToAccept:   ratio(r.TotalSecondsToAccept, r.NumTimedAccepts),
+		TokensPerAcceptedCell: ratio(float64(r.TotalInputTokens+r.TotalOutputTokens), r.NumAccepted),
+	}
+}
+
+// ratio returns n/d or 0 if d is 0.
+func ratio(n float64, d int64) float64 {
+	if d == 0 {
+		return 0
+	}
+	return n / float64(d)
+}
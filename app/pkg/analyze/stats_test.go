@@ -0,0 +1,198 @@
+package analyze
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/go-cmp/cmp"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func Test_GetSessionStats(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, store *Store) {
+		m, err := NewSessionsManager(store)
+		if err != nil {
+			t.Fatalf("Error creating SessionsManager: %v", err)
+		}
+		stats, err := NewStatsService(m)
+		if err != nil {
+			t.Fatalf("Error creating StatsService: %v", err)
+		}
+
+		day1 := timeMustParse(time.RFC3339, "2024-01-01T10:00:00Z").AsTime()
+		day2 := day1.Add(24 * time.Hour)
+		sessions := []*logspb.Session{
+			{
+				ContextId:         "01A",
+				StartTime:         timestamppb.New(day1),
+				AcceptTime:        timestamppb.New(day1.Add(10 * time.Second)),
+				TotalInputTokens:  100,
+				TotalOutputTokens: 20,
+				GenerateTraceIds:  []string{"t1"},
+				Model:             "gpt-4o",
+				RagEnabled:        true,
+				FullContext:       &v1alpha1.FullContext{NotebookUri: "file:///notebook1.md"},
+				LogEvents: []*v1alpha1.LogEvent{
+					{Type: v1alpha1.LogEventType_ACCEPTED},
+					{Type: v1alpha1.LogEventType_EXECUTE, ExecuteStatus: v1alpha1.LogEvent_FAILED},
+				},
+			},
+			{
+				ContextId:         "01B",
+				StartTime:         timestamppb.New(day1.Add(time.Hour)),
+				AcceptTime:        timestamppb.New(day1.Add(time.Hour + 30*time.Second)),
+				TotalInputTokens:  200,
+				TotalOutputTokens: 40,
+				GenerateTraceIds:  []string{"t2"},
+				Model:             "claude-3-5-sonnet",
+				FullContext:       &v1alpha1.FullContext{NotebookUri: "file:///notebook2.md"},
+				LogEvents: []*v1alpha1.LogEvent{
+					{Type: v1alpha1.LogEventType_ACCEPTED},
+					{Type: v1alpha1.LogEventType_EXECUTE, ExecuteStatus: v1alpha1.LogEvent_SUCCEEDED},
+				},
+			},
+			{
+				ContextId:         "01C",
+				StartTime:         timestamppb.New(day2),
+				TotalInputTokens:  300,
+				TotalOutputTokens: 60,
+				GenerateTraceIds:  []string{"t3"},
+				Model:             "gpt-4o",
+				RagEnabled:        true,
+				FullContext:       &v1alpha1.FullContext{NotebookUri: "file:///notebook1.md"},
+				LogEvents:         []*v1alpha1.LogEvent{{Type: v1alpha1.LogEventType_REJECTED}},
+			},
+			{
+				// A session in which no completion was generated.
+				ContextId:   "01D",
+				StartTime:   timestamppb.New(day2.Add(time.Hour)),
+				FullContext: &v1alpha1.FullContext{NotebookUri: "file:///notebook1.md"},
+				LogEvents:   []*v1alpha1.LogEvent{{Type: v1alpha1.LogEventType_EXECUTE, ExecuteStatus: v1alpha1.LogEvent_SUCCEEDED}},
+			},
+		}
+		for _, s := range sessions {
+			s := s
+			if err := m.Update(context.Background(), s.ContextId, func(session *logspb.Session) error {
+				proto.Merge(session, s)
+				return nil
+			}); err != nil {
+				t.Fatalf("Error updating session: %v", err)
+			}
+		}
+
+		type testCase struct {
+			name     string
+			request  *logspb.GetSessionStatsRequest
+			expected []*logspb.SessionStats
+		}
+
+		cases := []testCase{
+			{
+				name:    "day",
+				request: &logspb.GetSessionStatsRequest{},
+				expected: []*logspb.SessionStats{
+					{
+						Value:                 "2024-01-01",
+						NumSessions:           2,
+						NumSuggested:          2,
+						NumAccepted:           2,
+						NumSuggestedExecuted:  2,
+						NumExecuted:           2,
+						NumExecuteFailed:      1,
+						TotalInputTokens:      300,
+						TotalOutputTokens:     60,
+						AcceptanceRate:        1,
+						ExecutionRate:         1,
+						FailureRate:           0.5,
+						MeanSecondsToAccept:   20,
+						TokensPerAcceptedCell: 180,
+					},
+					{
+						Value:             "2024-01-02",
+						NumSessions:       2,
+						NumSuggested:      1,
+						NumRejected:       1,
+						NumExecuted:       1,
+						TotalInputTokens:  300,
+						TotalOutputTokens: 60,
+					},
+				},
+			},
+			{
+				name:    "rag",
+				request: &logspb.GetSessionStatsRequest{Dimension: logspb.StatsDimension_RAG, EndTime: timestamppb.New(day2.Add(time.Minute))},
+				expected: []*logspb.SessionStats{
+					{
+						Value:                 "disabled",
+						NumSessions:           1,
+						NumSuggested:          1,
+						NumAccepted:           1,
+						NumSuggestedExecuted:  1,
+						NumExecuted:           1,
+						TotalInputTokens:      200,
+						TotalOutputTokens:     40,
+						AcceptanceRate:        1,
+						ExecutionRate:         1,
+						MeanSecondsToAccept:   30,
+						TokensPerAcceptedCell: 240,
+					},
+					{
+						Value:                 "enabled",
+						NumSessions:           2,
+						NumSuggested:          2,
+						NumAccepted:           1,
+						NumRejected:           1,
+						NumSuggestedExecuted:  1,
+						NumExecuted:           1,
+						NumExecuteFailed:      1,
+						TotalInputTokens:      400,
+						TotalOutputTokens:     80,
+						AcceptanceRate:        0.5,
+						ExecutionRate:         0.5,
+						FailureRate:           1,
+						MeanSecondsToAccept:   10,
+						TokensPerAcceptedCell: 480,
+					},
+				},
+			},
+			{
+				name:    "model-for-notebook",
+				request: &logspb.GetSessionStatsRequest{Dimension: logspb.StatsDimension_MODEL, NotebookUri: "file:///notebook2.md"},
+				expected: []*logspb.SessionStats{
+					{
+						Value:                 "claude-3-5-sonnet",
+						NumSessions:           1,
+						NumSuggested:          1,
+						NumAccepted:           1,
+						NumSuggestedExecuted:  1,
+						NumExecuted:           1,
+						TotalInputTokens:      200,
+						TotalOutputTokens:     40,
+						AcceptanceRate:        1,
+						ExecutionRate:         1,
+						MeanSecondsToAccept:   30,
+						TokensPerAcceptedCell: 240,
+					},
+				},
+			},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				resp, err := stats.GetSessionStats(context.Background(), connect.NewRequest(c.request))
+				if err != nil {
+					t.Fatalf("GetSessionStats failed: %v", err)
+				}
+				if d := cmp.Diff(c.expected, resp.Msg.GetStats(), protocmp.Transform()); d != "" {
+					t.Errorf("Unexpected stats:\n%s", d)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,264 @@
+package analyze
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jlewi/foyle/app/pkg/analyze/fsql"
+	"github.com/jlewi/foyle/app/pkg/analyze/fsql/pgsql"
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PostgresDriver is the name of the database/sql driver for Postgres.
+	PostgresDriver = "pgx"
+)
+
+// Store is the SQL database the sessions and evaluation results are stored in. SQLite is the default; Postgres
+// lets a team share a database. The managers access the database through the fsql.Querier returned by Queries
+// so the SQL differences between the databases are hidden from them.
+type Store struct {
+	db      *sql.DB
+	dialect dbutil.Dialect
+}
+
+// OpenStore opens the database described by the config.
+func OpenStore(cfg config.DatabaseConfig) (*Store, error) {
+	var driver string
+	var dialect dbutil.Dialect
+	switch cfg.Driver {
+	case config.SQLiteDriver, "":
+		driver = SQLLiteDriver
+		dialect = dbutil.SQLite
+	case config.PostgresDriver:
+		driver = PostgresDriver
+		dialect = dbutil.Postgres
+	default:
+		return nil, errors.Errorf("Unsupported database driver %q; the driver must be %s or %s", cfg.Driver, config.SQLiteDriver, config.PostgresDriver)
+	}
+	if cfg.DSN == "" {
+		return nil, errors.Errorf("The DSN of the %s database must be set", cfg.Driver)
+	}
+	db, err := sql.Open(driver, cfg.DSN)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to open %s database", cfg.Driver)
+	}
+	return NewStore(db, dialect), nil
+}
+
+// NewStore creates a store for a database that is already open.
+func NewStore(db *sql.DB, dialect dbutil.Dialect) *Store {
+	return &Store{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+// DB returns the underlying database.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Dialect returns the SQL dialect of the database.
+func (s *Store) Dialect() dbutil.Dialect {
+	return s.dialect
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Queries returns the queries for the database. db is either the database or a transaction.
+func (s *Store) Queries(db fsql.DBTX) fsql.Querier {
+	if s.dialect == dbutil.Postgres {
+		return &pgQueries{q: pgsql.New(db)}
+	}
+	return fsql.New(db)
+}
+
+// BeginTx starts a transaction to read-modify-write rows. Postgres transactions are serializable so that
+// concurrent updates to the same row fail rather than overwrite each other; SQLite only allows one writer at a time
+// so its transactions already are.
+func (s *Store) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	opts := &sql.TxOptions{}
+	if s.dialect == dbutil.Postgres {
+		opts.Isolation = sql.LevelSerializable
+	}
+	return s.db.BeginTx(ctx, opts)
+}
+
+// migrate applies the migrations for the dialect of the store.
+func (s *Store) migrate(ctx context.Context, component string, sqlite []dbutil.Migration, postgres []dbutil.Migration) error {
+	migrations := sqlite
+	if s.dialect == dbutil.Postgres {
+		migrations = postgres
+	}
+	return dbutil.Migrate(ctx, s.db, s.dialect, component, migrations)
+}
+
+// pgQueries implements fsql.Querier using the Postgres queries. The Postgres queries return the same rows as
+// the SQLite queries but their parameters are typed. The SQLite queries use the empty string for optional time
+// parameters that aren't set whereas the Postgres queries use NULL. Like the SQLite queries, lists are nil if there
+// are no rows.
+type pgQueries struct {
+	q *pgsql.Queries
+}
+
+var _ fsql.Querier = (*pgQueries)(nil)
+
+func (p *pgQueries) CountByCellsMatchResult(ctx context.Context) ([]fsql.CountByCellsMatchResultRow, error) {
+	rows, err := p.q.CountByCellsMatchResult(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var items []fsql.CountByCellsMatchResultRow
+	for _, r := range rows {
+		items = append(items, fsql.CountByCellsMatchResultRow(r))
+	}
+	return items, nil
+}
+
+func (p *pgQueries) CountErrors(ctx context.Context) (int64, error) {
+	return p.q.CountErrors(ctx)
+}
+
+func (p *pgQueries) CountResults(ctx context.Context) (int64, error) {
+	return p.q.CountResults(ctx)
+}
+
+func (p *pgQueries) CountSessionsBefore(ctx context.Context, starttime time.Time) (int64, error) {
+	return p.q.CountSessionsBefore(ctx, starttime)
+}
+
+func (p *pgQueries) DeleteSessionsBefore(ctx context.Context, starttime time.Time) (int64, error) {
+	return p.q.DeleteSessionsBefore(ctx, starttime)
+}
+
+func (p *pgQueries) GetResult(ctx context.Context, id string) (fsql.Result, error) {
+	r, err := p.q.GetResult(ctx, id)
+	return fsql.Result(r), err
+}
+
+func (p *pgQueries) GetSession(ctx context.Context, contextid string) (fsql.Session, error) {
+	s, err := p.q.GetSession(ctx, contextid)
+	return fsql.Session(s), err
+}
+
+func (p *pgQueries) ListResults(ctx context.Context, arg fsql.ListResultsParams) ([]fsql.Result, error) {
+	rows, err := p.q.ListResults(ctx, pgsql.ListResultsParams{
+		Cursor:   nullTime(arg.Cursor),
+		PageSize: arg.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var items []fsql.Result
+	for _, r := range rows {
+		items = append(items, fsql.Result(r))
+	}
+	return items, nil
+}
+
+func (p *pgQueries) ListSessionProtos(ctx context.Context) ([]fsql.ListSessionProtosRow, error) {
+	rows, err := p.q.ListSessionProtos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var items []fsql.ListSessionProtosRow
+	for _, r := range rows {
+		items = append(items, fsql.ListSessionProtosRow(r))
+	}
+	return items, nil
+}
+
+func (p *pgQueries) ListSessions(ctx context.Context) ([]fsql.Session, error) {
+	rows, err := p.q.ListSessions(ctx)
+	return toFSQLSessions(rows), err
+}
+
+func (p *pgQueries) ListSessionsForExamples(ctx context.Context, arg fsql.ListSessionsForExamplesParams) ([]fsql.Session, error) {
+	rows, err := p.q.ListSessionsForExamples(ctx, pgsql.ListSessionsForExamplesParams{
+		Cursor:   toString(arg.Cursor),
+		PageSize: arg.PageSize,
+	})
+	return toFSQLSessions(rows), err
+}
+
+func (p *pgQueries) SearchSessions(ctx context.Context, arg fsql.SearchSessionsParams) ([]fsql.Session, error) {
+	rows, err := p.q.SearchSessions(ctx, pgsql.SearchSessionsParams{
+		Cursor:           toString(arg.Cursor),
+		StartTime:        nullTime(arg.StartTime),
+		EndTime:          nullTime(arg.EndTime),
+		NotebookUri:      toString(arg.NotebookUri),
+		SuggestionStatus: toString(arg.SuggestionStatus),
+		Executed:         toInt64(arg.Executed),
+		MinInputTokens:   toInt64(arg.MinInputTokens),
+		MaxInputTokens:   toInt64(arg.MaxInputTokens),
+		MinOutputTokens:  toInt64(arg.MinOutputTokens),
+		MaxOutputTokens:  toInt64(arg.MaxOutputTokens),
+		Query:            toString(arg.Query),
+		PageSize:         arg.PageSize,
+	})
+	return toFSQLSessions(rows), err
+}
+
+func (p *pgQueries) SessionStats(ctx context.Context, arg fsql.SessionStatsParams) ([]fsql.SessionStatsRow, error) {
+	rows, err := p.q.SessionStats(ctx, pgsql.SessionStatsParams{
+		Dimension:   toString(arg.Dimension),
+		StartTime:   nullTime(arg.StartTime),
+		EndTime:     nullTime(arg.EndTime),
+		NotebookUri: toString(arg.NotebookUri),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var items []fsql.SessionStatsRow
+	for _, r := range rows {
+		items = append(items, fsql.SessionStatsRow(r))
+	}
+	return items, nil
+}
+
+func (p *pgQueries) UpdateResult(ctx context.Context, arg fsql.UpdateResultParams) error {
+	return p.q.UpdateResult(ctx, pgsql.UpdateResultParams(arg))
+}
+
+func (p *pgQueries) UpdateSession(ctx context.Context, arg fsql.UpdateSessionParams) error {
+	return p.q.UpdateSession(ctx, pgsql.UpdateSessionParams(arg))
+}
+
+func toFSQLSessions(rows []pgsql.Session) []fsql.Session {
+	var items []fsql.Session
+	for _, r := range rows {
+		items = append(items, fsql.Session(r))
+	}
+	return items
+}
+
+// nullTime converts an optional time parameter of a SQLite query to a Postgres parameter.
+func nullTime(v interface{}) sql.NullTime {
+	t, ok := v.(time.Time)
+	return sql.NullTime{Time: t, Valid: ok}
+}
+
+func toString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func toInt64(v interface{}) int64 {
+	switch i := v.(type) {
+	case int64:
+		return i
+	case int32:
+		return int64(i)
+	case int:
+		return int64(i)
+	}
+	return 0
+}
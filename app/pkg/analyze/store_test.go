@@ -0,0 +1,48 @@
+package analyze
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	"github.com/jlewi/foyle/app/pkg/testutil"
+)
+
+// newTestStore creates an empty database of the dialect for the test. Postgres tests are skipped if Postgres
+// isn't available.
+func newTestStore(t *testing.T, dialect dbutil.Dialect) *Store {
+	t.Helper()
+	cfg := config.DatabaseConfig{Driver: config.SQLiteDriver, DSN: filepath.Join(t.TempDir(), "sessions.db")}
+	if dialect == dbutil.Postgres {
+		cfg = config.DatabaseConfig{Driver: config.PostgresDriver, DSN: testutil.NewPostgresDB(t)}
+	}
+	store, err := OpenStore(cfg)
+	if err != nil {
+		t.Fatalf("Error opening the %s database: %v", dialect, err)
+	}
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Logf("Error closing the %s database: %v", dialect, err)
+		}
+	})
+	return store
+}
+
+// forEachDialect runs the test against a database of each dialect foyle supports.
+func forEachDialect(t *testing.T, test func(t *testing.T, store *Store)) {
+	for _, dialect := range []dbutil.Dialect{dbutil.SQLite, dbutil.Postgres} {
+		t.Run(string(dialect), func(t *testing.T) {
+			test(t, newTestStore(t, dialect))
+		})
+	}
+}
+
+func Test_OpenStore(t *testing.T) {
+	if _, err := OpenStore(config.DatabaseConfig{Driver: "mysql", DSN: "foyle"}); err == nil {
+		t.Errorf("Expected an error for an unsupported driver")
+	}
+	if _, err := OpenStore(config.DatabaseConfig{Driver: config.PostgresDriver}); err == nil {
+		t.Errorf("Expected an error if the DSN isn't set")
+	}
+}
@@ -0,0 +1,156 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// promptCachingBeta is the beta header that enables prompt caching.
+	promptCachingBeta = "prompt-caching-2024-07-31"
+	betaHeader        = "anthropic-beta"
+)
+
+type cacheUsageKey struct{}
+
+// cacheUsage is the prompt cache usage of a request. The version of go-anthropic we use doesn't support prompt
+// caching; it can't mark content with cache_control and drops the cache fields from the usage. So cacheTransport
+// adds the cache breakpoints to the request and reads the usage from the response into the cacheUsage in the
+// request's context.
+type cacheUsage struct {
+	// mark is true if the request should be marked for caching.
+	mark bool
+
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// withCacheUsage returns a context that tells cacheTransport to report the cache usage of the request and
+// optionally to mark the request for caching.
+func withCacheUsage(ctx context.Context, mark bool) (context.Context, *cacheUsage) {
+	u := &cacheUsage{mark: mark}
+	return context.WithValue(ctx, cacheUsageKey{}, u), u
+}
+
+// cacheTransport is an http.RoundTripper that implements prompt caching for requests whose context was created
+// with withCacheUsage. Other requests are passed through unchanged.
+type cacheTransport struct {
+	next http.RoundTripper
+}
+
+func newCacheTransport(next http.RoundTripper) *cacheTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cacheTransport{next: next}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, ok := req.Context().Value(cacheUsageKey{}).(*cacheUsage)
+	if !ok || req.Body == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	if u.mark {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read request body")
+		}
+		if err := req.Body.Close(); err != nil {
+			return nil, errors.Wrapf(err, "Failed to close request body")
+		}
+		marked, err := markCacheBreakpoints(body)
+		if err != nil {
+			// Send the request without caching rather than failing it.
+			marked = body
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(marked))
+		req.ContentLength = int64(len(marked))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(marked)), nil
+		}
+		if beta := req.Header.Get(betaHeader); beta != "" {
+			req.Header.Set(betaHeader, beta+","+promptCachingBeta)
+		} else {
+			req.Header.Set(betaHeader, promptCachingBeta)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read response body")
+	}
+	if err := resp.Body.Close(); err != nil {
+		return nil, errors.Wrapf(err, "Failed to close response body")
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	usage := struct {
+		Usage *cacheUsage `json:"usage"`
+	}{
+		Usage: u,
+	}
+	// Ignore errors; the usage is best effort and the client reports errors parsing the response.
+	_ = json.Unmarshal(body, &usage)
+	return resp, nil
+}
+
+// markCacheBreakpoints marks the system prompt and the first content block of the first message for caching.
+// Completers put the cacheable prefix of the message in its own content block so the first block is only marked
+// if the message has more than one block.
+func markCacheBreakpoints(body []byte) ([]byte, error) {
+	request := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return nil, errors.Wrapf(err, "Failed to unmarshal request")
+	}
+	cacheControl := map[string]string{"type": "ephemeral"}
+
+	if raw, ok := request["system"]; ok {
+		system := ""
+		if err := json.Unmarshal(raw, &system); err == nil && system != "" {
+			blocks := []map[string]any{
+				{
+					"type":          "text",
+					"text":          system,
+					"cache_control": cacheControl,
+				},
+			}
+			b, err := json.Marshal(blocks)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to marshal system prompt")
+			}
+			request["system"] = b
+		}
+	}
+
+	messages := []map[string]any{}
+	if err := json.Unmarshal(request["messages"], &messages); err != nil {
+		return nil, errors.Wrapf(err, "Failed to unmarshal messages")
+	}
+	if len(messages) > 0 {
+		content, ok := messages[0]["content"].([]any)
+		if ok && len(content) > 1 {
+			if block, ok := content[0].(map[string]any); ok {
+				block["cache_control"] = cacheControl
+			}
+		}
+		b, err := json.Marshal(messages)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to marshal messages")
+		}
+		request["messages"] = b
+	}
+
+	return json.Marshal(request)
+}
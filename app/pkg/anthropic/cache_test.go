@@ -0,0 +1,92 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/liushuangls/go-anthropic/v2"
+)
+
+func Test_PromptCaching(t *testing.T) {
+	var body []byte
+	var beta string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read request body: %v", err)
+		}
+		beta = r.Header.Get(betaHeader)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"hello"}],"stop_reason":"end_turn","usage":{"input_tokens":10,"output_tokens":5,"cache_creation_input_tokens":0,"cache_read_input_tokens":100}}`))
+	}))
+	defer server.Close()
+
+	client := anthropic.NewClient("apikey", anthropic.WithBaseURL(server.URL+"/v1"), anthropic.WithHTTPClient(&http.Client{Transport: newCacheTransport(nil)}))
+	cfg := config.Config{
+		Agent: &api.AgentConfig{
+			Model: "claude-3-5-sonnet-20240620",
+			Cache: &api.CacheConfig{PromptCaching: true},
+		},
+	}
+	completer, err := NewCompleter(cfg, client)
+	if err != nil {
+		t.Fatalf("Failed to create completer: %v", err)
+	}
+
+	var usage api.LLMUsage
+	ctx := llms.WithUsageRecorder(context.Background(), func(u api.LLMUsage) {
+		usage = u
+	})
+	ctx = llms.WithCacheablePrefix(ctx, len("instructions\n"))
+	if _, err := completer.Complete(ctx, "system prompt", "instructions\ndocument"); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	if beta != promptCachingBeta {
+		t.Errorf("Expected beta header %q; got %q", promptCachingBeta, beta)
+	}
+
+	request := struct {
+		System   []map[string]any `json:"system"`
+		Messages []struct {
+			Content []map[string]any `json:"content"`
+		} `json:"messages"`
+	}{}
+	if err := json.Unmarshal(body, &request); err != nil {
+		t.Fatalf("Failed to unmarshal request: %v", err)
+	}
+	ephemeral := map[string]any{"type": "ephemeral"}
+	if len(request.System) != 1 || !cmp.Equal(request.System[0]["cache_control"], ephemeral) {
+		t.Errorf("The system prompt wasn't marked for caching: %s", body)
+	}
+	if len(request.Messages) != 1 || len(request.Messages[0].Content) != 2 {
+		t.Fatalf("Expected the prefix and the document in separate blocks: %s", body)
+	}
+	content := request.Messages[0].Content
+	if content[0]["text"] != "instructions\n" || !cmp.Equal(content[0]["cache_control"], ephemeral) {
+		t.Errorf("The prefix wasn't marked for caching: %s", body)
+	}
+	if _, ok := content[1]["cache_control"]; ok || content[1]["text"] != "document" {
+		t.Errorf("Only the prefix should be marked for caching: %s", body)
+	}
+
+	expected := api.LLMUsage{
+		InputTokens:       110,
+		CachedInputTokens: 100,
+		OutputTokens:      5,
+		Model:             "claude-3-5-sonnet-20240620",
+		Provider:          string(api.ModelProviderAnthropic),
+	}
+	if d := cmp.Diff(expected, usage); d != "" {
+		t.Errorf("Unexpected usage:\n%s", d)
+	}
+}
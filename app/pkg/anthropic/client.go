@@ -31,6 +31,9 @@ func NewClient(cfg config.Config) (*anthropic.Client, error) {
 		httpClient.Transport = otelhttp.NewTransport(httpClient.Transport)
 	}
 
+	// Add cache breakpoints to requests and read the cache usage from responses.
+	httpClient.Transport = newCacheTransport(httpClient.Transport)
+
 	if cfg.Anthropic == nil {
 		return nil, errors.New("Anthropic config is nil; You must configure Anthropic to create an Anthropic client")
 	}
@@ -3,8 +3,7 @@ package anthropic
 import (
 	"context"
 	"net/http"
-
-	"github.com/jlewi/foyle/app/pkg/logs/matchers"
+	"time"
 
 	"github.com/jlewi/foyle/app/api"
 	"go.opentelemetry.io/otel/attribute"
@@ -44,18 +43,40 @@ func (c *Completer) Complete(ctx context.Context, systemPrompt string, message s
 	ctx, span := tp.Start(ctx, "Complete", trace.WithAttributes(attribute.String("llm.model", c.config.GetModel()), attribute.String("llm.provider", string(api.ModelProviderAnthropic))))
 	defer span.End()
 
-	log := logs.FromContext(ctx)
 	// See: https://docs.anthropic.com/en/api/messages
 	// Claude doesn't have a system prompt.
 	// First message must also be a user message.
 
+	content := []anthropic.MessageContent{
+		{Type: anthropic.MessagesContentTypeText,
+			Text: proto.String(message),
+		},
+	}
+
+	usePromptCaching := c.config.UsePromptCaching()
+	ctx, cache := withCacheUsage(ctx, usePromptCaching)
+	if usePromptCaching {
+		// Put the cacheable prefix in its own content block so cacheTransport can mark it for caching.
+		if prefix, rest := llms.CacheablePrefix(ctx, message); prefix != "" && rest != "" {
+			content = []anthropic.MessageContent{
+				anthropic.NewTextMessageContent(prefix),
+				anthropic.NewTextMessageContent(rest),
+			}
+		}
+	}
+
+	// N.B. Images go after the text so the cacheable prefix is still the first content block.
+	for _, i := range llms.Images(ctx) {
+		content = append(content, anthropic.NewImageMessageContent(anthropic.MessageContentImageSource{
+			Type:      "base64",
+			MediaType: i.MimeType,
+			Data:      i.Base64(),
+		}))
+	}
+
 	messages := []anthropic.Message{
 		{Role: anthropic.RoleUser,
-			Content: []anthropic.MessageContent{
-				{Type: anthropic.MessagesContentTypeText,
-					Text: proto.String(message),
-				},
-			},
+			Content: content,
 		},
 	}
 
@@ -67,10 +88,17 @@ func (c *Completer) Complete(ctx context.Context, systemPrompt string, message s
 		System:      systemPrompt,
 	}
 
-	log.Info("Anthropic:CreateMessages", matchers.RequestField, request)
+	call := api.LLMCall{
+		Provider: api.ModelProviderAnthropic,
+		Model:    c.config.GetModel(),
+	}
+	start := time.Now()
 	resp, err := c.client.CreateMessages(ctx, request)
+	call.LatencyMs = time.Since(start).Milliseconds()
 
 	if err != nil {
+		call.Error = err.Error()
+		logs.LogLLMCall(ctx, call, request, nil)
 		// https://docs.anthropic.com/en/api/errors
 		aErr, ok := err.(*anthropic.RequestError)
 		if ok {
@@ -87,17 +115,23 @@ func (c *Completer) Complete(ctx context.Context, systemPrompt string, message s
 	span.SetAttributes(
 		attribute.Int("llm.input_tokens", resp.Usage.InputTokens),
 		attribute.Int("llm.output_tokens", resp.Usage.OutputTokens),
+		attribute.Int("llm.cache_read_input_tokens", cache.CacheReadInputTokens),
+		attribute.Int("llm.cache_creation_input_tokens", cache.CacheCreationInputTokens),
 		attribute.String("llm.stop_reason", string(resp.StopReason)),
 	)
 
-	log.Info("Anthropic:CreateMessages response", matchers.ResponseField, resp)
+	// Anthropic's input tokens don't include the tokens read from or written to the cache.
 	usage := api.LLMUsage{
-		InputTokens:  resp.Usage.InputTokens,
-		OutputTokens: resp.Usage.OutputTokens,
-		Model:        c.config.GetModel(),
-		Provider:     string(api.ModelProviderAnthropic),
+		InputTokens:           resp.Usage.InputTokens + cache.CacheReadInputTokens + cache.CacheCreationInputTokens,
+		CachedInputTokens:     cache.CacheReadInputTokens,
+		CacheWriteInputTokens: cache.CacheCreationInputTokens,
+		OutputTokens:          resp.Usage.OutputTokens,
+		Model:                 c.config.GetModel(),
+		Provider:              string(api.ModelProviderAnthropic),
 	}
-	logs.LogLLMUsage(ctx, usage)
+	call.Usage = usage
+	logs.LogLLMCall(ctx, call, request, resp)
+	llms.RecordUsage(ctx, usage)
 
 	blocks, err := c.parseResponse(ctx, &resp)
 	if err != nil {
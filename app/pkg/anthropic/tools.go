@@ -0,0 +1,144 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	"github.com/liushuangls/go-anthropic/v2"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// SupportsTools implements llms.ToolCompleter.
+func (c *Completer) SupportsTools() bool {
+	return true
+}
+
+// CompleteWithTools implements llms.ToolCompleter using Anthropic tool use.
+// N.B. The request and response are logged from this function; its name needs to match matchers.AnthropicComplete.
+func (c *Completer) CompleteWithTools(ctx context.Context, systemPrompt string, messages []llms.Message, tools []llms.Tool) (*llms.Message, error) {
+	tp := tracer()
+	ctx, span := tp.Start(ctx, "CompleteWithTools", trace.WithAttributes(attribute.String("llm.model", c.config.GetModel()), attribute.String("llm.provider", string(api.ModelProviderAnthropic))))
+	defer span.End()
+	log := logs.FromContext(ctx)
+
+	request := anthropic.MessagesRequest{
+		Model:       c.config.GetModel(),
+		Messages:    toAnthropicMessages(messages),
+		MaxTokens:   2000,
+		Temperature: proto.Float32(temperature),
+		System:      systemPrompt,
+	}
+	for _, t := range tools {
+		request.Tools = append(request.Tools, anthropic.ToolDefinition{
+			Name:        t.Name,
+			Description: t.Description,
+			InputSchema: t.Parameters,
+		})
+	}
+
+	call := api.LLMCall{
+		Provider: api.ModelProviderAnthropic,
+		Model:    c.config.GetModel(),
+	}
+	start := time.Now()
+	resp, err := c.client.CreateMessages(ctx, request)
+	call.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		call.Error = err.Error()
+		logs.LogLLMCall(ctx, call, request, nil)
+		aErr, ok := err.(*anthropic.RequestError)
+		if ok && aErr.StatusCode == http.StatusRequestEntityTooLarge {
+			return nil, llms.ContextLengthExceededError{Cause: err}
+		}
+		return nil, errors.Wrapf(err, "CreateMessages failed")
+	}
+
+	span.SetAttributes(
+		attribute.Int("llm.input_tokens", resp.Usage.InputTokens),
+		attribute.Int("llm.output_tokens", resp.Usage.OutputTokens),
+		attribute.String("llm.stop_reason", string(resp.StopReason)),
+	)
+
+	usage := api.LLMUsage{
+		InputTokens:  resp.Usage.InputTokens,
+		OutputTokens: resp.Usage.OutputTokens,
+		Model:        c.config.GetModel(),
+		Provider:     string(api.ModelProviderAnthropic),
+	}
+	call.Usage = usage
+	logs.LogLLMCall(ctx, call, request, resp)
+	llms.RecordUsage(ctx, usage)
+
+	reply := &llms.Message{
+		Role: llms.RoleAssistant,
+	}
+	text := make([]string, 0, len(resp.Content))
+	for _, content := range resp.Content {
+		switch content.Type {
+		case anthropic.MessagesContentTypeText:
+			text = append(text, content.GetText())
+		case anthropic.MessagesContentTypeToolUse:
+			if content.MessageContentToolUse == nil {
+				continue
+			}
+			reply.ToolCalls = append(reply.ToolCalls, llms.ToolCall{
+				ID:        content.MessageContentToolUse.ID,
+				Name:      content.MessageContentToolUse.Name,
+				Arguments: string(content.MessageContentToolUse.Input),
+			})
+		default:
+			log.Info("Skipping unsupported content in Anthropic response", "type", content.Type)
+		}
+	}
+	reply.Content = strings.Join(text, "\n")
+	return reply, nil
+}
+
+// toAnthropicMessages converts the conversation to Anthropic messages. Anthropic expects the results of tool calls
+// to be sent in a user message so consecutive tool results are combined into a single message.
+func toAnthropicMessages(messages []llms.Message) []anthropic.Message {
+	results := make([]anthropic.Message, 0, len(messages))
+	for _, m := range messages {
+		switch m.Role {
+		case llms.RoleTool:
+			content := anthropic.NewToolResultMessageContent(m.ToolCallID, m.Content, false)
+			last := len(results) - 1
+			if last >= 0 && results[last].Role == anthropic.RoleUser && results[last].GetFirstContent().Type == anthropic.MessagesContentTypeToolResult {
+				results[last].Content = append(results[last].Content, content)
+				continue
+			}
+			results = append(results, anthropic.Message{
+				Role:    anthropic.RoleUser,
+				Content: []anthropic.MessageContent{content},
+			})
+		case llms.RoleAssistant:
+			msg := anthropic.Message{
+				Role:    anthropic.RoleAssistant,
+				Content: make([]anthropic.MessageContent, 0, len(m.ToolCalls)+1),
+			}
+			if m.Content != "" {
+				msg.Content = append(msg.Content, anthropic.NewTextMessageContent(m.Content))
+			}
+			for _, call := range m.ToolCalls {
+				args := call.Arguments
+				if args == "" {
+					args = "{}"
+				}
+				msg.Content = append(msg.Content, anthropic.NewToolUseMessageContent(call.ID, call.Name, json.RawMessage(args)))
+			}
+			results = append(results, msg)
+		default:
+			results = append(results, anthropic.NewUserTextMessage(m.Content))
+		}
+	}
+	return results
+}
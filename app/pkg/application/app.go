@@ -2,7 +2,6 @@ package application
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,18 +19,23 @@ import (
 
 	"github.com/jlewi/foyle/app/pkg/llms"
 
-	"github.com/cockroachdb/pebble"
 	"github.com/jlewi/foyle/app/pkg/agent"
 	"github.com/jlewi/foyle/app/pkg/anthropic"
+	"github.com/jlewi/foyle/app/pkg/budget"
 	"github.com/jlewi/foyle/app/pkg/dbutil"
+	"github.com/jlewi/foyle/app/pkg/events"
 	"github.com/jlewi/foyle/app/pkg/learn"
+	"github.com/jlewi/foyle/app/pkg/llmcache"
 	"github.com/jlewi/foyle/app/pkg/oai"
+	"github.com/jlewi/foyle/app/pkg/router"
 	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/logs/logspbconnect"
 
 	"github.com/jlewi/foyle/app/pkg/analyze"
 
 	"github.com/jlewi/foyle/app/api"
 	"github.com/jlewi/foyle/app/pkg/eval"
+	"github.com/jlewi/foyle/app/pkg/gemini"
 	"github.com/jlewi/hydros/pkg/util"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -42,6 +46,9 @@ import (
 	"github.com/jlewi/hydros/pkg/files"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/sdk/trace"
 
 	"github.com/go-logr/zapr"
@@ -58,6 +65,9 @@ type logCloser func()
 const (
 	// FoyleLogName is the name of the log in Google Cloud Logging
 	FoyleLogName = "foyle"
+
+	// eventBusSize is the number of trace events buffered before new events are dropped.
+	eventBusSize = 1000
 )
 
 // App is a struct that takes care of wiring together all the different
@@ -69,11 +79,13 @@ type App struct {
 	otelShutdownFn      func()
 	logClosers          []logCloser
 	Registry            *controllers.Registry
-	logEntriesDB        *pebble.DB
+	logEntriesDB        dbutil.KV
 	LockingLogEntriesDB *dbutil.LockingDB[*logspb.LogEntries]
-	TracesDB            *pebble.DB
-	blocksDB            *pebble.DB
+	TracesDB            dbutil.KV
+	blocksDB            dbutil.KV
 	LockingBlocksDB     *dbutil.LockingDB[*logspb.BlockLog]
+	// QueuesDB backs the analyzer's and learner's durable work queues.
+	QueuesDB dbutil.KV
 
 	analyzer           *analyze.Analyzer
 	learner            *learn.Learner
@@ -81,7 +93,16 @@ type App struct {
 	completer          llms.Completer
 	inMemoryExamplesDB *learn.InMemoryExampleDB
 
-	sessionsDB      *sql.DB
+	// summarizer is the completer used to summarize long outputs. If nil the agent uses completer.
+	summarizer llms.Completer
+	// bus delivers the trace and session events published by the agent to the analyzer.
+	bus *events.Bus
+	// genAIExporter exports traces to an OTLP collector; nil unless configured.
+	genAIExporter *analyze.GenAIExporter
+	// gc garbage collects the logs directory; nil unless retention is configured.
+	gc *analyze.GarbageCollector
+
+	sessionsDB      *analyze.Store
 	sessionsManager *analyze.SessionsManager
 }
 
@@ -415,16 +436,45 @@ func (a *App) SetupAnalyzer() (*analyze.Analyzer, error) {
 		return nil, errors.New("Config is nil; call LoadConfig first")
 	}
 
-	// If the directory doesn't exit opening the SQLLite database will fail.
-	sessionsDBFile := a.Config.GetSessionsDB()
-	dbDir := filepath.Dir(sessionsDBFile)
-	if err := os.MkdirAll(dbDir, helpers.UserGroupAllPerm); err != nil {
-		return nil, errors.Wrapf(err, "Failed to create directory: %v", dbDir)
+	manager, err := a.setupSessionsManager()
+	if err != nil {
+		return nil, err
+	}
+
+	var exporter analyze.TraceExporter
+	if a.Config.UseOTLP() {
+		genAIExporter, err := a.newGenAIExporter()
+		if err != nil {
+			return nil, err
+		}
+		a.genAIExporter = genAIExporter
+		exporter = genAIExporter
+	}
+
+	maxDelay := time.Duration(a.Config.GetLogsMaxDelaySeconds()) * time.Second
+	analyzer, err := analyze.NewAnalyzer(a.Config.GetLogOffsetsFile(), maxDelay, a.LockingLogEntriesDB, a.TracesDB, a.LockingBlocksDB, manager, llms.NewPriceTable(a.Config.Pricing), exporter, a.QueuesDB)
+	if err != nil {
+		return nil, err
 	}
-	db, err := sql.Open(analyze.SQLLiteDriver, sessionsDBFile)
+	return analyzer, nil
+}
 
+// setupSessionsManager opens the sessions database.
+func (a *App) setupSessionsManager() (*analyze.SessionsManager, error) {
+	if a.sessionsManager != nil {
+		return a.sessionsManager, nil
+	}
+	dbConfig := a.Config.GetSessionsDBConfig()
+	if dbConfig.Driver == config.SQLiteDriver {
+		// If the directory doesn't exit opening the SQLLite database will fail.
+		dbDir := filepath.Dir(dbConfig.DSN)
+		if err := os.MkdirAll(dbDir, helpers.UserGroupAllPerm); err != nil {
+			return nil, errors.Wrapf(err, "Failed to create directory: %v", dbDir)
+		}
+	}
+	db, err := analyze.OpenStore(dbConfig)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Failed to open database: %v", a.Config.GetSessionsDB())
+		return nil, errors.Wrapf(err, "Failed to open the sessions database")
 	}
 
 	manager, err := analyze.NewSessionsManager(db)
@@ -433,13 +483,72 @@ func (a *App) SetupAnalyzer() (*analyze.Analyzer, error) {
 	}
 	a.sessionsManager = manager
 	a.sessionsDB = db
+	return manager, nil
+}
 
-	maxDelay := time.Duration(a.Config.GetLogsMaxDelaySeconds()) * time.Second
-	analyzer, err := analyze.NewAnalyzer(a.Config.GetLogOffsetsFile(), maxDelay, a.LockingLogEntriesDB, a.TracesDB, a.LockingBlocksDB, manager)
+// newGarbageCollector creates the garbage collector for the logs directory. watermark should return the analyzer's
+// watermark.
+func (a *App) newGarbageCollector(watermark func() *logspb.LogsWaterMark) (*analyze.GarbageCollector, error) {
+	if a.TracesDB == nil {
+		return nil, errors.New("TracesDB is nil; call OpenDBs first")
+	}
+	manager, err := a.setupSessionsManager()
 	if err != nil {
 		return nil, err
 	}
-	return analyzer, nil
+	return analyze.NewGarbageCollector(a.Config.GetRetentionConfig(), a.Config.GetRawLogDir(), watermark, a.logEntriesDB, a.TracesDB, a.blocksDB, manager)
+}
+
+// CollectGarbage deletes the logs and the data built from them that are past the retention period. It is intended
+// to be run when the server isn't running; the server garbage collects in the background.
+func (a *App) CollectGarbage(ctx context.Context, dryRun bool) (*analyze.GCReport, error) {
+	if a.Config == nil {
+		return nil, errors.New("Config is nil; call LoadConfig first")
+	}
+	if !a.Config.UseRetention() {
+		return nil, errors.New("No retention policy is configured; set logging.retention.maxAge or logging.retention.maxRawLogsMB")
+	}
+	watermark, err := analyze.WatermarkFromFile(a.Config.GetLogOffsetsFile())
+	if err != nil {
+		return nil, err
+	}
+	gc, err := a.newGarbageCollector(watermark)
+	if err != nil {
+		return nil, err
+	}
+	return gc.Collect(ctx, dryRun)
+}
+
+// newGenAIExporter creates the exporter that sends traces to the configured OTLP collector.
+func (a *App) newGenAIExporter() (*analyze.GenAIExporter, error) {
+	log := zapr.NewLogger(zap.L())
+	cfg := a.Config.Telemetry.OTLP
+	protocol := a.Config.GetOTLPProtocol()
+	log.Info("Exporting traces to OTLP collector", "endpoint", cfg.Endpoint, "protocol", protocol)
+
+	var client otlptrace.Client
+	switch protocol {
+	case config.OTLPProtocolGRPC:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint), otlptracegrpc.WithHeaders(cfg.Headers)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		client = otlptracegrpc.NewClient(opts...)
+	case config.OTLPProtocolHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint), otlptracehttp.WithHeaders(cfg.Headers)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		client = otlptracehttp.NewClient(opts...)
+	default:
+		return nil, errors.Errorf("Unsupported OTLP protocol %q; protocol must be %s or %s", protocol, config.OTLPProtocolGRPC, config.OTLPProtocolHTTP)
+	}
+
+	exporter, err := otlptrace.New(context.Background(), client)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create OTLP exporter for %s", cfg.Endpoint)
+	}
+	return analyze.NewGenAIExporter(exporter, "foyle", cfg.IncludeContent)
 }
 
 // SetupLearner	sets up the learner
@@ -447,11 +556,12 @@ func (a *App) SetupLearner() (*learn.Learner, error) {
 	if a.LockingBlocksDB == nil {
 		return nil, errors.New("LockingBlocksDB is nil; call OpenDBs first")
 	}
-	client, err := oai.NewClient(*a.Config)
+	vectorizer, err := a.newVectorizer()
 	if err != nil {
 		return nil, err
 	}
-	return learn.NewLearner(*a.Config, client, a.sessionsManager)
+	a.vectorizer = vectorizer
+	return learn.NewLearner(*a.Config, vectorizer, a.sessionsManager, a.QueuesDB)
 }
 
 func (a *App) createComponents() error {
@@ -460,6 +570,8 @@ func (a *App) createComponents() error {
 		return err
 	}
 	a.analyzer = analyzer
+	a.bus = events.NewBus(eventBusSize)
+	a.analyzer.Subscribe(a.bus)
 
 	learner, err := a.SetupLearner()
 
@@ -484,45 +596,153 @@ func (a *App) createComponents() error {
 }
 
 func (a *App) setupLLM() error {
-	client, err := oai.NewClient(*a.Config)
+	if a.vectorizer == nil {
+		vectorizer, err := a.newVectorizer()
+		if err != nil {
+			return err
+		}
+		a.vectorizer = vectorizer
+	}
+
+	completer, err := newCompleter(*a.Config)
 	if err != nil {
 		return err
 	}
-	a.vectorizer = oai.NewVectorizer(client)
+	a.completer = completer
 
-	switch a.Config.Agent.ModelProvider {
-	case api.ModelProviderAnthropic:
-		client, err := anthropic.NewClient(*a.Config)
+	if a.Config.UseRouter() {
+		routerCfg := *a.Config.Agent.Router
+		primary := router.Route{
+			Model:     a.Config.GetModel(),
+			Provider:  a.Config.Agent.ModelProvider,
+			Completer: completer,
+		}
+		fallbacks := make([]router.Route, 0, len(routerCfg.Fallbacks))
+		for _, m := range routerCfg.Fallbacks {
+			r, err := newRoute(a.Config.WithModel(m))
+			if err != nil {
+				return err
+			}
+			fallbacks = append(fallbacks, *r)
+		}
+		var small *router.Route
+		if routerCfg.Small != nil {
+			small, err = newRoute(a.Config.WithModel(*routerCfg.Small))
+			if err != nil {
+				return err
+			}
+		}
+		r, err := router.NewCompleter(routerCfg, primary, fallbacks, small)
 		if err != nil {
 			return err
 		}
-		completer, err := anthropic.NewCompleter(*a.Config, client)
+		a.completer = r
+	}
+
+	reducerCfg := a.Config.GetOutputReducerConfig()
+	if reducerCfg.Strategy == api.OutputReducerLLM && reducerCfg.Model != nil {
+		summarizer, err := newCompleter(a.Config.WithModel(*reducerCfg.Model))
 		if err != nil {
-			return err
+			return errors.Wrapf(err, "Failed to create completer for summarizing outputs with model %s", reducerCfg.Model.Model)
 		}
-		a.completer = completer
-	case api.ModelProviderReplicate:
-		chatClient, err := replicate.NewChatClient(*a.Config)
+		a.summarizer = summarizer
+	}
+
+	if a.Config.Budget != nil {
+		b, err := budget.NewBudget(*a.Config.Budget, llms.NewPriceTable(a.Config.Pricing), a.Config.GetBudgetFile())
 		if err != nil {
 			return err
 		}
-		completer, err := replicate.NewCompleter(*a.Config, chatClient)
+		completer, err := budget.NewCompleter(a.completer, b)
 		if err != nil {
 			return err
 		}
+		a.completer = completer
 
+		if a.summarizer != nil {
+			summarizer, err := budget.NewCompleter(a.summarizer, b)
+			if err != nil {
+				return err
+			}
+			a.summarizer = summarizer
+		}
+	}
+
+	// The response cache wraps the budget so cached responses don't count against the budget.
+	if a.Config.UseResponseCache() {
+		completer, err := llmcache.NewCompleter(a.completer, a.Config.GetCacheConfig())
+		if err != nil {
+			return err
+		}
 		a.completer = completer
+	}
+
+	return nil
+}
+
+// newCompleter creates the completer for the model in the configuration.
+func newCompleter(cfg config.Config) (llms.Completer, error) {
+	switch cfg.Agent.ModelProvider {
+	case api.ModelProviderAnthropic:
+		client, err := anthropic.NewClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return anthropic.NewCompleter(cfg, client)
+	case api.ModelProviderReplicate:
+		chatClient, err := replicate.NewChatClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return replicate.NewCompleter(cfg, chatClient)
+	case api.ModelProviderGemini:
+		client, err := gemini.NewClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return gemini.NewCompleter(cfg, client)
 	case api.ModelProviderOpenAI:
 		fallthrough
 	default:
-		completer, err := oai.NewCompleter(*a.Config, client)
+		client, err := oai.NewClient(cfg)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		a.completer = completer
+		return oai.NewCompleter(cfg, client)
 	}
+}
 
-	return nil
+// newVectorizer creates the vectorizer for the embedding provider in the configuration.
+func (a *App) newVectorizer() (llms.Vectorizer, error) {
+	switch a.Config.GetEmbeddingProvider() {
+	case api.ModelProviderGemini:
+		client, err := gemini.NewClient(*a.Config)
+		if err != nil {
+			return nil, err
+		}
+		return gemini.NewVectorizer(client), nil
+	case api.ModelProviderOpenAI:
+		client, err := oai.NewClient(*a.Config)
+		if err != nil {
+			return nil, err
+		}
+		return oai.NewVectorizer(client), nil
+	default:
+		return nil, errors.Errorf("Unsupported embedding provider %s", a.Config.GetEmbeddingProvider())
+	}
+}
+
+// newRoute creates a route for the router to the model in the configuration.
+func newRoute(cfg config.Config) (*router.Route, error) {
+	completer, err := newCompleter(cfg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create completer for model %s", cfg.GetModel())
+	}
+	return &router.Route{
+		Model:     cfg.GetModel(),
+		Provider:  cfg.Agent.ModelProvider,
+		Completer: completer,
+	}, nil
 }
 
 // Serve sets up and runs the server
@@ -543,6 +763,16 @@ func (a *App) Serve() error {
 	if err := a.analyzer.Run(context.Background(), logDirs, a.learner.Enqueue); err != nil {
 		return err
 	}
+	a.bus.Run(context.Background())
+
+	if a.Config.UseRetention() {
+		gc, err := a.newGarbageCollector(a.analyzer.GetWatermark)
+		if err != nil {
+			return err
+		}
+		a.gc = gc
+		a.gc.Start(context.Background())
+	}
 
 	if a.learner != nil {
 		if err := a.learner.Start(context.Background(), a.inMemoryExamplesDB.EnqueueExample); err != nil {
@@ -554,7 +784,7 @@ func (a *App) Serve() error {
 		return err
 	}
 
-	agent, err := agent.NewAgent(*a.Config, a.completer, a.inMemoryExamplesDB)
+	agent, err := agent.NewAgent(*a.Config, a.completer, a.summarizer, a.inMemoryExamplesDB, a.bus)
 
 	if err != nil {
 		return err
@@ -653,14 +883,14 @@ func (a *App) OpenDBs() error {
 	log := zapr.NewLogger(zap.L())
 
 	log.Info("Opening traces database", "database", a.Config.GetTracesDBDir())
-	tracesDB, err := pebble.Open(a.Config.GetTracesDBDir(), &pebble.Options{})
+	tracesDB, err := dbutil.OpenPebbleKV(a.Config.GetTracesDBDir(), false)
 	if err != nil {
 		return errors.Wrapf(err, "could not open traces database %s", a.Config.GetTracesDBDir())
 	}
 	a.TracesDB = tracesDB
 
 	log.Info("Opening blocks database", "database", a.Config.GetBlocksDBDir())
-	blocksDB, err := pebble.Open(a.Config.GetBlocksDBDir(), &pebble.Options{})
+	blocksDB, err := dbutil.OpenPebbleKV(a.Config.GetBlocksDBDir(), false)
 	if err != nil {
 		return errors.Wrapf(err, "could not open blocks database %s", a.Config.GetBlocksDBDir())
 	}
@@ -668,8 +898,8 @@ func (a *App) OpenDBs() error {
 
 	a.LockingBlocksDB = analyze.NewLockingBlocksDB(blocksDB)
 
-	log.Info("Opening loglines database", "database", a.Config.GetBlocksDBDir())
-	logEntries, err := pebble.Open(a.Config.GetLogEntriesDBDir(), &pebble.Options{})
+	log.Info("Opening loglines database", "database", a.Config.GetLogEntriesDBDir())
+	logEntries, err := dbutil.OpenPebbleKV(a.Config.GetLogEntriesDBDir(), false)
 	if err != nil {
 		return errors.Wrapf(err, "could not open log entries database %s", a.Config.GetLogEntriesDBDir())
 	}
@@ -677,6 +907,61 @@ func (a *App) OpenDBs() error {
 
 	a.LockingLogEntriesDB = analyze.NewLockingEntriesDB(a.logEntriesDB)
 
+	return a.OpenQueuesDB(false)
+}
+
+// OpenDBsReadOnly opens the traces and blocks databases for commands that only read them. Pebble databases can only
+// be opened by one process so if the server has them open the traces and blocks are read from the server's API at
+// endpoint instead. Reading from the server only supports looking up traces and blocks by id.
+func (a *App) OpenDBsReadOnly(endpoint string) error {
+	if a.Config == nil {
+		return errors.New("Config is nil; call LoadConfig first")
+	}
+	log := zapr.NewLogger(zap.L())
+
+	tracesDB, err := dbutil.OpenPebbleKV(a.Config.GetTracesDBDir(), true)
+	if err != nil {
+		if !dbutil.IsLocked(err) {
+			return errors.Wrapf(err, "could not open traces database %s", a.Config.GetTracesDBDir())
+		}
+		log.Info("Traces and blocks databases are in use; reading them from the server", "endpoint", endpoint)
+		client := logspbconnect.NewLogsServiceClient(http.DefaultClient, endpoint)
+		a.TracesDB = analyze.NewRemoteTracesDB(client)
+		a.blocksDB = analyze.NewRemoteBlocksDB(client)
+		return nil
+	}
+	a.TracesDB = tracesDB
+
+	blocksDB, err := dbutil.OpenPebbleKV(a.Config.GetBlocksDBDir(), true)
+	if err != nil {
+		return errors.Wrapf(err, "could not open blocks database %s", a.Config.GetBlocksDBDir())
+	}
+	a.blocksDB = blocksDB
+	return nil
+}
+
+// BlocksDB returns the blocks database. It is nil until the databases are opened.
+func (a *App) BlocksDB() dbutil.KV {
+	return a.blocksDB
+}
+
+// OpenQueuesDB opens the database backing the work queues. It is called by OpenDBs; commands that only need the
+// queues can call it directly. The queues are only held in the server's memory so unlike the traces and blocks they
+// can't be read from the server; if the server has the database open the command has to wait until it is stopped.
+func (a *App) OpenQueuesDB(readOnly bool) error {
+	if a.Config == nil {
+		return errors.New("Config is nil; call LoadConfig first")
+	}
+	log := zapr.NewLogger(zap.L())
+	log.Info("Opening queues database", "database", a.Config.GetQueuesDBDir(), "readOnly", readOnly)
+	queuesDB, err := dbutil.OpenPebbleKV(a.Config.GetQueuesDBDir(), readOnly)
+	if err != nil {
+		if dbutil.IsLocked(err) {
+			return errors.Wrapf(err, "queues database %s is in use; stop the server and try again", a.Config.GetQueuesDBDir())
+		}
+		return errors.Wrapf(err, "could not open queues database %s", a.Config.GetQueuesDBDir())
+	}
+	a.QueuesDB = queuesDB
 	return nil
 }
 
@@ -695,12 +980,29 @@ func (a *App) Shutdown() error {
 	}
 
 	log.Info("Logs flushed.")
+	if a.gc != nil {
+		if err := a.gc.Shutdown(context.Background()); err != nil {
+			log.Error(err, "Error shutting down garbage collector")
+		}
+	}
+	// The bus should be shutdown before the analyzer so the events already published are processed.
+	if a.bus != nil {
+		if err := a.bus.Shutdown(context.Background()); err != nil {
+			log.Error(err, "Error shutting down event bus")
+		}
+	}
 	if a.analyzer != nil {
 		if err := a.analyzer.Shutdown(context.Background()); err != nil {
 			log.Error(err, "Error shutting down analyzer")
 		}
 	}
 
+	if a.genAIExporter != nil {
+		if err := a.genAIExporter.Shutdown(context.Background()); err != nil {
+			log.Error(err, "Error shutting down GenAI trace exporter")
+		}
+	}
+
 	// Analyzer should be shutdown before the learner because analyzer tries to enqueue learner items
 	if a.learner != nil {
 		if err := a.learner.Shutdown(context.Background()); err != nil {
@@ -733,6 +1035,13 @@ func (a *App) Shutdown() error {
 		}
 	}
 
+	if a.QueuesDB != nil {
+		log.Info("Closing queues database")
+		if err := a.QueuesDB.Close(); err != nil {
+			log.Error(err, "Error closing queues database")
+		}
+	}
+
 	if a.sessionsDB != nil {
 		log.Info("Closing sessions database")
 		if err := a.sessionsDB.Close(); err != nil {
@@ -0,0 +1,89 @@
+package application
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	"github.com/jlewi/hydros/pkg/util"
+)
+
+// holdDBEnv is set when the test binary is run as the child process that holds the lock on the traces database.
+const holdDBEnv = "FOYLE_TEST_HOLD_TRACES_DB"
+
+func Test_OpenDBsReadOnlyWhileLocked(t *testing.T) {
+	if dir := os.Getenv(holdDBEnv); dir != "" {
+		holdTracesDB(t, dir)
+		return
+	}
+
+	logDir := t.TempDir()
+	cfg := &config.Config{Logging: config.Logging{LogDir: logDir}}
+
+	// Pebble's lock is per process so the database has to be opened by another process to simulate the server.
+	child := exec.Command(os.Args[0], "-test.run=^Test_OpenDBsReadOnlyWhileLocked$")
+	child.Env = append(os.Environ(), holdDBEnv+"="+cfg.GetTracesDBDir())
+	stdin, err := child.StdinPipe()
+	if err != nil {
+		t.Fatalf("Failed to get stdin: %v", err)
+	}
+	stdout, err := child.StdoutPipe()
+	if err != nil {
+		t.Fatalf("Failed to get stdout: %v", err)
+	}
+	if err := child.Start(); err != nil {
+		t.Fatalf("Failed to start child process: %v", err)
+	}
+	defer func() {
+		// Closing stdin tells the child to close the database and exit.
+		_ = stdin.Close()
+		if err := child.Wait(); err != nil {
+			t.Errorf("Child process failed: %v", err)
+		}
+	}()
+
+	scanner := bufio.NewScanner(stdout)
+	ready := false
+	for scanner.Scan() {
+		if scanner.Text() == "ready" {
+			ready = true
+			break
+		}
+	}
+	if !ready {
+		t.Fatalf("Child process didn't open the database")
+	}
+
+	if _, err := dbutil.OpenPebbleKV(cfg.GetTracesDBDir(), true); !dbutil.IsLocked(err) {
+		t.Fatalf("Expected opening a database held by another process to fail with a lock error; got %v", err)
+	}
+
+	app := &App{Config: cfg}
+	if err := app.OpenDBsReadOnly("http://localhost:0"); err != nil {
+		t.Fatalf("OpenDBsReadOnly failed: %+v", err)
+	}
+	if _, ok := app.TracesDB.(*dbutil.PebbleKV); ok {
+		t.Errorf("Expected the traces to be read from the server")
+	}
+	if _, ok := app.BlocksDB().(*dbutil.PebbleKV); ok {
+		t.Errorf("Expected the blocks to be read from the server")
+	}
+}
+
+// holdTracesDB opens the database and keeps it open until stdin is closed.
+func holdTracesDB(t *testing.T, dir string) {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	db, err := dbutil.OpenPebbleKV(dir, false)
+	if err != nil {
+		t.Fatalf("Failed to open database: %+v", err)
+	}
+	defer util.DeferIgnoreError(db.Close)
+	os.Stdout.WriteString("ready\n")
+	_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+}
@@ -0,0 +1,314 @@
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// DefaultUser is the user usage is attributed to when the request doesn't identify the user.
+	DefaultUser = "default"
+
+	scopeGlobal = "global"
+	scopeUser   = "user"
+
+	windowMinute = "minute"
+	windowDay    = "day"
+
+	unitTokens = "tokens"
+	unitUSD    = "usd"
+)
+
+var (
+	remainingGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "llm_budget_remaining",
+		Help: "Remaining LLM budget in the current window",
+	},
+		[]string{"scope", "user", "window", "unit"},
+	)
+
+	rejectedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "llm_budget_rejected_total",
+		Help: "Number of LLM calls rejected because the budget was exceeded",
+	},
+		[]string{"scope", "window", "unit"},
+	)
+)
+
+// ExceededError is returned when a request is rejected because a budget is exhausted.
+type ExceededError struct {
+	Scope   string
+	User    string
+	Window  string
+	Unit    string
+	Limit   float64
+	Used    float64
+	ResetAt time.Time
+}
+
+func (e *ExceededError) Error() string {
+	who := "all users"
+	if e.Scope == scopeUser {
+		who = fmt.Sprintf("user %s", e.User)
+	}
+	return fmt.Sprintf("LLM budget exceeded; %s used %v of the %v %s allowed per %s; the budget resets at %s", who, e.Used, e.Limit, e.Unit, e.Window, e.ResetAt.Format(time.RFC3339))
+}
+
+// window is the usage in a fixed window of time.
+type window struct {
+	Start   time.Time `json:"start"`
+	Tokens  int64     `json:"tokens"`
+	CostUSD float64   `json:"costUsd"`
+}
+
+// usage is the usage counted against a set of limits.
+type usage struct {
+	Minute window `json:"minute"`
+	Day    window `json:"day"`
+}
+
+// state is the state of the budget that is persisted across restarts.
+type state struct {
+	Global *usage            `json:"global"`
+	Users  map[string]*usage `json:"users"`
+}
+
+// Budget tracks LLM usage and enforces limits on it.
+type Budget struct {
+	config    config.BudgetConfig
+	pricing   *llms.PriceTable
+	stateFile string
+
+	mu    sync.Mutex
+	state *state
+	now   func() time.Time
+}
+
+// NewBudget creates a new budget. Usage is persisted to stateFile so that limits are enforced across restarts.
+func NewBudget(cfg config.BudgetConfig, pricing *llms.PriceTable, stateFile string) (*Budget, error) {
+	return newBudget(cfg, pricing, stateFile, time.Now)
+}
+
+func newBudget(cfg config.BudgetConfig, pricing *llms.PriceTable, stateFile string, now func() time.Time) (*Budget, error) {
+	b := &Budget{
+		config:    cfg,
+		pricing:   pricing,
+		stateFile: stateFile,
+		state: &state{
+			Global: &usage{},
+			Users:  make(map[string]*usage),
+		},
+		now: now,
+	}
+
+	if stateFile != "" {
+		raw, err := os.ReadFile(stateFile)
+		if err == nil {
+			s := &state{}
+			if err := json.Unmarshal(raw, s); err != nil {
+				return nil, errors.Wrapf(err, "Failed to unmarshal budget state from %s", stateFile)
+			}
+			if s.Global == nil {
+				s.Global = &usage{}
+			}
+			if s.Users == nil {
+				s.Users = make(map[string]*usage)
+			}
+			b.state = s
+		} else if !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "Failed to read budget state from %s", stateFile)
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rollWindows(b.state.Global)
+	b.updateMetrics(scopeGlobal, "", b.state.Global, b.config.Global)
+	b.pruneUsers()
+	for user, u := range b.state.Users {
+		b.rollWindows(u)
+		b.updateMetrics(scopeUser, user, u, b.config.PerUser)
+	}
+	return b, nil
+}
+
+// Check returns an ExceededError if the user or global budget has been used up.
+func (b *Budget) Check(user string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollWindows(b.state.Global)
+	if err := b.check(scopeGlobal, "", b.state.Global, b.config.Global); err != nil {
+		return err
+	}
+
+	// Don't add the user to the state just to check it; users are only added when usage is recorded.
+	u, ok := b.state.Users[user]
+	if !ok {
+		return nil
+	}
+	b.rollWindows(u)
+	return b.check(scopeUser, user, u, b.config.PerUser)
+}
+
+// Record counts the usage against the user and global budgets.
+func (b *Budget) Record(ctx context.Context, user string, llmUsage api.LLMUsage) {
+	log := logs.FromContext(ctx)
+	cost, ok := b.pricing.Cost(llmUsage)
+	if !ok {
+		log.V(logs.Debug).Info("No price for model; only tokens will be counted against the budget", "provider", llmUsage.Provider, "model", llmUsage.Model)
+	}
+	tokens := int64(llmUsage.InputTokens + llmUsage.OutputTokens)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, u := range []*usage{b.state.Global, b.getUsage(user)} {
+		b.rollWindows(u)
+		u.Minute.Tokens += tokens
+		u.Minute.CostUSD += cost
+		u.Day.Tokens += tokens
+		u.Day.CostUSD += cost
+	}
+
+	b.updateMetrics(scopeGlobal, "", b.state.Global, b.config.Global)
+	b.updateMetrics(scopeUser, user, b.getUsage(user), b.config.PerUser)
+
+	if err := b.save(); err != nil {
+		log.Error(err, "Failed to persist budget state", "file", b.stateFile)
+	}
+}
+
+// getUsage returns the usage for the user creating it if necessary. Caller must hold the lock.
+func (b *Budget) getUsage(user string) *usage {
+	u, ok := b.state.Users[user]
+	if !ok {
+		u = &usage{}
+		b.state.Users[user] = u
+	}
+	b.rollWindows(u)
+	return u
+}
+
+// rollWindows resets the windows if the current time is outside them. Caller must hold the lock.
+func (b *Budget) rollWindows(u *usage) {
+	now := b.now().UTC()
+	minuteStart := now.Truncate(time.Minute)
+	if !u.Minute.Start.Equal(minuteStart) {
+		u.Minute = window{Start: minuteStart}
+	}
+
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !u.Day.Start.Equal(dayStart) {
+		u.Day = window{Start: dayStart}
+	}
+}
+
+func (b *Budget) check(scope string, user string, u *usage, limits *config.BudgetLimits) error {
+	if limits == nil {
+		return nil
+	}
+
+	type limitCheck struct {
+		window string
+		unit   string
+		limit  float64
+		used   float64
+		reset  time.Time
+	}
+
+	checks := []limitCheck{
+		{window: windowMinute, unit: unitTokens, limit: float64(limits.TokensPerMinute), used: float64(u.Minute.Tokens), reset: u.Minute.Start.Add(time.Minute)},
+		{window: windowDay, unit: unitTokens, limit: float64(limits.TokensPerDay), used: float64(u.Day.Tokens), reset: u.Day.Start.Add(24 * time.Hour)},
+		{window: windowMinute, unit: unitUSD, limit: limits.CostPerMinute, used: u.Minute.CostUSD, reset: u.Minute.Start.Add(time.Minute)},
+		{window: windowDay, unit: unitUSD, limit: limits.CostPerDay, used: u.Day.CostUSD, reset: u.Day.Start.Add(24 * time.Hour)},
+	}
+
+	for _, c := range checks {
+		if c.limit <= 0 || c.used < c.limit {
+			continue
+		}
+		rejectedCounter.WithLabelValues(scope, c.window, c.unit).Inc()
+		return &ExceededError{
+			Scope:   scope,
+			User:    user,
+			Window:  c.window,
+			Unit:    c.unit,
+			Limit:   c.limit,
+			Used:    c.used,
+			ResetAt: c.reset,
+		}
+	}
+	return nil
+}
+
+func (b *Budget) updateMetrics(scope string, user string, u *usage, limits *config.BudgetLimits) {
+	if limits == nil {
+		return
+	}
+	set := func(window string, unit string, limit float64, used float64) {
+		if limit <= 0 {
+			return
+		}
+		remaining := limit - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		remainingGauge.WithLabelValues(scope, user, window, unit).Set(remaining)
+	}
+	set(windowMinute, unitTokens, float64(limits.TokensPerMinute), float64(u.Minute.Tokens))
+	set(windowDay, unitTokens, float64(limits.TokensPerDay), float64(u.Day.Tokens))
+	set(windowMinute, unitUSD, limits.CostPerMinute, u.Minute.CostUSD)
+	set(windowDay, unitUSD, limits.CostPerDay, u.Day.CostUSD)
+}
+
+// pruneUsers removes the users whose daily window has ended. Their usage no longer counts against any budget so
+// removing them keeps the state from growing with every user ever seen. Caller must hold the lock.
+func (b *Budget) pruneUsers() {
+	now := b.now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for user, u := range b.state.Users {
+		if u.Day.Start.Before(dayStart) {
+			delete(b.state.Users, user)
+			remainingGauge.DeletePartialMatch(prometheus.Labels{"scope": scopeUser, "user": user})
+		}
+	}
+}
+
+// save prunes the users whose usage has expired and writes the state to disk. Caller must hold the lock.
+func (b *Budget) save() error {
+	b.pruneUsers()
+	if b.stateFile == "" {
+		return nil
+	}
+	raw, err := json.Marshal(b.state)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to marshal budget state")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.stateFile), 0755); err != nil {
+		return errors.Wrapf(err, "Failed to create directory for %s", b.stateFile)
+	}
+	// Write to a temporary file and rename it so a crash doesn't leave a partially written file.
+	tmp := b.stateFile + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0644); err != nil {
+		return errors.Wrapf(err, "Failed to write %s", tmp)
+	}
+	if err := os.Rename(tmp, b.stateFile); err != nil {
+		return errors.Wrapf(err, "Failed to rename %s to %s", tmp, b.stateFile)
+	}
+	return nil
+}
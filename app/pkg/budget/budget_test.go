@@ -0,0 +1,139 @@
+package budget
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+type fakeCompleter struct {
+	usage api.LLMUsage
+	calls int
+}
+
+func (f *fakeCompleter) Complete(ctx context.Context, systemPrompt string, message string) ([]*v1alpha1.Block, error) {
+	f.calls++
+	llms.RecordUsage(ctx, f.usage)
+	return []*v1alpha1.Block{}, nil
+}
+
+func Test_Budget(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "budget.json")
+	cfg := config.BudgetConfig{
+		Global: &config.BudgetLimits{
+			TokensPerDay: 250,
+		},
+		PerUser: &config.BudgetLimits{
+			TokensPerMinute: 100,
+		},
+	}
+
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	b, err := newBudget(cfg, llms.NewPriceTable(nil), stateFile, func() time.Time { return now })
+	if err != nil {
+		t.Fatalf("Failed to create budget: %+v", err)
+	}
+
+	fake := &fakeCompleter{
+		usage: api.LLMUsage{InputTokens: 80, OutputTokens: 20},
+	}
+	completer, err := NewCompleter(fake, b)
+	if err != nil {
+		t.Fatalf("Failed to create completer: %+v", err)
+	}
+
+	aliceCtx := WithUser(context.Background(), "alice")
+	bobCtx := WithUser(context.Background(), "bob")
+
+	if _, err := completer.Complete(aliceCtx, "", "hello"); err != nil {
+		t.Fatalf("First call should succeed: %+v", err)
+	}
+
+	// Alice has used her per minute budget.
+	_, err = completer.Complete(aliceCtx, "", "hello")
+	exceeded := &ExceededError{}
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("Expected ExceededError; got %v", err)
+	}
+	if exceeded.Scope != scopeUser || exceeded.Window != windowMinute {
+		t.Errorf("Unexpected error %+v", exceeded)
+	}
+
+	// Bob has his own budget.
+	if _, err := completer.Complete(bobCtx, "", "hello"); err != nil {
+		t.Fatalf("Call for bob should succeed: %+v", err)
+	}
+
+	// After a minute alice's budget resets.
+	now = now.Add(time.Minute)
+	if _, err := completer.Complete(aliceCtx, "", "hello"); err != nil {
+		t.Fatalf("Call after the minute rolled over should succeed: %+v", err)
+	}
+
+	// Reload the budget from disk; the global daily budget of 250 tokens has been used up.
+	reloaded, err := newBudget(cfg, llms.NewPriceTable(nil), stateFile, func() time.Time { return now.Add(2 * time.Minute) })
+	if err != nil {
+		t.Fatalf("Failed to reload budget: %+v", err)
+	}
+	err = reloaded.Check("carol")
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("Expected ExceededError after reload; got %v", err)
+	}
+	if exceeded.Scope != scopeGlobal || exceeded.Window != windowDay {
+		t.Errorf("Unexpected error %+v", exceeded)
+	}
+
+	if fake.calls != 3 {
+		t.Errorf("Expected the underlying completer to be called 3 times; got %d", fake.calls)
+	}
+}
+
+func Test_BudgetPrunesUsers(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "budget.json")
+	cfg := config.BudgetConfig{
+		PerUser: &config.BudgetLimits{
+			TokensPerDay: 1000,
+		},
+	}
+
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	b, err := newBudget(cfg, llms.NewPriceTable(nil), stateFile, func() time.Time { return now })
+	if err != nil {
+		t.Fatalf("Failed to create budget: %+v", err)
+	}
+
+	llmUsage := api.LLMUsage{InputTokens: 10}
+	for i := 0; i < 5; i++ {
+		b.Record(context.Background(), fmt.Sprintf("user-%d", i), llmUsage)
+	}
+	if err := b.Check("never-seen"); err != nil {
+		t.Fatalf("Check failed: %+v", err)
+	}
+	if len(b.state.Users) != 5 {
+		t.Fatalf("Expected 5 users; got %d", len(b.state.Users))
+	}
+
+	// The next day the usage of the users from the previous day has expired so they are pruned when the state is
+	// saved.
+	now = now.Add(24 * time.Hour)
+	b.Record(context.Background(), "alice", llmUsage)
+	if len(b.state.Users) != 1 {
+		t.Errorf("Expected only alice after pruning; got %v", b.state.Users)
+	}
+
+	reloaded, err := newBudget(cfg, llms.NewPriceTable(nil), stateFile, func() time.Time { return now.Add(24 * time.Hour) })
+	if err != nil {
+		t.Fatalf("Failed to reload budget: %+v", err)
+	}
+	if len(reloaded.state.Users) != 0 {
+		t.Errorf("Expected all users to be pruned on load; got %v", reloaded.state.Users)
+	}
+}
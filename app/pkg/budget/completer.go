@@ -0,0 +1,94 @@
+package budget
+
+import (
+	"context"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+type userKey struct{}
+
+// WithUser returns a context identifying the user usage should be attributed to.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the user in the context or DefaultUser if there isn't one.
+func UserFromContext(ctx context.Context) string {
+	user, ok := ctx.Value(userKey{}).(string)
+	if !ok || user == "" {
+		return DefaultUser
+	}
+	return user
+}
+
+// Completer wraps a completer and enforces the budget before each call.
+type Completer struct {
+	completer llms.Completer
+	budget    *Budget
+}
+
+// NewCompleter creates a completer that enforces the budget.
+func NewCompleter(completer llms.Completer, budget *Budget) (*Completer, error) {
+	if completer == nil {
+		return nil, errors.New("Completer is required")
+	}
+	if budget == nil {
+		return nil, errors.New("Budget is required")
+	}
+	return &Completer{
+		completer: completer,
+		budget:    budget,
+	}, nil
+}
+
+func (c *Completer) Complete(ctx context.Context, systemPrompt string, message string) ([]*v1alpha1.Block, error) {
+	user := UserFromContext(ctx)
+	if err := c.budget.Check(user); err != nil {
+		return nil, err
+	}
+
+	ctx = llms.WithUsageRecorder(ctx, func(usage api.LLMUsage) {
+		c.budget.Record(ctx, user, usage)
+	})
+	return c.completer.Complete(ctx, systemPrompt, message)
+}
+
+// CompleteN enforces the budget once before generating all the candidates.
+func (c *Completer) CompleteN(ctx context.Context, systemPrompt string, message string, n int) ([][]*v1alpha1.Block, error) {
+	user := UserFromContext(ctx)
+	if err := c.budget.Check(user); err != nil {
+		return nil, err
+	}
+
+	ctx = llms.WithUsageRecorder(ctx, func(usage api.LLMUsage) {
+		c.budget.Record(ctx, user, usage)
+	})
+	return llms.CompleteN(ctx, c.completer, systemPrompt, message, n)
+}
+
+// SupportsTools returns whether the wrapped completer supports tools.
+func (c *Completer) SupportsTools() bool {
+	return llms.SupportsTools(c.completer)
+}
+
+// CompleteWithTools enforces the budget before each call in a conversation with tools. It returns an error if the
+// wrapped completer doesn't support tools.
+func (c *Completer) CompleteWithTools(ctx context.Context, systemPrompt string, messages []llms.Message, tools []llms.Tool) (*llms.Message, error) {
+	tc, ok := c.completer.(llms.ToolCompleter)
+	if !ok || !tc.SupportsTools() {
+		return nil, errors.WithStack(llms.ErrToolsNotSupported)
+	}
+	user := UserFromContext(ctx)
+	if err := c.budget.Check(user); err != nil {
+		return nil, err
+	}
+
+	ctx = llms.WithUsageRecorder(ctx, func(usage api.LLMUsage) {
+		c.budget.Record(ctx, user, usage)
+	})
+	return tc.CompleteWithTools(ctx, systemPrompt, messages, tools)
+}
@@ -0,0 +1,2 @@
+// Package budget enforces limits on the tokens and money spent on LLM calls.
+package budget
@@ -28,11 +28,22 @@ import (
 //such as files, environment variables, and command line flags. After merging, viper unmarshals the configuration into the Configuration struct, which is then used throughout the application.
 
 const (
-	ConfigFlagName    = "config"
-	LevelFlagName     = "level"
-	appName           = "foyle"
-	ConfigDir         = "." + appName
-	defaultMaxResults = 3
+	ConfigFlagName        = "config"
+	LevelFlagName         = "level"
+	appName               = "foyle"
+	ConfigDir             = "." + appName
+	defaultMaxResults     = 3
+	defaultMaxSuffixCells = 3
+
+	defaultMaxToolCalls       = 5
+	defaultToolTimeoutSeconds = 30
+
+	defaultCacheMaxEntries = 1000
+	defaultCacheTTLSeconds = 600
+
+	defaultMaxImages = 3
+
+	defaultMinLLMChars = 20000
 
 	// defaultHTTPPort should be kept in sync with the default in RunMe
 	// https://github.com/stateful/vscode-runme/blob/f1cc965ab0c4cdffa9adb70922e2da792d7e23de/package.json#L849
@@ -40,11 +51,22 @@ const (
 	defaultHTTPPort = 8877
 
 	defaultRagEnabled = true
+
+	defaultRetentionInterval = time.Hour
+
+	// SQLiteDriver and PostgresDriver are the databases the sessions can be stored in.
+	SQLiteDriver   = "sqlite"
+	PostgresDriver = "postgres"
 )
 
 var (
 	// globalV is the global instance of viper
 	globalV *viper.Viper
+
+	// defaultInterpreters are the interpreters used for code cells when none is configured for the language.
+	defaultInterpreters = map[string][]string{
+		"python": {"python3"},
+	}
 )
 
 // Config represents the persistent configuration data for Foyle.
@@ -73,6 +95,16 @@ type Config struct {
 
 	Replicate *ReplicateConfig `json:"replicate,omitempty" yaml:"replicate,omitempty"`
 	Anthropic *AnthropicConfig `json:"anthropic,omitempty" yaml:"anthropic,omitempty"`
+	Gemini    *GeminiConfig    `json:"gemini,omitempty" yaml:"gemini,omitempty"`
+
+	// Pricing overrides or extends the built in table of model prices used to compute the cost of LLM calls.
+	Pricing []api.ModelPrice `json:"pricing,omitempty" yaml:"pricing,omitempty"`
+
+	// Budget limits the tokens and money spent on LLM calls. A nil value means no limits.
+	Budget *BudgetConfig `json:"budget,omitempty" yaml:"budget,omitempty"`
+
+	// Executor configures how code cells are executed.
+	Executor *ExecutorConfig `json:"executor,omitempty" yaml:"executor,omitempty"`
 
 	// configFile is the configuration file used. It is
 	configFile string
@@ -91,9 +123,39 @@ type LearnerConfig struct {
 	ExampleDirs []string `json:"exampleDirs" yaml:"exampleDirs"`
 }
 
+// BudgetConfig configures limits on LLM usage.
+type BudgetConfig struct {
+	// Global limits apply to all requests combined.
+	Global *BudgetLimits `json:"global,omitempty" yaml:"global,omitempty"`
+	// PerUser limits apply to each user separately. The user is identified by the Foyle-User header. The header
+	// isn't authenticated so any client can choose the user it is attributed to; per user limits protect against
+	// runaway notebooks, not against clients that use another user's budget. Use Global to bound the total.
+	PerUser *BudgetLimits `json:"perUser,omitempty" yaml:"perUser,omitempty"`
+}
+
+// BudgetLimits are the maximum usage allowed in a window. A value of zero means no limit.
+type BudgetLimits struct {
+	TokensPerMinute int64   `json:"tokensPerMinute,omitempty" yaml:"tokensPerMinute,omitempty"`
+	TokensPerDay    int64   `json:"tokensPerDay,omitempty" yaml:"tokensPerDay,omitempty"`
+	CostPerMinute   float64 `json:"costPerMinute,omitempty" yaml:"costPerMinute,omitempty"`
+	CostPerDay      float64 `json:"costPerDay,omitempty" yaml:"costPerDay,omitempty"`
+}
+
+// ExecutorConfig configures how code cells are executed.
+type ExecutorConfig struct {
+	// Interpreters maps the language of a code cell (e.g. python or sql) to the command used to run it. The contents
+	// of the cell are passed to the command on stdin; e.g. ["sqlite3", "/path/to/db.sqlite"]. Shell cells are always
+	// run by the executor itself. python defaults to python3.
+	Interpreters map[string][]string `json:"interpreters,omitempty" yaml:"interpreters,omitempty"`
+}
+
 type EvalConfig struct {
 	// GCPServiceAccount is the service account to use to update Google Sheets
 	GCPServiceAccount string `json:"gcpServiceAccount" yaml:"gcpServiceAccount"`
+
+	// ResultsDB configures the database the EvalService reads evaluation results from. Defaults to a SQLite
+	// database named results.sqlite in the configuration directory.
+	ResultsDB *DatabaseConfig `json:"resultsDB,omitempty" yaml:"resultsDB,omitempty"`
 }
 
 // ServerConfig configures the server
@@ -125,6 +187,10 @@ type OpenAIConfig struct {
 
 	// BaseURL is the baseURL for the API.
 	BaseURL string `json:"baseURL" yaml:"baseURL"`
+
+	// SupportsTools is whether the model supports function calling. Defaults to true; set it to false for OpenAI
+	// compatible servers (e.g. Ollama) whose models don't.
+	SupportsTools *bool `json:"supportsTools,omitempty" yaml:"supportsTools,omitempty"`
 }
 
 type AnthropicConfig struct {
@@ -135,9 +201,32 @@ type AnthropicConfig struct {
 	BaseURL string `json:"baseURL" yaml:"baseURL"`
 }
 
+type GeminiConfig struct {
+	// APIKeyFile is the path to the file containing the API key for the Gemini API. It isn't used with Vertex AI.
+	APIKeyFile string `json:"apiKeyFile" yaml:"apiKeyFile"`
+
+	// BaseURL is the baseURL for the API.
+	BaseURL string `json:"baseURL" yaml:"baseURL"`
+
+	// Vertex configures using Gemini through Vertex AI rather than the Gemini API. Vertex AI uses application
+	// default credentials.
+	Vertex *VertexConfig `json:"vertex,omitempty" yaml:"vertex,omitempty"`
+}
+
+type VertexConfig struct {
+	// Project is the GCP project to use.
+	Project string `json:"project" yaml:"project"`
+	// Location is the region to use e.g. us-central1.
+	Location string `json:"location" yaml:"location"`
+}
+
 type ReplicateConfig struct {
 	// APIKeyFile is the path to the file containing the API key
 	APIKeyFile string `json:"apiKeyFile" yaml:"apiKeyFile"`
+
+	// SupportsTools is whether the model supports function calling. Defaults to false because most of the models
+	// served by Replicate don't.
+	SupportsTools bool `json:"supportsTools,omitempty" yaml:"supportsTools,omitempty"`
 }
 
 type AzureOpenAIConfig struct {
@@ -203,6 +292,28 @@ type Logging struct {
 	MaxDelaySeconds int `json:"maxDelaySeconds,omitempty" yaml:"maxDelaySeconds,omitempty"`
 
 	LogFields *LogFields `json:"logFields,omitempty" yaml:"logFields,omitempty"`
+
+	// Retention configures how long the raw logs and the traces, blocks and sessions built from them are kept.
+	// If nil everything is kept forever.
+	Retention *RetentionConfig `json:"retention,omitempty" yaml:"retention,omitempty"`
+
+	// SessionsDB configures the database the sessions are stored in. Defaults to a SQLite database in the log
+	// directory.
+	SessionsDB *DatabaseConfig `json:"sessionsDB,omitempty" yaml:"sessionsDB,omitempty"`
+}
+
+// DatabaseConfig configures a SQL database. It is defined in the api package so experiments can use it too.
+type DatabaseConfig = api.DatabaseConfig
+
+// RetentionConfig configures the garbage collection of the logs directory.
+type RetentionConfig struct {
+	// MaxAge is how long to keep raw logs, traces, blocks and sessions; e.g. 720h. Zero means there is no age limit.
+	MaxAge time.Duration `json:"maxAge,omitempty" yaml:"maxAge,omitempty"`
+	// MaxRawLogsMB is the maximum size of the raw logs directory in megabytes. When it is exceeded the oldest log
+	// files that have already been processed are deleted. Zero means there is no size limit.
+	MaxRawLogsMB int `json:"maxRawLogsMB,omitempty" yaml:"maxRawLogsMB,omitempty"`
+	// Interval is how often the server garbage collects. Defaults to 1h.
+	Interval time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
 }
 
 // LogFields is the fields to use when logging to structured logging
@@ -222,6 +333,30 @@ type LogSink struct {
 
 type TelemetryConfig struct {
 	Honeycomb *HoneycombConfig `json:"honeycomb,omitempty" yaml:"honeycomb,omitempty"`
+	// OTLP configures exporting Foyle's traces as spans following the OpenTelemetry GenAI semantic conventions to an
+	// OTLP collector; e.g. Jaeger or Tempo.
+	OTLP *OTLPConfig `json:"otlp,omitempty" yaml:"otlp,omitempty"`
+}
+
+const (
+	// OTLPProtocolGRPC exports spans using OTLP over gRPC.
+	OTLPProtocolGRPC = "grpc"
+	// OTLPProtocolHTTP exports spans using OTLP over HTTP with protobuf payloads.
+	OTLPProtocolHTTP = "http/protobuf"
+)
+
+type OTLPConfig struct {
+	// Endpoint is the host and port of the collector; e.g. localhost:4318.
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	// Protocol is either grpc or http/protobuf. Defaults to http/protobuf.
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	// Insecure disables TLS; e.g. for a collector running locally.
+	Insecure bool `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+	// Headers are sent with every export request; e.g. to authenticate with the collector.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// IncludeContent adds the prompts, completions and retrieved examples to the spans. It is off by default
+	// because they are large and may contain sensitive data.
+	IncludeContent bool `json:"includeContent,omitempty" yaml:"includeContent,omitempty"`
 }
 
 type HoneycombConfig struct {
@@ -253,6 +388,11 @@ func (c *Config) GetLogDir() string {
 	return filepath.Join(c.GetConfigDir(), "logs")
 }
 
+// GetBudgetFile returns the file used to persist the usage counted against the budget.
+func (c *Config) GetBudgetFile() string {
+	return filepath.Join(c.GetLogDir(), "budget.json")
+}
+
 func (c *Config) GetLogOffsetsFile() string {
 	return filepath.Join(c.GetLogDir(), "offsets.v1.json")
 }
@@ -273,10 +413,45 @@ func (c *Config) GetTracesDBDir() string {
 	return filepath.Join(c.GetLogDir(), "traces")
 }
 
+// GetQueuesDBDir returns the directory of the database backing the analyzer's and learner's work queues.
+func (c *Config) GetQueuesDBDir() string {
+	return filepath.Join(c.GetLogDir(), "queues")
+}
+
 func (c *Config) GetSessionsDB() string {
 	return filepath.Join(c.GetLogDir(), "sessions.sqllite3")
 }
 
+// GetSessionsDBConfig returns the configuration of the sessions database with the defaults filled in.
+func (c *Config) GetSessionsDBConfig() DatabaseConfig {
+	d := DatabaseConfig{}
+	if c.Logging.SessionsDB != nil {
+		d = *c.Logging.SessionsDB
+	}
+	if d.Driver == "" {
+		d.Driver = SQLiteDriver
+	}
+	if d.Driver == SQLiteDriver && d.DSN == "" {
+		d.DSN = c.GetSessionsDB()
+	}
+	return d
+}
+
+// GetResultsDBConfig returns the configuration of the evaluation results database with the defaults filled in.
+func (c *Config) GetResultsDBConfig() DatabaseConfig {
+	d := DatabaseConfig{}
+	if c.Eval != nil && c.Eval.ResultsDB != nil {
+		d = *c.Eval.ResultsDB
+	}
+	if d.Driver == "" {
+		d.Driver = SQLiteDriver
+	}
+	if d.Driver == SQLiteDriver && d.DSN == "" {
+		d.DSN = filepath.Join(c.GetConfigDir(), "results.sqlite")
+	}
+	return d
+}
+
 func (c *Config) GetTrainingDirs() []string {
 	dirs := make([]string, 0)
 	if c.Learner != nil {
@@ -328,6 +503,185 @@ func (c *Config) GetAssetsDir() string {
 	return filepath.Join(c.GetConfigDir(), "assets")
 }
 
+// GetStreamConfig returns the configuration for streaming completions.
+func (c *Config) GetStreamConfig() api.StreamConfig {
+	if c.Agent == nil || c.Agent.Stream == nil {
+		return api.StreamConfig{}
+	}
+	return *c.Agent.Stream
+}
+
+// GetNumCandidates returns the number of candidate completions to generate for each request.
+func (c *Config) GetNumCandidates() int {
+	if c.Agent == nil || c.Agent.NumCandidates <= 0 {
+		return 1
+	}
+	return c.Agent.NumCandidates
+}
+
+// UseFIM returns true if fill in the middle prompts are enabled.
+func (c *Config) UseFIM() bool {
+	if c.Agent == nil || c.Agent.FIM == nil {
+		return false
+	}
+	return c.Agent.FIM.Enabled
+}
+
+// GetFIMConfig returns the configuration for fill in the middle prompts with defaults applied.
+func (c *Config) GetFIMConfig() api.FIMConfig {
+	cfg := api.FIMConfig{}
+	if c.Agent != nil && c.Agent.FIM != nil {
+		cfg = *c.Agent.FIM
+	}
+	if cfg.MaxSuffixCells <= 0 {
+		cfg.MaxSuffixCells = defaultMaxSuffixCells
+	}
+	return cfg
+}
+
+// UseTools returns true if the agent can run read-only commands to gather information.
+func (c *Config) UseTools() bool {
+	if c.Agent == nil || c.Agent.Tools == nil {
+		return false
+	}
+	return c.Agent.Tools.Enabled
+}
+
+// ModelSupportsTools returns true if the models of an OpenAI compatible provider support function calling.
+func (c *Config) ModelSupportsTools(provider api.ModelProvider) bool {
+	if provider == api.ModelProviderReplicate {
+		return c.Replicate != nil && c.Replicate.SupportsTools
+	}
+	if c.OpenAI == nil || c.OpenAI.SupportsTools == nil {
+		return true
+	}
+	return *c.OpenAI.SupportsTools
+}
+
+// GetToolsConfig returns the configuration for the tool using agent with defaults applied.
+func (c *Config) GetToolsConfig() api.ToolsConfig {
+	cfg := api.ToolsConfig{}
+	if c.Agent != nil && c.Agent.Tools != nil {
+		cfg = *c.Agent.Tools
+	}
+	if cfg.MaxCalls <= 0 {
+		cfg.MaxCalls = defaultMaxToolCalls
+	}
+	if cfg.TimeoutSeconds <= 0 {
+		cfg.TimeoutSeconds = defaultToolTimeoutSeconds
+	}
+	return cfg
+}
+
+// UseStructuredOutput returns true if the agent should ask the model for the cells as structured output.
+func (c *Config) UseStructuredOutput() bool {
+	if c.Agent == nil || c.Agent.StructuredOutput == nil {
+		return false
+	}
+	return c.Agent.StructuredOutput.Enabled
+}
+
+// UseVision returns true if images output by cells should be sent to the model.
+func (c *Config) UseVision() bool {
+	if c.Agent == nil || c.Agent.Vision == nil {
+		return false
+	}
+	return c.Agent.Vision.Enabled
+}
+
+// GetVisionConfig returns the vision configuration with defaults applied.
+func (c *Config) GetVisionConfig() api.VisionConfig {
+	cfg := api.VisionConfig{}
+	if c.Agent != nil && c.Agent.Vision != nil {
+		cfg = *c.Agent.Vision
+	}
+	if cfg.MaxImages <= 0 {
+		cfg.MaxImages = defaultMaxImages
+	}
+	return cfg
+}
+
+// GetOutputReducerConfig returns the configuration for reducing outputs with defaults applied.
+func (c *Config) GetOutputReducerConfig() api.OutputReducerConfig {
+	cfg := api.OutputReducerConfig{}
+	if c.Agent != nil && c.Agent.OutputReducer != nil {
+		cfg = *c.Agent.OutputReducer
+	}
+	if cfg.Strategy == "" {
+		cfg.Strategy = api.OutputReducerSmart
+	}
+	if cfg.MinLLMChars <= 0 {
+		cfg.MinLLMChars = defaultMinLLMChars
+	}
+	return cfg
+}
+
+// UsePromptCaching returns true if the static prefix of prompts should be marked for provider side caching.
+func (c *Config) UsePromptCaching() bool {
+	if c.Agent == nil || c.Agent.Cache == nil {
+		return false
+	}
+	return c.Agent.Cache.PromptCaching
+}
+
+// UseResponseCache returns true if responses should be cached locally.
+func (c *Config) UseResponseCache() bool {
+	if c.Agent == nil || c.Agent.Cache == nil {
+		return false
+	}
+	return c.Agent.Cache.Responses
+}
+
+// GetCacheConfig returns the cache configuration with defaults applied.
+func (c *Config) GetCacheConfig() api.CacheConfig {
+	cfg := api.CacheConfig{}
+	if c.Agent != nil && c.Agent.Cache != nil {
+		cfg = *c.Agent.Cache
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = defaultCacheMaxEntries
+	}
+	if cfg.TTLSeconds <= 0 {
+		cfg.TTLSeconds = defaultCacheTTLSeconds
+	}
+	return cfg
+}
+
+// UseRouter returns true if completions should be routed across several models.
+func (c *Config) UseRouter() bool {
+	if c.Agent == nil || c.Agent.Router == nil {
+		return false
+	}
+	r := c.Agent.Router
+	return len(r.Fallbacks) > 0 || r.Small != nil
+}
+
+// WithModel returns a copy of the configuration that uses the given model. It is used to create completers for
+// the models the router can route to.
+func (c *Config) WithModel(m api.ModelConfig) Config {
+	newCfg := *c
+	agent := api.AgentConfig{}
+	if c.Agent != nil {
+		agent = *c.Agent
+	}
+	agent.Model = m.Model
+	agent.ModelProvider = m.ModelProvider
+	newCfg.Agent = &agent
+	return newCfg
+}
+
+// GetInterpreter returns the command used to run code cells in the given language. lang should be normalized; e.g.
+// with docs.NormalizeLanguage. The second value is false if no interpreter is configured for the language.
+func (c *Config) GetInterpreter(lang string) ([]string, bool) {
+	if c.Executor != nil {
+		if command, ok := c.Executor.Interpreters[lang]; ok && len(command) > 0 {
+			return command, true
+		}
+	}
+	command, ok := defaultInterpreters[lang]
+	return command, ok
+}
+
 func (c *Config) UseRAG() bool {
 	if c.Agent == nil || c.Agent.RAG == nil {
 		return defaultRagEnabled
@@ -335,6 +689,14 @@ func (c *Config) UseRAG() bool {
 	return c.Agent.RAG.Enabled
 }
 
+// GetEmbeddingProvider returns the provider used to compute embeddings for RAG.
+func (c *Config) GetEmbeddingProvider() api.ModelProvider {
+	if c.Agent == nil || c.Agent.RAG == nil || c.Agent.RAG.EmbeddingProvider == "" {
+		return api.ModelProviderOpenAI
+	}
+	return c.Agent.RAG.EmbeddingProvider
+}
+
 func (c *Config) RagMaxResults() int {
 	if c.Agent == nil || c.Agent.RAG == nil {
 		return -1
@@ -345,6 +707,22 @@ func (c *Config) RagMaxResults() int {
 	return c.Agent.RAG.MaxResults
 }
 
+// UseOTLP returns true if traces should be exported to an OTLP collector.
+func (c *Config) UseOTLP() bool {
+	if c.Telemetry == nil || c.Telemetry.OTLP == nil {
+		return false
+	}
+	return c.Telemetry.OTLP.Endpoint != ""
+}
+
+// GetOTLPProtocol returns the protocol used to export traces to the OTLP collector.
+func (c *Config) GetOTLPProtocol() string {
+	if c.Telemetry == nil || c.Telemetry.OTLP == nil || c.Telemetry.OTLP.Protocol == "" {
+		return OTLPProtocolHTTP
+	}
+	return c.Telemetry.OTLP.Protocol
+}
+
 func (c *Config) UseHoneycomb() bool {
 	if c.Telemetry == nil {
 		return false
@@ -466,6 +844,27 @@ func (c *Config) APIBaseURL() string {
 	return fmt.Sprintf("http://%s:%d/%s", c.Server.BindAddress, c.Server.HttpPort, c.APIPrefix())
 }
 
+// UseRetention returns true if logs should be garbage collected.
+func (c *Config) UseRetention() bool {
+	r := c.Logging.Retention
+	if r == nil {
+		return false
+	}
+	return r.MaxAge > 0 || r.MaxRawLogsMB > 0
+}
+
+// GetRetentionConfig returns the retention config with the defaults filled in.
+func (c *Config) GetRetentionConfig() RetentionConfig {
+	r := RetentionConfig{}
+	if c.Logging.Retention != nil {
+		r = *c.Logging.Retention
+	}
+	if r.Interval <= 0 {
+		r.Interval = defaultRetentionInterval
+	}
+	return r
+}
+
 func (c *Config) GetLogsMaxDelaySeconds() int {
 	return c.Logging.MaxDelaySeconds
 }
@@ -0,0 +1,148 @@
+package dbutil
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotFound is returned when a key isn't in the store.
+	ErrNotFound = errors.New("not found")
+	// ErrReadOnly is returned when writing to a store that was opened read only.
+	ErrReadOnly = errors.New("store is read only")
+)
+
+// KV is a key value store. The traces, blocks and log entries are stored in KV stores. It is the subset of pebble
+// that foyle uses so that the databases can be backed by something other than a pebble database opened by the
+// current process; e.g. an in memory store in tests or the server's API in commands that run while the server has
+// the pebble databases open.
+type KV interface {
+	// Get returns the value of key. The error wraps ErrNotFound if the key doesn't exist.
+	Get(key []byte) ([]byte, error)
+	// Set sets the value of key.
+	Set(key []byte, value []byte) error
+	// Delete deletes the keys in a single batch. Keys that don't exist are ignored.
+	Delete(keys ...[]byte) error
+	// Apply applies the writes in the batch atomically.
+	Apply(batch *Batch) error
+	// Scan calls visit with every key that starts with prefix and its value in key order; a nil prefix visits every
+	// key. The slices are only valid until visit returns. Scan stops and returns the error if visit returns an error.
+	Scan(prefix []byte, visit func(key []byte, value []byte) error) error
+	// Close closes the store.
+	Close() error
+}
+
+// Batch is a set of writes that KV.Apply applies atomically. Writes are applied in the order they were added.
+type Batch struct {
+	writes []batchWrite
+}
+
+type batchWrite struct {
+	key   []byte
+	value []byte
+	// del is true if the key is deleted.
+	del bool
+}
+
+// NewBatch creates an empty batch.
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// Set adds a write that sets the value of key.
+func (b *Batch) Set(key []byte, value []byte) {
+	b.writes = append(b.writes, batchWrite{key: key, value: value})
+}
+
+// Delete adds a write that deletes key. Deleting a key that doesn't exist is ignored.
+func (b *Batch) Delete(key []byte) {
+	b.writes = append(b.writes, batchWrite{key: key, del: true})
+}
+
+// Compacter is implemented by stores that can reclaim the space of deleted keys.
+type Compacter interface {
+	// Compact compacts the keys in [start, end).
+	Compact(start []byte, end []byte) error
+}
+
+// MemKV is a KV that is stored in memory.
+type MemKV struct {
+	mu     sync.RWMutex
+	values map[string][]byte
+}
+
+var _ KV = (*MemKV)(nil)
+
+// NewMemKV creates an empty in memory store.
+func NewMemKV() *MemKV {
+	return &MemKV{values: make(map[string][]byte)}
+}
+
+func (m *MemKV) Get(key []byte) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.values[string(key)]
+	if !ok {
+		return nil, errors.Wrapf(ErrNotFound, "key %s", string(key))
+	}
+	return append([]byte{}, v...), nil
+}
+
+func (m *MemKV) Set(key []byte, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (m *MemKV) Delete(keys ...[]byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, k := range keys {
+		delete(m.values, string(k))
+	}
+	return nil
+}
+
+func (m *MemKV) Apply(batch *Batch) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, w := range batch.writes {
+		if w.del {
+			delete(m.values, string(w.key))
+			continue
+		}
+		m.values[string(w.key)] = append([]byte{}, w.value...)
+	}
+	return nil
+}
+
+// Scan visits a snapshot of the store so visit can modify the store.
+func (m *MemKV) Scan(prefix []byte, visit func(key []byte, value []byte) error) error {
+	m.mu.RLock()
+	keys := make([]string, 0, len(m.values))
+	values := make(map[string][]byte, len(m.values))
+	for k, v := range m.values {
+		if !strings.HasPrefix(k, string(prefix)) {
+			continue
+		}
+		keys = append(keys, k)
+		values[k] = v
+	}
+	m.mu.RUnlock()
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := visit([]byte(k), append([]byte{}, values[k]...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MemKV) Close() error {
+	return nil
+}
@@ -0,0 +1,216 @@
+package dbutil
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jlewi/monogo/helpers"
+	"github.com/pkg/errors"
+)
+
+func Test_KV(t *testing.T) {
+	type testCase struct {
+		name string
+		open func(t *testing.T) KV
+	}
+
+	cases := []testCase{
+		{
+			name: "memory",
+			open: func(t *testing.T) KV {
+				return NewMemKV()
+			},
+		},
+		{
+			name: "pebble",
+			open: func(t *testing.T) KV {
+				db, err := OpenPebbleKV(filepath.Join(t.TempDir(), "db"), false)
+				if err != nil {
+					t.Fatalf("Failed to open database: %+v", err)
+				}
+				return db
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := c.open(t)
+			defer helpers.DeferIgnoreError(db.Close)
+
+			if _, err := db.Get([]byte("a")); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Expected ErrNotFound; got %v", err)
+			}
+
+			for _, k := range []string{"c", "a", "b"} {
+				if err := db.Set([]byte(k), []byte("value-"+k)); err != nil {
+					t.Fatalf("Failed to set %s: %+v", k, err)
+				}
+			}
+
+			v, err := db.Get([]byte("b"))
+			if err != nil {
+				t.Fatalf("Failed to get b: %+v", err)
+			}
+			if string(v) != "value-b" {
+				t.Errorf("Expected value-b; got %s", string(v))
+			}
+
+			if err := db.Delete([]byte("b"), []byte("missing")); err != nil {
+				t.Fatalf("Failed to delete keys: %+v", err)
+			}
+
+			var keys []string
+			if err := db.Scan(nil, func(key []byte, value []byte) error {
+				keys = append(keys, string(key)+"="+string(value))
+				return nil
+			}); err != nil {
+				t.Fatalf("Failed to scan: %+v", err)
+			}
+			if d := cmp.Diff([]string{"a=value-a", "c=value-c"}, keys); d != "" {
+				t.Errorf("Unexpected keys:\n%s", d)
+			}
+
+			batch := NewBatch()
+			batch.Set([]byte("p/1"), []byte("one"))
+			batch.Set([]byte("p/2"), []byte("two"))
+			batch.Set([]byte("q/1"), []byte("other"))
+			batch.Delete([]byte("a"))
+			if err := db.Apply(batch); err != nil {
+				t.Fatalf("Failed to apply batch: %+v", err)
+			}
+			if _, err := db.Get([]byte("a")); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Expected a to be deleted by the batch; got %v", err)
+			}
+
+			keys = nil
+			if err := db.Scan([]byte("p/"), func(key []byte, value []byte) error {
+				keys = append(keys, string(key)+"="+string(value))
+				return nil
+			}); err != nil {
+				t.Fatalf("Failed to scan prefix: %+v", err)
+			}
+			if d := cmp.Diff([]string{"p/1=one", "p/2=two"}, keys); d != "" {
+				t.Errorf("Unexpected keys with prefix p/:\n%s", d)
+			}
+
+			stop := errors.New("stop")
+			count := 0
+			err = db.Scan(nil, func(key []byte, value []byte) error {
+				count++
+				return stop
+			})
+			if !errors.Is(err, stop) || count != 1 {
+				t.Errorf("Expected Scan to stop after the first key with the visit error; got %v after %d keys", err, count)
+			}
+		})
+	}
+}
+
+func Test_PebbleKVReadOnly(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "db")
+	if _, err := OpenPebbleKV(dir, true); err == nil {
+		t.Errorf("Expected opening a database that doesn't exist read only to fail")
+	}
+
+	db, err := OpenPebbleKV(dir, false)
+	if err != nil {
+		t.Fatalf("Failed to open database: %+v", err)
+	}
+	if err := db.Set([]byte("a"), []byte("1")); err != nil {
+		t.Fatalf("Failed to set a: %+v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Failed to close database: %+v", err)
+	}
+
+	db, err = OpenPebbleKV(dir, true)
+	if err != nil {
+		t.Fatalf("Failed to open database read only: %+v", err)
+	}
+	defer helpers.DeferIgnoreError(db.Close)
+
+	v, err := db.Get([]byte("a"))
+	if err != nil {
+		t.Fatalf("Failed to get a: %+v", err)
+	}
+	if string(v) != "1" {
+		t.Errorf("Expected 1; got %s", string(v))
+	}
+	if err := db.Set([]byte("b"), []byte("2")); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly; got %v", err)
+	}
+	if err := db.Delete([]byte("a")); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly; got %v", err)
+	}
+	batch := NewBatch()
+	batch.Set([]byte("b"), []byte("2"))
+	if err := db.Apply(batch); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly; got %v", err)
+	}
+}
+
+func Test_IsLocked(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "db")
+	db, err := OpenPebbleKV(dir, false)
+	if err != nil {
+		t.Fatalf("Failed to open database: %+v", err)
+	}
+	defer helpers.DeferIgnoreError(db.Close)
+
+	// Opening the database a second time in the same process fails but not because another process holds the lock.
+	if _, err := OpenPebbleKV(dir, true); err == nil || IsLocked(err) {
+		t.Errorf("Expected an error that isn't a lock error; got %v", err)
+	}
+
+	// The errno of a lock held by another process depends on the platform.
+	for _, errno := range []syscall.Errno{syscall.EAGAIN, syscall.EACCES} {
+		if !IsLocked(lockError(dir, errors.Wrapf(errno, "Failed to lock %s", dir))) {
+			t.Errorf("Expected %v to be a lock error", errno)
+		}
+	}
+
+	// A permission error isn't a lock error. Without a LOCK file that we can open the errno can't come from locking it.
+	missing := filepath.Join(t.TempDir(), "missing")
+	if err := lockError(missing, errors.Wrapf(syscall.EACCES, "Failed to open %s", missing)); IsLocked(err) || !errors.Is(err, syscall.EACCES) {
+		t.Errorf("Expected the permission error to be returned; got %v", err)
+	}
+
+	noPerms := filepath.Join(t.TempDir(), "db")
+	other, err := OpenPebbleKV(noPerms, false)
+	if err != nil {
+		t.Fatalf("Failed to open database: %+v", err)
+	}
+	if err := other.Close(); err != nil {
+		t.Fatalf("Failed to close database: %+v", err)
+	}
+	if err := os.Chmod(filepath.Join(noPerms, "LOCK"), 0); err != nil {
+		t.Fatalf("Failed to change permissions: %v", err)
+	}
+	if os.Geteuid() == 0 {
+		t.Skipf("Root can open the LOCK file regardless of its permissions")
+	}
+	_, err = OpenPebbleKV(noPerms, true)
+	if err == nil || IsLocked(err) || !errors.Is(err, syscall.EACCES) {
+		t.Errorf("Expected a permission error that isn't a lock error; got %v", err)
+	}
+}
+
+func Test_PrefixUpperBound(t *testing.T) {
+	cases := []struct {
+		prefix   []byte
+		expected []byte
+	}{
+		{prefix: []byte("dead/"), expected: []byte("dead0")},
+		{prefix: []byte{'a', 0xff}, expected: []byte("b")},
+		{prefix: []byte{0xff, 0xff}, expected: nil},
+	}
+	for _, c := range cases {
+		if d := cmp.Diff(c.expected, prefixUpperBound(c.prefix)); d != "" {
+			t.Errorf("Unexpected upper bound for %q:\n%s", c.prefix, d)
+		}
+	}
+}
@@ -5,26 +5,25 @@ import (
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
-	"github.com/cockroachdb/pebble"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
 )
 
-// LockingDB is a wrapper around a Pebble DB that provides row locking functionality.
+// LockingDB is a wrapper around a KV store that provides row locking functionality.
 type LockingDB[T proto.Message] struct {
-	db         *pebble.DB
+	db         KV
 	newProto   func() T
 	getVersion func(T) string
 	setVersion func(T, string)
 	locks      sync.Map
 }
 
-// NewLockingDB constructs a new LockingDB with the given Pebble DB and proto message constructor.
+// NewLockingDB constructs a new LockingDB with the given KV store and proto message constructor.
 // We need to pass in a function to construct a new proto message because with generics it's not possible to create a
 // new instance of the type parameter for the generic.
 // We also need to pass in a function to get the version from the message.
-func NewLockingDB[T proto.Message](db *pebble.DB, newFunc func() T, getVersionFunc func(T) string, setVersionFunc func(T, string)) *LockingDB[T] {
+func NewLockingDB[T proto.Message](db KV, newFunc func() T, getVersionFunc func(T) string, setVersionFunc func(T, string)) *LockingDB[T] {
 	return &LockingDB[T]{db: db, newProto: newFunc, getVersion: getVersionFunc, setVersion: setVersionFunc}
 }
 
@@ -43,17 +42,13 @@ func (d *LockingDB[T]) Get(key string) (T, error) {
 func (d *LockingDB[T]) ReadModifyWrite(key string, modify func(T) error) error {
 	// Non-nil error means a non retryable error occurred
 	op := func() (bool, error) {
-		b, closer, err := d.db.Get([]byte(key))
-		if err != nil && !errors.Is(err, pebble.ErrNotFound) {
+		b, err := d.db.Get([]byte(key))
+		if err != nil && !errors.Is(err, ErrNotFound) {
 			return false, errors.Wrapf(err, "Failed to read record with key %s", key)
 		}
-		// Closer is nil on not found
-		if closer != nil {
-			defer closer.Close()
-		}
 
 		msg := d.newProto()
-		if !errors.Is(err, pebble.ErrNotFound) {
+		if !errors.Is(err, ErrNotFound) {
 			if err := proto.Unmarshal(b, msg); err != nil {
 				return false, errors.Wrapf(err, "Failed to unmarshal record with key %s", key)
 			}
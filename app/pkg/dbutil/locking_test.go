@@ -7,7 +7,6 @@ import (
 
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 
-	"github.com/cockroachdb/pebble"
 	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 	"github.com/jlewi/monogo/helpers"
 )
@@ -18,7 +17,7 @@ func Test_Locking(t *testing.T) {
 		t.Fatalf("Failed to create temp dir: %v", err)
 	}
 
-	baseDB, err := pebble.Open(oDir, &pebble.Options{})
+	baseDB, err := OpenPebbleKV(oDir, false)
 	if err != nil {
 		t.Fatalf("could not open database %s", oDir)
 	}
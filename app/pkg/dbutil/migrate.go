@@ -0,0 +1,197 @@
+package dbutil
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jlewi/foyle/app/pkg/logs"
+	"github.com/pkg/errors"
+)
+
+// migrationsTable records the migrations that have been applied to a SQL database.
+const migrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    component VARCHAR(255) NOT NULL,
+    version INT NOT NULL,
+    description TEXT NOT NULL,
+    applied_time TIMESTAMP NOT NULL,
+    PRIMARY KEY (component, version)
+);`
+
+// Dialect is the SQL dialect of a database.
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// Migration is a versioned change to the schema or data of a SQL database.
+type Migration struct {
+	// Version orders the migrations of a component. Versions start at 1 and must increase by 1.
+	Version int
+	// Description is a short human readable description of the migration.
+	Description string
+	// Up applies the migration. It runs in the same transaction that records the migration was applied.
+	Up func(ctx context.Context, tx *sql.Tx) error
+}
+
+// Column is a column to add to a table.
+type Column struct {
+	Name string
+	// Definition is everything after the column name in the column definition e.g. "INT NOT NULL DEFAULT 0".
+	Definition string
+}
+
+// Migrate applies the migrations of the component that haven't been applied to the database yet. Each migration
+// is applied in its own transaction so if a migration fails the ones before it stay applied. The component
+// identifies a set of migrations so several components can keep their tables in the same database.
+func Migrate(ctx context.Context, db *sql.DB, dialect Dialect, component string, migrations []Migration) error {
+	log := logs.FromContext(ctx)
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return errors.Errorf("Migrations for %s must be numbered consecutively from 1; migration %d has version %d", component, i, m.Version)
+		}
+		if m.Up == nil {
+			return errors.Errorf("Migration %d for %s has no Up function", m.Version, component)
+		}
+	}
+
+	if _, err := db.ExecContext(ctx, migrationsTable); err != nil {
+		return errors.Wrapf(err, "Failed to create the schema_migrations table")
+	}
+
+	current, err := MigrationVersion(ctx, db, dialect, component)
+	if err != nil {
+		return err
+	}
+	if current > len(migrations) {
+		return errors.Errorf("Database has version %d of %s but the latest version this binary knows is %d; was it written by a newer version of foyle?", current, component, len(migrations))
+	}
+
+	for _, m := range migrations[current:] {
+		log.Info("Applying database migration", "component", component, "version", m.Version, "description", m.Description)
+		if err := applyMigration(ctx, db, dialect, component, m); err != nil {
+			return errors.Wrapf(err, "Failed to apply migration %d (%s) for %s", m.Version, m.Description, component)
+		}
+	}
+	return nil
+}
+
+// MigrationVersion returns the latest migration of the component applied to the database; 0 if none have been.
+func MigrationVersion(ctx context.Context, db *sql.DB, dialect Dialect, component string) (int, error) {
+	var version sql.NullInt64
+	err := db.QueryRowContext(ctx, Rebind(dialect, "SELECT MAX(version) FROM schema_migrations WHERE component = ?"), component).Scan(&version)
+	if err != nil {
+		// SQLite and Postgres respectively report that the table doesn't exist.
+		if strings.Contains(err.Error(), "no such table") || strings.Contains(err.Error(), "does not exist") {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, "Failed to read the migration version of %s", component)
+	}
+	return int(version.Int64), nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, dialect Dialect, component string, m Migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to start transaction")
+	}
+	if err := m.Up(ctx, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, Rebind(dialect, "INSERT INTO schema_migrations (component, version, description, applied_time) VALUES (?, ?, ?, ?)"), component, m.Version, m.Description, time.Now().UTC()); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrapf(err, "Failed to record migration")
+	}
+	return errors.Wrapf(tx.Commit(), "Failed to commit migration")
+}
+
+// Rebind rewrites the ? placeholders in the query to the placeholders of the dialect. Postgres numbers its
+// placeholders $1, $2, ... The query must not contain ? other than as placeholders.
+func Rebind(dialect Dialect, query string) string {
+	if dialect != Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c != '?' {
+			b.WriteRune(c)
+			continue
+		}
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+// Exec returns a migration function that executes the statements in order.
+func Exec(stmts ...string) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		for _, stmt := range stmts {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return errors.Wrapf(err, "Failed to execute %s", stmt)
+			}
+		}
+		return nil
+	}
+}
+
+// AddColumns returns a migration function that adds the columns to the table. Columns the table already has are
+// skipped. This lets migrations add columns that databases created before migrations were tracked might have.
+// AddColumns only supports SQLite; Postgres migrations can use ADD COLUMN IF NOT EXISTS.
+func AddColumns(table string, columns ...Column) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		existing, err := tableColumns(ctx, tx, table)
+		if err != nil {
+			return err
+		}
+		for _, c := range columns {
+			if existing[c.Name] {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", table, c.Name, c.Definition)); err != nil {
+				return errors.Wrapf(err, "Failed to add column %s to table %s", c.Name, table)
+			}
+		}
+		return nil
+	}
+}
+
+// Steps returns a migration function that runs the functions in order.
+func Steps(steps ...func(ctx context.Context, tx *sql.Tx) error) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		for _, s := range steps {
+			if err := s(ctx, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// tableColumns returns the names of the columns of the table including generated columns.
+func tableColumns(ctx context.Context, tx *sql.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT name FROM pragma_table_xinfo('%s');", table))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get columns of table %s", table)
+	}
+	defer rows.Close()
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, errors.Wrapf(err, "Failed to read columns of table %s", table)
+		}
+		columns[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "Failed to read columns of table %s", table)
+	}
+	return columns, nil
+}
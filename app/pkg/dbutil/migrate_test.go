@@ -0,0 +1,73 @@
+package dbutil
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	_ "modernc.org/sqlite"
+)
+
+func Test_Migrate(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	migrations := []Migration{
+		{Version: 1, Description: "create", Up: Exec("CREATE TABLE items (id VARCHAR(255) PRIMARY KEY);")},
+		{Version: 2, Description: "add columns", Up: AddColumns("items", Column{Name: "name", Definition: "TEXT NOT NULL DEFAULT ''"})},
+	}
+	if err := Migrate(ctx, db, SQLite, "items", migrations); err != nil {
+		t.Fatalf("Migrate failed: %+v", err)
+	}
+	// Migrations that were applied aren't applied again; if they were creating the table would fail.
+	if err := Migrate(ctx, db, SQLite, "items", migrations); err != nil {
+		t.Fatalf("Migrate failed the second time: %+v", err)
+	}
+	if v, err := MigrationVersion(ctx, db, SQLite, "items"); err != nil || v != 2 {
+		t.Errorf("Expected version 2; got %d, %v", v, err)
+	}
+	// Versions are tracked separately for each component.
+	if v, err := MigrationVersion(ctx, db, SQLite, "other"); err != nil || v != 0 {
+		t.Errorf("Expected version 0 for other; got %d, %v", v, err)
+	}
+
+	// A failed migration is rolled back and isn't recorded.
+	failing := append(migrations, Migration{Version: 3, Description: "fails", Up: Steps(
+		AddColumns("items", Column{Name: "size", Definition: "INT NOT NULL DEFAULT 0"}),
+		func(ctx context.Context, tx *sql.Tx) error { return errors.New("some error") },
+	)})
+	if err := Migrate(ctx, db, SQLite, "items", failing); err == nil {
+		t.Fatalf("Expected the migration to fail")
+	}
+	if v, _ := MigrationVersion(ctx, db, SQLite, "items"); v != 2 {
+		t.Errorf("Expected version 2 after the failed migration; got %d", v)
+	}
+	if _, err := db.Exec("SELECT size FROM items"); err == nil {
+		t.Errorf("Expected the column added by the failed migration to be rolled back")
+	}
+
+	// A binary that knows fewer migrations than were applied refuses to run.
+	if err := Migrate(ctx, db, SQLite, "items", migrations[:1]); err == nil {
+		t.Errorf("Expected an error when the database is newer than the migrations")
+	}
+
+	if err := Migrate(ctx, db, SQLite, "bad", []Migration{{Version: 2, Up: Exec()}}); err == nil {
+		t.Errorf("Expected an error for migrations that don't start at 1")
+	}
+}
+
+func Test_Rebind(t *testing.T) {
+	query := "INSERT INTO t (a, b) VALUES (?, ?)"
+	if got := Rebind(SQLite, query); got != query {
+		t.Errorf("Expected the SQLite query to be unchanged; got %s", got)
+	}
+	if got, want := Rebind(Postgres, query), "INSERT INTO t (a, b) VALUES ($1, $2)"; got != want {
+		t.Errorf("Expected %s; got %s", want, got)
+	}
+}
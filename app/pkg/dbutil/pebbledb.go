@@ -1,18 +1,171 @@
 package dbutil
 
 import (
+	"os"
+	"path/filepath"
+	"syscall"
+
 	"github.com/cockroachdb/pebble"
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
 )
 
-// GetProto reads a proto message from a Pebble DB.
-func GetProto(db *pebble.DB, key string, value proto.Message) error {
-	b, closer, err := db.Get([]byte(key))
+// PebbleKV is a KV backed by a Pebble DB.
+type PebbleKV struct {
+	db *pebble.DB
+}
+
+var _ KV = (*PebbleKV)(nil)
+var _ Compacter = (*PebbleKV)(nil)
+
+// NewPebbleKV creates a KV backed by an open Pebble DB. Closing the KV closes the DB.
+func NewPebbleKV(db *pebble.DB) *PebbleKV {
+	return &PebbleKV{db: db}
+}
+
+// OpenPebbleKV opens the Pebble DB in dir. If readOnly is true writes fail with ErrReadOnly and the DB must
+// already exist. N.B. Pebble locks the directory even when the DB is read only so the DB still can't be opened if
+// another process has it open.
+func OpenPebbleKV(dir string, readOnly bool) (*PebbleKV, error) {
+	db, err := pebble.Open(dir, &pebble.Options{ReadOnly: readOnly})
 	if err != nil {
-		return errors.Wrapf(err, "Failed to read proto with key %s", key)
+		return nil, errors.Wrapf(lockError(dir, err), "Failed to open pebble database %s", dir)
+	}
+	return NewPebbleKV(db), nil
+}
+
+// ErrLocked is returned when a Pebble DB can't be opened because another process has it open.
+var ErrLocked = errors.New("database is locked by another process")
+
+// IsLocked returns true if the error opening a Pebble DB is because another process has it open.
+func IsLocked(err error) bool {
+	return errors.Is(err, ErrLocked)
+}
+
+// lockError returns ErrLocked if err is the error from taking the lock on the Pebble DB in dir. Pebble locks the
+// LOCK file in the directory with fcntl which fails with EAGAIN or EACCES depending on the platform. Opening a file
+// without permission also fails with EACCES so the error is only a lock error if we can open the LOCK file.
+func lockError(dir string, err error) error {
+	if !errors.Is(err, syscall.EAGAIN) && !errors.Is(err, syscall.EACCES) {
+		return err
+	}
+	f, openErr := os.OpenFile(filepath.Join(dir, "LOCK"), os.O_RDWR, 0)
+	if openErr != nil {
+		return err
+	}
+	// Ignore the error; the file was only opened to check the permissions.
+	_ = f.Close()
+	return errors.Wrap(ErrLocked, err.Error())
+}
+
+func (p *PebbleKV) Get(key []byte) ([]byte, error) {
+	b, closer, err := p.db.Get(key)
+	if err != nil {
+		return nil, pebbleError(err)
 	}
 	defer closer.Close()
+	// The value is only valid until the closer is closed.
+	return append([]byte{}, b...), nil
+}
+
+func (p *PebbleKV) Set(key []byte, value []byte) error {
+	return pebbleError(p.db.Set(key, value, pebble.Sync))
+}
+
+func (p *PebbleKV) Delete(keys ...[]byte) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	batch := p.db.NewBatch()
+	defer batch.Close()
+	for _, k := range keys {
+		if err := batch.Delete(k, nil); err != nil {
+			return errors.Wrapf(err, "Failed to delete key %s", string(k))
+		}
+	}
+	return pebbleError(batch.Commit(pebble.Sync))
+}
+
+func (p *PebbleKV) Apply(b *Batch) error {
+	batch := p.db.NewBatch()
+	defer batch.Close()
+	for _, w := range b.writes {
+		if w.del {
+			if err := batch.Delete(w.key, nil); err != nil {
+				return errors.Wrapf(err, "Failed to delete key %s", string(w.key))
+			}
+			continue
+		}
+		if err := batch.Set(w.key, w.value, nil); err != nil {
+			return errors.Wrapf(err, "Failed to set key %s", string(w.key))
+		}
+	}
+	return pebbleError(batch.Commit(pebble.Sync))
+}
+
+func (p *PebbleKV) Scan(prefix []byte, visit func(key []byte, value []byte) error) error {
+	var opts *pebble.IterOptions
+	if len(prefix) > 0 {
+		opts = &pebble.IterOptions{LowerBound: prefix, UpperBound: prefixUpperBound(prefix)}
+	}
+	iter, err := p.db.NewIter(opts)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create iterator")
+	}
+	defer iter.Close()
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		value, err := iter.ValueAndErr()
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read value for key %s", string(iter.Key()))
+		}
+		if err := visit(iter.Key(), value); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+func (p *PebbleKV) Compact(start []byte, end []byte) error {
+	return p.db.Compact(start, end, false)
+}
+
+func (p *PebbleKV) Close() error {
+	return p.db.Close()
+}
+
+// prefixUpperBound returns the first key after all the keys that start with prefix or nil if there is no such key;
+// i.e. the prefix is all 0xff bytes.
+func prefixUpperBound(prefix []byte) []byte {
+	upper := append([]byte{}, prefix...)
+	for i := len(upper) - 1; i >= 0; i-- {
+		upper[i]++
+		if upper[i] != 0 {
+			return upper[:i+1]
+		}
+	}
+	return nil
+}
+
+// pebbleError converts the pebble errors that callers check for into the KV errors.
+func pebbleError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, pebble.ErrNotFound):
+		return errors.Wrap(ErrNotFound, err.Error())
+	case errors.Is(err, pebble.ErrReadOnly):
+		return errors.Wrap(ErrReadOnly, err.Error())
+	}
+	return err
+}
+
+// GetProto reads a proto message from a KV store.
+func GetProto(db KV, key string, value proto.Message) error {
+	b, err := db.Get([]byte(key))
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read proto with key %s", key)
+	}
 
 	if err := proto.Unmarshal(b, value); err != nil {
 		return errors.Wrapf(err, "Failed to unmarshal proto with key %s", key)
@@ -21,13 +174,13 @@ func GetProto(db *pebble.DB, key string, value proto.Message) error {
 	return nil
 }
 
-// SetProto reads a proto message from a Pebble DB.
+// SetProto writes a proto message to a KV store.
 // TODO(jeremy): We should deprecate this. We should always use the locking DB and ReadWriteModify.
-func SetProto(db *pebble.DB, key string, value proto.Message) error {
+func SetProto(db KV, key string, value proto.Message) error {
 	b, err := proto.Marshal(value)
 	if err != nil {
 		return errors.Wrapf(err, "Failed to marshal proto with key %s", key)
 	}
 
-	return db.Set([]byte(key), b, pebble.Sync)
+	return db.Set([]byte(key), b)
 }
@@ -1,7 +1,10 @@
 package docs
 
 const (
-	BASHLANG = "bash"
+	BASHLANG   = "bash"
+	PYTHONLANG = "python"
+	SQLLANG    = "sql"
+	JQLANG     = "jq"
 	// OUTPUTLANG is the language to give to output code blocks.
 	// We want to potentially distinguish output from code blocks because output blocks are nested inside blocks
 	// in notebooks. Therefore if we want to be able to convert a markdown document into a document with blocks
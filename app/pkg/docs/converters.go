@@ -1,6 +1,8 @@
 package docs
 
 import (
+	"context"
+	"fmt"
 	"math"
 	"strings"
 
@@ -8,6 +10,7 @@ import (
 	parserv1 "github.com/stateful/runme/v3/pkg/api/gen/proto/go/runme/parser/v1"
 	"github.com/stateful/runme/v3/pkg/document/identity"
 
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 	"github.com/stateful/runme/v3/pkg/document/editor"
 )
@@ -15,19 +18,31 @@ import (
 const (
 	codeTruncationMessage = "<...code was truncated...>"
 	truncationMessage     = "<...stdout was truncated...>"
+	// binaryOutputMessage replaces outputs that aren't text; e.g. images. Images can be sent to models that support
+	// them as separate parts of the request.
+	binaryOutputMessage = "<...%s output of %d bytes...>"
 )
 
 // BlockToMarkdown converts a block to markdown
 // maxLength is a maximum length for the generated markdown. This is a soft limit and may be exceeded slightly
 // because we don't account for some characters like the outputLength and the truncation message
 // A value <=0 means no limit.
+// Outputs that are too long are truncated.
 func BlockToMarkdown(block *v1alpha1.Block, maxLength int) string {
+	md, _ := blockToMarkdown(context.Background(), block, maxLength, &TruncateReducer{})
+	return md
+}
+
+// blockToMarkdown converts a block to markdown using reducer to shorten outputs that are too long. It returns a
+// span for each output that was reduced.
+func blockToMarkdown(ctx context.Context, block *v1alpha1.Block, maxLength int, reducer OutputReducer) (string, []*logspb.OutputReductionSpan) {
 	sb := strings.Builder{}
-	writeBlockMarkdown(&sb, block, maxLength)
-	return sb.String()
+	reductions := writeBlockMarkdown(ctx, &sb, block, maxLength, reducer)
+	return sb.String(), reductions
 }
 
-func writeBlockMarkdown(sb *strings.Builder, block *v1alpha1.Block, maxLength int) {
+func writeBlockMarkdown(ctx context.Context, sb *strings.Builder, block *v1alpha1.Block, maxLength int, reducer OutputReducer) []*logspb.OutputReductionSpan {
+	var reductions []*logspb.OutputReductionSpan
 
 	maxInputLength := -1
 	maxOutputLength := -1
@@ -45,7 +60,7 @@ func writeBlockMarkdown(sb *strings.Builder, block *v1alpha1.Block, maxLength in
 	switch block.GetKind() {
 	case v1alpha1.BlockKind_CODE:
 		// Code just gets written as a code block
-		sb.WriteString("```" + BASHLANG + "\n")
+		sb.WriteString("```" + NormalizeLanguage(block.GetLanguage()) + "\n")
 
 		data := block.GetContents()
 		if len(data) > maxInputLength && maxInputLength > 0 {
@@ -92,22 +107,37 @@ func writeBlockMarkdown(sb *strings.Builder, block *v1alpha1.Block, maxLength in
 			}
 
 			sb.WriteString("```" + OUTPUTLANG + "\n")
+			if len(oi.GetData()) > 0 || converters.IsBinaryMime(oi.GetMime()) {
+				sb.WriteString(fmt.Sprintf(binaryOutputMessage, oi.GetMime(), len(oi.GetData())))
+				sb.WriteString("\n```\n")
+				continue
+			}
+
 			textData := oi.GetTextData()
+			if summary, ok := SummarizeOutput(oi.GetMime(), textData); ok {
+				textData = summary
+			}
 			if 0 < maxOutputLength && len(textData) > maxOutputLength {
-				textData = textData[:maxOutputLength]
-				sb.WriteString(textData)
-				// Don't write a newline before writing truncation because that is more likely to lead to confusion
-				// because people might not realize the line was truncated.
-				// Emit a message indicating that the output was truncated
-				// This is intended for the LLM so it knows that it is working with a truncated output.
-				sb.WriteString(truncationMessage)
-			} else {
-				sb.WriteString(textData)
+				span := &logspb.OutputReductionSpan{
+					BlockId:        block.GetId(),
+					Strategy:       reducer.Name(),
+					OriginalLength: int32(len(textData)),
+				}
+				reduced, err := reducer.Reduce(ctx, textData, maxOutputLength)
+				if err != nil {
+					span.Error = err.Error()
+					reduced, _ = (&TruncateReducer{}).Reduce(ctx, textData, maxOutputLength)
+				}
+				span.ReducedLength = int32(len(reduced))
+				reductions = append(reductions, span)
+				textData = reduced
 			}
+			sb.WriteString(textData)
 
 			sb.WriteString("\n```\n")
 		}
 	}
+	return reductions
 }
 
 // BlocksToMarkdown converts a sequence of blocks to markdown
@@ -115,7 +145,7 @@ func BlocksToMarkdown(blocks []*v1alpha1.Block) string {
 	sb := strings.Builder{}
 
 	for _, block := range blocks {
-		writeBlockMarkdown(&sb, block, -1)
+		writeBlockMarkdown(context.Background(), &sb, block, -1, &TruncateReducer{})
 	}
 
 	return sb.String()
@@ -44,6 +44,15 @@ func Test_BlockToMarkdown(t *testing.T) {
 			},
 			expected: "```bash\necho \"something something\"\n```\n```output\nsomething something\n```\n",
 		},
+		{
+			name: "python",
+			block: &v1alpha1.Block{
+				Kind:     v1alpha1.BlockKind_CODE,
+				Language: "python",
+				Contents: "print(\"hello\")",
+			},
+			expected: "```python\nprint(\"hello\")\n```\n",
+		},
 		{
 			name: "filter-by-mime-type",
 			block: &v1alpha1.Block{
@@ -70,6 +79,25 @@ func Test_BlockToMarkdown(t *testing.T) {
 			},
 			expected: "```bash\necho \"something something\"\n```\n```output\nShould be included\n```\n",
 		},
+		{
+			name: "image-output",
+			block: &v1alpha1.Block{
+				Kind:     v1alpha1.BlockKind_CODE,
+				Language: "python",
+				Contents: "plot()",
+				Outputs: []*v1alpha1.BlockOutput{
+					{
+						Items: []*v1alpha1.BlockOutputItem{
+							{
+								Mime: "image/png",
+								Data: []byte{0x89, 'P', 'N', 'G'},
+							},
+						},
+					},
+				},
+			},
+			expected: "```python\nplot()\n```\n```output\n<...image/png output of 4 bytes...>\n```\n",
+		},
 		{
 			name: "truncate-output",
 			block: &v1alpha1.Block{
@@ -0,0 +1,55 @@
+package docs
+
+import (
+	"strings"
+
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+)
+
+// languageAliases maps the names editors and models use for a language to the name Foyle uses.
+var languageAliases = map[string]string{
+	"":            BASHLANG,
+	"sh":          BASHLANG,
+	"shell":       BASHLANG,
+	"shellscript": BASHLANG,
+	"console":     BASHLANG,
+	"zsh":         BASHLANG,
+	"py":          PYTHONLANG,
+	"python3":     PYTHONLANG,
+	"sqlite":      SQLLANG,
+	"postgres":    SQLLANG,
+	"postgresql":  SQLLANG,
+}
+
+// NormalizeLanguage returns the name Foyle uses for the language of a code block. Shell languages
+// (e.g. sh, shellscript) and blocks without a language are bash.
+func NormalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if alias, ok := languageAliases[lang]; ok {
+		return alias
+	}
+	return lang
+}
+
+// IsShellLanguage returns true if code blocks in the language are shell commands.
+func IsShellLanguage(lang string) bool {
+	return NormalizeLanguage(lang) == BASHLANG
+}
+
+// CodeLanguages returns the languages of the code blocks in the order they first appear.
+func CodeLanguages(blocks []*v1alpha1.Block) []string {
+	seen := make(map[string]bool)
+	languages := make([]string, 0, 2)
+	for _, b := range blocks {
+		if b.GetKind() != v1alpha1.BlockKind_CODE {
+			continue
+		}
+		lang := NormalizeLanguage(b.GetLanguage())
+		if lang == OUTPUTLANG || seen[lang] {
+			continue
+		}
+		seen[lang] = true
+		languages = append(languages, lang)
+	}
+	return languages
+}
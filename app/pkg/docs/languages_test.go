@@ -0,0 +1,39 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+)
+
+func Test_NormalizeLanguage(t *testing.T) {
+	cases := map[string]string{
+		"":            BASHLANG,
+		"shellscript": BASHLANG,
+		"Bash":        BASHLANG,
+		"python3":     PYTHONLANG,
+		"sqlite":      SQLLANG,
+		"jq":          JQLANG,
+		"go":          "go",
+	}
+	for in, expected := range cases {
+		if actual := NormalizeLanguage(in); actual != expected {
+			t.Errorf("NormalizeLanguage(%q) = %q; want %q", in, actual, expected)
+		}
+	}
+}
+
+func Test_CodeLanguages(t *testing.T) {
+	blocks := []*v1alpha1.Block{
+		{Kind: v1alpha1.BlockKind_MARKUP, Contents: "# Count users"},
+		{Kind: v1alpha1.BlockKind_CODE, Contents: "ls"},
+		{Kind: v1alpha1.BlockKind_CODE, Language: "py", Contents: "print(1)"},
+		{Kind: v1alpha1.BlockKind_CODE, Language: OUTPUTLANG, Contents: "1"},
+		{Kind: v1alpha1.BlockKind_CODE, Language: "sh", Contents: "pwd"},
+	}
+	expected := []string{BASHLANG, PYTHONLANG}
+	if d := cmp.Diff(expected, CodeLanguages(blocks)); d != "" {
+		t.Errorf("Unexpected languages:\n%s", d)
+	}
+}
@@ -11,24 +11,15 @@ import (
 // It removes ghost cells and all cells after the selected index. Therefore the position of the cells in the returned
 // array may not match original positions.
 func PreprocessDoc(req *v1alpha1.GenerateRequest) []*v1alpha1.Block {
-	// We want to remove all cells after the selected cell because our prompt doesn't know how to take them into account.
+	// We remove all cells after the selected cell because the prompt only takes them into account when fill in the
+	// middle is enabled; in which case SuffixBlocks is used to get them.
 	// We also need to remove any Ghost cells
 	cells := make([]*v1alpha1.Block, 0, req.GetSelectedIndex()+1)
 
 	for i := int32(0); i < req.GetSelectedIndex()+1; i++ {
 		c := req.Doc.GetBlocks()[i]
-		metadata := c.Metadata
-		if metadata == nil {
-			metadata = make(map[string]string)
-		}
-
-		if v, ok := metadata[converters.GhostKeyField]; ok {
-			// ParseBool returns error if the value is not a valid boolean value. In this case we just consider
-			// it to not be a ghost cell
-			if isGhost, err := strconv.ParseBool(v); err == nil && isGhost {
-				// Ignore ghost cells
-				continue
-			}
+		if isGhost(c) {
+			continue
 		}
 
 		cells = append(cells, c)
@@ -36,3 +27,30 @@ func PreprocessDoc(req *v1alpha1.GenerateRequest) []*v1alpha1.Block {
 
 	return cells
 }
+
+// SuffixBlocks returns up to maxCells of the cells after the selected index. Ghost cells are removed.
+// These are used as the suffix in fill in the middle prompts.
+func SuffixBlocks(req *v1alpha1.GenerateRequest, maxCells int) []*v1alpha1.Block {
+	cells := make([]*v1alpha1.Block, 0, maxCells)
+	blocks := req.GetDoc().GetBlocks()
+	for i := int(req.GetSelectedIndex()) + 1; i < len(blocks) && len(cells) < maxCells; i++ {
+		c := blocks[i]
+		if isGhost(c) {
+			continue
+		}
+		cells = append(cells, c)
+	}
+	return cells
+}
+
+// isGhost returns true if the block is a ghost cell; i.e. a suggestion that hasn't been accepted yet.
+func isGhost(block *v1alpha1.Block) bool {
+	v, ok := block.GetMetadata()[converters.GhostKeyField]
+	if !ok {
+		return false
+	}
+	// ParseBool returns error if the value is not a valid boolean value. In this case we just consider
+	// it to not be a ghost cell
+	isGhost, err := strconv.ParseBool(v)
+	return err == nil && isGhost
+}
@@ -114,3 +114,73 @@ func Test_peprocessDoc(t *testing.T) {
 		})
 	}
 }
+
+func Test_SuffixBlocks(t *testing.T) {
+	doc := &v1alpha1.Doc{
+		Blocks: []*v1alpha1.Block{
+			{
+				Kind:     v1alpha1.BlockKind_MARKUP,
+				Contents: "cell 0",
+			},
+			{
+				Kind: v1alpha1.BlockKind_CODE,
+				Metadata: map[string]string{
+					converters.GhostKeyField: "true",
+				},
+				Contents: "cell 1",
+			},
+			{
+				Kind:     v1alpha1.BlockKind_MARKUP,
+				Contents: "cell 2",
+			},
+			{
+				Kind:     v1alpha1.BlockKind_CODE,
+				Contents: "cell 3",
+			},
+		},
+	}
+
+	type testCase struct {
+		name     string
+		input    *v1alpha1.GenerateRequest
+		maxCells int
+		expected []*v1alpha1.Block
+	}
+
+	cases := []testCase{
+		{
+			name: "skip-ghost-and-limit",
+			input: &v1alpha1.GenerateRequest{
+				Doc:           doc,
+				SelectedIndex: 0,
+			},
+			maxCells: 1,
+			expected: []*v1alpha1.Block{
+				{
+					Kind:     v1alpha1.BlockKind_MARKUP,
+					Contents: "cell 2",
+				},
+			},
+		},
+		{
+			name: "last-cell",
+			input: &v1alpha1.GenerateRequest{
+				Doc:           doc,
+				SelectedIndex: 3,
+			},
+			maxCells: 3,
+			expected: []*v1alpha1.Block{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := SuffixBlocks(c.input, c.maxCells)
+
+			opts := cmpopts.IgnoreUnexported(v1alpha1.Block{})
+			if d := cmp.Diff(c.expected, actual, opts); d != "" {
+				t.Errorf("Unexpected diff:\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/pkg/errors"
+)
+
+const (
+	omittedLinesMessage  = "<...%d lines of output were omitted...>"
+	omittedCharsMessage  = "<...%d characters of output were omitted...>"
+	omittedErrorsMessage = "<...%d lines of output were omitted except for the %d error and warning lines below...>"
+	endOfErrorsMessage   = "<...end of the error and warning lines...>"
+	repeatedLinesMessage = "<...%d similar lines were omitted...>"
+)
+
+var (
+	// problemRegex matches lines that are likely to contain errors or warnings.
+	problemRegex = regexp.MustCompile(`(?i)\b(error|errors|err|fatal|fail|failed|failure|panic|exception|traceback|warn|warning|denied|refused|timeout|timed out)\b`)
+	digitsRegex  = regexp.MustCompile(`[0-9]+`)
+)
+
+// OutputReducer shortens the output of a cell so the document fits in the prompt.
+type OutputReducer interface {
+	// Name is the name of the strategy; it is recorded in the trace.
+	Name() string
+	// Reduce returns the output shortened to about maxLength characters. It is only called with outputs that are
+	// longer than maxLength.
+	Reduce(ctx context.Context, output string, maxLength int) (string, error)
+}
+
+// NewOutputReducer returns the reducer for the strategy. The llm strategy isn't supported because it requires a
+// model; see agent.NewLLMReducer.
+func NewOutputReducer(strategy string) (OutputReducer, error) {
+	switch strategy {
+	case api.OutputReducerTruncate:
+		return &TruncateReducer{}, nil
+	case api.OutputReducerHeadTail:
+		return &HeadTailReducer{}, nil
+	case api.OutputReducerSmart, "":
+		return &SmartReducer{}, nil
+	default:
+		return nil, errors.Errorf("Unsupported output reducer strategy %q; strategy must be one of %s, %s, %s or %s", strategy, api.OutputReducerTruncate, api.OutputReducerHeadTail, api.OutputReducerSmart, api.OutputReducerLLM)
+	}
+}
+
+type outputReducerKey struct{}
+
+// WithOutputReducer returns a context indicating that the Tailer should use the reducer to shorten outputs.
+func WithOutputReducer(ctx context.Context, r OutputReducer) context.Context {
+	return context.WithValue(ctx, outputReducerKey{}, r)
+}
+
+// outputReducer returns the reducer set by WithOutputReducer. It defaults to truncating outputs.
+func outputReducer(ctx context.Context) OutputReducer {
+	if r, ok := ctx.Value(outputReducerKey{}).(OutputReducer); ok && r != nil {
+		return r
+	}
+	return &TruncateReducer{}
+}
+
+// TruncateReducer keeps the beginning of the output.
+type TruncateReducer struct{}
+
+func (r *TruncateReducer) Name() string {
+	return api.OutputReducerTruncate
+}
+
+func (r *TruncateReducer) Reduce(ctx context.Context, output string, maxLength int) (string, error) {
+	if len(output) <= maxLength {
+		return output, nil
+	}
+	// Don't write a newline before writing truncation because that is more likely to lead to confusion
+	// because people might not realize the line was truncated.
+	// Emit a message indicating that the output was truncated
+	// This is intended for the LLM so it knows that it is working with a truncated output.
+	return output[:maxLength] + truncationMessage, nil
+}
+
+// HeadTailReducer keeps the beginning and the end of the output. The end gets more of the budget because errors
+// and results are usually at the end.
+type HeadTailReducer struct{}
+
+func (r *HeadTailReducer) Name() string {
+	return api.OutputReducerHeadTail
+}
+
+func (r *HeadTailReducer) Reduce(ctx context.Context, output string, maxLength int) (string, error) {
+	if len(output) <= maxLength {
+		return output, nil
+	}
+	headChars := maxLength / 3
+	return headTail(output, headChars, maxLength-headChars), nil
+}
+
+// SmartReducer collapses runs of similar lines (e.g. progress or retry messages that only differ by numbers). If the
+// output is still too long it keeps the beginning, the end and as many of the error and warning lines in between as
+// fit.
+type SmartReducer struct{}
+
+func (r *SmartReducer) Name() string {
+	return api.OutputReducerSmart
+}
+
+func (r *SmartReducer) Reduce(ctx context.Context, output string, maxLength int) (string, error) {
+	collapsed := collapseRepeatedLines(output)
+	if len(collapsed) <= maxLength {
+		return collapsed, nil
+	}
+
+	lines := strings.Split(collapsed, "\n")
+	head, tail := splitHeadTail(lines, maxLength/4, maxLength/2)
+	if head == 0 && tail == len(lines) {
+		// Not even a single line fits.
+		headChars := maxLength / 3
+		return headTail(collapsed, headChars, maxLength-headChars), nil
+	}
+
+	budget := maxLength - linesLength(lines[:head]) - linesLength(lines[tail:])
+	problems := make([]string, 0, 10)
+	for _, l := range lines[head:tail] {
+		if !problemRegex.MatchString(l) {
+			continue
+		}
+		if len(l)+1 > budget {
+			break
+		}
+		budget -= len(l) + 1
+		problems = append(problems, l)
+	}
+
+	reduced := make([]string, 0, head+len(problems)+len(lines)-tail+2)
+	reduced = append(reduced, lines[:head]...)
+	if len(problems) == 0 {
+		reduced = append(reduced, fmt.Sprintf(omittedLinesMessage, tail-head))
+	} else {
+		reduced = append(reduced, fmt.Sprintf(omittedErrorsMessage, tail-head, len(problems)))
+		reduced = append(reduced, problems...)
+		reduced = append(reduced, endOfErrorsMessage)
+	}
+	reduced = append(reduced, lines[tail:]...)
+	return strings.Join(reduced, "\n"), nil
+}
+
+// headTail keeps about headChars characters at the beginning and tailChars at the end of s. It keeps whole lines
+// unless a single line doesn't fit.
+func headTail(s string, headChars int, tailChars int) string {
+	lines := strings.Split(s, "\n")
+	head, tail := splitHeadTail(lines, headChars, tailChars)
+	if head == 0 && tail == len(lines) {
+		if headChars+tailChars >= len(s) {
+			return s
+		}
+		head := truncateUTF8(s, headChars)
+		// Move the start of the tail forward to the start of a character so neither end splits a UTF-8 character.
+		start := len(s) - tailChars
+		for start < len(s) && !utf8.RuneStart(s[start]) {
+			start++
+		}
+		return head + fmt.Sprintf(omittedCharsMessage, start-len(head)) + s[start:]
+	}
+
+	reduced := make([]string, 0, head+len(lines)-tail+1)
+	reduced = append(reduced, lines[:head]...)
+	reduced = append(reduced, fmt.Sprintf(omittedLinesMessage, tail-head))
+	reduced = append(reduced, lines[tail:]...)
+	return strings.Join(reduced, "\n")
+}
+
+// splitHeadTail returns the number of lines that fit in headChars and the index of the first line of the tail that
+// fits in tailChars.
+func splitHeadTail(lines []string, headChars int, tailChars int) (int, int) {
+	head := 0
+	used := 0
+	for head < len(lines) && used+len(lines[head])+1 <= headChars {
+		used += len(lines[head]) + 1
+		head++
+	}
+
+	tail := len(lines)
+	used = 0
+	for tail > head && used+len(lines[tail-1])+1 <= tailChars {
+		used += len(lines[tail-1]) + 1
+		tail--
+	}
+	return head, tail
+}
+
+func linesLength(lines []string) int {
+	n := 0
+	for _, l := range lines {
+		n += len(l) + 1
+	}
+	return n
+}
+
+// collapseRepeatedLines replaces runs of three or more lines that are the same except for numbers with the first
+// line and a message saying how many lines were omitted.
+func collapseRepeatedLines(s string) string {
+	lines := strings.Split(s, "\n")
+	collapsed := make([]string, 0, len(lines))
+
+	prevKey := ""
+	repeats := 0
+	flush := func(i int) {
+		if repeats >= 2 {
+			collapsed = append(collapsed, fmt.Sprintf(repeatedLinesMessage, repeats))
+		} else {
+			collapsed = append(collapsed, lines[i-repeats:i]...)
+		}
+		repeats = 0
+	}
+
+	for i, l := range lines {
+		key := digitsRegex.ReplaceAllString(strings.TrimSpace(l), "0")
+		if i > 0 && key != "" && key == prevKey {
+			repeats++
+			continue
+		}
+		flush(i)
+		collapsed = append(collapsed, l)
+		prevKey = key
+	}
+	flush(len(lines))
+	return strings.Join(collapsed, "\n")
+}
@@ -0,0 +1,124 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+)
+
+func Test_Reducers(t *testing.T) {
+	lines := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	logOutput := strings.Join(lines, "\n")
+
+	type testCase struct {
+		name      string
+		reducer   OutputReducer
+		input     string
+		maxLength int
+		expected  string
+	}
+
+	cases := []testCase{
+		{
+			name:      "truncate",
+			reducer:   &TruncateReducer{},
+			input:     "some really long output",
+			maxLength: 6,
+			expected:  "some r<...stdout was truncated...>",
+		},
+		{
+			name:      "head-tail",
+			reducer:   &HeadTailReducer{},
+			input:     "first\nsecond\nthird\nfourth\nfifth\nError: the last line",
+			maxLength: 40,
+			expected:  "first\nsecond\n<...2 lines of output were omitted...>\nfifth\nError: the last line",
+		},
+		{
+			name:      "head-tail-single-line",
+			reducer:   &HeadTailReducer{},
+			input:     "abcdefghijklmnopqrstuvwxyz",
+			maxLength: 9,
+			expected:  "abc<...17 characters of output were omitted...>uvwxyz",
+		},
+		{
+			// é is 2 bytes so both ends are moved to the nearest character boundary.
+			name:      "head-tail-utf8",
+			reducer:   &HeadTailReducer{},
+			input:     "aéééé",
+			maxLength: 7,
+			expected:  "a<...4 characters of output were omitted...>éé",
+		},
+		{
+			name:      "smart-collapse",
+			reducer:   &SmartReducer{},
+			input:     "start\n" + logOutput + "\ndone",
+			maxLength: 100,
+			expected:  "start\nline 0\n<...99 similar lines were omitted...>\ndone",
+		},
+		{
+			name:      "smart-errors",
+			reducer:   &SmartReducer{},
+			input:     "Starting the build\nstep one\nstep two\nwarn: old flag\nstep three\nstep four\nstep five\nstep six\nerror: missing file\nstep seven\nBuild failed",
+			maxLength: 80,
+			expected:  "Starting the build\n<...8 lines of output were omitted except for the 2 error and warning lines below...>\nwarn: old flag\nerror: missing file\n<...end of the error and warning lines...>\nstep seven\nBuild failed",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := c.reducer.Reduce(context.Background(), c.input, c.maxLength)
+			if err != nil {
+				t.Fatalf("Reduce failed: %v", err)
+			}
+			if d := cmp.Diff(c.expected, actual); d != "" {
+				t.Errorf("Unexpected output (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func Test_NewOutputReducer(t *testing.T) {
+	for _, s := range []string{api.OutputReducerTruncate, api.OutputReducerHeadTail, api.OutputReducerSmart} {
+		r, err := NewOutputReducer(s)
+		if err != nil {
+			t.Fatalf("Failed to create reducer %s: %v", s, err)
+		}
+		if r.Name() != s {
+			t.Errorf("Expected reducer %s; got %s", s, r.Name())
+		}
+	}
+	if _, err := NewOutputReducer(api.OutputReducerLLM); err == nil {
+		t.Errorf("Expected an error for the llm strategy")
+	}
+}
+
+func Test_TailerReducer(t *testing.T) {
+	output := "first\n" + strings.Repeat("middle\n", 50) + "Error: the last line"
+	blocks := []*v1alpha1.Block{
+		{
+			Kind:     v1alpha1.BlockKind_CODE,
+			Contents: "make build",
+			Outputs: []*v1alpha1.BlockOutput{
+				{
+					Items: []*v1alpha1.BlockOutputItem{{TextData: output}},
+				},
+			},
+		},
+	}
+
+	ctx := WithOutputReducer(context.Background(), &SmartReducer{})
+	text := NewTailer(ctx, blocks, 200).Text()
+	for _, s := range []string{"first", "<...49 similar lines were omitted...>", "Error: the last line"} {
+		if !strings.Contains(text, s) {
+			t.Errorf("Expected the document to contain %q; got:\n%s", s, text)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package docs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// summarizeMinChars is the length above which JSON, YAML and tabular outputs are summarized.
+	summarizeMinChars = 1000
+	// summaryListItems is the number of items of each list kept in the sample of JSON and YAML outputs.
+	summaryListItems = 3
+	// summaryRows is the number of rows kept in the sample of tabular outputs.
+	summaryRows = 5
+	// summaryMaxStringChars is the maximum length of string values in the sample of JSON and YAML outputs.
+	summaryMaxStringChars = 200
+	// summarySchemaDepth is how many levels of nested objects are described in the schema.
+	summarySchemaDepth = 4
+)
+
+var (
+	yamlKeyRegex     = regexp.MustCompile(`^[A-Za-z_][\w.-]*:(\s|$)`)
+	tableHeaderRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_()%/.-]*(\s{2,}[A-Z][A-Z0-9_()%/. -]*)+$`)
+	columnsRegex     = regexp.MustCompile(`\s{2,}`)
+)
+
+// SummarizeOutput summarizes long JSON, YAML and tabular outputs (e.g. the output of kubectl get) so they take up
+// less of the prompt. JSON and YAML are summarized by their schema and a sample in which lists are truncated.
+// Tables are summarized by their columns and the first rows. The second value is false if the output isn't
+// summarized; e.g. because it isn't structured or the summary wouldn't be shorter.
+func SummarizeOutput(mime string, text string) (string, bool) {
+	if len(text) <= summarizeMinChars {
+		return "", false
+	}
+	summary, ok := summarizeOutput(strings.ToLower(mime), text)
+	if !ok || len(summary) >= len(text) {
+		return "", false
+	}
+	return summary, true
+}
+
+func summarizeOutput(mime string, text string) (string, bool) {
+
+	trimmed := strings.TrimSpace(text)
+	if strings.Contains(mime, "json") || strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		if summary, ok := summarizeJSON(trimmed); ok {
+			return summary, true
+		}
+	}
+
+	if strings.Contains(mime, "yaml") || looksLikeYAML(trimmed) {
+		if summary, ok := summarizeYAML(trimmed); ok {
+			return summary, true
+		}
+	}
+
+	return summarizeTable(trimmed)
+}
+
+func summarizeJSON(text string) (string, bool) {
+	d := json.NewDecoder(strings.NewReader(text))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return "", false
+	}
+	if d.More() {
+		// There is more than one JSON value so it isn't a JSON document.
+		return "", false
+	}
+	if !isCollection(v) {
+		return "", false
+	}
+	sample, err := json.MarshalIndent(sampleValue(v), "", "  ")
+	if err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("<...JSON output of %d characters was summarized; lists are truncated to %d items...>\nschema: %s\nsample:\n%s", len(text), summaryListItems, describeSchema(v, summarySchemaDepth), sample), true
+}
+
+func summarizeYAML(text string) (string, bool) {
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(text), &v); err != nil {
+		return "", false
+	}
+	if !isCollection(v) {
+		return "", false
+	}
+	var b bytes.Buffer
+	e := yaml.NewEncoder(&b)
+	e.SetIndent(2)
+	if err := e.Encode(sampleValue(v)); err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("<...YAML output of %d characters was summarized; lists are truncated to %d items...>\nschema: %s\nsample:\n%s", len(text), summaryListItems, describeSchema(v, summarySchemaDepth), strings.TrimSpace(b.String())), true
+}
+
+// summarizeTable summarizes tab or comma separated values and tables whose header is in upper case and whose
+// columns are separated by two or more spaces; e.g. the output of kubectl get or docker ps.
+func summarizeTable(text string) (string, bool) {
+	lines := strings.Split(text, "\n")
+	if len(lines) <= summaryRows+2 {
+		return "", false
+	}
+	header := strings.TrimRight(lines[0], " \r")
+
+	var columns []string
+	switch {
+	case consistentFields(lines, "\t"):
+		columns = strings.Split(header, "\t")
+	case consistentFields(lines, ","):
+		columns = strings.Split(header, ",")
+	case tableHeaderRegex.MatchString(header):
+		columns = columnsRegex.Split(header, -1)
+	default:
+		return "", false
+	}
+
+	rows := lines[1:]
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<...table with %d rows was summarized; columns: %s...>\n", len(rows), strings.Join(columns, ", ")))
+	sb.WriteString(header + "\n")
+	for _, r := range rows[:summaryRows] {
+		sb.WriteString(r + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("<...%d more rows...>", len(rows)-summaryRows))
+	return sb.String(), true
+}
+
+// consistentFields returns true if every line has the same number (at least 2) of fields separated by sep.
+func consistentFields(lines []string, sep string) bool {
+	n := strings.Count(lines[0], sep)
+	if n == 0 {
+		return false
+	}
+	for _, l := range lines[1:] {
+		if strings.Count(l, sep) != n {
+			return false
+		}
+	}
+	return true
+}
+
+func looksLikeYAML(text string) bool {
+	for _, l := range strings.Split(text, "\n") {
+		l = strings.TrimRight(l, " \r")
+		if l == "" || l == "---" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		return yamlKeyRegex.MatchString(l) || strings.HasPrefix(l, "- ")
+	}
+	return false
+}
+
+func isCollection(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// sampleValue returns a copy of v in which lists are truncated to summaryListItems and long strings are truncated.
+func sampleValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		sample := make(map[string]interface{}, len(t))
+		for k, item := range t {
+			sample[k] = sampleValue(item)
+		}
+		return sample
+	case []interface{}:
+		n := len(t)
+		if n > summaryListItems {
+			n = summaryListItems
+		}
+		sample := make([]interface{}, 0, n)
+		for _, item := range t[:n] {
+			sample = append(sample, sampleValue(item))
+		}
+		return sample
+	case string:
+		if len(t) > summaryMaxStringChars {
+			return truncateUTF8(t, summaryMaxStringChars) + "..."
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// truncateUTF8 returns the longest prefix of s that is at most n bytes and doesn't split a UTF-8 character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// describeSchema describes the structure of v; e.g. {items: [{name: string, ready: boolean}] (25 items)}.
+func describeSchema(v interface{}, depth int) string {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if depth <= 0 {
+			return "object"
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fields := make([]string, 0, len(keys))
+		for _, k := range keys {
+			fields = append(fields, k+": "+describeSchema(t[k], depth-1))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case []interface{}:
+		if len(t) == 0 {
+			return "[]"
+		}
+		return fmt.Sprintf("[%s] (%d items)", describeSchema(t[0], depth), len(t))
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	case json.Number, int, int64, uint64, float64:
+		return "number"
+	case time.Time:
+		return "timestamp"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
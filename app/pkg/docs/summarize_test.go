@@ -0,0 +1,128 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func Test_SummarizeOutput(t *testing.T) {
+	pods := make([]string, 0, 50)
+	for i := 0; i < 50; i++ {
+		pods = append(pods, fmt.Sprintf(`{"name": "pod-%d", "ready": true, "restarts": %d}`, i, i))
+	}
+	jsonOutput := `{"kind": "List", "items": [` + strings.Join(pods, ", ") + `]}`
+
+	yamlLines := []string{"apiVersion: v1", "kind: List", "items:"}
+	for i := 0; i < 50; i++ {
+		yamlLines = append(yamlLines, fmt.Sprintf("- name: pod-%d\n  ready: true", i))
+	}
+	yamlOutput := strings.Join(yamlLines, "\n")
+
+	tableLines := []string{"NAME       READY   STATUS    RESTARTS   AGE"}
+	for i := 0; i < 50; i++ {
+		tableLines = append(tableLines, fmt.Sprintf("pod-%-6d 1/1     Running   0          5d", i))
+	}
+	tableOutput := strings.Join(tableLines, "\n")
+
+	type testCase struct {
+		name     string
+		mime     string
+		input    string
+		expected bool
+		contains []string
+	}
+
+	cases := []testCase{
+		{
+			name:     "json",
+			input:    jsonOutput,
+			expected: true,
+			contains: []string{
+				"<...JSON output of",
+				"schema: {items: [{name: string, ready: boolean, restarts: number}] (50 items), kind: string}",
+				`"name": "pod-2"`,
+			},
+		},
+		{
+			name:     "yaml",
+			mime:     "application/yaml",
+			input:    yamlOutput,
+			expected: true,
+			contains: []string{
+				"<...YAML output of",
+				"schema: {apiVersion: string, items: [{name: string, ready: boolean}] (50 items), kind: string}",
+				"name: pod-2",
+			},
+		},
+		{
+			name:     "table",
+			input:    tableOutput,
+			expected: true,
+			contains: []string{
+				"<...table with 50 rows was summarized; columns: NAME, READY, STATUS, RESTARTS, AGE...>",
+				"pod-4 ",
+				"<...45 more rows...>",
+			},
+		},
+		{
+			name:     "short",
+			input:    `{"items": [1, 2, 3, 4, 5]}`,
+			expected: false,
+		},
+		{
+			name:     "text",
+			input:    strings.Repeat("some log line that isn't structured\n", 100),
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, ok := SummarizeOutput(c.mime, c.input)
+			if ok != c.expected {
+				t.Fatalf("Expected summarized to be %v but got %v", c.expected, ok)
+			}
+			for _, s := range c.contains {
+				if !strings.Contains(actual, s) {
+					t.Errorf("Expected summary to contain %q; got:\n%s", s, actual)
+				}
+			}
+			if ok && strings.Contains(actual, "pod-10") {
+				t.Errorf("Expected the summary to be truncated; got:\n%s", actual)
+			}
+		})
+	}
+}
+
+func Test_TruncateUTF8(t *testing.T) {
+	type testCase struct {
+		input    string
+		n        int
+		expected string
+	}
+
+	cases := []testCase{
+		{input: "hello", n: 10, expected: "hello"},
+		{input: "hello", n: 3, expected: "hel"},
+		// é is 2 bytes so truncating in the middle of it drops it.
+		{input: "café", n: 4, expected: "caf"},
+		{input: "café", n: 5, expected: "café"},
+		// 世 is 3 bytes.
+		{input: "世界", n: 5, expected: "世"},
+		{input: "世界", n: 2, expected: ""},
+	}
+	for _, c := range cases {
+		actual := truncateUTF8(c.input, c.n)
+		if actual != c.expected {
+			t.Errorf("truncateUTF8(%q, %d): expected %q; got %q", c.input, c.n, c.expected, actual)
+		}
+	}
+
+	long := strings.Repeat("é", summaryMaxStringChars)
+	sample, ok := sampleValue(long).(string)
+	if !ok || !utf8.ValidString(sample) {
+		t.Errorf("Expected the sample of a long string to be valid UTF-8; got %q", sample)
+	}
+}
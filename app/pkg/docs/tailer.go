@@ -4,8 +4,11 @@ import (
 	"context"
 	"strings"
 
+	"github.com/jlewi/foyle/app/pkg/events"
 	"github.com/jlewi/foyle/app/pkg/logs"
+	"github.com/jlewi/foyle/app/pkg/logs/matchers"
 	"github.com/jlewi/foyle/app/pkg/runme/ulid"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 )
@@ -19,11 +22,41 @@ type Tailer struct {
 
 	// firstBlock is the index of the first block to include in the prompt
 	firstBlock int
+
+	// suffixBlocks keeps track of the markdown for the blocks after the selected block in fill in the middle prompts.
+	suffixBlocks []string
+	// numSuffix is the number of suffix blocks to include in the prompt
+	numSuffix int
 }
 
 func NewTailer(ctx context.Context, blocks []*v1alpha1.Block, maxCharLen int) *Tailer {
+	return NewFIMTailer(ctx, blocks, nil, maxCharLen, 0)
+}
+
+// NewFIMTailer creates a tailer for a fill in the middle prompt. suffix are the blocks following the selected block.
+// The suffix is limited to maxSuffixChars and counts against maxCharLen; the rest of the budget is used for the
+// tail of blocks.
+func NewFIMTailer(ctx context.Context, blocks []*v1alpha1.Block, suffix []*v1alpha1.Block, maxCharLen int, maxSuffixChars int) *Tailer {
 	log := logs.FromContext(ctx)
+	reducer := outputReducer(ctx)
 	mdBlocks := make([]string, len(blocks))
+	reductions := make([]*logspb.OutputReductionSpan, 0)
+
+	// Take the head of the suffix.
+	suffixBlocks := make([]string, 0, len(suffix))
+	if maxSuffixChars > maxCharLen {
+		maxSuffixChars = maxCharLen
+	}
+	for _, block := range suffix {
+		if maxSuffixChars <= 0 {
+			break
+		}
+		md, r := blockToMarkdown(ctx, block, maxSuffixChars, reducer)
+		reductions = append(reductions, r...)
+		maxSuffixChars = maxSuffixChars - len(md)
+		maxCharLen = maxCharLen - len(md)
+		suffixBlocks = append(suffixBlocks, md)
+	}
 
 	firstBlock := len(blocks) - 1
 
@@ -38,7 +71,8 @@ func NewTailer(ctx context.Context, blocks []*v1alpha1.Block, maxCharLen int) *T
 	for ; firstBlock >= 0 && maxCharLen > 0; firstBlock-- {
 		block := blocks[firstBlock]
 		numBlocks += 1
-		md := BlockToMarkdown(block, maxCharLen)
+		md, r := blockToMarkdown(ctx, block, maxCharLen, reducer)
+		reductions = append(reductions, r...)
 		maxCharLen = maxCharLen - len(md)
 		if maxCharLen <= 0 && numBlocks == 1 {
 			// Since this is the first block and its truncated we fail the assertion.
@@ -48,9 +82,15 @@ func NewTailer(ctx context.Context, blocks []*v1alpha1.Block, maxCharLen int) *T
 		mdBlocks[firstBlock] = md
 	}
 
-	log.Info(logs.Level1Assertion, "assertion", assertion)
+	logs.LogAssertion(ctx, assertion)
+	for _, r := range reductions {
+		log.Info("Reduced output", "blockId", r.GetBlockId(), "strategy", r.GetStrategy(), logs.ZapProto(matchers.OutputReductionField, r))
+		events.Publish(ctx, &events.Event{Span: &logspb.Span{Data: &logspb.Span_Reduction{Reduction: r}}})
+	}
 	return &Tailer{
-		mdBlocks: mdBlocks,
+		mdBlocks:     mdBlocks,
+		suffixBlocks: suffixBlocks,
+		numSuffix:    len(suffixBlocks),
 	}
 }
 
@@ -65,9 +105,25 @@ func (p *Tailer) Text() string {
 	return sb.String()
 }
 
+// Suffix returns the text of the blocks following the selected block. It is empty unless the tailer was created
+// with NewFIMTailer.
+func (p *Tailer) Suffix() string {
+	var sb strings.Builder
+	for i := 0; i < p.numSuffix; i++ {
+		sb.WriteString(p.suffixBlocks[i])
+	}
+	return sb.String()
+}
+
 // Shorten shortens the doc that will be generated on the next call to Text.
+// The suffix is shortened before the doc since the blocks preceding the selected block are more predictive.
 // Return false if the doc can't be shortened any further.
 func (p *Tailer) Shorten() bool {
+	if p.numSuffix > 0 {
+		p.numSuffix -= 1
+		return true
+	}
+
 	if p.firstBlock+1 >= len(p.mdBlocks) {
 		return false
 	}
@@ -91,6 +91,49 @@ func Test_Tailer(t *testing.T) {
 	}
 }
 
+func Test_FIMTailer(t *testing.T) {
+	blocks := []*v1alpha1.Block{
+		{
+			Kind:     v1alpha1.BlockKind_MARKUP,
+			Contents: "Cell1",
+		},
+		{
+			Kind:     v1alpha1.BlockKind_MARKUP,
+			Contents: "Cell2",
+		},
+	}
+	suffix := []*v1alpha1.Block{
+		{
+			Kind:     v1alpha1.BlockKind_MARKUP,
+			Contents: "Cell3",
+		},
+		{
+			Kind:     v1alpha1.BlockKind_MARKUP,
+			Contents: "Cell4",
+		},
+	}
+
+	// The suffix gets at most 12 characters and uses up 12 of the 18 characters leaving room for one prefix block.
+	tailer := NewFIMTailer(context.Background(), blocks, suffix, 18, 12)
+	if d := cmp.Diff("Cell2\n", tailer.Text()); d != "" {
+		t.Errorf("Unexpected text diff:\n%v", d)
+	}
+	if d := cmp.Diff("Cell3\nCell4\n", tailer.Suffix()); d != "" {
+		t.Errorf("Unexpected suffix diff:\n%v", d)
+	}
+
+	// Shortening drops suffix blocks before prefix blocks.
+	if !tailer.Shorten() {
+		t.Fatalf("Expected Shorten to succeed")
+	}
+	if d := cmp.Diff("Cell3\n", tailer.Suffix()); d != "" {
+		t.Errorf("Unexpected suffix diff after shortening:\n%v", d)
+	}
+	if d := cmp.Diff("Cell2\n", tailer.Text()); d != "" {
+		t.Errorf("Unexpected text diff after shortening:\n%v", d)
+	}
+}
+
 func Test_tailLines(t *testing.T) {
 	type testCase struct {
 		name     string
@@ -0,0 +1,339 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/executor"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+	parserv1 "github.com/stateful/runme/v3/pkg/api/gen/proto/go/runme/parser/v1"
+)
+
+// ResultAssertion is an assertion that is evaluated against the EvalResult for an example.
+// Unlike Assertion it has access to the full result so it can be used for user defined assertions declared
+// in the experiment.
+type ResultAssertion interface {
+	Assert(ctx context.Context, result *v1alpha1.EvalResult) (*v1alpha1.Assertion, error)
+	// Name returns the name of the assertion.
+	Name() string
+}
+
+// NewCustomAssertions creates the assertions declared in the experiment.
+func NewCustomAssertions(specs []api.AssertionSpec) ([]ResultAssertion, error) {
+	parser, err := executor.NewBashishParser()
+	if err != nil {
+		return nil, err
+	}
+
+	assertions := make([]ResultAssertion, 0, len(specs))
+	names := make(map[string]bool)
+	for _, spec := range specs {
+		if spec.Name == "" {
+			return nil, errors.New("Assertion is missing a name")
+		}
+
+		// Custom assertions are reported alongside the built in ones so they can't reuse their names.
+		if _, ok := v1alpha1.Assertion_Name_value[spec.Name]; ok {
+			return nil, errors.Errorf("Assertion name %s is reserved for a built in assertion", spec.Name)
+		}
+
+		if names[spec.Name] {
+			return nil, errors.Errorf("Assertion names must be unique; %s is used more than once", spec.Name)
+		}
+		names[spec.Name] = true
+
+		a, err := newCustomAssertion(spec, parser)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create assertion %s", spec.Name)
+		}
+		assertions = append(assertions, a)
+	}
+	return assertions, nil
+}
+
+func newCustomAssertion(spec api.AssertionSpec, parser *executor.BashishParser) (ResultAssertion, error) {
+	numSet := 0
+	var a ResultAssertion
+	if spec.Regex != nil {
+		numSet++
+		re, err := regexp.Compile(spec.Regex.Pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid regex %s", spec.Regex.Pattern)
+		}
+		a = &AssertRegex{name: spec.Name, re: re, negate: spec.Regex.Negate}
+	}
+
+	if spec.Binaries != nil {
+		numSet++
+		if len(spec.Binaries.Required) == 0 && len(spec.Binaries.Forbidden) == 0 {
+			return nil, errors.New("Binaries assertion must specify at least one required or forbidden binary")
+		}
+		a = &AssertBinaries{name: spec.Name, parser: parser, required: spec.Binaries.Required, forbidden: spec.Binaries.Forbidden}
+	}
+
+	if spec.MaxCells != nil {
+		numSet++
+		if spec.MaxCells.Max < 0 {
+			return nil, errors.Errorf("MaxCells must be non-negative; got %d", spec.MaxCells.Max)
+		}
+		a = &AssertMaxCells{name: spec.Name, max: spec.MaxCells.Max}
+	}
+
+	if spec.CEL != nil {
+		numSet++
+		celAssertion, err := NewAssertCEL(spec.Name, spec.CEL.Expression)
+		if err != nil {
+			return nil, err
+		}
+		a = celAssertion
+	}
+
+	if numSet != 1 {
+		return nil, errors.Errorf("Exactly one assertion type must be set; got %d", numSet)
+	}
+	return a, nil
+}
+
+// newCustomResult returns an Assertion proto for the named custom assertion.
+func newCustomResult(name string) *v1alpha1.Assertion {
+	return &v1alpha1.Assertion{
+		Name:       v1alpha1.Assertion_CUSTOM,
+		CustomName: name,
+	}
+}
+
+// assertionName returns the name used to report the assertion.
+func assertionName(a *v1alpha1.Assertion) string {
+	if a.GetName() == v1alpha1.Assertion_CUSTOM {
+		return a.GetCustomName()
+	}
+	return a.GetName().String()
+}
+
+// setAssertion adds the assertion to the result replacing any existing assertion with the same name.
+// This ensures reprocessing an example doesn't create duplicate assertions.
+func setAssertion(result *v1alpha1.EvalResult, a *v1alpha1.Assertion) {
+	name := assertionName(a)
+	for i, existing := range result.Assertions {
+		if assertionName(existing) == name {
+			result.Assertions[i] = a
+			return
+		}
+	}
+	result.Assertions = append(result.Assertions, a)
+}
+
+// generatedCommands returns the contents of the generated code cells.
+func generatedCommands(result *v1alpha1.EvalResult) []string {
+	commands := make([]string, 0, len(result.GetActualCells()))
+	for _, c := range result.GetActualCells() {
+		if c.GetKind() != parserv1.CellKind_CELL_KIND_CODE {
+			continue
+		}
+		commands = append(commands, c.GetValue())
+	}
+	return commands
+}
+
+// AssertRegex checks whether the generated commands match a regular expression.
+type AssertRegex struct {
+	name   string
+	re     *regexp.Regexp
+	negate bool
+}
+
+func (a *AssertRegex) Assert(ctx context.Context, result *v1alpha1.EvalResult) (*v1alpha1.Assertion, error) {
+	assertion := newCustomResult(a.name)
+
+	commands := generatedCommands(result)
+	if len(commands) == 0 {
+		assertion.Result = v1alpha1.AssertResult_SKIPPED
+		assertion.Detail = "Answer doesn't contain a code cell"
+		return assertion, nil
+	}
+
+	matched := a.re.MatchString(strings.Join(commands, "\n"))
+
+	if matched == a.negate {
+		assertion.Result = v1alpha1.AssertResult_FAILED
+		if a.negate {
+			assertion.Detail = fmt.Sprintf("Generated command matches forbidden pattern %s", a.re.String())
+		} else {
+			assertion.Detail = fmt.Sprintf("Generated command doesn't match pattern %s", a.re.String())
+		}
+		return assertion, nil
+	}
+
+	assertion.Result = v1alpha1.AssertResult_PASSED
+	return assertion, nil
+}
+
+func (a *AssertRegex) Name() string {
+	return a.name
+}
+
+// AssertBinaries checks which binaries are invoked by the generated commands.
+type AssertBinaries struct {
+	name      string
+	parser    *executor.BashishParser
+	required  []string
+	forbidden []string
+}
+
+func (a *AssertBinaries) Assert(ctx context.Context, result *v1alpha1.EvalResult) (*v1alpha1.Assertion, error) {
+	assertion := newCustomResult(a.name)
+
+	commands := generatedCommands(result)
+	if len(commands) == 0 {
+		assertion.Result = v1alpha1.AssertResult_SKIPPED
+		assertion.Detail = "Answer doesn't contain a code cell"
+		return assertion, nil
+	}
+
+	binaries := make(map[string]bool)
+	for _, c := range commands {
+		instructions, err := a.parser.Parse(c)
+		if err != nil {
+			// The parser only understands a subset of bash so we don't want a parse failure to abort the evaluation.
+			assertion.Result = v1alpha1.AssertResult_UNKNOWN_AssertResult
+			assertion.Detail = fmt.Sprintf("Failed to parse command; %v", err)
+			return assertion, nil
+		}
+		for _, i := range instructions {
+			if i.Command == nil || i.Command.Name == "" {
+				continue
+			}
+			binaries[filepath.Base(i.Command.Name)] = true
+		}
+	}
+
+	problems := make([]string, 0, len(a.required)+len(a.forbidden))
+	for _, b := range a.required {
+		if !binaries[b] {
+			problems = append(problems, fmt.Sprintf("required binary %s isn't invoked", b))
+		}
+	}
+
+	for _, b := range a.forbidden {
+		if binaries[b] {
+			problems = append(problems, fmt.Sprintf("forbidden binary %s is invoked", b))
+		}
+	}
+
+	if len(problems) > 0 {
+		assertion.Result = v1alpha1.AssertResult_FAILED
+		assertion.Detail = strings.Join(problems, "; ")
+		return assertion, nil
+	}
+
+	assertion.Result = v1alpha1.AssertResult_PASSED
+	return assertion, nil
+}
+
+func (a *AssertBinaries) Name() string {
+	return a.name
+}
+
+// AssertMaxCells checks the number of generated cells doesn't exceed a limit.
+type AssertMaxCells struct {
+	name string
+	max  int
+}
+
+func (a *AssertMaxCells) Assert(ctx context.Context, result *v1alpha1.EvalResult) (*v1alpha1.Assertion, error) {
+	assertion := newCustomResult(a.name)
+
+	numCells := len(result.GetActualCells())
+	if numCells > a.max {
+		assertion.Result = v1alpha1.AssertResult_FAILED
+		assertion.Detail = fmt.Sprintf("Answer has %d cells; the maximum is %d", numCells, a.max)
+		return assertion, nil
+	}
+
+	assertion.Result = v1alpha1.AssertResult_PASSED
+	return assertion, nil
+}
+
+func (a *AssertMaxCells) Name() string {
+	return a.name
+}
+
+// AssertCEL evaluates a CEL expression over the EvalResult.
+// The result is bound to the variable "result" and the expression must evaluate to a bool.
+type AssertCEL struct {
+	name       string
+	expression string
+	program    cel.Program
+}
+
+// NewAssertCEL compiles the expression and returns an assertion that evaluates it.
+func NewAssertCEL(name string, expression string) (*AssertCEL, error) {
+	if expression == "" {
+		return nil, errors.New("CEL expression is empty")
+	}
+
+	env, err := cel.NewEnv(
+		cel.Types(&v1alpha1.EvalResult{}),
+		cel.Variable("result", cel.ObjectType(string((&v1alpha1.EvalResult{}).ProtoReflect().Descriptor().FullName()))),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create CEL environment")
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Wrapf(issues.Err(), "Failed to compile CEL expression %s", expression)
+	}
+
+	if ast.OutputType() != cel.BoolType {
+		return nil, errors.Errorf("CEL expression %s must evaluate to a bool; got %v", expression, ast.OutputType())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create program for CEL expression %s", expression)
+	}
+
+	return &AssertCEL{
+		name:       name,
+		expression: expression,
+		program:    program,
+	}, nil
+}
+
+func (a *AssertCEL) Assert(ctx context.Context, result *v1alpha1.EvalResult) (*v1alpha1.Assertion, error) {
+	assertion := newCustomResult(a.name)
+
+	out, _, err := a.program.ContextEval(ctx, map[string]interface{}{
+		"result": result,
+	})
+	if err != nil {
+		assertion.Result = v1alpha1.AssertResult_UNKNOWN_AssertResult
+		assertion.Detail = fmt.Sprintf("Failed to evaluate CEL expression; %v", err)
+		return assertion, nil
+	}
+
+	passed, ok := out.Value().(bool)
+	if !ok {
+		return nil, errors.Errorf("CEL expression %s didn't evaluate to a bool; got %v", a.expression, out.Value())
+	}
+
+	if !passed {
+		assertion.Result = v1alpha1.AssertResult_FAILED
+		assertion.Detail = fmt.Sprintf("Expression %s evaluated to false", a.expression)
+		return assertion, nil
+	}
+
+	assertion.Result = v1alpha1.AssertResult_PASSED
+	return assertion, nil
+}
+
+func (a *AssertCEL) Name() string {
+	return a.name
+}
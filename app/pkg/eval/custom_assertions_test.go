@@ -0,0 +1,174 @@
+package eval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	parserv1 "github.com/stateful/runme/v3/pkg/api/gen/proto/go/runme/parser/v1"
+)
+
+func Test_CustomAssertions(t *testing.T) {
+	type testCase struct {
+		name     string
+		spec     api.AssertionSpec
+		result   *v1alpha1.EvalResult
+		expected v1alpha1.AssertResult
+	}
+
+	kubectlResult := &v1alpha1.EvalResult{
+		ActualCells: []*parserv1.Cell{
+			{
+				Kind:  parserv1.CellKind_CELL_KIND_MARKUP,
+				Value: "List the pods",
+			},
+			{
+				Kind:  parserv1.CellKind_CELL_KIND_CODE,
+				Value: "kubectl get pods | grep foyle",
+			},
+		},
+		GenerateTimeMs: 100,
+	}
+
+	emptyResult := &v1alpha1.EvalResult{}
+
+	cases := []testCase{
+		{
+			name: "regex-passed",
+			spec: api.AssertionSpec{
+				Name:  "regex",
+				Regex: &api.RegexAssertion{Pattern: `^kubectl get`},
+			},
+			result:   kubectlResult,
+			expected: v1alpha1.AssertResult_PASSED,
+		},
+		{
+			name: "regex-negate-failed",
+			spec: api.AssertionSpec{
+				Name:  "regex",
+				Regex: &api.RegexAssertion{Pattern: `kubectl`, Negate: true},
+			},
+			result:   kubectlResult,
+			expected: v1alpha1.AssertResult_FAILED,
+		},
+		{
+			name: "regex-no-code",
+			spec: api.AssertionSpec{
+				Name:  "regex",
+				Regex: &api.RegexAssertion{Pattern: `kubectl`},
+			},
+			result:   emptyResult,
+			expected: v1alpha1.AssertResult_SKIPPED,
+		},
+		{
+			name: "binaries-passed",
+			spec: api.AssertionSpec{
+				Name: "binaries",
+				Binaries: &api.BinariesAssertion{
+					Required:  []string{"kubectl", "grep"},
+					Forbidden: []string{"rm"},
+				},
+			},
+			result:   kubectlResult,
+			expected: v1alpha1.AssertResult_PASSED,
+		},
+		{
+			name: "binaries-forbidden",
+			spec: api.AssertionSpec{
+				Name: "binaries",
+				Binaries: &api.BinariesAssertion{
+					Forbidden: []string{"grep"},
+				},
+			},
+			result:   kubectlResult,
+			expected: v1alpha1.AssertResult_FAILED,
+		},
+		{
+			name: "max-cells-failed",
+			spec: api.AssertionSpec{
+				Name:     "maxCells",
+				MaxCells: &api.MaxCellsAssertion{Max: 1},
+			},
+			result:   kubectlResult,
+			expected: v1alpha1.AssertResult_FAILED,
+		},
+		{
+			name: "cel-passed",
+			spec: api.AssertionSpec{
+				Name: "cel",
+				CEL:  &api.CELAssertion{Expression: `size(result.actual_cells) == 2 && result.generate_time_ms < 1000`},
+			},
+			result:   kubectlResult,
+			expected: v1alpha1.AssertResult_PASSED,
+		},
+		{
+			name: "cel-failed",
+			spec: api.AssertionSpec{
+				Name: "cel",
+				CEL:  &api.CELAssertion{Expression: `result.actual_cells.exists(c, c.value.startsWith("gcloud"))`},
+			},
+			result:   kubectlResult,
+			expected: v1alpha1.AssertResult_FAILED,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertions, err := NewCustomAssertions([]api.AssertionSpec{c.spec})
+			if err != nil {
+				t.Fatalf("Error creating assertions; %v", err)
+			}
+
+			actual, err := assertions[0].Assert(context.Background(), c.result)
+			if err != nil {
+				t.Fatalf("Error running assertion; %v", err)
+			}
+
+			expected := &v1alpha1.Assertion{
+				Name:       v1alpha1.Assertion_CUSTOM,
+				CustomName: c.spec.Name,
+				Result:     c.expected,
+			}
+
+			opts := cmpopts.IgnoreUnexported(v1alpha1.Assertion{})
+			if d := cmp.Diff(expected, actual, opts, cmpopts.IgnoreFields(v1alpha1.Assertion{}, "Detail")); d != "" {
+				t.Errorf("Unexpected diff:\n%v", d)
+			}
+		})
+	}
+}
+
+func Test_CustomAssertionsInvalid(t *testing.T) {
+	cases := map[string][]api.AssertionSpec{
+		"no-name": {
+			{MaxCells: &api.MaxCellsAssertion{Max: 1}},
+		},
+		"duplicate-name": {
+			{Name: "a", MaxCells: &api.MaxCellsAssertion{Max: 1}},
+			{Name: "a", MaxCells: &api.MaxCellsAssertion{Max: 2}},
+		},
+		"built-in-name": {
+			{Name: "ONE_CODE_CELL", MaxCells: &api.MaxCellsAssertion{Max: 1}},
+		},
+		"multiple-types": {
+			{Name: "a", MaxCells: &api.MaxCellsAssertion{Max: 1}, Regex: &api.RegexAssertion{Pattern: "a"}},
+		},
+		"cel-not-bool": {
+			{Name: "a", CEL: &api.CELAssertion{Expression: "result.generate_time_ms"}},
+		},
+		"bad-regex": {
+			{Name: "a", Regex: &api.RegexAssertion{Pattern: "("}},
+		},
+	}
+
+	for name, specs := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewCustomAssertions(specs); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,433 @@
+package eval
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agnivade/levenshtein"
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/executor"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// datasetManifestSuffix is appended to the OutputDB to get the file the manifest is stored in. The manifest is
+	// named after the OutputDB so experiments with OutputDBs in the same directory don't share a manifest.
+	datasetManifestSuffix = ".dataset.json"
+
+	defaultDedupeThreshold = 0.95
+	defaultTestFraction    = 0.2
+)
+
+// prepareDataset applies the dataset configuration in the experiment to the examples.
+// It returns the examples to process, in time order, along with the split each example belongs to.
+//
+// The first time an experiment is run the manifest is computed and written to disk; subsequent runs reuse the
+// manifest so that resuming an experiment doesn't change the dataset. If the dataset spec changed since the manifest
+// was written an error is returned; delete the manifest to rebuild the dataset.
+func prepareDataset(ctx context.Context, experiment api.Experiment, examples []*v1alpha1.EvalExample) ([]*v1alpha1.EvalExample, map[string]v1alpha1.DatasetSplit, error) {
+	log := logs.FromContext(ctx)
+	splits := make(map[string]v1alpha1.DatasetSplit)
+	if experiment.Spec.Dataset == nil {
+		return examples, splits, nil
+	}
+
+	specHash, err := hashDatasetSpec(experiment.Spec.Dataset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manifestFile := experiment.Spec.OutputDB + datasetManifestSuffix
+	manifest := &v1alpha1.EvalDatasetManifest{}
+	b, err := os.ReadFile(manifestFile)
+	if err == nil {
+		if err := protojson.Unmarshal(b, manifest); err != nil {
+			return nil, nil, errors.Wrapf(err, "Failed to unmarshal dataset manifest %s", manifestFile)
+		}
+		if manifest.GetSpecHash() != specHash {
+			return nil, nil, errors.Errorf("The dataset spec changed since the dataset manifest %s was built; delete the manifest to rebuild the dataset or use a new OutputDB", manifestFile)
+		}
+		log.Info("Using existing dataset manifest", "file", manifestFile, "numEntries", len(manifest.GetEntries()))
+	} else if os.IsNotExist(err) {
+		manifest, err = buildManifest(ctx, experiment.Spec.Dataset, examples)
+		if err != nil {
+			return nil, nil, err
+		}
+		manifest.SpecHash = specHash
+
+		opts := protojson.MarshalOptions{
+			Indent: "  ",
+		}
+		b, err := opts.Marshal(manifest)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "Failed to marshal dataset manifest")
+		}
+		if err := os.MkdirAll(filepath.Dir(manifestFile), 0755); err != nil {
+			return nil, nil, errors.Wrapf(err, "Failed to create directory for dataset manifest")
+		}
+		if err := os.WriteFile(manifestFile, b, 0644); err != nil {
+			return nil, nil, errors.Wrapf(err, "Failed to write dataset manifest %s", manifestFile)
+		}
+		log.Info("Wrote dataset manifest", "file", manifestFile, "numEntries", len(manifest.GetEntries()))
+	} else {
+		return nil, nil, errors.Wrapf(err, "Failed to read dataset manifest %s", manifestFile)
+	}
+
+	selected, splits := applyManifest(examples, manifest)
+	if len(selected) != len(manifest.GetEntries()) {
+		log.Info("Some examples in the manifest weren't found in the eval directory", "numEntries", len(manifest.GetEntries()), "numFound", len(selected))
+	}
+	return selected, splits, nil
+}
+
+// hashDatasetSpec returns a hash of the spec used to detect when the spec of an experiment changes.
+func hashDatasetSpec(spec *api.DatasetSpec) (string, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to marshal dataset spec")
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// applyManifest returns the examples in the manifest and a map from example id to split.
+// The order of the examples is preserved.
+func applyManifest(examples []*v1alpha1.EvalExample, manifest *v1alpha1.EvalDatasetManifest) ([]*v1alpha1.EvalExample, map[string]v1alpha1.DatasetSplit) {
+	splits := make(map[string]v1alpha1.DatasetSplit)
+	for _, e := range manifest.GetEntries() {
+		splits[e.GetId()] = e.GetSplit()
+	}
+
+	selected := make([]*v1alpha1.EvalExample, 0, len(splits))
+	for _, e := range examples {
+		if _, ok := splits[e.GetId()]; ok {
+			selected = append(selected, e)
+		}
+	}
+	return selected, splits
+}
+
+// buildManifest dedupes, splits and samples the examples according to the spec.
+func buildManifest(ctx context.Context, spec *api.DatasetSpec, examples []*v1alpha1.EvalExample) (*v1alpha1.EvalDatasetManifest, error) {
+	log := logs.FromContext(ctx)
+
+	// Sort the examples so that the result is deterministic regardless of the order the files were listed in.
+	examples = append([]*v1alpha1.EvalExample{}, examples...)
+	sort.SliceStable(examples, func(i, j int) bool {
+		ti := examples[i].GetTime().AsTime()
+		tj := examples[j].GetTime().AsTime()
+		if ti.Equal(tj) {
+			return examples[i].GetId() < examples[j].GetId()
+		}
+		return ti.Before(tj)
+	})
+
+	if spec.Dedupe != nil {
+		threshold := spec.Dedupe.Threshold
+		if threshold == 0 {
+			threshold = defaultDedupeThreshold
+		}
+		if threshold < 0 || threshold > 1 {
+			return nil, errors.Errorf("Dedupe threshold must be between 0 and 1; got %v", threshold)
+		}
+		before := len(examples)
+		examples = dedupeExamples(examples, threshold)
+		log.Info("Deduplicated examples", "before", before, "after", len(examples))
+	}
+
+	splits := make(map[string]v1alpha1.DatasetSplit)
+	if spec.Split != nil {
+		var err error
+		splits, err = splitExamples(spec.Split, examples)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Only the examples that will be evaluated without learning are sampled. Train examples are always replayed
+	// because learning from them is the point of the train split.
+	toSample := make([]*v1alpha1.EvalExample, 0, len(examples))
+	included := make(map[string]bool)
+	for _, e := range examples {
+		if splits[e.GetId()] == v1alpha1.DatasetSplit_TRAIN {
+			included[e.GetId()] = true
+			continue
+		}
+		toSample = append(toSample, e)
+	}
+
+	strata := make(map[string]string)
+	if spec.Sample != nil {
+		parser, err := executor.NewBashishParser()
+		if err != nil {
+			return nil, err
+		}
+		sampled, sampleStrata, err := sampleExamples(spec.Sample, toSample, parser)
+		if err != nil {
+			return nil, err
+		}
+		toSample = sampled
+		strata = sampleStrata
+	}
+
+	for _, e := range toSample {
+		included[e.GetId()] = true
+	}
+
+	manifest := &v1alpha1.EvalDatasetManifest{
+		Entries: make([]*v1alpha1.DatasetEntry, 0, len(included)),
+	}
+	for _, e := range examples {
+		if !included[e.GetId()] {
+			continue
+		}
+		manifest.Entries = append(manifest.Entries, &v1alpha1.DatasetEntry{
+			Id:      e.GetId(),
+			Split:   splits[e.GetId()],
+			Stratum: strata[e.GetId()],
+		})
+	}
+	return manifest, nil
+}
+
+// dedupeExamples removes examples that are near-identical to an earlier example.
+// Examples should be sorted in time order so the earliest copy is kept.
+func dedupeExamples(examples []*v1alpha1.EvalExample, threshold float64) []*v1alpha1.EvalExample {
+	kept := make([]*v1alpha1.EvalExample, 0, len(examples))
+	keptText := make([]string, 0, len(examples))
+	for _, e := range examples {
+		text := dedupeText(e)
+		isDup := false
+		for _, other := range keptText {
+			if similarity(text, other) >= threshold {
+				isDup = true
+				break
+			}
+		}
+		if isDup {
+			continue
+		}
+		kept = append(kept, e)
+		keptText = append(keptText, text)
+	}
+	return kept
+}
+
+// dedupeText returns the text used to decide if two examples are duplicates. We use the selected cell of the input
+// and the expected cell; comparing entire notebooks would be expensive and notebooks often share a long prefix.
+func dedupeText(e *v1alpha1.EvalExample) string {
+	parts := make([]string, 0, 2)
+	cells := e.GetFullContext().GetNotebook().GetCells()
+	selected := int(e.GetFullContext().GetSelected())
+	if selected >= 0 && selected < len(cells) {
+		parts = append(parts, cells[selected].GetValue())
+	}
+	for _, c := range e.GetExpectedCells() {
+		parts = append(parts, c.GetValue())
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, "\n")), " ")
+}
+
+// similarity returns the normalized levenshtein similarity of two strings; 1 means the strings are identical.
+func similarity(a, b string) float64 {
+	maxLen := len([]rune(a))
+	if l := len([]rune(b)); l > maxLen {
+		maxLen = l
+	}
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein.ComputeDistance(a, b))/float64(maxLen)
+}
+
+// splitExamples assigns each example to the train or test split. Examples should be sorted in time order.
+func splitExamples(spec *api.SplitSpec, examples []*v1alpha1.EvalExample) (map[string]v1alpha1.DatasetSplit, error) {
+	testFraction := spec.TestFraction
+	if testFraction == 0 {
+		testFraction = defaultTestFraction
+	}
+	if testFraction < 0 || testFraction > 1 {
+		return nil, errors.Errorf("TestFraction must be between 0 and 1; got %v", testFraction)
+	}
+
+	splits := make(map[string]v1alpha1.DatasetSplit)
+	switch spec.By {
+	case api.SplitByTime:
+		if spec.Cutoff != "" {
+			cutoff, err := time.Parse(time.RFC3339, spec.Cutoff)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to parse cutoff %s; it should be an RFC3339 timestamp", spec.Cutoff)
+			}
+			for _, e := range examples {
+				if e.GetTime().AsTime().Before(cutoff) {
+					splits[e.GetId()] = v1alpha1.DatasetSplit_TRAIN
+				} else {
+					splits[e.GetId()] = v1alpha1.DatasetSplit_TEST
+				}
+			}
+			return splits, nil
+		}
+
+		numTest := int(math.Ceil(testFraction * float64(len(examples))))
+		for i, e := range examples {
+			if i >= len(examples)-numTest {
+				splits[e.GetId()] = v1alpha1.DatasetSplit_TEST
+			} else {
+				splits[e.GetId()] = v1alpha1.DatasetSplit_TRAIN
+			}
+		}
+	case api.SplitByNotebook:
+		for _, e := range examples {
+			key := notebookKey(e)
+			// Hash the notebook so the assignment of a notebook doesn't change as new examples are added.
+			h := fnv.New64a()
+			h.Write([]byte(fmt.Sprintf("%d/%s", spec.Seed, key)))
+			if float64(h.Sum64()%10000)/10000 < testFraction {
+				splits[e.GetId()] = v1alpha1.DatasetSplit_TEST
+			} else {
+				splits[e.GetId()] = v1alpha1.DatasetSplit_TRAIN
+			}
+		}
+	default:
+		return nil, errors.Errorf("Unsupported split %q; must be one of %s, %s", spec.By, api.SplitByTime, api.SplitByNotebook)
+	}
+	return splits, nil
+}
+
+// sampleExamples samples up to spec.Size examples. It returns the sampled examples in their original order along
+// with the stratum of each sampled example.
+func sampleExamples(spec *api.SampleSpec, examples []*v1alpha1.EvalExample, parser *executor.BashishParser) ([]*v1alpha1.EvalExample, map[string]string, error) {
+	if spec.Size <= 0 {
+		return nil, nil, errors.Errorf("Sample size must be positive; got %d", spec.Size)
+	}
+
+	strata := make(map[string]string)
+	groups := make(map[string][]*v1alpha1.EvalExample)
+	for _, e := range examples {
+		key, err := stratumKey(spec.StratifyBy, e, parser)
+		if err != nil {
+			return nil, nil, err
+		}
+		strata[e.GetId()] = key
+		groups[key] = append(groups[key], e)
+	}
+
+	if len(examples) <= spec.Size {
+		return examples, strata, nil
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// Allocate the sample proportionally to the size of each stratum using the largest remainder method.
+	alloc := make(map[string]int)
+	remainders := make([]string, 0, len(keys))
+	total := 0
+	for _, k := range keys {
+		exact := float64(spec.Size) * float64(len(groups[k])) / float64(len(examples))
+		alloc[k] = int(math.Floor(exact))
+		total += alloc[k]
+		remainders = append(remainders, k)
+	}
+	sort.SliceStable(remainders, func(i, j int) bool {
+		ri := float64(spec.Size)*float64(len(groups[remainders[i]]))/float64(len(examples)) - float64(alloc[remainders[i]])
+		rj := float64(spec.Size)*float64(len(groups[remainders[j]]))/float64(len(examples)) - float64(alloc[remainders[j]])
+		return ri > rj
+	})
+	for i := 0; total < spec.Size; i++ {
+		alloc[remainders[i%len(remainders)]]++
+		total++
+	}
+
+	rng := rand.New(rand.NewSource(spec.Seed))
+	selected := make(map[string]bool)
+	for _, k := range keys {
+		group := groups[k]
+		perm := rng.Perm(len(group))
+		for i := 0; i < alloc[k] && i < len(group); i++ {
+			selected[group[perm[i]].GetId()] = true
+		}
+	}
+
+	sampled := make([]*v1alpha1.EvalExample, 0, spec.Size)
+	for _, e := range examples {
+		if selected[e.GetId()] {
+			sampled = append(sampled, e)
+		}
+	}
+	return sampled, strata, nil
+}
+
+// stratumKey returns the value of the stratification key for the example.
+func stratumKey(by string, e *v1alpha1.EvalExample, parser *executor.BashishParser) (string, error) {
+	switch by {
+	case "":
+		return "", nil
+	case api.StratifyByCommandFamily:
+		return commandFamily(e, parser), nil
+	case api.StratifyByNotebook:
+		return notebookKey(e), nil
+	case api.StratifyByUser:
+		return userFromURI(e.GetFullContext().GetNotebookUri()), nil
+	default:
+		return "", errors.Errorf("Unsupported stratifyBy %q; must be one of %s, %s, %s", by, api.StratifyByCommandFamily, api.StratifyByNotebook, api.StratifyByUser)
+	}
+}
+
+// commandFamily returns the binary invoked by the expected cell e.g. "kubectl" or "gcloud".
+func commandFamily(e *v1alpha1.EvalExample, parser *executor.BashishParser) string {
+	for _, c := range e.GetExpectedCells() {
+		instructions, err := parser.Parse(c.GetValue())
+		if err == nil {
+			for _, i := range instructions {
+				if i.Command != nil && i.Command.Name != "" {
+					return filepath.Base(i.Command.Name)
+				}
+			}
+		}
+		// Fall back to the first word if the parser can't handle the command.
+		if fields := strings.Fields(c.GetValue()); len(fields) > 0 {
+			return filepath.Base(fields[0])
+		}
+	}
+	return ""
+}
+
+// notebookKey returns the key identifying the notebook an example came from.
+// If the notebook URI isn't known then each example is treated as its own notebook.
+func notebookKey(e *v1alpha1.EvalExample) string {
+	if uri := e.GetFullContext().GetNotebookUri(); uri != "" {
+		return uri
+	}
+	return e.GetId()
+}
+
+// userFromURI infers the user from the path of the notebook e.g. file:///Users/jlewi/notes.md -> jlewi.
+// Sessions don't record the user so the home directory in the path is the best signal we have.
+// Returns an empty string if the user can't be determined.
+func userFromURI(uri string) string {
+	pieces := strings.Split(uri, "/")
+	for i, p := range pieces {
+		if (p == "Users" || p == "home") && i+1 < len(pieces) {
+			return pieces[i+1]
+		}
+	}
+	return ""
+}
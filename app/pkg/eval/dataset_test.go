@@ -0,0 +1,279 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/executor"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	parserv1 "github.com/stateful/runme/v3/pkg/api/gen/proto/go/runme/parser/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newDatasetExample(id string, minute int, notebook string, command string) *v1alpha1.EvalExample {
+	return &v1alpha1.EvalExample{
+		Id:   id,
+		Time: timestamppb.New(time.Date(2024, 9, 1, 0, minute, 0, 0, time.UTC)),
+		FullContext: &v1alpha1.FullContext{
+			NotebookUri: notebook,
+			Notebook: &parserv1.Notebook{
+				Cells: []*parserv1.Cell{
+					{
+						Kind:  parserv1.CellKind_CELL_KIND_MARKUP,
+						Value: "Run the command",
+					},
+				},
+			},
+		},
+		ExpectedCells: []*parserv1.Cell{
+			{
+				Kind:  parserv1.CellKind_CELL_KIND_CODE,
+				Value: command,
+			},
+		},
+	}
+}
+
+func ids(examples []*v1alpha1.EvalExample) []string {
+	result := make([]string, 0, len(examples))
+	for _, e := range examples {
+		result = append(result, e.GetId())
+	}
+	return result
+}
+
+func Test_DedupeExamples(t *testing.T) {
+	examples := []*v1alpha1.EvalExample{
+		newDatasetExample("a", 0, "", "kubectl get pods -n foyle"),
+		newDatasetExample("b", 1, "", "kubectl get pods -n foyle "),
+		newDatasetExample("c", 2, "", "gcloud builds list"),
+	}
+
+	actual := ids(dedupeExamples(examples, defaultDedupeThreshold))
+	expected := []string{"a", "c"}
+	if d := cmp.Diff(expected, actual); d != "" {
+		t.Errorf("Unexpected examples; diff:\n%v", d)
+	}
+}
+
+func Test_SplitExamples(t *testing.T) {
+	examples := []*v1alpha1.EvalExample{
+		newDatasetExample("a", 0, "file:///Users/alice/a.md", "ls"),
+		newDatasetExample("b", 1, "file:///Users/alice/a.md", "ls"),
+		newDatasetExample("c", 2, "file:///Users/bob/b.md", "ls"),
+		newDatasetExample("d", 3, "file:///Users/bob/c.md", "ls"),
+		newDatasetExample("e", 4, "file:///Users/bob/d.md", "ls"),
+	}
+
+	type testCase struct {
+		name     string
+		spec     *api.SplitSpec
+		expected map[string]v1alpha1.DatasetSplit
+	}
+
+	cases := []testCase{
+		{
+			name: "time-fraction",
+			spec: &api.SplitSpec{By: api.SplitByTime, TestFraction: 0.4},
+			expected: map[string]v1alpha1.DatasetSplit{
+				"a": v1alpha1.DatasetSplit_TRAIN,
+				"b": v1alpha1.DatasetSplit_TRAIN,
+				"c": v1alpha1.DatasetSplit_TRAIN,
+				"d": v1alpha1.DatasetSplit_TEST,
+				"e": v1alpha1.DatasetSplit_TEST,
+			},
+		},
+		{
+			name: "time-cutoff",
+			spec: &api.SplitSpec{By: api.SplitByTime, Cutoff: "2024-09-01T00:02:00Z"},
+			expected: map[string]v1alpha1.DatasetSplit{
+				"a": v1alpha1.DatasetSplit_TRAIN,
+				"b": v1alpha1.DatasetSplit_TRAIN,
+				"c": v1alpha1.DatasetSplit_TEST,
+				"d": v1alpha1.DatasetSplit_TEST,
+				"e": v1alpha1.DatasetSplit_TEST,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := splitExamples(c.spec, examples)
+			if err != nil {
+				t.Fatalf("Error splitting examples; %v", err)
+			}
+			if d := cmp.Diff(c.expected, actual); d != "" {
+				t.Errorf("Unexpected splits; diff:\n%v", d)
+			}
+		})
+	}
+
+	t.Run("notebook", func(t *testing.T) {
+		actual, err := splitExamples(&api.SplitSpec{By: api.SplitByNotebook, TestFraction: 0.5, Seed: 7}, examples)
+		if err != nil {
+			t.Fatalf("Error splitting examples; %v", err)
+		}
+		// Examples from the same notebook must always end up in the same split.
+		if actual["a"] != actual["b"] {
+			t.Errorf("Examples from the same notebook were assigned different splits; %v", actual)
+		}
+	})
+}
+
+func Test_SampleExamples(t *testing.T) {
+	parser, err := executor.NewBashishParser()
+	if err != nil {
+		t.Fatalf("Error creating parser; %v", err)
+	}
+
+	examples := make([]*v1alpha1.EvalExample, 0, 10)
+	for i := 0; i < 8; i++ {
+		examples = append(examples, newDatasetExample(fmt.Sprintf("kubectl-%d", i), i, "", "kubectl get pods"))
+	}
+	for i := 0; i < 2; i++ {
+		examples = append(examples, newDatasetExample(fmt.Sprintf("gcloud-%d", i), 10+i, "", "gcloud builds list"))
+	}
+
+	spec := &api.SampleSpec{Size: 5, StratifyBy: api.StratifyByCommandFamily, Seed: 1}
+	sampled, strata, err := sampleExamples(spec, examples, parser)
+	if err != nil {
+		t.Fatalf("Error sampling examples; %v", err)
+	}
+
+	counts := make(map[string]int)
+	for _, e := range sampled {
+		counts[strata[e.GetId()]]++
+	}
+
+	expected := map[string]int{
+		"kubectl": 4,
+		"gcloud":  1,
+	}
+	if d := cmp.Diff(expected, counts); d != "" {
+		t.Errorf("Unexpected counts per stratum; diff:\n%v", d)
+	}
+
+	// Sampling with the same seed should be deterministic.
+	again, _, err := sampleExamples(spec, examples, parser)
+	if err != nil {
+		t.Fatalf("Error sampling examples; %v", err)
+	}
+	if d := cmp.Diff(ids(sampled), ids(again)); d != "" {
+		t.Errorf("Sampling isn't deterministic; diff:\n%v", d)
+	}
+}
+
+func Test_BuildManifest(t *testing.T) {
+	examples := []*v1alpha1.EvalExample{
+		newDatasetExample("a", 0, "", "kubectl get pods"),
+		newDatasetExample("b", 1, "", "gcloud builds list"),
+		newDatasetExample("c", 2, "", "docker ps"),
+		newDatasetExample("d", 3, "", "git status"),
+	}
+
+	spec := &api.DatasetSpec{
+		Split:  &api.SplitSpec{By: api.SplitByTime, TestFraction: 0.5},
+		Sample: &api.SampleSpec{Size: 1, Seed: 3},
+	}
+
+	manifest, err := buildManifest(context.Background(), spec, examples)
+	if err != nil {
+		t.Fatalf("Error building manifest; %v", err)
+	}
+
+	numTrain := 0
+	numTest := 0
+	for _, e := range manifest.GetEntries() {
+		switch e.GetSplit() {
+		case v1alpha1.DatasetSplit_TRAIN:
+			numTrain++
+		case v1alpha1.DatasetSplit_TEST:
+			numTest++
+		}
+	}
+
+	// Only the test split is sampled.
+	if numTrain != 2 || numTest != 1 {
+		t.Errorf("Unexpected manifest; want 2 train and 1 test examples; got %d train and %d test", numTrain, numTest)
+	}
+}
+
+func Test_PrepareDataset(t *testing.T) {
+	examples := []*v1alpha1.EvalExample{
+		newDatasetExample("a", 0, "", "kubectl get pods"),
+		newDatasetExample("b", 1, "", "gcloud builds list"),
+		newDatasetExample("c", 2, "", "docker ps"),
+		newDatasetExample("d", 3, "", "git status"),
+	}
+
+	dir := t.TempDir()
+	newExperiment := func(name string, testFraction float64) api.Experiment {
+		return api.Experiment{
+			Spec: api.ExperimentSpec{
+				OutputDB: filepath.Join(dir, name+".sqlite"),
+				Dataset: &api.DatasetSpec{
+					Split: &api.SplitSpec{By: api.SplitByTime, TestFraction: testFraction},
+				},
+			},
+		}
+	}
+
+	countTest := func(splits map[string]v1alpha1.DatasetSplit) int {
+		n := 0
+		for _, s := range splits {
+			if s == v1alpha1.DatasetSplit_TEST {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Two experiments with OutputDBs in the same directory must each get their own manifest.
+	first := newExperiment("first", 0.25)
+	second := newExperiment("second", 0.75)
+	for _, c := range []struct {
+		experiment api.Experiment
+		numTest    int
+	}{
+		{experiment: first, numTest: 1},
+		{experiment: second, numTest: 3},
+		// Rerunning the first experiment reuses its manifest.
+		{experiment: first, numTest: 1},
+	} {
+		_, splits, err := prepareDataset(context.Background(), c.experiment, examples)
+		if err != nil {
+			t.Fatalf("Error preparing dataset for %s; %+v", c.experiment.Spec.OutputDB, err)
+		}
+		if n := countTest(splits); n != c.numTest {
+			t.Errorf("Experiment %s: want %d test examples; got %d", c.experiment.Spec.OutputDB, c.numTest, n)
+		}
+		if _, err := os.Stat(c.experiment.Spec.OutputDB + datasetManifestSuffix); err != nil {
+			t.Errorf("Expected the manifest for %s to exist; %v", c.experiment.Spec.OutputDB, err)
+		}
+	}
+
+	// Editing the spec of an experiment that already has a manifest is an error.
+	edited := newExperiment("first", 0.5)
+	if _, _, err := prepareDataset(context.Background(), edited, examples); err == nil {
+		t.Errorf("Expected an error when the dataset spec changed")
+	}
+}
+
+func Test_UserFromURI(t *testing.T) {
+	cases := map[string]string{
+		"file:///Users/jlewi/git_foyle/notes.md": "jlewi",
+		"file:///home/alice/notes.md":            "alice",
+		"file:///tmp/notes.md":                   "",
+	}
+	for uri, expected := range cases {
+		if actual := userFromURI(uri); actual != expected {
+			t.Errorf("userFromURI(%v); got %v, want %v", uri, actual, expected)
+		}
+	}
+}
@@ -109,9 +109,15 @@ func (e *Evaluator) Reconcile(ctx context.Context, experiment api.Experiment) er
 		connect.WithInterceptors(otelInterceptor),
 	)
 
-	manager, err := openResultsManager(experiment.Spec.OutputDB)
+	// Create the custom assertions before processing any examples so that invalid assertions fail fast.
+	assertions, err := NewCustomAssertions(experiment.Spec.Assertions)
 	if err != nil {
-		return errors.Wrapf(err, "Failed to open results manager from file %s", experiment.Spec.OutputDB)
+		return errors.Wrapf(err, "Failed to create custom assertions")
+	}
+
+	manager, err := openResultsManager(resultsDBConfig(experiment))
+	if err != nil {
+		return errors.Wrapf(err, "Failed to open results manager for %s", experiment.Spec.OutputDB)
 	}
 
 	// Find all the binary protobuf files in the eval directory.
@@ -157,8 +163,14 @@ func (e *Evaluator) Reconcile(ctx context.Context, experiment api.Experiment) er
 	// Now sort the examples in time order so we can process them in the same order they actually occurred
 	sortEvalExamplesInTime(examples)
 
+	// Select the examples to evaluate and the split each one belongs to.
+	examples, splits, err := prepareDataset(ctx, experiment, examples)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to prepare the eval dataset")
+	}
+
 	// Now generate predictions for any results that are missing them.
-	if err := e.processExamples(ctx, experiment, examples, lastProcessedTime, aiClient, logsClient, manager); err != nil {
+	if err := e.processExamples(ctx, experiment, examples, lastProcessedTime, aiClient, logsClient, manager, assertions, splits); err != nil {
 		return err
 	}
 
@@ -194,7 +206,7 @@ func (e *Evaluator) Reconcile(ctx context.Context, experiment api.Experiment) er
 	return nil
 }
 
-func (e *Evaluator) processExamples(ctx context.Context, experiment api.Experiment, examples []*v1alpha1.EvalExample, lastProcessedTime time.Time, client v1alpha1connect.AIServiceClient, logsClient logspbconnect.LogsServiceClient, manager *ResultsManager) error {
+func (e *Evaluator) processExamples(ctx context.Context, experiment api.Experiment, examples []*v1alpha1.EvalExample, lastProcessedTime time.Time, client v1alpha1connect.AIServiceClient, logsClient logspbconnect.LogsServiceClient, manager *ResultsManager, assertions []ResultAssertion, splits map[string]v1alpha1.DatasetSplit) error {
 	oLog := logs.FromContext(ctx)
 
 	oaiClient, err := oai.NewClient(e.config)
@@ -223,14 +235,14 @@ func (e *Evaluator) processExamples(ctx context.Context, experiment api.Experime
 		log.Info("Processing example", "index", eIndex, "numExamples", len(examples))
 
 		exampleCtx := logr.NewContext(ctx, log)
-		if err := e.processExample(exampleCtx, experiment.Metadata.Name, example, client, logsClient, manager, judge); err != nil {
+		if err := e.processExample(exampleCtx, experiment.Metadata.Name, example, splits[example.GetId()], client, logsClient, manager, judge, assertions); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (e *Evaluator) processExample(originalCtx context.Context, name string, example *v1alpha1.EvalExample, client v1alpha1connect.AIServiceClient, logsClient logspbconnect.LogsServiceClient, manager *ResultsManager, judge *Judge) error {
+func (e *Evaluator) processExample(originalCtx context.Context, name string, example *v1alpha1.EvalExample, split v1alpha1.DatasetSplit, client v1alpha1connect.AIServiceClient, logsClient logspbconnect.LogsServiceClient, manager *ResultsManager, judge *Judge, assertions []ResultAssertion) error {
 	log := logs.FromContext(originalCtx).WithValues("exampleId", example.GetId())
 	// We need to start a new trace for this example
 	tp := tracer()
@@ -243,7 +255,7 @@ func (e *Evaluator) processExample(originalCtx context.Context, name string, exa
 	var processErr error
 
 	uErr := manager.Update(ctx, example.GetId(), func(result *v1alpha1.EvalResult) error {
-		processErr = e.processResult(ctx, result, example, client, logsClient, judge)
+		processErr = e.processResult(ctx, result, example, split, client, logsClient, judge, assertions)
 		// We need to return for the transaction to be committed.
 		return nil
 	})
@@ -271,10 +283,13 @@ func (e *Evaluator) processExample(originalCtx context.Context, name string, exa
 		return nil
 	}
 
-	if err := e.waitForBlockLog(ctx, result, logsClient); err != nil {
-		log.Error(err, "Failed to wait for block log")
-		// For now we abort on error to see what's going on.
-		return errors.Wrapf(err, "Failed to get block log for example %s", example.GetId())
+	// Test examples are never executed so there's no block log to wait for.
+	if split != v1alpha1.DatasetSplit_TEST {
+		if err := e.waitForBlockLog(ctx, result, logsClient); err != nil {
+			log.Error(err, "Failed to wait for block log")
+			// For now we abort on error to see what's going on.
+			return errors.Wrapf(err, "Failed to get block log for example %s", example.GetId())
+		}
 	}
 
 	var ragErr error
@@ -299,8 +314,9 @@ func (e *Evaluator) processExample(originalCtx context.Context, name string, exa
 }
 
 // processResult process the result. It is updated in place
-func (e *Evaluator) processResult(ctx context.Context, result *v1alpha1.EvalResult, example *v1alpha1.EvalExample, client v1alpha1connect.AIServiceClient, logsClient logspbconnect.LogsServiceClient, judge *Judge) error {
+func (e *Evaluator) processResult(ctx context.Context, result *v1alpha1.EvalResult, example *v1alpha1.EvalExample, split v1alpha1.DatasetSplit, client v1alpha1connect.AIServiceClient, logsClient logspbconnect.LogsServiceClient, judge *Judge, assertions []ResultAssertion) error {
 	result.Example = example
+	result.Split = split
 	log := logs.FromContext(ctx).WithValues("exampleId", example.GetId())
 	ctx = logr.NewContext(ctx, log)
 	ctx, span := tracer().Start(ctx, "(*Evaluator).processResult")
@@ -317,8 +333,12 @@ func (e *Evaluator) processResult(ctx context.Context, result *v1alpha1.EvalResu
 		return nil
 	}
 
-	if err := runExecute(ctx, result, client); err != nil {
-		return err
+	// Executing the cell is what allows the agent to learn from the example. We don't execute test examples
+	// so that the agent never learns from the examples it is evaluated on.
+	if split != v1alpha1.DatasetSplit_TEST {
+		if err := runExecute(ctx, result, client); err != nil {
+			return err
+		}
 	}
 
 	if err := judge.Score(ctx, result); err != nil {
@@ -327,6 +347,19 @@ func (e *Evaluator) processResult(ctx context.Context, result *v1alpha1.EvalResu
 		return err
 	}
 
+	// Run the custom assertions last so they can make use of the judge's results.
+	return runAssertions(ctx, result, assertions)
+}
+
+// runAssertions evaluates the assertions against the result and stores them in the result.
+func runAssertions(ctx context.Context, result *v1alpha1.EvalResult, assertions []ResultAssertion) error {
+	for _, a := range assertions {
+		assertion, err := a.Assert(ctx, result)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to run assertion %s", a.Name())
+		}
+		setAssertion(result, assertion)
+	}
 	return nil
 }
 
@@ -428,6 +461,10 @@ func runExecute(ctx context.Context, result *v1alpha1.EvalResult, client v1alpha
 	// Start a session to execute the cell
 	execSessionID := ulid.GenerateID()
 
+	if result.GetSplit() == v1alpha1.DatasetSplit_TEST {
+		return errors.Errorf("Example %s is in the test split; test examples must not be executed because the agent would learn from them", result.GetExample().GetId())
+	}
+
 	if len(result.Example.ExpectedCells) != 1 {
 		return errors.New("Expected cells isn't 1; How did this make it into the evaluation dataset? Shouldn't all examples in the eval set have 1 expected cell")
 	}
@@ -589,6 +626,9 @@ func (e *Evaluator) reconcileBestRAGResult(ctx context.Context, evalResult *v1al
 		return errors.WithStack(errors.Errorf("Trace %s is nil", evalResult.GenTraceId))
 	}
 
+	// Record the cost while we have the trace so the report doesn't need to fetch it again.
+	evalResult.CostUsd = genTrace.GetCostUsd()
+
 	for _, span := range genTrace.Spans {
 		if span.GetRag() == nil {
 			continue
@@ -647,7 +687,7 @@ func (e *Evaluator) buildExperimentReport(ctx context.Context, name string, mana
 	r.CellsMatchCounts = make(map[string]int32)
 
 	for _, c := range counts {
-		if c.MatchResult == nil {
+		if !c.MatchResult.Valid {
 			// N.B. I think for unknown it ends up being a nil value. I suspect this is because default values are
 			// elided when marshalling to JSON. We should fix that by changing the JSON serialization.
 			key := v1alpha1.CellsMatchResult_UNKNOWN_CellsMatchResult.String()
@@ -657,17 +697,14 @@ func (e *Evaluator) buildExperimentReport(ctx context.Context, name string, mana
 			r.CellsMatchCounts[key] = r.CellsMatchCounts[key] + int32(c.Count)
 			continue
 		}
-		s, ok := c.MatchResult.(string)
-		if !ok {
-			return r, errors.New("Failed to convert cellsMatchResult to string")
-		}
-		r.CellsMatchCounts[s] = int32(c.Count)
+		r.CellsMatchCounts[c.MatchResult.String] = int32(c.Count)
 	}
 
 	// Compute the 90th, 95th, 99th Percentile of generate time
 	// And assertionstats
-	assertionStats := make(map[v1alpha1.Assertion_Name]*v1alpha1.AssertionCounts)
+	assertionStats := make(map[string]*v1alpha1.AssertionCounts)
 	generateTimes := make([]int, 0, numExamples)
+	costs := make([]float64, 0, numExamples)
 	var cursor *time.Time
 	for {
 		var listErr error
@@ -682,6 +719,11 @@ func (e *Evaluator) buildExperimentReport(ctx context.Context, name string, mana
 		}
 		for _, result := range results {
 			generateTimes = append(generateTimes, int(result.GenerateTimeMs))
+			costs = append(costs, result.GetCostUsd())
+			r.TotalCostUsd += result.GetCostUsd()
+
+			// Assertions stored on the result are the custom assertions declared in the experiment.
+			accumulateAssertionCounts(assertionStats, result.GetAssertions())
 
 			// Get the Level1 assertions for this trace
 			if result.GetGenTraceId() != "" {
@@ -703,38 +745,47 @@ func (e *Evaluator) buildExperimentReport(ctx context.Context, name string, mana
 
 	r.GenerateLatencyStats = percentiles
 
+	costPercentiles, err := computePercentilesOfFloats(costs, []float64{.5, .75, .9, .95})
+	if err != nil {
+		return r, errors.Wrapf(err, "Failed to compute cost percentiles")
+	}
+	r.CostStats = costPercentiles
+
 	// Add the assertions in sorted order based on key
 	statKeys := make([]string, 0, len(assertionStats))
 	for k := range assertionStats {
-		statKeys = append(statKeys, k.String())
+		statKeys = append(statKeys, k)
 	}
+	sort.Strings(statKeys)
 
 	r.AssertionCounts = make([]*v1alpha1.AssertionCounts, 0, len(assertionStats))
 	for _, key := range statKeys {
-		stat := assertionStats[v1alpha1.Assertion_Name(v1alpha1.Assertion_Name_value[key])]
-		r.AssertionCounts = append(r.AssertionCounts, stat)
+		r.AssertionCounts = append(r.AssertionCounts, assertionStats[key])
 	}
 	return r, nil
 }
 
-// accumulateAssertionCounts accumulates assertions into the stats map
-func accumulateAssertionCounts(stats map[v1alpha1.Assertion_Name]*v1alpha1.AssertionCounts, assertions []*v1alpha1.Assertion) {
+// accumulateAssertionCounts accumulates assertions into the stats map. The map is keyed by the name of the assertion
+// as returned by assertionName.
+func accumulateAssertionCounts(stats map[string]*v1alpha1.AssertionCounts, assertions []*v1alpha1.Assertion) {
 	for _, assertion := range assertions {
-		if _, ok := stats[assertion.GetName()]; !ok {
-			stats[assertion.GetName()] = &v1alpha1.AssertionCounts{
-				Name: assertion.GetName(),
+		name := assertionName(assertion)
+		if _, ok := stats[name]; !ok {
+			stats[name] = &v1alpha1.AssertionCounts{
+				Name:       assertion.GetName(),
+				CustomName: assertion.GetCustomName(),
 			}
 		}
 
 		switch assertion.GetResult() {
 		case v1alpha1.AssertResult_PASSED:
-			stats[assertion.GetName()].Passed++
+			stats[name].Passed++
 		case v1alpha1.AssertResult_FAILED:
-			stats[assertion.GetName()].Failed++
+			stats[name].Failed++
 		case v1alpha1.AssertResult_UNKNOWN_AssertResult:
-			stats[assertion.GetName()].Unknown++
+			stats[name].Unknown++
 		case v1alpha1.AssertResult_SKIPPED:
-			stats[assertion.GetName()].Skipped++
+			stats[name].Skipped++
 		}
 	}
 }
@@ -248,7 +248,7 @@ func Test_buildExperimentReport(t *testing.T) {
 		t.Fatalf("Error unmarshalling experiment; %v", err)
 	}
 
-	resultsManager, err := openResultsManager(experiment.Spec.OutputDB)
+	resultsManager, err := openResultsManager(resultsDBConfig(*experiment))
 
 	if err != nil {
 		t.Fatalf("Error opening results manager; %v", err)
@@ -279,15 +279,15 @@ func Test_buildExperimentReport(t *testing.T) {
 func Test_AccumulateAssertionCounts(t *testing.T) {
 	type testCase struct {
 		name       string
-		stats      map[v1alpha1.Assertion_Name]*v1alpha1.AssertionCounts
+		stats      map[string]*v1alpha1.AssertionCounts
 		assertions []*v1alpha1.Assertion
-		expected   map[v1alpha1.Assertion_Name]*v1alpha1.AssertionCounts
+		expected   map[string]*v1alpha1.AssertionCounts
 	}
 
 	cases := []testCase{
 		{
 			name:  "basic",
-			stats: map[v1alpha1.Assertion_Name]*v1alpha1.AssertionCounts{},
+			stats: map[string]*v1alpha1.AssertionCounts{},
 			assertions: []*v1alpha1.Assertion{
 				{
 					Name:   v1alpha1.Assertion_ONE_CODE_CELL,
@@ -298,17 +298,41 @@ func Test_AccumulateAssertionCounts(t *testing.T) {
 					Result: v1alpha1.AssertResult_FAILED,
 				},
 			},
-			expected: map[v1alpha1.Assertion_Name]*v1alpha1.AssertionCounts{
-				v1alpha1.Assertion_ONE_CODE_CELL: {
+			expected: map[string]*v1alpha1.AssertionCounts{
+				v1alpha1.Assertion_ONE_CODE_CELL.String(): {
 					Name:   v1alpha1.Assertion_ONE_CODE_CELL,
 					Passed: 1,
 				},
-				v1alpha1.Assertion_CODE_AFTER_MARKDOWN: {
+				v1alpha1.Assertion_CODE_AFTER_MARKDOWN.String(): {
 					Name:   v1alpha1.Assertion_CODE_AFTER_MARKDOWN,
 					Failed: 1,
 				},
 			},
 		},
+		{
+			name:  "custom",
+			stats: map[string]*v1alpha1.AssertionCounts{},
+			assertions: []*v1alpha1.Assertion{
+				{
+					Name:       v1alpha1.Assertion_CUSTOM,
+					CustomName: "usesKubectl",
+					Result:     v1alpha1.AssertResult_PASSED,
+				},
+				{
+					Name:       v1alpha1.Assertion_CUSTOM,
+					CustomName: "usesKubectl",
+					Result:     v1alpha1.AssertResult_SKIPPED,
+				},
+			},
+			expected: map[string]*v1alpha1.AssertionCounts{
+				"usesKubectl": {
+					Name:       v1alpha1.Assertion_CUSTOM,
+					CustomName: "usesKubectl",
+					Passed:     1,
+					Skipped:    1,
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -66,11 +66,11 @@ func llmSpanToHTML(ctx context.Context, trace *logspb.Trace) (template.HTML, tem
 		return template.HTML("No generate trace was provided"), template.HTML("No generate trace was provided")
 	}
 
+	// Use the last call to the model; when the agent uses tools its request contains the whole conversation.
 	var llmSpan *logspb.LLMSpan
 	for _, t := range trace.GetSpans() {
 		if t.GetLlm() != nil {
 			llmSpan = t.GetLlm()
-			break
 		}
 	}
 
@@ -8,8 +8,10 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/jlewi/foyle/app/api"
 	"github.com/jlewi/foyle/app/pkg/analyze"
 	"github.com/jlewi/foyle/app/pkg/analyze/fsql"
+	"github.com/jlewi/foyle/app/pkg/config"
 	"github.com/jlewi/foyle/app/pkg/logs"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 	"github.com/jlewi/monogo/helpers"
@@ -19,50 +21,51 @@ import (
 
 // ResultsManager manages the database containing the evaluation results
 type ResultsManager struct {
-	queries *fsql.Queries
-	db      *sql.DB
+	queries fsql.Querier
+	store   *analyze.Store
 }
 
 // EvalResultUpdater is a function that updates an evaluation result.
 type EvalResultUpdater func(result *v1alpha1.EvalResult) error
 
-func openResultsManager(dbFile string) (*ResultsManager, error) {
-	stat, err := os.Stat(dbFile)
-	if err == nil && stat.IsDir() {
-		return nil, errors.Wrapf(err, "Can't open database: %v; it is a directory", dbFile)
-	}
-	dbDir := filepath.Dir(dbFile)
-	if err := os.MkdirAll(dbDir, helpers.UserGroupAllPerm); err != nil {
-		return nil, errors.Wrapf(err, "Failed to create directory: %v", dbDir)
+// openResultsManager opens the results database described by cfg. The directory of a SQLite database is created if
+// it doesn't exist.
+func openResultsManager(cfg config.DatabaseConfig) (*ResultsManager, error) {
+	if cfg.Driver == config.SQLiteDriver || cfg.Driver == "" {
+		stat, err := os.Stat(cfg.DSN)
+		if err == nil && stat.IsDir() {
+			return nil, errors.Errorf("Can't open database: %v; it is a directory", cfg.DSN)
+		}
+		dbDir := filepath.Dir(cfg.DSN)
+		if err := os.MkdirAll(dbDir, helpers.UserGroupAllPerm); err != nil {
+			return nil, errors.Wrapf(err, "Failed to create directory: %v", dbDir)
+		}
 	}
 
-	db, err := sql.Open(analyze.SQLLiteDriver, dbFile)
-
+	store, err := analyze.OpenStore(cfg)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Failed to open database: %v", dbFile)
+		return nil, errors.Wrapf(err, "Failed to open the results database")
 	}
+	return NewResultsManager(store)
+}
 
-	manager, err := NewResultsManager(db)
-	if err != nil {
-		return nil, err
+// resultsDBConfig returns the database the results of the experiment are stored in; the experiment's ResultsDB if it
+// is set and otherwise a SQLite database in OutputDB.
+func resultsDBConfig(experiment api.Experiment) config.DatabaseConfig {
+	if experiment.Spec.ResultsDB != nil {
+		return *experiment.Spec.ResultsDB
 	}
-	return manager, nil
+	return config.DatabaseConfig{Driver: config.SQLiteDriver, DSN: experiment.Spec.OutputDB}
 }
 
-func NewResultsManager(db *sql.DB) (*ResultsManager, error) {
-	// create tables
-	// TODO(jeremy): This creates the analyzer and ResultsManager table because we don't separate the DDL statements.
-	// We might want to refactor to support that.
-	if _, err := db.ExecContext(context.TODO(), analyze.GetDDL()); err != nil {
+func NewResultsManager(store *analyze.Store) (*ResultsManager, error) {
+	if err := analyze.MigrateResultsDB(context.TODO(), store); err != nil {
 		return nil, err
 	}
 
-	// Create the dbtx from the actual database
-	queries := fsql.New(db)
-
 	return &ResultsManager{
-		queries: queries,
-		db:      db,
+		queries: store.Queries(store.DB()),
+		store:   store,
 	}, nil
 }
 
@@ -104,12 +107,12 @@ func (m *ResultsManager) Update(ctx context.Context, id string, updateFunc EvalR
 	}
 	log = log.WithValues("exampleId", id)
 
-	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{})
+	tx, err := m.store.BeginTx(ctx)
 	if err != nil {
 		return errors.Wrapf(err, "Failed to start transaction")
 	}
 
-	queries := m.queries.WithTx(tx)
+	queries := m.store.Queries(tx)
 	// Read the record
 	row, err := queries.GetResult(ctx, id)
 
@@ -2,13 +2,16 @@ package eval
 
 import (
 	"context"
-	"database/sql"
-	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/jlewi/foyle/app/api"
 	"github.com/jlewi/foyle/app/pkg/analyze"
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	"github.com/jlewi/foyle/app/pkg/testutil"
+	"github.com/jlewi/monogo/helpers"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -65,21 +68,57 @@ func Test_protoToRowUpdate(t *testing.T) {
 	}
 }
 
-func Test_ListResults(t *testing.T) {
-	tempDir, err := os.MkdirTemp("", "Test_ListResults")
-	defer os.RemoveAll(tempDir)
+// newTestStore creates an empty database of the dialect for the test. Postgres tests are skipped if Postgres
+// isn't available.
+func newTestStore(t *testing.T, dialect dbutil.Dialect) *analyze.Store {
+	t.Helper()
+	cfg := config.DatabaseConfig{Driver: config.SQLiteDriver, DSN: filepath.Join(t.TempDir(), "results.db")}
+	if dialect == dbutil.Postgres {
+		cfg = config.DatabaseConfig{Driver: config.PostgresDriver, DSN: testutil.NewPostgresDB(t)}
+	}
+	store, err := analyze.OpenStore(cfg)
 	if err != nil {
-		t.Fatalf("Error creating temp dir: %v", err)
+		t.Fatalf("Error opening the %s database: %v", dialect, err)
 	}
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Logf("Error closing the %s database: %v", dialect, err)
+		}
+	})
+	return store
+}
 
-	dbFile := filepath.Join(tempDir, "results.db")
+func Test_OpenResultsManager(t *testing.T) {
+	for _, dialect := range []dbutil.Dialect{dbutil.SQLite, dbutil.Postgres} {
+		t.Run(string(dialect), func(t *testing.T) {
+			experiment := api.Experiment{Spec: api.ExperimentSpec{
+				OutputDB: filepath.Join(t.TempDir(), "experiment", "results.sqlite"),
+			}}
+			if dialect == dbutil.Postgres {
+				experiment.Spec.ResultsDB = &config.DatabaseConfig{Driver: config.PostgresDriver, DSN: testutil.NewPostgresDB(t)}
+			}
+			m, err := openResultsManager(resultsDBConfig(experiment))
+			if err != nil {
+				t.Fatalf("Error opening results manager: %+v", err)
+			}
+			defer helpers.DeferIgnoreError(m.store.Close)
+			if m.store.Dialect() != dialect {
+				t.Errorf("Expected the results to be stored in %s; got %s", dialect, m.store.Dialect())
+			}
+		})
+	}
+}
 
-	db, err := sql.Open(analyze.SQLLiteDriver, dbFile)
-	if err != nil {
-		t.Fatalf("Error creating database: %v", err)
+func Test_ListResults(t *testing.T) {
+	for _, dialect := range []dbutil.Dialect{dbutil.SQLite, dbutil.Postgres} {
+		t.Run(string(dialect), func(t *testing.T) {
+			testListResults(t, newTestStore(t, dialect))
+		})
 	}
+}
 
-	m, err := NewResultsManager(db)
+func testListResults(t *testing.T, store *analyze.Store) {
+	m, err := NewResultsManager(store)
 	if err != nil {
 		t.Fatalf("Error creating ResultsManager: %v", err)
 	}
@@ -102,12 +141,14 @@ func Test_ListResults(t *testing.T) {
 				Id:   "1",
 				Time: timestamppb.New(baseTime),
 			},
+			CellsMatchResult: v1alpha1.CellsMatchResult_MATCH,
 		},
 		{
 			Example: &v1alpha1.EvalExample{
 				Id:   "2",
 				Time: timestamppb.New(baseTime.Add(time.Hour)),
 			},
+			Error: "some error",
 		},
 		{
 			Example: &v1alpha1.EvalExample{
@@ -143,7 +184,29 @@ func Test_ListResults(t *testing.T) {
 	}
 
 	expected := baseTime.Add(-1 * time.Hour)
-	if *cursor != baseTime.Add(-1*time.Hour) {
+	if !cursor.Equal(expected) {
 		t.Fatalf("Cursor is invalid; Got %v; Want %v", *cursor, expected)
 	}
+
+	// The generated columns are computed from the JSON.
+	numErrors, err := m.queries.CountErrors(context.Background())
+	if err != nil {
+		t.Fatalf("Error counting errors: %v", err)
+	}
+	if numErrors != 1 {
+		t.Errorf("Expected 1 error but got %v", numErrors)
+	}
+	counts, err := m.queries.CountByCellsMatchResult(context.Background())
+	if err != nil {
+		t.Fatalf("Error counting cells match results: %v", err)
+	}
+	matches := 0
+	for _, c := range counts {
+		if c.MatchResult.Valid && c.MatchResult.String == v1alpha1.CellsMatchResult_MATCH.String() {
+			matches = int(c.Count)
+		}
+	}
+	if matches != 1 {
+		t.Errorf("Expected 1 match but got %v; counts %v", matches, counts)
+	}
 }
@@ -2,7 +2,7 @@ package eval
 
 import (
 	"context"
-	"path/filepath"
+	"sort"
 
 	"github.com/jlewi/foyle/app/pkg/config"
 	"github.com/jlewi/foyle/app/pkg/dbutil"
@@ -16,7 +16,6 @@ import (
 	"go.uber.org/zap"
 
 	"connectrpc.com/connect"
-	"github.com/cockroachdb/pebble"
 	"github.com/jlewi/foyle/app/pkg/logs"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 	"github.com/jlewi/monogo/helpers"
@@ -28,11 +27,11 @@ import (
 // This is used to make results available to the frontend.
 type EvalServer struct {
 	manager  *ResultsManager
-	tracesDB *pebble.DB
+	tracesDB dbutil.KV
 	config   config.Config
 }
 
-func NewEvalServer(cfg config.Config, tracesDB *pebble.DB) *EvalServer {
+func NewEvalServer(cfg config.Config, tracesDB dbutil.KV) *EvalServer {
 	return &EvalServer{
 		config:   cfg,
 		tracesDB: tracesDB,
@@ -51,41 +50,29 @@ func (s *EvalServer) List(
 		return nil, err
 	}
 
-	db, err := pebble.Open(req.Msg.GetDatabase(), &pebble.Options{})
+	db, err := dbutil.OpenPebbleKV(req.Msg.GetDatabase(), false)
 	if err != nil {
 		log.Error(err, "Failed to open database")
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 	defer helpers.DeferIgnoreError(db.Close)
 
-	iter, err := db.NewIterWithContext(ctx, nil)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
-	}
-	defer iter.Close()
-
 	results := &v1alpha1.EvalResultListResponse{
 		Items: make([]*v1alpha1.EvalResult, 0, 100),
 	}
 
-	for iter.First(); iter.Valid(); iter.Next() {
-		key := iter.Key()
-		if key == nil {
-			break
-		}
-
-		value, err := iter.ValueAndErr()
-		if err != nil {
-			log.Error(err, "Failed to read Value for key", "key", string(key))
-			continue
-		}
-
+	err = db.Scan(nil, func(key []byte, value []byte) error {
 		result := &v1alpha1.EvalResult{}
 		if err := proto.Unmarshal(value, result); err != nil {
 			log.Error(err, "Failed to unmarshal Value for", "key", string(key))
-			continue
+			return nil
 		}
 		results.Items = append(results.Items, result)
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Failed to read database")
+		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
 	res := connect.NewResponse(results)
@@ -134,7 +121,7 @@ func (s *EvalServer) GetEvalResult(
 
 	trace := &logspb.Trace{}
 	if err := dbutil.GetProto(s.tracesDB, result.GetGenTraceId(), trace); err != nil {
-		if errors.Is(err, pebble.ErrNotFound) {
+		if errors.Is(err, dbutil.ErrNotFound) {
 			return nil, connect.NewError(connect.CodeNotFound, errors.Wrapf(err, "Failed to get trace with id %s", result.GetGenTraceId()))
 		} else {
 			log := logs.FromContext(ctx)
@@ -169,50 +156,51 @@ func (s *EvalServer) AssertionTable(
 		return nil, err
 	}
 
-	db, err := pebble.Open(req.Msg.GetDatabase(), &pebble.Options{})
+	db, err := dbutil.OpenPebbleKV(req.Msg.GetDatabase(), false)
 	if err != nil {
 		log.Error(err, "Failed to open database")
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 	defer helpers.DeferIgnoreError(db.Close)
 
-	iter, err := db.NewIterWithContext(ctx, nil)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
-	}
-	defer iter.Close()
-
 	results := &v1alpha1.AssertionTableResponse{
 		Rows: make([]*v1alpha1.AssertionRow, 0, 100),
 	}
 
-	for iter.First(); iter.Valid(); iter.Next() {
-		key := iter.Key()
-		if key == nil {
-			break
-		}
-
-		value, err := iter.ValueAndErr()
-		if err != nil {
-			log.Error(err, "Failed to read Value for key", "key", string(key))
-			continue
-		}
+	// The columns are the union of the assertions across all rows. This lets us report user defined assertions
+	// without having to add a field to AssertionRow for each one.
+	columns := make(map[string]bool)
 
+	err = db.Scan(nil, func(key []byte, value []byte) error {
 		result := &v1alpha1.EvalResult{}
 		if err := proto.Unmarshal(value, result); err != nil {
 			log.Error(err, "Failed to unmarshal Value for", "key", string(key))
-			continue
+			return nil
 		}
 
 		row, err := toAssertionRow(result)
 		if err != nil {
 			// TODO(jeremy): Should we put this in the response
 			log.Error(err, "Failed to convert to assertion row", "key", string(key))
-			continue
+			return nil
 		}
 		results.Rows = append(results.Rows, row)
+		for name := range row.GetResults() {
+			columns[name] = true
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Failed to read database")
+		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	results.Columns = make([]string, 0, len(columns))
+	for name := range columns {
+		results.Columns = append(results.Columns, name)
+	}
+	sort.Strings(results.Columns)
+
 	res := connect.NewResponse(results)
 	res.Header().Set("Eval-Version", "v1alpha1")
 	return res, nil
@@ -226,9 +214,9 @@ func (s *EvalServer) tryToLoadResultsManager() error {
 	}
 
 	log := zapr.NewLogger(zap.L())
-	dbFile := filepath.Join(s.config.GetConfigDir(), "results.sqlite")
-	log.Info("Opening results manager", "dbFile", dbFile)
-	manager, err := openResultsManager(dbFile)
+	dbConfig := s.config.GetResultsDBConfig()
+	log.Info("Opening results manager", "driver", dbConfig.Driver)
+	manager, err := openResultsManager(dbConfig)
 	if err != nil {
 		return err
 	}
@@ -242,6 +230,7 @@ func toAssertionRow(result *v1alpha1.EvalResult) (*v1alpha1.AssertionRow, error)
 	row := &v1alpha1.AssertionRow{
 		Id:          result.Example.GetId(),
 		ExampleFile: result.GetExample().FullContext.NotebookUri,
+		Results:     make(map[string]v1alpha1.AssertResult),
 	}
 
 	doc, err := converters.NotebookToDoc(result.GetExample().GetFullContext().GetNotebook())
@@ -262,6 +251,8 @@ func toAssertionRow(result *v1alpha1.EvalResult) (*v1alpha1.AssertionRow, error)
 	row.AnswerMd = docs.DocToMarkdown(actualDoc)
 
 	for _, a := range result.GetAssertions() {
+		row.Results[assertionName(a)] = a.GetResult()
+
 		switch a.Name {
 		case v1alpha1.Assertion_CODE_AFTER_MARKDOWN:
 			row.CodeAfterMarkdown = a.GetResult()
@@ -269,8 +260,10 @@ func toAssertionRow(result *v1alpha1.EvalResult) (*v1alpha1.AssertionRow, error)
 			row.OneCodeCell = a.GetResult()
 		case v1alpha1.Assertion_ENDS_WITH_CODE_CELL:
 			row.EndsWithCodeCell = a.GetResult()
+		case v1alpha1.Assertion_CUSTOM:
+			// Custom assertions are only reported in results.
 		default:
-			log.Info("Unknown assertion", "name", a.Name)
+			log.V(logs.Debug).Info("Assertion only reported in results", "name", a.Name)
 		}
 	}
 
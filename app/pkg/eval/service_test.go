@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	parserv1 "github.com/stateful/runme/v3/pkg/api/gen/proto/go/runme/parser/v1"
 
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
@@ -42,6 +43,11 @@ func Test_ToAssertRow(t *testing.T) {
 						Name:   v1alpha1.Assertion_CODE_AFTER_MARKDOWN,
 						Result: v1alpha1.AssertResult_PASSED,
 					},
+					{
+						Name:       v1alpha1.Assertion_CUSTOM,
+						CustomName: "maxOneCell",
+						Result:     v1alpha1.AssertResult_FAILED,
+					},
 				},
 			},
 			expected: &v1alpha1.AssertionRow{
@@ -49,6 +55,10 @@ func Test_ToAssertRow(t *testing.T) {
 				DocMd:             "Hello world\n",
 				AnswerMd:          "word\n",
 				CodeAfterMarkdown: v1alpha1.AssertResult_PASSED,
+				Results: map[string]v1alpha1.AssertResult{
+					"CODE_AFTER_MARKDOWN": v1alpha1.AssertResult_PASSED,
+					"maxOneCell":          v1alpha1.AssertResult_FAILED,
+				},
 			},
 		},
 	}
@@ -71,6 +81,9 @@ func Test_ToAssertRow(t *testing.T) {
 			if actual.CodeAfterMarkdown != tc.expected.CodeAfterMarkdown {
 				t.Errorf("Unexpected CodeAfterMarkdown; got %v, want %v", actual.CodeAfterMarkdown, tc.expected.CodeAfterMarkdown)
 			}
+			if d := cmp.Diff(tc.expected.Results, actual.Results); d != "" {
+				t.Errorf("Unexpected Results; diff:\n%v", d)
+			}
 		})
 	}
 }
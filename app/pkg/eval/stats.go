@@ -11,7 +11,17 @@ import (
 // computePercentilesOfInts computes the percentiles of a slice of integers.
 // p is a slice of percentiles to compute. Values should be between > 0 and <1.
 func computePercentilesOfInts(data []int, p []float64) ([]*v1alpha1.PercentileStat, error) {
-	sort.Ints(data)
+	return computePercentiles(data, p)
+}
+
+// computePercentilesOfFloats computes the percentiles of a slice of floats.
+// p is a slice of percentiles to compute. Values should be between > 0 and <1.
+func computePercentilesOfFloats(data []float64, p []float64) ([]*v1alpha1.PercentileStat, error) {
+	return computePercentiles(data, p)
+}
+
+func computePercentiles[T int | float64](data []T, p []float64) ([]*v1alpha1.PercentileStat, error) {
+	sort.Slice(data, func(i, j int) bool { return data[i] < data[j] })
 
 	indexes := map[int]bool{}
 	for _, p := range p {
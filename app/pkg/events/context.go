@@ -0,0 +1,69 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+type scopeKey struct{}
+
+// scope is the bus and the trace and session that events published with a context belong to.
+type scope struct {
+	bus       *Bus
+	traceID   string
+	contextID string
+	evalMode  bool
+}
+
+func scopeFromContext(ctx context.Context) scope {
+	if s, ok := ctx.Value(scopeKey{}).(scope); ok {
+		return s
+	}
+	return scope{}
+}
+
+// WithBus returns a context whose events are published to bus.
+func WithBus(ctx context.Context, bus *Bus) context.Context {
+	s := scopeFromContext(ctx)
+	s.bus = bus
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// WithTrace returns a context whose events belong to the generate trace traceID. Use an empty traceID for calls
+// that shouldn't be part of the trace; e.g. summarizing outputs.
+func WithTrace(ctx context.Context, traceID string, evalMode bool) context.Context {
+	s := scopeFromContext(ctx)
+	s.traceID = traceID
+	s.evalMode = evalMode
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// WithContextID returns a context whose events belong to the session contextID.
+func WithContextID(ctx context.Context, contextID string) context.Context {
+	s := scopeFromContext(ctx)
+	s.contextID = contextID
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// Publish publishes the event to the bus in the context. The trace, session and evaluation mode are set from the
+// context unless they are already set. It is a no-op if the context doesn't have a bus so callers don't need to
+// check whether the event pipeline is enabled.
+func Publish(ctx context.Context, e *Event) {
+	s := scopeFromContext(ctx)
+	if s.bus == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.TraceID == "" {
+		e.TraceID = s.traceID
+	}
+	if e.ContextID == "" {
+		e.ContextID = s.contextID
+	}
+	if s.evalMode {
+		e.EvalMode = true
+	}
+	s.bus.Publish(e)
+}
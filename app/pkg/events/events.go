@@ -0,0 +1,178 @@
+// Package events is an in-process event bus for traces and sessions.
+//
+// The agent, the completers and the LogEvents handler publish typed events describing what happened while
+// generating a completion (e.g. the request, the LLM calls and the response) and the events sent by the frontend.
+// The Analyzer subscribes to the bus and writes traces, blocks and sessions directly. Previously the Analyzer
+// reconstructed traces by tailing Foyle's logs and matching the names of the functions that logged each entry; that
+// added latency and broke whenever a function was renamed. Processing logs is now only used to backfill entries
+// written before the bus started.
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/jlewi/foyle/app/api"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+)
+
+var (
+	publishedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "trace_events_published_total",
+		Help: "Number of trace events published on the event bus broken down by type"},
+		[]string{"type"},
+	)
+
+	droppedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "trace_events_dropped_total",
+		Help: "Number of trace events dropped because the event bus was full broken down by type"},
+		[]string{"type"},
+	)
+)
+
+// Event is something that happened while generating a completion or in a session. Exactly one of the payload
+// fields (GenerateRequest through FullContext) is set.
+type Event struct {
+	// Time is when the event happened. Publish sets it to the current time if it is zero.
+	Time time.Time
+	// TraceID is the id of the generate trace the event belongs to. Publish sets it from the context.
+	TraceID string
+	// ContextID is the id of the session the event belongs to. Publish sets it from the context.
+	ContextID string
+	// EvalMode is true if the event was generated in evaluation mode. Publish sets it from the context.
+	EvalMode bool
+
+	// GenerateRequest starts a generate trace.
+	GenerateRequest *v1alpha1.GenerateRequest
+	// GenerateResponse ends a generate trace.
+	GenerateResponse *v1alpha1.GenerateResponse
+	// GenerateError ends a generate trace that failed.
+	GenerateError string
+	// Span is a span of the trace; e.g. a tool call or a reduced output.
+	Span *logspb.Span
+	// LLMCall is a call to an LLM. Its usage is added to the trace and the session.
+	LLMCall *api.LLMCall
+	// Assertion is an assertion about the trace.
+	Assertion *v1alpha1.Assertion
+	// LogEvent is an event sent by the frontend.
+	LogEvent *v1alpha1.LogEvent
+	// FullContext is the context sent by the frontend at the start of a stream.
+	FullContext *v1alpha1.FullContext
+}
+
+// Type returns the name of the payload of the event. It is used as the label of metrics.
+func (e *Event) Type() string {
+	switch {
+	case e.GenerateRequest != nil:
+		return "generateRequest"
+	case e.GenerateResponse != nil:
+		return "generateResponse"
+	case e.GenerateError != "":
+		return "generateError"
+	case e.Span != nil:
+		return "span"
+	case e.LLMCall != nil:
+		return "llmCall"
+	case e.Assertion != nil:
+		return "assertion"
+	case e.LogEvent != nil:
+		return "logEvent"
+	case e.FullContext != nil:
+		return "fullContext"
+	default:
+		return "unknown"
+	}
+}
+
+// Handler processes events. Handlers are invoked one event at a time in the order the events were published so
+// they shouldn't block for long.
+type Handler func(ctx context.Context, e *Event)
+
+// Bus delivers published events to the subscribed handlers. Events are delivered asynchronously so publishing
+// doesn't add latency to requests. If the handlers fall behind and the buffer fills up events are dropped rather
+// than blocking the request that published them; the traces and sessions they belong to will be incomplete.
+type Bus struct {
+	events   chan *Event
+	handlers []Handler
+	// mu guards closed, running and handlers. Publish holds a read lock while sending so Shutdown can't close the channel
+	// during a send.
+	mu      sync.RWMutex
+	closed  bool
+	running bool
+	done    chan struct{}
+}
+
+// NewBus creates a bus that buffers up to size events. Publish drops events when the buffer is full.
+func NewBus(size int) *Bus {
+	return &Bus{
+		events: make(chan *Event, size),
+		done:   make(chan struct{}),
+	}
+}
+
+// Subscribe adds a handler for all events. Handlers must be subscribed before calling Run.
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// Run starts delivering events to the handlers. It is non blocking.
+func (b *Bus) Run(ctx context.Context) {
+	// N.B. The handlers are copied so delivering an event doesn't need the lock.
+	b.mu.Lock()
+	b.running = true
+	handlers := append([]Handler{}, b.handlers...)
+	b.mu.Unlock()
+	go func() {
+		defer close(b.done)
+		for e := range b.events {
+			for _, h := range handlers {
+				h(ctx, e)
+			}
+		}
+	}()
+}
+
+// Publish adds the event to the bus. It never blocks; if the buffer is full the event is dropped and counted in
+// trace_events_dropped_total. Events published after Shutdown are dropped.
+func (b *Bus) Publish(e *Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+	select {
+	case b.events <- e:
+		publishedCounter.WithLabelValues(e.Type()).Inc()
+	default:
+		droppedCounter.WithLabelValues(e.Type()).Inc()
+	}
+}
+
+// Shutdown stops accepting events and waits until the events already published have been delivered.
+func (b *Bus) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.events)
+	}
+	running := b.running
+	b.mu.Unlock()
+
+	if !running {
+		return nil
+	}
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,114 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+)
+
+func Test_Bus(t *testing.T) {
+	bus := NewBus(10)
+	received := make([]*Event, 0, 3)
+	bus.Subscribe(func(ctx context.Context, e *Event) {
+		received = append(received, e)
+	})
+	bus.Run(context.Background())
+
+	// Publishing without a bus in the context is a no-op.
+	Publish(context.Background(), &Event{Assertion: &v1alpha1.Assertion{}})
+
+	ctx := WithBus(context.Background(), bus)
+	ctx = WithContextID(ctx, "context1")
+	ctx = WithTrace(ctx, "trace1", true)
+	Publish(ctx, &Event{GenerateRequest: &v1alpha1.GenerateRequest{}})
+	Publish(WithTrace(ctx, "", false), &Event{LLMCall: &api.LLMCall{}})
+	Publish(ctx, &Event{ContextID: "context2", LogEvent: &v1alpha1.LogEvent{}})
+
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Failed to shutdown bus: %v", err)
+	}
+	// Events published after shutdown are dropped.
+	Publish(ctx, &Event{Assertion: &v1alpha1.Assertion{}})
+
+	type expected struct {
+		eventType string
+		traceID   string
+		contextID string
+		evalMode  bool
+	}
+	expectedEvents := []expected{
+		{eventType: "generateRequest", traceID: "trace1", contextID: "context1", evalMode: true},
+		{eventType: "llmCall", traceID: "", contextID: "context1", evalMode: false},
+		{eventType: "logEvent", traceID: "trace1", contextID: "context2", evalMode: true},
+	}
+	if len(received) != len(expectedEvents) {
+		t.Fatalf("Expected %d events; got %d", len(expectedEvents), len(received))
+	}
+	for i, e := range expectedEvents {
+		actual := received[i]
+		if actual.Type() != e.eventType {
+			t.Errorf("Event %d: expected type %s; got %s", i, e.eventType, actual.Type())
+		}
+		if actual.TraceID != e.traceID {
+			t.Errorf("Event %d: expected traceID %q; got %q", i, e.traceID, actual.TraceID)
+		}
+		if actual.ContextID != e.contextID {
+			t.Errorf("Event %d: expected contextID %q; got %q", i, e.contextID, actual.ContextID)
+		}
+		if actual.EvalMode != e.evalMode {
+			t.Errorf("Event %d: expected evalMode %v; got %v", i, e.evalMode, actual.EvalMode)
+		}
+		if actual.Time.IsZero() {
+			t.Errorf("Event %d: expected time to be set", i)
+		}
+	}
+}
+
+func Test_BusDropsEventsWhenFull(t *testing.T) {
+	bus := NewBus(1)
+	block := make(chan struct{})
+	delivered := make(chan *Event, 3)
+	bus.Subscribe(func(ctx context.Context, e *Event) {
+		<-block
+		delivered <- e
+	})
+	bus.Run(context.Background())
+
+	ctx := WithBus(context.Background(), bus)
+	published := make(chan struct{})
+	go func() {
+		// The handler is blocked so the first event is being delivered, the second is buffered and the third
+		// doesn't fit.
+		for i := 0; i < 3; i++ {
+			Publish(ctx, &Event{Assertion: &v1alpha1.Assertion{}})
+			if i == 0 {
+				waitForEmpty(bus)
+			}
+		}
+		close(published)
+	}()
+
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Publish blocked when the buffer was full")
+	}
+	close(block)
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Failed to shutdown bus: %v", err)
+	}
+	// The third event was dropped.
+	if len(delivered) != 2 {
+		t.Errorf("Expected 2 events to be delivered; got %d", len(delivered))
+	}
+}
+
+// waitForEmpty waits until the delivery loop has taken the buffered events.
+func waitForEmpty(bus *Bus) {
+	for len(bus.events) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -254,4 +254,8 @@ type Instruction struct {
 
 	// Piped should be set to true if the output of this command should be piped to the next instruction.
 	Piped bool
+
+	// Stdin is passed to the command on standard input. It is used to pass the contents of a cell to an interpreter.
+	// It is ignored if the output of the previous instruction is piped to the command.
+	Stdin string
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/docs"
 
 	"go.uber.org/zap"
 
@@ -50,14 +51,10 @@ func (e *Executor) Execute(ctx context.Context, req *v1alpha1.ExecuteRequest) (*
 	if req.GetBlock() == nil {
 		return nil, status.Errorf(codes.InvalidArgument, "Block is required")
 	}
-	instructions, err := e.p.Parse(req.GetBlock().GetContents())
+	instructions, err := e.instructions(req.GetBlock())
 	if err != nil {
-		log.Error(err, "Failed to parse instructions")
-		return nil, status.Errorf(codes.InvalidArgument, "Failed to parse instructions: %v", err)
-	}
-
-	if len(instructions) == 0 {
-		return nil, status.Errorf(codes.InvalidArgument, "No instructions to execute")
+		log.Error(err, "Failed to get the instructions for the block", "language", req.GetBlock().GetLanguage())
+		return nil, err
 	}
 
 	result := e.executeInstructions(ctx, instructions)
@@ -67,6 +64,36 @@ func (e *Executor) Execute(ctx context.Context, req *v1alpha1.ExecuteRequest) (*
 	return resp, nil
 }
 
+// instructions returns the instructions to execute a block. Shell blocks are parsed by the bashish parser. Blocks
+// in other languages are passed on stdin to the interpreter configured for the language.
+func (e *Executor) instructions(block *v1alpha1.Block) ([]Instruction, error) {
+	lang := docs.NormalizeLanguage(block.GetLanguage())
+	if docs.IsShellLanguage(lang) {
+		instructions, err := e.p.Parse(block.GetContents())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Failed to parse instructions: %v", err)
+		}
+		if len(instructions) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "No instructions to execute")
+		}
+		return instructions, nil
+	}
+
+	interpreter, ok := e.config.GetInterpreter(lang)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "No interpreter is configured for language %s; set executor.interpreters.%s in the configuration", lang, lang)
+	}
+	if strings.TrimSpace(block.GetContents()) == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "No instructions to execute")
+	}
+	return []Instruction{
+		{
+			Command: cmd.NewCmd(interpreter[0], interpreter[1:]...),
+			Stdin:   block.GetContents(),
+		},
+	}, nil
+}
+
 type result struct {
 	exitCode int
 	stdOut   string
@@ -93,7 +120,9 @@ func (e *Executor) executeInstructions(ctx context.Context, instructions []Instr
 	for _, i := range instructions {
 		var statusChan <-chan cmd.Status
 		// Start the command in non blocking mode
-		if in == nil {
+		if in == nil && i.Stdin != "" {
+			statusChan = i.Command.StartWithStdin(strings.NewReader(i.Stdin))
+		} else if in == nil {
 			statusChan = i.Command.Start()
 		} else {
 			statusChan = i.Command.StartWithStdin(in)
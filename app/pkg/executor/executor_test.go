@@ -12,6 +12,9 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/jlewi/foyle/app/pkg/testutil"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func Test_Executor(t *testing.T) {
@@ -64,3 +67,65 @@ func Test_Executor(t *testing.T) {
 		})
 	}
 }
+
+func Test_RunAllowed(t *testing.T) {
+	e, err := NewExecutor(config.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+	policy := NewCommandPolicy([]string{"echo", "kubectl get"})
+
+	result, err := e.RunAllowed(context.Background(), "echo hello", policy)
+	if err != nil {
+		t.Fatalf("Failed to run allowed command: %v", err)
+	}
+	if d := cmp.Diff(&CommandResult{ExitCode: 0, StdOut: "hello"}, result); d != "" {
+		t.Errorf("Unexpected diff:\n%s", d)
+	}
+
+	for _, command := range []string{"rm -rf /tmp/foyle", "echo hello | rm -rf /tmp/foyle", "kubectl delete pods", "kubectl getx"} {
+		t.Run(command, func(t *testing.T) {
+			_, err := e.RunAllowed(context.Background(), command, policy)
+			notAllowed := &NotAllowedError{}
+			if !errors.As(err, &notAllowed) {
+				t.Errorf("Expected NotAllowedError; got %v", err)
+			}
+		})
+	}
+}
+
+func Test_ExecuteInterpreter(t *testing.T) {
+	cfg := config.Config{
+		Executor: &config.ExecutorConfig{
+			// Use cat as the interpreter so the test doesn't depend on python being installed.
+			Interpreters: map[string][]string{"python": {"cat"}},
+		},
+	}
+	e, err := NewExecutor(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	resp, err := e.Execute(context.Background(), &v1alpha1.ExecuteRequest{Block: &v1alpha1.Block{Language: "py", Contents: "print('hello')"}})
+	if err != nil {
+		t.Fatalf("Failed to execute: %v", err)
+	}
+	expected := &v1alpha1.ExecuteResponse{
+		Outputs: []*v1alpha1.BlockOutput{
+			{
+				Items: []*v1alpha1.BlockOutputItem{{Mime: MimePlainText, TextData: "exitCode: 0"}},
+			},
+			{
+				Items: []*v1alpha1.BlockOutputItem{{Mime: MimePlainText, TextData: "stdout:\nprint('hello')"}},
+			},
+		},
+	}
+	if d := cmp.Diff(expected, resp, testutil.BlockComparer, cmpopts.IgnoreUnexported(v1alpha1.ExecuteResponse{})); d != "" {
+		t.Errorf("Unexpected response (-want +got):\n%v", d)
+	}
+
+	_, err = e.Execute(context.Background(), &v1alpha1.ExecuteRequest{Block: &v1alpha1.Block{Language: "sql", Contents: "select 1;"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a language without an interpreter; got %v", err)
+	}
+}
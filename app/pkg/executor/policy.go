@@ -0,0 +1,452 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-cmd/cmd"
+	"github.com/pkg/errors"
+)
+
+// CommandRule allows a command to be run with an explicit set of flags and positional arguments. Anything that
+// isn't explicitly allowed is rejected so that flags which modify state or write files (e.g. git diff --output) can't
+// be used.
+type CommandRule struct {
+	// Command is the binary followed by its subcommands; e.g. ["kubectl", "get"].
+	Command []string
+	// Flags are the allowed flags. Flags that take a value end in "="; e.g. "--namespace=". Values can be passed
+	// as "--namespace=foo", "--namespace foo" or for single letter flags "-nfoo".
+	Flags []string
+	// Values validates the values of flags that take one. It is keyed by the flag without the "="; flags without
+	// a validator accept any value.
+	Values map[string]func(value string) bool
+	// Args validates the positional arguments. If nil no positional arguments are allowed.
+	Args func(args []string) bool
+}
+
+// DefaultReadOnlyCommands are the commands allowed when the configuration doesn't specify which commands are
+// allowed. Commands that print the contents of arbitrary files (e.g. cat, grep) aren't included because the output is
+// sent to the model; nor are resources such as secrets that hold credentials.
+var DefaultReadOnlyCommands = []*CommandRule{
+	{
+		Command: []string{"kubectl", "get"},
+		Flags:   append([]string{"-o=", "--output=", "--show-labels", "--sort-by=", "--field-selector="}, kubectlListFlags...),
+		Values:  map[string]func(string) bool{"-o": isKubectlOutputFormat, "--output": isKubectlOutputFormat},
+		Args:    isKubectlResourceList,
+	},
+	{
+		Command: []string{"kubectl", "describe"},
+		Flags:   kubectlListFlags,
+		Args:    isKubectlResourceList,
+	},
+	{
+		Command: []string{"kubectl", "logs"},
+		Flags: append([]string{"-c=", "--container=", "--tail=", "--since=", "-p", "--previous", "--all-containers",
+			"--timestamps"}, kubectlListFlags...),
+		Args: anyArgs,
+	},
+	{
+		Command: []string{"kubectl", "top", "pod"},
+		Flags:   append([]string{"--containers"}, kubectlListFlags...),
+		Args:    anyArgs,
+	},
+	{
+		Command: []string{"kubectl", "top", "node"},
+		Flags:   []string{"-l=", "--selector=", "--context="},
+		Args:    anyArgs,
+	},
+	{
+		Command: []string{"kubectl", "config", "current-context"},
+	},
+	{
+		Command: []string{"kubectl", "config", "get-contexts"},
+	},
+	{
+		Command: []string{"gcloud", "config", "list"},
+		Flags:   []string{"--format="},
+	},
+	{
+		Command: []string{"gcloud", "auth", "list"},
+		Flags:   []string{"--format="},
+	},
+	{
+		Command: []string{"git", "status"},
+		Flags:   []string{"-s", "--short", "-b", "--branch", "--porcelain"},
+		Args:    isGitRepoArgs,
+	},
+	{
+		Command: []string{"git", "log"},
+		Flags: []string{"--oneline", "-n=", "--max-count=", "--since=", "--until=", "--author=", "--grep=", "--graph",
+			"--stat", "--name-only", "--name-status", "--decorate", "--all", "-p", "--patch", "--format=", "--pretty="},
+		Args: isGitRepoArgs,
+	},
+	{
+		// git diff compares two arbitrary files, even ones outside the repository, when it is given two paths that
+		// aren't both in the worktree or is run outside a repository. Only a single revision or range is allowed so
+		// it can't be used to read files.
+		Command: []string{"git", "diff"},
+		Flags:   []string{"--stat", "--name-only", "--name-status", "--cached", "--staged"},
+		Args: func(args []string) bool {
+			return len(args) <= 1 && isGitRepoArgs(args)
+		},
+	},
+	{
+		// Positional arguments would create a branch so only listing is allowed.
+		Command: []string{"git", "branch"},
+		Flags:   []string{"-a", "--all", "-r", "--remotes", "-v", "--verbose", "--show-current"},
+	},
+	{
+		Command: []string{"ls"},
+		Flags:   []string{"-l", "-a", "-A", "-h", "-1", "-t", "-r", "-R", "-S", "-d"},
+		Args:    anyArgs,
+	},
+	{
+		Command: []string{"wc"},
+		Flags:   []string{"-l", "-w", "-c", "-m"},
+		Args:    anyArgs,
+	},
+	{
+		Command: []string{"pwd"},
+	},
+	{
+		Command: []string{"whoami"},
+	},
+	{
+		// Positional arguments other than a format would set the date.
+		Command: []string{"date"},
+		Flags:   []string{"-u", "--utc"},
+		Args: func(args []string) bool {
+			return len(args) == 0 || (len(args) == 1 && strings.HasPrefix(args[0], "+"))
+		},
+	},
+	{
+		Command: []string{"which"},
+		Args:    anyArgs,
+	},
+	{
+		Command: []string{"uname"},
+		Flags:   []string{"-a", "-s", "-r", "-m"},
+	},
+}
+
+// kubectlListFlags are the flags used to select the resources kubectl reads.
+var kubectlListFlags = []string{"-n=", "--namespace=", "-A", "--all-namespaces", "-l=", "--selector=", "--context="}
+
+// kubectlResources are the resource types kubectl is allowed to read by default. Secrets and configmaps aren't
+// included because they can contain credentials.
+var kubectlResources = newSet(
+	"all", "pod", "pods", "po", "deployment", "deployments", "deploy", "replicaset", "replicasets", "rs",
+	"statefulset", "statefulsets", "sts", "daemonset", "daemonsets", "ds", "job", "jobs", "cronjob", "cronjobs",
+	"cj", "service", "services", "svc", "ingress", "ingresses", "ing", "node", "nodes", "no", "namespace",
+	"namespaces", "ns", "event", "events", "ev", "persistentvolumeclaim", "persistentvolumeclaims", "pvc",
+	"persistentvolume", "persistentvolumes", "pv", "endpoints", "ep", "horizontalpodautoscaler",
+	"horizontalpodautoscalers", "hpa",
+)
+
+func newSet(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+func anyArgs(args []string) bool {
+	return true
+}
+
+// isGitRepoArgs returns true if none of the arguments can refer to a file outside the repository; i.e. they aren't
+// absolute or relative to the home directory and don't contain a ".." path element. Ranges such as main..HEAD are
+// allowed.
+func isGitRepoArgs(args []string) bool {
+	for _, a := range args {
+		if strings.HasPrefix(a, "/") || strings.HasPrefix(a, "~") {
+			return false
+		}
+		for _, p := range strings.Split(a, "/") {
+			if p == ".." {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isKubectlResourceList returns true if the arguments only refer to allowed resource types. The first argument is
+// a type or comma separated list of types unless it is in TYPE/NAME form; the remaining arguments are names or
+// TYPE/NAME pairs.
+func isKubectlResourceList(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	for i, a := range args {
+		if strings.Contains(a, "/") {
+			if !isKubectlResource(strings.SplitN(a, "/", 2)[0]) {
+				return false
+			}
+			continue
+		}
+		if i > 0 {
+			continue
+		}
+		for _, t := range strings.Split(a, ",") {
+			if !isKubectlResource(t) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isKubectlResource returns true if the resource type is allowed. Types can be qualified by their group;
+// e.g. deployments.apps.
+func isKubectlResource(t string) bool {
+	return kubectlResources[strings.ToLower(strings.SplitN(t, ".", 2)[0])]
+}
+
+// isKubectlOutputFormat returns true if the output format only changes how the output is printed; formats that read
+// templates from files aren't allowed.
+func isKubectlOutputFormat(f string) bool {
+	switch f {
+	case "wide", "yaml", "json", "name":
+		return true
+	}
+	return strings.HasPrefix(f, "jsonpath=") || strings.HasPrefix(f, "custom-columns=")
+}
+
+// fileWritingFlags are flags that write files. They are rejected even when a configured prefix allows the command.
+var fileWritingFlags = map[string][]string{
+	"git":     {"--output"},
+	"kubectl": {"--log-file", "--log-dir"},
+}
+
+// NotAllowedError is returned when a command isn't allowed by the policy.
+type NotAllowedError struct {
+	Command string
+	Reason  string
+}
+
+func (e *NotAllowedError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("command %q is not an allowed read-only command; %s", e.Command, e.Reason)
+	}
+	return fmt.Sprintf("command %q is not an allowed read-only command", e.Command)
+}
+
+// CommandPolicy decides which commands can be run.
+type CommandPolicy struct {
+	rules    []*CommandRule
+	prefixes [][]string
+}
+
+// NewCommandPolicy creates a policy that allows commands starting with one of the prefixes. Prefixes are configured
+// by the operator so any flags and arguments are allowed except for known flags that write files.
+// If prefixes is empty DefaultReadOnlyCommands are used.
+func NewCommandPolicy(prefixes []string) *CommandPolicy {
+	if len(prefixes) == 0 {
+		return &CommandPolicy{
+			rules: DefaultReadOnlyCommands,
+		}
+	}
+	allowed := make([][]string, 0, len(prefixes))
+	for _, p := range prefixes {
+		fields := strings.Fields(p)
+		if len(fields) == 0 {
+			continue
+		}
+		allowed = append(allowed, fields)
+	}
+	return &CommandPolicy{
+		prefixes: allowed,
+	}
+}
+
+// Commands returns the commands the policy allows; e.g. to tell the model what it can run.
+func (p *CommandPolicy) Commands() []string {
+	commands := make([]string, 0, len(p.rules)+len(p.prefixes))
+	for _, r := range p.rules {
+		commands = append(commands, strings.Join(r.Command, " "))
+	}
+	for _, prefix := range p.prefixes {
+		commands = append(commands, strings.Join(prefix, " "))
+	}
+	return commands
+}
+
+// Check returns a NotAllowedError if any of the instructions isn't allowed.
+func (p *CommandPolicy) Check(instructions []Instruction) error {
+	for _, i := range instructions {
+		args := append([]string{i.Command.Name}, i.Command.Args...)
+		if reason, ok := p.isAllowed(args); !ok {
+			return &NotAllowedError{Command: strings.Join(args, " "), Reason: reason}
+		}
+	}
+	return nil
+}
+
+// isAllowed returns true if the command is allowed. If it isn't the reason is returned if there is one.
+func (p *CommandPolicy) isAllowed(args []string) (string, bool) {
+	for _, r := range p.rules {
+		if hasPrefix(args, r.Command) {
+			return r.check(args[len(r.Command):])
+		}
+	}
+	for _, prefix := range p.prefixes {
+		if !hasPrefix(args, prefix) {
+			continue
+		}
+		for _, a := range args[1:] {
+			for _, f := range fileWritingFlags[args[0]] {
+				if a == f || strings.HasPrefix(a, f+"=") {
+					return fmt.Sprintf("flag %s writes files", f), false
+				}
+			}
+		}
+		return "", true
+	}
+	return "", false
+}
+
+func hasPrefix(args []string, prefix []string) bool {
+	if len(args) < len(prefix) {
+		return false
+	}
+	for i, f := range prefix {
+		if args[i] != f {
+			return false
+		}
+	}
+	return true
+}
+
+// check checks the arguments following the command against the rule.
+func (r *CommandRule) check(args []string) (string, bool) {
+	flags := make(map[string]bool)
+	for _, f := range r.Flags {
+		name := strings.TrimSuffix(f, "=")
+		flags[name] = strings.HasSuffix(f, "=")
+	}
+	checkValue := func(flag string, value string) (string, bool) {
+		if valid, ok := r.Values[flag]; ok && !valid(value) {
+			return fmt.Sprintf("value %q of flag %s isn't allowed", value, flag), false
+		}
+		return "", true
+	}
+
+	positional := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--":
+			positional = append(positional, args[i+1:]...)
+			i = len(args)
+		case strings.HasPrefix(a, "--"):
+			name, value, hasValue := strings.Cut(a, "=")
+			takesValue, ok := flags[name]
+			if !ok {
+				return fmt.Sprintf("flag %s isn't allowed", name), false
+			}
+			if !takesValue {
+				if hasValue {
+					return fmt.Sprintf("flag %s doesn't take a value", name), false
+				}
+				continue
+			}
+			if !hasValue {
+				if i+1 >= len(args) {
+					return fmt.Sprintf("flag %s requires a value", name), false
+				}
+				i++
+				value = args[i]
+			}
+			if reason, ok := checkValue(name, value); !ok {
+				return reason, false
+			}
+		case strings.HasPrefix(a, "-") && len(a) > 1:
+			// Single letter flags can be combined; e.g. -la. The first flag that takes a value consumes the rest of
+			// the argument or the next argument.
+			letters := a[1:]
+			for j := 0; j < len(letters); j++ {
+				name := "-" + letters[j:j+1]
+				takesValue, ok := flags[name]
+				if !ok {
+					return fmt.Sprintf("flag %s isn't allowed", name), false
+				}
+				if !takesValue {
+					continue
+				}
+				value := strings.TrimPrefix(letters[j+1:], "=")
+				if j+1 >= len(letters) {
+					if i+1 >= len(args) {
+						return fmt.Sprintf("flag %s requires a value", name), false
+					}
+					i++
+					value = args[i]
+				}
+				if reason, ok := checkValue(name, value); !ok {
+					return reason, false
+				}
+				break
+			}
+		default:
+			positional = append(positional, a)
+		}
+	}
+
+	if len(positional) == 0 {
+		return "", true
+	}
+	if r.Args == nil {
+		return "arguments aren't allowed", false
+	}
+	if !r.Args(positional) {
+		return fmt.Sprintf("arguments %s aren't allowed", strings.Join(positional, " ")), false
+	}
+	return "", true
+}
+
+// CommandResult is the result of running a command.
+type CommandResult struct {
+	ExitCode int
+	StdOut   string
+	StdErr   string
+}
+
+// RunAllowed parses and runs the command if every instruction in it is allowed by the policy.
+// It returns a NotAllowedError without running anything if the policy rejects the command.
+// The command is killed if the context's deadline is exceeded.
+func (e *Executor) RunAllowed(ctx context.Context, command string, policy *CommandPolicy) (*CommandResult, error) {
+	if policy == nil {
+		return nil, errors.New("A command policy is required")
+	}
+	instructions, err := e.p.Parse(command)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse command %q", command)
+	}
+	if len(instructions) == 0 {
+		return nil, errors.Errorf("No instructions to execute in %q", command)
+	}
+	if err := policy.Check(instructions); err != nil {
+		return nil, err
+	}
+
+	r := e.executeInstructions(ctx, instructions)
+	for _, i := range instructions {
+		stopIfRunning(i.Command)
+	}
+	return &CommandResult{
+		ExitCode: r.exitCode,
+		StdOut:   r.stdOut,
+		StdErr:   r.stdErr,
+	}, nil
+}
+
+// stopIfRunning stops the command if it is still running; e.g. because it timed out.
+func stopIfRunning(c *cmd.Cmd) {
+	s := c.Status()
+	if s.StartTs > 0 && !s.Complete {
+		// Stop returns an error if the command isn't running; that's fine.
+		_ = c.Stop()
+	}
+}
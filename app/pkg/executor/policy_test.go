@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func Test_DefaultCommandPolicy(t *testing.T) {
+	parser, err := NewBashishParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	policy := NewCommandPolicy(nil)
+
+	type testCase struct {
+		command string
+		allowed bool
+	}
+
+	cases := []testCase{
+		{command: "kubectl get pods -n foyle", allowed: true},
+		{command: "kubectl get pods -n=foyle -o yaml", allowed: true},
+		{command: "kubectl get deployments.apps,svc -A -oyaml", allowed: true},
+		{command: "kubectl get pod/foyle-0 -o jsonpath={.status.phase}", allowed: true},
+		{command: "kubectl describe pods foyle-0 --namespace foyle", allowed: true},
+		{command: "kubectl logs foyle-0 -c foyle --tail=100", allowed: true},
+		{command: "kubectl get pods -o json | wc -l", allowed: true},
+		{command: "git status -sb", allowed: true},
+		{command: "git log --oneline -n 5 -- app", allowed: true},
+		{command: "git diff --stat HEAD~1", allowed: true},
+		{command: "git diff main..HEAD", allowed: true},
+		{command: "git branch -a", allowed: true},
+		{command: "ls -la /tmp", allowed: true},
+		{command: "date +%Y", allowed: true},
+
+		// Commands that modify refs.
+		{command: "git branch -D main", allowed: false},
+		{command: "git branch -m main other", allowed: false},
+		{command: "git branch newbranch", allowed: false},
+		// Flags that write files.
+		{command: "git diff --output=/tmp/out", allowed: false},
+		{command: "git diff --output /tmp/out", allowed: false},
+		{command: "git log --output=/tmp/out", allowed: false},
+		// Commands that print arbitrary files. git diff compares files outside the repository as if --no-index was
+		// passed.
+		{command: "git diff /etc/hostname /dev/null", allowed: false},
+		{command: "git diff .bashrc .profile", allowed: false},
+		{command: "git diff -- /etc/hostname", allowed: false},
+		{command: "git diff ../other/secrets", allowed: false},
+		{command: "git log -p -- ~/.ssh/id_rsa", allowed: false},
+		{command: "cat /root/.ssh/id_rsa", allowed: false},
+		{command: "head /root/.ssh/id_rsa", allowed: false},
+		{command: "tail /root/.ssh/id_rsa", allowed: false},
+		{command: "grep -r BEGIN /root/.ssh/id_rsa", allowed: false},
+		{command: "kubectl get pods | cat /root/.ssh/id_rsa", allowed: false},
+		// Secrets.
+		{command: "kubectl get secret -o yaml", allowed: false},
+		{command: "kubectl get secrets", allowed: false},
+		{command: "kubectl get pods,secrets", allowed: false},
+		{command: "kubectl get secret/foyle -o yaml", allowed: false},
+		{command: "kubectl get pods pod/foyle secret/foyle", allowed: false},
+		{command: "kubectl describe secret foyle", allowed: false},
+		{command: "kubectl get configmaps", allowed: false},
+		// Output formats that read files.
+		{command: "kubectl get pods -o go-template-file=/tmp/template", allowed: false},
+		// Flags that aren't explicitly allowed.
+		{command: "kubectl get pods --kubeconfig=/tmp/config", allowed: false},
+		{command: "kubectl logs -f foyle-0", allowed: false},
+		{command: "date -s 2024-01-01", allowed: false},
+		{command: "date 010100002024", allowed: false},
+		{command: "rm -rf /tmp/foyle", allowed: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.command, func(t *testing.T) {
+			instructions, err := parser.Parse(c.command)
+			if err != nil {
+				t.Fatalf("Failed to parse command: %v", err)
+			}
+			err = policy.Check(instructions)
+			if c.allowed && err != nil {
+				t.Errorf("Expected command to be allowed; got %v", err)
+			}
+			if !c.allowed {
+				notAllowed := &NotAllowedError{}
+				if !errors.As(err, &notAllowed) {
+					t.Errorf("Expected NotAllowedError; got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func Test_PrefixCommandPolicy(t *testing.T) {
+	parser, err := NewBashishParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	policy := NewCommandPolicy([]string{"git diff", "kubectl get"})
+
+	cases := map[string]bool{
+		"git diff --color-words":         true,
+		"kubectl get pods --watch-only":  true,
+		"git diff --output=/tmp/out":     false,
+		"git diff --output /tmp/out":     false,
+		"kubectl get pods --log-file=/x": false,
+		"git log":                        false,
+	}
+	for command, allowed := range cases {
+		t.Run(command, func(t *testing.T) {
+			instructions, err := parser.Parse(command)
+			if err != nil {
+				t.Fatalf("Failed to parse command: %v", err)
+			}
+			if err := policy.Check(instructions); (err == nil) != allowed {
+				t.Errorf("Expected allowed=%v; got %v", allowed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/zapr"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/monogo/files"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// DefaultBaseURL is the base URL of the Gemini API.
+	DefaultBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// Client is a client for the Gemini REST API. The same client works with Vertex AI; only the URLs and
+// authentication differ.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	vertex     bool
+}
+
+// NewClient helper function to create a new Gemini client from a config
+func NewClient(cfg config.Config) (*Client, error) {
+	log := zapr.NewLogger(zap.L())
+	if cfg.Gemini == nil {
+		return nil, errors.New("Gemini config is nil; You must configure Gemini to create a Gemini client")
+	}
+
+	// Handle retryable errors
+	// To handle retryable errors we use hashi corp's retryable client. This client will automatically retry on
+	// retryable errors like 429; rate limiting
+	retryClient := retryablehttp.NewClient()
+	httpClient := retryClient.StandardClient()
+
+	if cfg.UseHoneycomb() {
+		httpClient.Transport = otelhttp.NewTransport(httpClient.Transport)
+	}
+
+	if v := cfg.Gemini.Vertex; v != nil {
+		if v.Project == "" || v.Location == "" {
+			return nil, errors.New("Gemini.Vertex.Project and Gemini.Vertex.Location are required when using Vertex AI")
+		}
+		creds, err := google.FindDefaultCredentials(context.Background(), cloudPlatformScope)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to find application default credentials for Vertex AI")
+		}
+		httpClient.Transport = &oauth2.Transport{
+			Source: creds.TokenSource,
+			Base:   httpClient.Transport,
+		}
+		baseURL := cfg.Gemini.BaseURL
+		if baseURL == "" {
+			baseURL = fmt.Sprintf("https://%s-aiplatform.googleapis.com/v1", v.Location)
+		}
+		baseURL = fmt.Sprintf("%s/projects/%s/locations/%s/publishers/google", strings.TrimSuffix(baseURL, "/"), v.Project, v.Location)
+		log.Info("Configuring Gemini client using Vertex AI", "baseURL", baseURL)
+		return newClient(httpClient, baseURL, "", true), nil
+	}
+
+	log.Info("Configuring Gemini client")
+	if cfg.Gemini.APIKeyFile == "" {
+		return nil, errors.New("Gemini APIKeyFile is required when using the Gemini API")
+	}
+	apiKey, err := readAPIKey(cfg.Gemini.APIKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	baseURL := DefaultBaseURL
+	if cfg.Gemini.BaseURL != "" {
+		log.Info("Using custom Gemini base URL", "baseURL", cfg.Gemini.BaseURL)
+		baseURL = cfg.Gemini.BaseURL
+	}
+	return newClient(httpClient, baseURL, apiKey, false), nil
+}
+
+func newClient(httpClient *http.Client, baseURL string, apiKey string, vertex bool) *Client {
+	return &Client{
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		vertex:     vertex,
+	}
+}
+
+// GenerateContent generates a response from the model.
+func (c *Client) GenerateContent(ctx context.Context, model string, request GenerateContentRequest) (*GenerateContentResponse, error) {
+	request.Model = ""
+	resp := &GenerateContentResponse{}
+	if err := c.post(ctx, c.modelURL(model, "generateContent"), request, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// EmbedContent computes the embedding of the text.
+func (c *Client) EmbedContent(ctx context.Context, model string, text string) ([]float32, error) {
+	if c.vertex {
+		// Vertex AI serves the embedding models through the predict method.
+		request := map[string]any{
+			"instances": []map[string]string{{"content": text}},
+		}
+		resp := &struct {
+			Predictions []struct {
+				Embeddings struct {
+					Values []float32 `json:"values"`
+				} `json:"embeddings"`
+			} `json:"predictions"`
+		}{}
+		if err := c.post(ctx, c.modelURL(model, "predict"), request, resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Predictions) != 1 {
+			return nil, errors.Errorf("Expected exactly 1 prediction but got %d", len(resp.Predictions))
+		}
+		return resp.Predictions[0].Embeddings.Values, nil
+	}
+
+	request := struct {
+		Content Content `json:"content"`
+	}{
+		Content: Content{Parts: []Part{{Text: text}}},
+	}
+	resp := &struct {
+		Embedding struct {
+			Values []float32 `json:"values"`
+		} `json:"embedding"`
+	}{}
+	if err := c.post(ctx, c.modelURL(model, "embedContent"), request, resp); err != nil {
+		return nil, err
+	}
+	return resp.Embedding.Values, nil
+}
+
+func (c *Client) modelURL(model string, method string) string {
+	return fmt.Sprintf("%s/models/%s:%s", c.baseURL, model, method)
+}
+
+// post sends the request and decodes the response. Errors returned by the API are returned as *APIError.
+func (c *Client) post(ctx context.Context, url string, request any, response any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to marshal request")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("x-goog-api-key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to send request to %s", url)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read response body")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &struct {
+			Error *APIError `json:"error"`
+		}{}
+		if err := json.Unmarshal(b, apiErr); err == nil && apiErr.Error != nil {
+			return apiErr.Error
+		}
+		return &APIError{Code: resp.StatusCode, Status: resp.Status, Message: string(b)}
+	}
+
+	if err := json.Unmarshal(b, response); err != nil {
+		return errors.Wrapf(err, "Failed to unmarshal response")
+	}
+	return nil
+}
+
+func readAPIKey(apiKeyFile string) (string, error) {
+	if apiKeyFile == "" {
+		return "", errors.New("APIKeyFile is required")
+	}
+	apiKeyBytes, err := files.Read(apiKeyFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not read APIKeyFile: %v", apiKeyFile)
+	}
+	// make sure there is no leading or trailing whitespace
+	apiKey := strings.TrimSpace(string(apiKeyBytes))
+	return apiKey, nil
+}
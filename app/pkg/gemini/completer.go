@@ -0,0 +1,166 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/docs"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	pkgerrors "github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	temperature = 0.9
+)
+
+func NewCompleter(cfg config.Config, client *Client) (*Completer, error) {
+	if client == nil {
+		return nil, pkgerrors.New("Gemini client is required")
+	}
+	return &Completer{
+		client: client,
+		config: cfg,
+	}, nil
+}
+
+// Completer is a wrapper around Gemini that implements the Completer interface.
+type Completer struct {
+	client *Client
+	config config.Config
+}
+
+// Complete returns a ContextLengthExceededError if the context is too long
+func (c *Completer) Complete(ctx context.Context, systemPrompt string, message string) ([]*v1alpha1.Block, error) {
+	candidates, err := c.CompleteN(ctx, systemPrompt, message, 1)
+	if err != nil {
+		return nil, err
+	}
+	allBlocks := make([]*v1alpha1.Block, 0, 10)
+	for _, blocks := range candidates {
+		allBlocks = append(allBlocks, blocks...)
+	}
+	return allBlocks, nil
+}
+
+// CompleteN generates n candidate completions in a single request using candidateCount.
+func (c *Completer) CompleteN(ctx context.Context, systemPrompt string, message string, n int) ([][]*v1alpha1.Block, error) {
+	tp := tracer()
+	ctx, span := tp.Start(ctx, "Complete", trace.WithAttributes(attribute.String("llm.model", c.config.GetModel()), attribute.String("llm.provider", string(api.ModelProviderGemini))))
+	defer span.End()
+
+	t := float32(temperature)
+	request := GenerateContentRequest{
+		Model: c.config.GetModel(),
+		Contents: []Content{
+			{
+				Role:  RoleUser,
+				Parts: userParts(ctx, message),
+			},
+		},
+		SystemInstruction: &Content{
+			Parts: []Part{{Text: systemPrompt}},
+		},
+		GenerationConfig: &GenerationConfig{
+			Temperature:     &t,
+			MaxOutputTokens: 2000,
+		},
+	}
+	if n > 1 {
+		request.GenerationConfig.CandidateCount = n
+	}
+
+	call := api.LLMCall{
+		Provider: api.ModelProviderGemini,
+		Model:    c.config.GetModel(),
+	}
+	start := time.Now()
+	resp, err := c.client.GenerateContent(ctx, c.config.GetModel(), request)
+	call.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		call.Error = err.Error()
+		logs.LogLLMCall(ctx, call, request, nil)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			span.SetAttributes(attribute.Int("llm.statusCode", apiErr.Code))
+			if apiErr.isContextLengthExceeded() {
+				return nil, llms.ContextLengthExceededError{Cause: err}
+			}
+		}
+		return nil, pkgerrors.Wrapf(err, "GenerateContent failed")
+	}
+
+	usage := api.LLMUsage{
+		InputTokens:       resp.UsageMetadata.PromptTokenCount,
+		CachedInputTokens: resp.UsageMetadata.CachedContentTokenCount,
+		OutputTokens:      resp.UsageMetadata.CandidatesTokenCount,
+		Model:             c.config.GetModel(),
+		Provider:          string(api.ModelProviderGemini),
+	}
+	call.Usage = usage
+	logs.LogLLMCall(ctx, call, request, resp)
+	llms.RecordUsage(ctx, usage)
+
+	stopReason := ""
+	if len(resp.Candidates) > 0 {
+		stopReason = resp.Candidates[0].FinishReason
+	}
+	span.SetAttributes(
+		attribute.Int("llm.input_tokens", resp.UsageMetadata.PromptTokenCount),
+		attribute.Int("llm.output_tokens", resp.UsageMetadata.CandidatesTokenCount),
+		attribute.String("llm.stop_reason", stopReason),
+	)
+
+	candidates, err := c.parseResponse(ctx, resp)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Failed to parse response")
+	}
+	return candidates, nil
+}
+
+// userParts returns the parts of the user message. Images in the context are added as inline data.
+func userParts(ctx context.Context, message string) []Part {
+	images := llms.Images(ctx)
+	parts := make([]Part, 0, len(images)+1)
+	parts = append(parts, Part{Text: message})
+	for _, i := range images {
+		parts = append(parts, Part{InlineData: &Blob{MimeType: i.MimeType, Data: i.Base64()}})
+	}
+	return parts
+}
+
+func (c *Completer) parseResponse(ctx context.Context, resp *GenerateContentResponse) ([][]*v1alpha1.Block, error) {
+	log := logs.FromContext(ctx)
+	candidates := make([][]*v1alpha1.Block, 0, len(resp.Candidates))
+	for _, candidate := range resp.Candidates {
+		text := candidate.Content.Text()
+		if text == "" {
+			continue
+		}
+
+		blocks, err := docs.MarkdownToBlocks(text)
+		if err != nil {
+			log.Error(err, "Failed to parse markdown to blocks", "markdown", text)
+			blocks = []*v1alpha1.Block{
+				{
+					Kind:     v1alpha1.BlockKind_MARKUP,
+					Contents: text,
+				},
+			}
+		}
+
+		// Set block ids
+		if _, err := docs.SetBlockIds(blocks); err != nil {
+			return nil, pkgerrors.Wrapf(err, "Failed to set block ids")
+		}
+
+		candidates = append(candidates, blocks)
+	}
+	return candidates, nil
+}
@@ -0,0 +1,135 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/llms"
+)
+
+func Test_Complete(t *testing.T) {
+	var path string
+	var apiKey string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read request body: %v", err)
+		}
+		path = r.URL.Path
+		apiKey = r.Header.Get("x-goog-api-key")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"List the pods\n"},{"text":"` + "```bash\\nkubectl get pods\\n```" + `"}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":100,"candidatesTokenCount":20,"cachedContentTokenCount":40},"modelVersion":"gemini-1.5-flash-002"}`))
+	}))
+	defer server.Close()
+
+	cfg := config.Config{
+		Agent: &api.AgentConfig{
+			Model:         "gemini-1.5-flash",
+			ModelProvider: api.ModelProviderGemini,
+		},
+	}
+	completer, err := NewCompleter(cfg, newClient(server.Client(), server.URL, "apikey", false))
+	if err != nil {
+		t.Fatalf("Failed to create completer: %v", err)
+	}
+
+	var usage api.LLMUsage
+	ctx := llms.WithUsageRecorder(context.Background(), func(u api.LLMUsage) {
+		usage = u
+	})
+	blocks, err := completer.Complete(ctx, "system prompt", "list the pods")
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	if path != "/models/gemini-1.5-flash:generateContent" {
+		t.Errorf("Unexpected path %q", path)
+	}
+	if apiKey != "apikey" {
+		t.Errorf("Expected the API key to be sent; got %q", apiKey)
+	}
+
+	request := map[string]any{}
+	if err := json.Unmarshal(body, &request); err != nil {
+		t.Fatalf("Failed to unmarshal request: %v", err)
+	}
+	if _, ok := request["model"]; ok {
+		t.Errorf("The model should be in the URL not the request body: %s", body)
+	}
+	for _, f := range []string{"contents", "systemInstruction", "generationConfig"} {
+		if _, ok := request[f]; !ok {
+			t.Errorf("Request is missing field %s: %s", f, body)
+		}
+	}
+
+	if len(blocks) != 2 {
+		t.Fatalf("Expected 2 blocks; got %d", len(blocks))
+	}
+	if blocks[1].Contents != "kubectl get pods" {
+		t.Errorf("Unexpected code block %q", blocks[1].Contents)
+	}
+	for _, b := range blocks {
+		if b.Id == "" {
+			t.Errorf("Block ids should be set")
+		}
+	}
+
+	expected := api.LLMUsage{
+		InputTokens:       100,
+		CachedInputTokens: 40,
+		OutputTokens:      20,
+		Model:             "gemini-1.5-flash",
+		Provider:          string(api.ModelProviderGemini),
+	}
+	if usage != expected {
+		t.Errorf("Unexpected usage; got %+v want %+v", usage, expected)
+	}
+}
+
+func Test_CompleteContextLengthExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"code":400,"message":"The input token count (2000000) exceeds the maximum number of tokens allowed (1048576).","status":"INVALID_ARGUMENT"}}`))
+	}))
+	defer server.Close()
+
+	cfg := config.Config{
+		Agent: &api.AgentConfig{
+			Model: "gemini-1.5-flash",
+		},
+	}
+	completer, err := NewCompleter(cfg, newClient(server.Client(), server.URL, "apikey", false))
+	if err != nil {
+		t.Fatalf("Failed to create completer: %v", err)
+	}
+
+	_, err = completer.Complete(context.Background(), "system prompt", "a very long document")
+	var cErr llms.ContextLengthExceededError
+	if !errors.As(err, &cErr) {
+		t.Errorf("Expected ContextLengthExceededError; got %v", err)
+	}
+}
+
+func Test_userParts(t *testing.T) {
+	ctx := llms.WithImages(context.Background(), []llms.Image{{MimeType: "image/png", Data: []byte("png")}})
+	parts := userParts(ctx, "describe the chart")
+	if len(parts) != 2 {
+		t.Fatalf("Expected 2 parts; got %d", len(parts))
+	}
+	if parts[0].Text != "describe the chart" {
+		t.Errorf("Unexpected text part %q", parts[0].Text)
+	}
+	if parts[1].InlineData == nil || parts[1].InlineData.MimeType != "image/png" || parts[1].InlineData.Data != "cG5n" {
+		t.Errorf("Unexpected image part %+v", parts[1].InlineData)
+	}
+}
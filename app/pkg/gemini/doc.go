@@ -0,0 +1,3 @@
+// Package gemini implements the LLM interfaces using Google's Gemini models through either the Gemini API or
+// Vertex AI.
+package gemini
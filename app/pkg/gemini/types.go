@@ -0,0 +1,91 @@
+package gemini
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The types below are the subset of the REST API that we use.
+// See: https://ai.google.dev/api/generate-content
+
+const (
+	RoleUser  = "user"
+	RoleModel = "model"
+)
+
+type Part struct {
+	Text string `json:"text,omitempty"`
+	// InlineData is an image sent with the prompt.
+	InlineData *Blob `json:"inlineData,omitempty"`
+}
+
+// Blob is inline data; e.g. an image.
+type Blob struct {
+	MimeType string `json:"mimeType"`
+	// Data is the base64 encoded data.
+	Data string `json:"data"`
+}
+
+type Content struct {
+	Role  string `json:"role,omitempty"`
+	Parts []Part `json:"parts"`
+}
+
+// Text returns the text of all the parts.
+func (c Content) Text() string {
+	var sb strings.Builder
+	for _, p := range c.Parts {
+		sb.WriteString(p.Text)
+	}
+	return sb.String()
+}
+
+type GenerationConfig struct {
+	Temperature     *float32 `json:"temperature,omitempty"`
+	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
+	CandidateCount  int      `json:"candidateCount,omitempty"`
+}
+
+type GenerateContentRequest struct {
+	// Model isn't part of the request body since the model is part of the URL. It is included so that logged
+	// requests identify the model; the client clears it before sending the request.
+	Model             string            `json:"model,omitempty"`
+	Contents          []Content         `json:"contents"`
+	SystemInstruction *Content          `json:"systemInstruction,omitempty"`
+	GenerationConfig  *GenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type Candidate struct {
+	Content      Content `json:"content"`
+	FinishReason string  `json:"finishReason,omitempty"`
+	Index        int     `json:"index"`
+}
+
+type UsageMetadata struct {
+	PromptTokenCount        int `json:"promptTokenCount"`
+	CandidatesTokenCount    int `json:"candidatesTokenCount"`
+	TotalTokenCount         int `json:"totalTokenCount"`
+	CachedContentTokenCount int `json:"cachedContentTokenCount,omitempty"`
+}
+
+type GenerateContentResponse struct {
+	Candidates    []Candidate   `json:"candidates"`
+	UsageMetadata UsageMetadata `json:"usageMetadata"`
+	ModelVersion  string        `json:"modelVersion,omitempty"`
+}
+
+// APIError is the error returned by the API.
+type APIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("gemini error %d %s: %s", e.Code, e.Status, e.Message)
+}
+
+// isContextLengthExceeded returns true if the error indicates the prompt has too many tokens.
+func (e *APIError) isContextLengthExceeded() bool {
+	return e.Status == "INVALID_ARGUMENT" && strings.Contains(e.Message, "exceeds the maximum number of tokens")
+}
@@ -0,0 +1,52 @@
+package gemini
+
+import (
+	"context"
+
+	"github.com/jlewi/foyle/app/pkg/docs"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// EmbeddingModel is the model used to compute embeddings.
+	EmbeddingModel = "text-embedding-004"
+	// EmbeddingDims is the dimension of the embeddings computed by EmbeddingModel.
+	EmbeddingDims = 768
+)
+
+func NewVectorizer(client *Client) *Vectorizer {
+	return &Vectorizer{
+		client: client,
+	}
+}
+
+// Vectorizer computes embeddings using Gemini's embedding model.
+type Vectorizer struct {
+	client *Client
+}
+
+func (v *Vectorizer) Embed(ctx context.Context, blocks []*v1alpha1.Block) (llms.Vector, error) {
+	text := docs.BlocksToMarkdown(blocks)
+
+	// Compute the embedding for the query.
+	log := logs.FromContext(ctx)
+	log.Info("RAG Query", "query", text)
+
+	// N.B. regarding retries. We should already be doing retries in the HTTP client.
+	values, err := v.client.EmbedContent(ctx, EmbeddingModel, text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create embeddings")
+	}
+
+	if len(values) != EmbeddingDims {
+		return nil, errors.Errorf("Embeddings have wrong dimension; got %v, want %v", len(values), EmbeddingDims)
+	}
+	return llms.Vector(values), nil
+}
+
+func (v *Vectorizer) Length() int {
+	return EmbeddingDims
+}
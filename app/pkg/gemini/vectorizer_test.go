@@ -0,0 +1,74 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+)
+
+func Test_Embed(t *testing.T) {
+	type testCase struct {
+		name     string
+		vertex   bool
+		path     string
+		response func(values []float32) any
+	}
+
+	cases := []testCase{
+		{
+			name:   "gemini",
+			vertex: false,
+			path:   "/models/" + EmbeddingModel + ":embedContent",
+			response: func(values []float32) any {
+				return map[string]any{"embedding": map[string]any{"values": values}}
+			},
+		},
+		{
+			name:   "vertex",
+			vertex: true,
+			path:   "/models/" + EmbeddingModel + ":predict",
+			response: func(values []float32) any {
+				return map[string]any{"predictions": []any{map[string]any{"embeddings": map[string]any{"values": values}}}}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			values := make([]float32, EmbeddingDims)
+			values[0] = 1
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != c.path {
+					t.Errorf("Unexpected path %q; want %q", r.URL.Path, c.path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(c.response(values)); err != nil {
+					t.Errorf("Failed to write response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			v := NewVectorizer(newClient(server.Client(), server.URL, "", c.vertex))
+			blocks := []*v1alpha1.Block{
+				{
+					Kind:     v1alpha1.BlockKind_MARKUP,
+					Contents: "list the pods",
+				},
+			}
+			vec, err := v.Embed(context.Background(), blocks)
+			if err != nil {
+				t.Fatalf("Embed failed: %v", err)
+			}
+			if len(vec) != v.Length() {
+				t.Errorf("Expected %d dimensions; got %d", v.Length(), len(vec))
+			}
+			if vec[0] != 1 {
+				t.Errorf("Unexpected embedding value %v", vec[0])
+			}
+		})
+	}
+}
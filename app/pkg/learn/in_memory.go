@@ -3,10 +3,12 @@ package learn
 import (
 	"context"
 	"io"
+	"slices"
 	"sort"
 	"sync"
 
 	"github.com/jlewi/foyle/app/pkg/docs"
+	"github.com/jlewi/foyle/app/pkg/events"
 
 	"github.com/jlewi/foyle/app/pkg/llms"
 
@@ -16,7 +18,7 @@ import (
 
 	"github.com/jlewi/foyle/app/pkg/config"
 	"github.com/jlewi/foyle/app/pkg/logs"
-	"github.com/jlewi/foyle/app/pkg/oai"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -80,6 +82,8 @@ func (db *InMemoryExampleDB) GetExamples(ctx context.Context, req *v1alpha1.Gene
 		return []*v1alpha1.Example{}, nil
 	}
 
+	languages := docs.CodeLanguages(docs.PreprocessDoc(req))
+
 	blocks, err := docs.CreateQuery(ctx, req)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to create query")
@@ -107,23 +111,58 @@ func (db *InMemoryExampleDB) GetExamples(ctx context.Context, req *v1alpha1.Gene
 	// only the 0:len(db.examples) row of embeddings are valid so we need to trim the indexes
 	sorted := sortIndexes(result, numExamples)
 
-	numResults := maxResults
-	if len(db.examples) < maxResults {
-		numResults = len(db.examples)
+	selected := selectExamples(db.examples, sorted, languages, maxResults)
+	results := make([]*v1alpha1.Example, 0, len(selected))
+	ragSpan := &logspb.RAGSpan{
+		Results: make([]*v1alpha1.RAGResult, 0, len(selected)),
 	}
 
-	results := make([]*v1alpha1.Example, 0, numResults)
-
-	for i := len(sorted) - numResults; i < len(sorted); i++ {
-		example := db.examples[sorted[i]]
-		score := result.AtVec(sorted[i])
+	for _, idx := range selected {
+		example := db.examples[idx]
+		score := result.AtVec(idx)
 		log.Info("RAG result", zap.Object("example", example), "score", score)
 		results = append(results, example)
+		ragSpan.Results = append(ragSpan.Results, &v1alpha1.RAGResult{
+			Example: example,
+			Score:   score,
+		})
 	}
+	events.Publish(ctx, &events.Event{Span: &logspb.Span{Data: &logspb.Span_Rag{Rag: ragSpan}}})
 
 	return results, nil
 }
 
+// selectExamples returns the indexes of up to maxResults examples. sorted are the indexes of the examples sorted
+// in increasing order of similarity. Examples in one of the languages of the document, or without a language, are
+// preferred; the remaining slots are filled with the most similar of the other examples. The indexes are returned
+// in increasing order of similarity.
+func selectExamples(examples []*v1alpha1.Example, sorted []int, languages []string, maxResults int) []int {
+	matches := func(e *v1alpha1.Example) bool {
+		if len(languages) == 0 || e.GetLanguage() == "" {
+			return true
+		}
+		return slices.Contains(languages, e.GetLanguage())
+	}
+
+	keep := make(map[int]bool)
+	for _, preferred := range []bool{true, false} {
+		for i := len(sorted) - 1; i >= 0 && len(keep) < maxResults; i-- {
+			idx := sorted[i]
+			if matches(examples[idx]) == preferred {
+				keep[idx] = true
+			}
+		}
+	}
+
+	selected := make([]int, 0, len(keep))
+	for _, idx := range sorted {
+		if keep[idx] {
+			selected = append(selected, idx)
+		}
+	}
+	return selected
+}
+
 func (db *InMemoryExampleDB) GetExample(ctx context.Context, id string) (*v1alpha1.Example, error) {
 	db.lock.RLock()
 	defer db.lock.RUnlock()
@@ -211,7 +250,7 @@ func (db *InMemoryExampleDB) loadExamples(ctx context.Context) error {
 		}
 
 		if db.embeddings == nil {
-			db.embeddings = mat.NewDense(initialNumberOfRows(len(matches)), oai.SmallEmbeddingsDims, nil)
+			db.embeddings = mat.NewDense(initialNumberOfRows(len(matches)), db.vectorizer.Length(), nil)
 		}
 
 		// Load the examples.
@@ -277,8 +316,8 @@ func (db *InMemoryExampleDB) loadRow(ctx context.Context, exampleFile string) er
 		return errors.Wrapf(err, "Failed to unmarshal example from %s", exampleFile)
 	}
 
-	if len(example.Embedding) != oai.SmallEmbeddingsDims {
-		return errors.Errorf("Expected embedding to have %d elements but got %d", oai.SmallEmbeddingsDims, len(example.Embedding))
+	if len(example.Embedding) != db.vectorizer.Length() {
+		return errors.Errorf("Expected embedding to have %d elements but got %d", db.vectorizer.Length(), len(example.Embedding))
 	}
 
 	return db.updateExample(example)
@@ -293,3 +293,60 @@ func Test_initialNumberOfRows(t *testing.T) {
 		})
 	}
 }
+
+func Test_selectExamples(t *testing.T) {
+	examples := []*v1alpha1.Example{
+		{Id: "bash", Language: "bash"},
+		{Id: "python", Language: "python"},
+		{Id: "legacy"},
+		{Id: "sql", Language: "sql"},
+	}
+
+	type testCase struct {
+		name       string
+		sorted     []int
+		languages  []string
+		maxResults int
+		expected   []int
+	}
+
+	cases := []testCase{
+		{
+			name:       "no-languages",
+			sorted:     []int{2, 1, 0, 3},
+			languages:  nil,
+			maxResults: 2,
+			expected:   []int{0, 3},
+		},
+		{
+			name:       "prefer-language",
+			sorted:     []int{1, 2, 0, 3},
+			languages:  []string{"python"},
+			maxResults: 2,
+			expected:   []int{1, 2},
+		},
+		{
+			name:       "fill-with-others",
+			sorted:     []int{1, 2, 0, 3},
+			languages:  []string{"python"},
+			maxResults: 3,
+			expected:   []int{1, 2, 3},
+		},
+		{
+			name:       "more-than-examples",
+			sorted:     []int{0, 1, 2, 3},
+			languages:  []string{"bash"},
+			maxResults: 10,
+			expected:   []int{0, 1, 2, 3},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := selectExamples(examples, c.sorted, c.languages, c.maxResults)
+			if d := cmp.Diff(c.expected, actual); d != "" {
+				t.Errorf("Unexpected indexes (-want +got):\n%s", d)
+			}
+		})
+	}
+}
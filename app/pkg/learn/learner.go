@@ -23,19 +23,21 @@ import (
 	"github.com/jlewi/monogo/files"
 	"github.com/jlewi/monogo/helpers"
 
-	"k8s.io/client-go/util/workqueue"
-
 	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	"github.com/jlewi/foyle/app/pkg/llms"
 	"github.com/jlewi/foyle/app/pkg/logs"
-	"github.com/jlewi/foyle/app/pkg/oai"
+	"github.com/jlewi/foyle/app/pkg/queue"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 	"github.com/pkg/errors"
-	"github.com/sashabaranov/go-openai"
 	"google.golang.org/protobuf/proto"
 )
 
 const (
 	fileSuffix = ".example.binpb"
+
+	// sessionQueueName is the name of the durable queue of sessions to learn from.
+	sessionQueueName = "sessions"
 )
 
 var (
@@ -74,30 +76,34 @@ var (
 // TODO(jeremy): Should we call this a trainer?
 type Learner struct {
 	Config          config.Config
-	client          *openai.Client
 	sessions        *analyze.SessionsManager
-	queue           workqueue.DelayingInterface
+	queue           *queue.Queue
 	postFunc        PostLearnEvent
 	eventLoopIsDone sync.WaitGroup
 	factory         *files.Factory
-	vectorizer      *oai.Vectorizer
+	vectorizer      llms.Vectorizer
 }
 
-func NewLearner(cfg config.Config, client *openai.Client, sessions *analyze.SessionsManager) (*Learner, error) {
-	if client == nil {
-		return nil, errors.New("OpenAI client is required")
+// NewLearner creates a learner. queuesDB is the database backing the queue of sessions to learn from; if it is nil
+// the queue is kept in memory.
+func NewLearner(cfg config.Config, vectorizer llms.Vectorizer, sessions *analyze.SessionsManager, queuesDB dbutil.KV) (*Learner, error) {
+	if vectorizer == nil {
+		return nil, errors.New("Vectorizer is required")
 	}
 
 	if sessions == nil {
 		return nil, errors.New("SessionsManager is required")
 	}
 
-	vectorizer := oai.NewVectorizer(client)
+	q, err := queue.New(queuesDB, sessionQueueName, queue.DefaultOptions())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create learner queue")
+	}
+
 	return &Learner{
 		Config:     cfg,
-		client:     client,
 		sessions:   sessions,
-		queue:      workqueue.NewDelayingQueue(),
+		queue:      q,
 		factory:    &files.Factory{},
 		vectorizer: vectorizer,
 	}, nil
@@ -129,7 +135,9 @@ func (l *Learner) Enqueue(session *logspb.Session) error {
 
 	log := zapr.NewLogger(zap.L())
 	log.V(logs.Debug).Info("Enqueue example", "contextId", session.GetContextId())
-	l.queue.Add(session.GetContextId())
+	if err := l.queue.Add(session.GetContextId()); err != nil {
+		return err
+	}
 	enqueuedCounter.Inc()
 	return nil
 }
@@ -138,26 +146,21 @@ func (l *Learner) eventLoop(ctx context.Context) {
 	log := logs.FromContext(ctx)
 	defer l.eventLoopIsDone.Done()
 	for {
-		item, shutdown := l.queue.Get()
+		exampleId, shutdown := l.queue.Get()
 		if shutdown {
 			return
 		}
-		func() {
-			defer l.queue.Done(item)
-			exampleId, ok := item.(string)
-			if !ok {
-				log.Error(errors.New("Failed to cast item to string"), "Failed to cast item to string", "item", item)
-				return
-			}
-
-			if err := l.Reconcile(ctx, exampleId); err != nil {
-				// N.B. Right now we treat learning errors as permanent and don't retry.
-				// The most likely source of retryable errors the vectorizer endpoint should already be handled
-				// by using a retryable HTTP client.
-				log.Error(err, "Error learning from example", "example", exampleId)
-				return
+		if err := l.Reconcile(ctx, exampleId); err != nil {
+			// Retry with backoff; if the example keeps failing it ends up in the dead letter store.
+			log.Error(err, "Error learning from example", "example", exampleId)
+			if err := l.queue.Retry(exampleId, err); err != nil {
+				log.Error(err, "Failed to retry example", "example", exampleId)
 			}
-		}()
+			continue
+		}
+		if err := l.queue.Done(exampleId); err != nil {
+			log.Error(err, "Failed to mark example as done", "example", exampleId)
+		}
 	}
 }
 
@@ -257,9 +260,16 @@ func (l *Learner) Reconcile(ctx context.Context, id string) error {
 	}
 
 	example := &v1alpha1.Example{
-		Id:     session.GetContextId(),
-		Query:  newDoc,
-		Answer: []*v1alpha1.Block{executedBlock},
+		Id:       session.GetContextId(),
+		Query:    newDoc,
+		Answer:   []*v1alpha1.Block{executedBlock},
+		Language: docs.NormalizeLanguage(executedBlock.GetLanguage()),
+	}
+
+	if l.Config.UseFIM() {
+		example.Suffix = &v1alpha1.Doc{
+			Blocks: docs.SuffixBlocks(req, l.Config.GetFIMConfig().MaxSuffixCells),
+		}
 	}
 
 	exampleId := session.GetContextId()
@@ -351,9 +361,9 @@ func (l *Learner) computeEmbeddings(ctx context.Context, example *v1alpha1.Examp
 		return err
 	}
 
-	if len(qVec) != oai.SmallEmbeddingsDims {
-		log.Error(err, "Embeddings have wrong dimension", "id", example.Id, "query", example.Query, "got", len(qVec), "want", oai.SmallEmbeddingsDims)
-		return errors.Wrapf(err, "Embeddings have wrong dimension; got %v, want %v", len(qVec), oai.SmallEmbeddingsDims)
+	if len(qVec) != l.vectorizer.Length() {
+		log.Error(err, "Embeddings have wrong dimension", "id", example.Id, "query", example.Query, "got", len(qVec), "want", l.vectorizer.Length())
+		return errors.Wrapf(err, "Embeddings have wrong dimension; got %v, want %v", len(qVec), l.vectorizer.Length())
 	}
 
 	example.Embedding = qVec
@@ -378,10 +388,15 @@ func sessionToQuery(session *logspb.Session) (*v1alpha1.GenerateRequest, error)
 		return nil, errors.Errorf("Unable to learn from session %s; because the selected cell is the first in the doc", session.GetContextId())
 	}
 
-	// We need to remove the last block from the doc. Because the last block in the doc i.e. the selected block
-	// is actually what we want to predict
-	doc.Blocks = doc.Blocks[:session.GetFullContext().GetSelected()]
-	selectedIndex := len(doc.Blocks) - 1
+	// We need to remove the selected block from the doc because it is actually what we want to predict.
+	// We keep the blocks after it because they are the suffix for fill in the middle prompts. The selected index
+	// points at the block preceding the removed block so PreprocessDoc will drop the suffix.
+	selected := int(session.GetFullContext().GetSelected())
+	if selected >= len(doc.Blocks) {
+		return nil, errors.Errorf("Unable to learn from session %s; the selected cell %d is out of bounds; the doc has %d cells", session.GetContextId(), selected, len(doc.Blocks))
+	}
+	doc.Blocks = append(doc.Blocks[:selected], doc.Blocks[selected+1:]...)
+	selectedIndex := selected - 1
 	req := &v1alpha1.GenerateRequest{
 		Doc:           doc,
 		SelectedIndex: int32(selectedIndex),
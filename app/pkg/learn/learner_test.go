@@ -15,6 +15,7 @@ import (
 	parserv1 "github.com/stateful/runme/v3/pkg/api/gen/proto/go/runme/parser/v1"
 
 	"github.com/jlewi/foyle/app/pkg/analyze"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
 
 	"github.com/jlewi/foyle/app/pkg/config"
 	"github.com/jlewi/foyle/app/pkg/oai"
@@ -45,7 +46,7 @@ func Test_Learner(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to open database: %v", cfg.GetSessionsDB())
 	}
-	sessions, err := analyze.NewSessionsManager(db)
+	sessions, err := analyze.NewSessionsManager(analyze.NewStore(db, dbutil.SQLite))
 	if err != nil {
 		t.Fatalf("Error creating sessions manager; %v", err)
 	}
@@ -55,7 +56,7 @@ func Test_Learner(t *testing.T) {
 		t.Fatalf("Error creating OpenAI client; %v", err)
 	}
 
-	l, err := NewLearner(*cfg, client, sessions)
+	l, err := NewLearner(*cfg, oai.NewVectorizer(client), sessions, nil)
 	if err != nil {
 		t.Fatalf("Error creating learner; %v", err)
 	}
@@ -107,6 +108,48 @@ func Test_sessionToQuery(t *testing.T) {
 				SelectedIndex: 0,
 			},
 		},
+		{
+			// The cells after the selected cell should be kept so they can be used as the suffix.
+			name: "suffix",
+			session: &logspb.Session{
+				FullContext: &v1alpha1.FullContext{
+					Notebook: &parserv1.Notebook{
+						Cells: []*parserv1.Cell{
+							{
+								Value: "Cell 1",
+								Kind:  parserv1.CellKind_CELL_KIND_MARKUP,
+							},
+							{
+								Value: "Cell 2",
+								Kind:  parserv1.CellKind_CELL_KIND_MARKUP,
+							},
+							{
+								Value: "Cell 3",
+								Kind:  parserv1.CellKind_CELL_KIND_MARKUP,
+							},
+						},
+					},
+					Selected: 1,
+				},
+			},
+			expected: &v1alpha1.GenerateRequest{
+				Doc: &v1alpha1.Doc{
+					Blocks: []*v1alpha1.Block{
+						{
+							Kind:     v1alpha1.BlockKind_MARKUP,
+							Contents: "Cell 1",
+							Outputs:  []*v1alpha1.BlockOutput{},
+						},
+						{
+							Kind:     v1alpha1.BlockKind_MARKUP,
+							Contents: "Cell 3",
+							Outputs:  []*v1alpha1.BlockOutput{},
+						},
+					},
+				},
+				SelectedIndex: 0,
+			},
+		},
 	}
 
 	for _, c := range testCases {
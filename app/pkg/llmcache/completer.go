@@ -0,0 +1,152 @@
+package llmcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/docs"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	cacheCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "llm_response_cache_requests_total",
+		Help: "Number of requests to the LLM response cache broken down by whether they were a hit or a miss"},
+		[]string{"result"},
+	)
+)
+
+// Completer wraps a completer and caches its responses keyed by a hash of the normalized prompt.
+// Conversations with tools aren't cached because the output of the commands can change.
+type Completer struct {
+	completer llms.Completer
+	cache     *expirable.LRU[string, [][]*v1alpha1.Block]
+}
+
+// NewCompleter creates a completer that caches the responses of completer.
+func NewCompleter(completer llms.Completer, cfg api.CacheConfig) (*Completer, error) {
+	if completer == nil {
+		return nil, errors.New("Completer is required")
+	}
+	if cfg.MaxEntries <= 0 {
+		return nil, errors.New("MaxEntries must be positive")
+	}
+	if cfg.TTLSeconds <= 0 {
+		return nil, errors.New("TTLSeconds must be positive")
+	}
+	return &Completer{
+		completer: completer,
+		cache:     expirable.NewLRU[string, [][]*v1alpha1.Block](cfg.MaxEntries, nil, time.Duration(cfg.TTLSeconds)*time.Second),
+	}, nil
+}
+
+func (c *Completer) Complete(ctx context.Context, systemPrompt string, message string) ([]*v1alpha1.Block, error) {
+	candidates, err := c.CompleteN(ctx, systemPrompt, message, 1)
+	if err != nil {
+		return nil, err
+	}
+	blocks := make([]*v1alpha1.Block, 0, len(candidates))
+	for _, candidate := range candidates {
+		blocks = append(blocks, candidate...)
+	}
+	return blocks, nil
+}
+
+func (c *Completer) CompleteN(ctx context.Context, systemPrompt string, message string, n int) ([][]*v1alpha1.Block, error) {
+	log := logs.FromContext(ctx)
+	key := cacheKey(systemPrompt, message, n, llms.Images(ctx))
+	if cached, ok := c.cache.Get(key); ok {
+		cacheCounter.WithLabelValues("hit").Inc()
+		log.Info("LLM response cache hit", "key", key)
+		return copyCandidates(cached)
+	}
+	cacheCounter.WithLabelValues("miss").Inc()
+
+	candidates, err := llms.CompleteN(ctx, c.completer, systemPrompt, message, n)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Add(key, cloneCandidates(candidates))
+	return candidates, nil
+}
+
+// SupportsTools returns whether the wrapped completer supports tools.
+func (c *Completer) SupportsTools() bool {
+	return llms.SupportsTools(c.completer)
+}
+
+// CompleteWithTools passes the conversation to the wrapped completer without caching it. It returns an error if
+// the wrapped completer doesn't support tools.
+func (c *Completer) CompleteWithTools(ctx context.Context, systemPrompt string, messages []llms.Message, tools []llms.Tool) (*llms.Message, error) {
+	tc, ok := c.completer.(llms.ToolCompleter)
+	if !ok || !tc.SupportsTools() {
+		return nil, errors.WithStack(llms.ErrToolsNotSupported)
+	}
+	return tc.CompleteWithTools(ctx, systemPrompt, messages, tools)
+}
+
+// cacheKey is the hash of the normalized prompt, the images sent with it and the number of candidates.
+func cacheKey(systemPrompt string, message string, n int, images []llms.Image) string {
+	h := sha256.New()
+	h.Write([]byte(normalize(systemPrompt)))
+	h.Write([]byte{0})
+	h.Write([]byte(normalize(message)))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.Itoa(n)))
+	for _, i := range images {
+		h.Write([]byte{0})
+		h.Write([]byte(i.MimeType))
+		h.Write([]byte{0})
+		h.Write(i.Data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalize removes whitespace that doesn't change the meaning of the prompt; e.g. trailing whitespace that is
+// added and removed as the user types.
+func normalize(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t\r")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func cloneCandidates(candidates [][]*v1alpha1.Block) [][]*v1alpha1.Block {
+	clones := make([][]*v1alpha1.Block, 0, len(candidates))
+	for _, blocks := range candidates {
+		clone := make([]*v1alpha1.Block, 0, len(blocks))
+		for _, b := range blocks {
+			clone = append(clone, proto.Clone(b).(*v1alpha1.Block))
+		}
+		clones = append(clones, clone)
+	}
+	return clones
+}
+
+// copyCandidates returns a copy of the cached candidates with new block ids. Block ids identify a suggestion
+// in the logs so each response needs its own ids.
+func copyCandidates(cached [][]*v1alpha1.Block) ([][]*v1alpha1.Block, error) {
+	candidates := cloneCandidates(cached)
+	for _, blocks := range candidates {
+		for _, b := range blocks {
+			b.Id = ""
+		}
+		if _, err := docs.SetBlockIds(blocks); err != nil {
+			return nil, errors.Wrapf(err, "Failed to set block ids")
+		}
+	}
+	return candidates, nil
+}
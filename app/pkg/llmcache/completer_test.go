@@ -0,0 +1,78 @@
+package llmcache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+)
+
+type fakeCompleter struct {
+	calls int
+}
+
+func (f *fakeCompleter) Complete(ctx context.Context, systemPrompt string, message string) ([]*v1alpha1.Block, error) {
+	f.calls++
+	return []*v1alpha1.Block{{Kind: v1alpha1.BlockKind_CODE, Contents: "echo " + message, Id: "id"}}, nil
+}
+
+func Test_Completer(t *testing.T) {
+	fake := &fakeCompleter{}
+	c, err := NewCompleter(fake, api.CacheConfig{MaxEntries: 10, TTLSeconds: 60})
+	if err != nil {
+		t.Fatalf("Failed to create completer: %v", err)
+	}
+
+	first, err := c.Complete(context.Background(), "system", "hello\nworld")
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	// Trailing whitespace doesn't change the key.
+	second, err := c.Complete(context.Background(), "system", "hello  \nworld\n")
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if fake.calls != 1 {
+		t.Errorf("Expected the second request to be served from the cache; completer was called %d times", fake.calls)
+	}
+	if len(second) != 1 || second[0].GetContents() != first[0].GetContents() {
+		t.Errorf("Expected the cached response; got %v", second)
+	}
+	if second[0].GetId() == "" || second[0].GetId() == first[0].GetId() {
+		t.Errorf("Cached blocks should be assigned new ids; got %q", second[0].GetId())
+	}
+
+	// Modifying the returned blocks doesn't modify the cache.
+	second[0].Contents = "modified"
+	third, err := c.Complete(context.Background(), "system", "hello\nworld")
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if third[0].GetContents() != first[0].GetContents() {
+		t.Errorf("The cached response was modified; got %v", third[0].GetContents())
+	}
+
+	// A different document or number of candidates misses the cache.
+	if _, err := c.Complete(context.Background(), "system", "hello"); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if _, err := c.CompleteN(context.Background(), "system", "hello\nworld", 2); err != nil {
+		t.Fatalf("CompleteN failed: %v", err)
+	}
+	// The fake doesn't implement MultiCompleter so CompleteN calls Complete once per candidate.
+	if fake.calls != 4 {
+		t.Errorf("Expected 4 calls to the completer; got %d", fake.calls)
+	}
+
+	// The same document with an image misses the cache.
+	ctx := llms.WithImages(context.Background(), []llms.Image{{MimeType: "image/png", Data: []byte("png")}})
+	if _, err := c.Complete(ctx, "system", "hello\nworld"); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if fake.calls != 5 {
+		t.Errorf("Expected 5 calls to the completer; got %d", fake.calls)
+	}
+}
@@ -0,0 +1,2 @@
+// Package llmcache caches the responses of LLMs so requests with identical prompts don't query the model again.
+package llmcache
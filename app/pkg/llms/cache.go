@@ -0,0 +1,24 @@
+package llms
+
+import (
+	"context"
+)
+
+type cacheablePrefixKey struct{}
+
+// WithCacheablePrefix returns a context indicating that the first n characters of the message sent to the
+// completer are the same across requests; e.g. the instructions and examples in the prompt. Completers for
+// providers that support prompt caching use this to mark the prefix so it can be cached.
+func WithCacheablePrefix(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, cacheablePrefixKey{}, n)
+}
+
+// CacheablePrefix splits the message into the cacheable prefix set by WithCacheablePrefix and the rest of the
+// message. The prefix is empty if the context doesn't have a prefix or it is longer than the message.
+func CacheablePrefix(ctx context.Context, message string) (string, string) {
+	n, ok := ctx.Value(cacheablePrefixKey{}).(int)
+	if !ok || n <= 0 || n > len(message) {
+		return "", message
+	}
+	return message[:n], message[n:]
+}
@@ -10,3 +10,35 @@ import (
 type Completer interface {
 	Complete(ctx context.Context, systemPrompt string, message string) ([]*v1alpha1.Block, error)
 }
+
+// MultiCompleter is implemented by completers that can generate several candidate completions in a single call;
+// e.g. using OpenAI's n parameter. Each element of the result is the blocks for one candidate.
+type MultiCompleter interface {
+	CompleteN(ctx context.Context, systemPrompt string, message string, n int) ([][]*v1alpha1.Block, error)
+}
+
+// CompleteN generates n candidate completions. If the completer implements MultiCompleter the candidates are
+// generated in a single call; otherwise Complete is called n times.
+func CompleteN(ctx context.Context, completer Completer, systemPrompt string, message string, n int) ([][]*v1alpha1.Block, error) {
+	if n <= 1 {
+		blocks, err := completer.Complete(ctx, systemPrompt, message)
+		if err != nil {
+			return nil, err
+		}
+		return [][]*v1alpha1.Block{blocks}, nil
+	}
+
+	if multi, ok := completer.(MultiCompleter); ok {
+		return multi.CompleteN(ctx, systemPrompt, message, n)
+	}
+
+	candidates := make([][]*v1alpha1.Block, 0, n)
+	for i := 0; i < n; i++ {
+		blocks, err := completer.Complete(ctx, systemPrompt, message)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, blocks)
+	}
+	return candidates, nil
+}
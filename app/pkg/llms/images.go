@@ -0,0 +1,37 @@
+package llms
+
+import (
+	"context"
+	"encoding/base64"
+)
+
+// Image is an image sent to the model along with the prompt; e.g. a chart output by a cell in the document.
+type Image struct {
+	// MimeType is the mime type of the image; e.g. image/png.
+	MimeType string
+	Data     []byte
+}
+
+// Base64 returns the base64 encoding of the image data.
+func (i Image) Base64() string {
+	return base64.StdEncoding.EncodeToString(i.Data)
+}
+
+// DataURL returns a data URL containing the image.
+func (i Image) DataURL() string {
+	return "data:" + i.MimeType + ";base64," + i.Base64()
+}
+
+type imagesKey struct{}
+
+// WithImages returns a context with images that should be sent to the model along with the message. Completers
+// for models that support images add them as image parts of the message.
+func WithImages(ctx context.Context, images []Image) context.Context {
+	return context.WithValue(ctx, imagesKey{}, images)
+}
+
+// Images returns the images set by WithImages.
+func Images(ctx context.Context) []Image {
+	images, _ := ctx.Value(imagesKey{}).([]Image)
+	return images
+}
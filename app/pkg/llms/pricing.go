@@ -0,0 +1,87 @@
+package llms
+
+import (
+	"strings"
+
+	"github.com/jlewi/foyle/app/api"
+)
+
+// DefaultPrices is the built in price table. Prices are in USD per million tokens and should be updated as
+// providers change their prices. Users can override or add prices in the configuration.
+var DefaultPrices = []api.ModelPrice{
+	{Provider: string(api.ModelProviderOpenAI), Model: "gpt-4o", InputPerMillion: 2.50, OutputPerMillion: 10.00, CachedInputPerMillion: 1.25},
+	{Provider: string(api.ModelProviderOpenAI), Model: "gpt-4o-mini", InputPerMillion: 0.15, OutputPerMillion: 0.60, CachedInputPerMillion: 0.075},
+	{Provider: string(api.ModelProviderOpenAI), Model: "gpt-4-turbo", InputPerMillion: 10.00, OutputPerMillion: 30.00},
+	{Provider: string(api.ModelProviderOpenAI), Model: "gpt-4", InputPerMillion: 30.00, OutputPerMillion: 60.00},
+	{Provider: string(api.ModelProviderOpenAI), Model: "gpt-3.5-turbo", InputPerMillion: 0.50, OutputPerMillion: 1.50},
+	{Provider: string(api.ModelProviderAnthropic), Model: "claude-3-5-sonnet", InputPerMillion: 3.00, OutputPerMillion: 15.00, CachedInputPerMillion: 0.30, CacheWriteInputPerMillion: 3.75},
+	{Provider: string(api.ModelProviderAnthropic), Model: "claude-3-5-haiku", InputPerMillion: 0.80, OutputPerMillion: 4.00, CachedInputPerMillion: 0.08, CacheWriteInputPerMillion: 1.00},
+	{Provider: string(api.ModelProviderAnthropic), Model: "claude-3-opus", InputPerMillion: 15.00, OutputPerMillion: 75.00, CachedInputPerMillion: 1.50, CacheWriteInputPerMillion: 18.75},
+	{Provider: string(api.ModelProviderAnthropic), Model: "claude-3-sonnet", InputPerMillion: 3.00, OutputPerMillion: 15.00},
+	{Provider: string(api.ModelProviderAnthropic), Model: "claude-3-haiku", InputPerMillion: 0.25, OutputPerMillion: 1.25, CachedInputPerMillion: 0.03, CacheWriteInputPerMillion: 0.30},
+}
+
+// PriceTable computes the cost of LLM usage.
+type PriceTable struct {
+	prices []api.ModelPrice
+}
+
+// NewPriceTable creates a price table from the default prices. Entries in overrides take precedence over
+// the defaults for the same provider and model.
+func NewPriceTable(overrides []api.ModelPrice) *PriceTable {
+	prices := make([]api.ModelPrice, 0, len(overrides)+len(DefaultPrices))
+	prices = append(prices, overrides...)
+	prices = append(prices, DefaultPrices...)
+	return &PriceTable{
+		prices: prices,
+	}
+}
+
+// Cost returns the cost in USD of the usage. The second return value is false if the model isn't in the table.
+// A nil table has no prices.
+func (t *PriceTable) Cost(usage api.LLMUsage) (float64, bool) {
+	price, ok := t.lookup(usage.Provider, usage.Model)
+	if !ok {
+		return 0, false
+	}
+	cachedPrice := price.CachedInputPerMillion
+	if cachedPrice == 0 {
+		cachedPrice = price.InputPerMillion
+	}
+	writePrice := price.CacheWriteInputPerMillion
+	if writePrice == 0 {
+		writePrice = price.InputPerMillion
+	}
+	uncached := usage.InputTokens - usage.CachedInputTokens - usage.CacheWriteInputTokens
+	if uncached < 0 {
+		uncached = 0
+	}
+	cost := float64(uncached)*price.InputPerMillion/1e6 +
+		float64(usage.CachedInputTokens)*cachedPrice/1e6 +
+		float64(usage.CacheWriteInputTokens)*writePrice/1e6 +
+		float64(usage.OutputTokens)*price.OutputPerMillion/1e6
+	return cost, true
+}
+
+// lookup finds the price with the longest model name that is a prefix of model. Since overrides come first
+// they win ties with the defaults.
+func (t *PriceTable) lookup(provider string, model string) (api.ModelPrice, bool) {
+	if t == nil {
+		return api.ModelPrice{}, false
+	}
+	var best api.ModelPrice
+	found := false
+	for _, p := range t.prices {
+		if p.Provider != "" && provider != "" && p.Provider != provider {
+			continue
+		}
+		if !strings.HasPrefix(model, p.Model) {
+			continue
+		}
+		if !found || len(p.Model) > len(best.Model) {
+			best = p
+			found = true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,94 @@
+package llms
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jlewi/foyle/app/api"
+)
+
+func Test_PriceTable(t *testing.T) {
+	type testCase struct {
+		name      string
+		overrides []api.ModelPrice
+		usage     api.LLMUsage
+		expected  float64
+		found     bool
+	}
+
+	cases := []testCase{
+		{
+			name: "exact",
+			usage: api.LLMUsage{
+				Provider:     string(api.ModelProviderOpenAI),
+				Model:        "gpt-4o",
+				InputTokens:  1000000,
+				OutputTokens: 1000000,
+			},
+			expected: 12.5,
+			found:    true,
+		},
+		{
+			// gpt-4o-mini-2024-07-18 should match gpt-4o-mini and not gpt-4o
+			name: "longest-prefix",
+			usage: api.LLMUsage{
+				Provider:     string(api.ModelProviderOpenAI),
+				Model:        "gpt-4o-mini-2024-07-18",
+				InputTokens:  2000000,
+				OutputTokens: 0,
+			},
+			expected: 0.3,
+			found:    true,
+		},
+		{
+			name: "override",
+			overrides: []api.ModelPrice{
+				{Model: "claude-3-5-sonnet", InputPerMillion: 1, OutputPerMillion: 2},
+			},
+			usage: api.LLMUsage{
+				Provider:     string(api.ModelProviderAnthropic),
+				Model:        "claude-3-5-sonnet-20240620",
+				InputTokens:  1000000,
+				OutputTokens: 1000000,
+			},
+			expected: 3,
+			found:    true,
+		},
+		{
+			// 1M input tokens of which 0.5M are read from the cache and 0.25M are written to the cache.
+			name: "cached",
+			usage: api.LLMUsage{
+				Provider:              string(api.ModelProviderAnthropic),
+				Model:                 "claude-3-5-sonnet-20240620",
+				InputTokens:           1000000,
+				CachedInputTokens:     500000,
+				CacheWriteInputTokens: 250000,
+			},
+			expected: 0.75 + 0.15 + 0.9375,
+			found:    true,
+		},
+		{
+			name: "unknown",
+			usage: api.LLMUsage{
+				Provider:    string(api.ModelProviderOpenAI),
+				Model:       "some-model",
+				InputTokens: 10,
+			},
+			expected: 0,
+			found:    false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := NewPriceTable(c.overrides)
+			actual, found := table.Cost(c.usage)
+			if found != c.found {
+				t.Fatalf("Unexpected found; got %v, want %v", found, c.found)
+			}
+			if math.Abs(actual-c.expected) > 1e-9 {
+				t.Errorf("Unexpected cost; got %v, want %v", actual, c.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package llms
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Roles of the messages in a conversation with tools.
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// Tool describes a function the model can call.
+type Tool struct {
+	Name        string
+	Description string
+	// Parameters is the JSON schema of the arguments of the tool.
+	Parameters json.RawMessage
+}
+
+// ToolCall is a request from the model to call a tool.
+type ToolCall struct {
+	ID   string
+	Name string
+	// Arguments are the JSON encoded arguments.
+	Arguments string
+}
+
+// Message is a message in a conversation with tools.
+type Message struct {
+	Role    string
+	Content string
+	// ToolCalls are the tool calls requested by the model in an assistant message.
+	ToolCalls []ToolCall
+	// ToolCallID is the ID of the call a tool message is the result of.
+	ToolCallID string
+}
+
+// ErrToolsNotSupported is returned by completers that wrap other completers when the wrapped completer doesn't
+// support tool calling.
+var ErrToolsNotSupported = errors.New("The completer doesn't support tool calling")
+
+// ToolCompleter is implemented by completers that support tool calling.
+type ToolCompleter interface {
+	// CompleteWithTools sends the conversation to the model and returns its reply. If the reply contains tool calls
+	// the caller should append the reply and the results of the calls to the conversation and call it again.
+	// If tools is empty the model has to answer without calling any tools.
+	CompleteWithTools(ctx context.Context, systemPrompt string, messages []Message, tools []Tool) (*Message, error)
+	// SupportsTools returns whether CompleteWithTools can be used. Completers that wrap other completers implement
+	// CompleteWithTools regardless of whether the completer they wrap supports tools so they report whether it does.
+	SupportsTools() bool
+}
+
+// SupportsTools returns true if the completer supports tool calling.
+func SupportsTools(c Completer) bool {
+	tc, ok := c.(ToolCompleter)
+	return ok && tc.SupportsTools()
+}
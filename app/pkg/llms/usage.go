@@ -0,0 +1,35 @@
+package llms
+
+import (
+	"context"
+
+	"github.com/jlewi/foyle/app/api"
+)
+
+// UsageRecorder is called with the usage of each LLM call.
+type UsageRecorder func(usage api.LLMUsage)
+
+type usageRecorderKey struct{}
+
+// WithUsageRecorder returns a context that reports usage to the recorder. Recorders already in the context
+// continue to be called.
+func WithUsageRecorder(ctx context.Context, recorder UsageRecorder) context.Context {
+	prev, _ := ctx.Value(usageRecorderKey{}).(UsageRecorder)
+	combined := func(usage api.LLMUsage) {
+		if prev != nil {
+			prev(usage)
+		}
+		recorder(usage)
+	}
+	return context.WithValue(ctx, usageRecorderKey{}, UsageRecorder(combined))
+}
+
+// RecordUsage reports the usage to the recorders in the context. Completers should call this after every
+// call to the LLM so that usage can be tracked e.g. to enforce budgets.
+func RecordUsage(ctx context.Context, usage api.LLMUsage) {
+	recorder, ok := ctx.Value(usageRecorderKey{}).(UsageRecorder)
+	if !ok || recorder == nil {
+		return
+	}
+	recorder(usage)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/jlewi/foyle/app/pkg/events"
+	"github.com/jlewi/foyle/app/pkg/logs/matchers"
 	"github.com/jlewi/foyle/app/pkg/runme/ulid"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 
@@ -72,11 +74,43 @@ func ZapProto(key string, pb proto.Message) zap.Field {
 // LogLLMUsage logs the usage of the LLM model
 // The purpose of this utility function is to create a standard log message independent of the model being used.
 // This simplifies post-processing
+// N.B. Completers now log the usage as part of the LLMCall logged by LogLLMCall. The analyzer still processes
+// usage logged by this function so that older logs can be processed.
 func LogLLMUsage(ctx context.Context, usage api.LLMUsage) {
 	log := FromContext(ctx)
 	log.Info("LLM usage", "usage", usage)
 }
 
+// LogLLMCall logs a call to an LLM. request and response are serialized to JSON and stored in the call;
+// response should be nil if the call failed.
+// Like LogLLMUsage this creates a standard log message independent of the provider so the analyzer can
+// process calls to any provider without knowing which function made the call. The call is also published as an
+// event so the analyzer can add it to the trace without waiting for the logs to be processed.
+func LogLLMCall(ctx context.Context, call api.LLMCall, request any, response any) {
+	log := FromContext(ctx)
+	call.Request = marshalLLMJSON(ctx, request)
+	call.Response = marshalLLMJSON(ctx, response)
+	log.Info("LLM call", matchers.LLMCallField, call)
+	events.Publish(ctx, &events.Event{LLMCall: &call})
+}
+
+// marshalLLMJSON serializes the request or response of an LLM call. It returns nil if v is nil.
+func marshalLLMJSON(ctx context.Context, v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		log := FromContext(ctx)
+		log.Error(err, "Failed to marshal LLM request or response")
+		return nil
+	}
+	if string(b) == "null" {
+		return nil
+	}
+	return b
+}
+
 // BuildAssertion creates an assertion based on the name.
 // N.B. We don't put this in the eval package because that would create a circular dependency.
 func BuildAssertion(name v1alpha1.Assertion_Name, passed bool) *v1alpha1.Assertion {
@@ -90,3 +124,10 @@ func BuildAssertion(name v1alpha1.Assertion_Name, passed bool) *v1alpha1.Asserti
 		Id:     ulid.GenerateID(),
 	}
 }
+
+// LogAssertion logs the assertion and publishes it as an event so it is added to the trace.
+func LogAssertion(ctx context.Context, assertion *v1alpha1.Assertion) {
+	log := FromContext(ctx)
+	log.Info(Level1Assertion, "assertion", assertion)
+	events.Publish(ctx, &events.Event{Assertion: assertion})
+}
@@ -6,6 +6,8 @@ package matchers
 import "strings"
 
 const (
+	// OAIComplete and AnthropicComplete match the functions that logged LLM requests and responses before
+	// completers logged an LLMCall (see LLMCallField). They are only needed to process older logs.
 	OAIComplete       = "github.com/jlewi/foyle/app/pkg/oai.(*Completer).Complete"
 	AnthropicComplete = "github.com/jlewi/foyle/app/pkg/anthropic.(*Completer).Complete"
 	LogEvents         = "github.com/jlewi/foyle/app/pkg/agent.(*Agent).LogEvents"
@@ -16,6 +18,19 @@ const (
 	// TODO(jeremy): The use of the abbreviation resp is inconsistent with the name of the field request but its what
 	// we used.
 	ResponseField = "resp"
+
+	// ToolSpanField is the field storing the ToolSpan proto logged by the agent for each tool call.
+	ToolSpanField = "toolSpan"
+
+	// LLMRouteField is the field storing the LLMSpan proto logged by the router with the model it chose.
+	LLMRouteField = "llmRoute"
+
+	// OutputReductionField is the field storing the OutputReductionSpan proto logged when an output is shortened to
+	// fit in the prompt.
+	OutputReductionField = "outputReduction"
+
+	// LLMCallField is the field storing the api.LLMCall logged by completers for every call to an LLM.
+	LLMCallField = "llmCall"
 )
 
 type Matcher func(name string) bool
@@ -21,6 +21,7 @@ const (
 
 	blockLogsView page = "blockLogs"
 	evalsView     page = "evals"
+	statsView     page = "stats"
 
 	getErrorState = "/getError"
 	blockLogState = "/blocklog"
@@ -82,6 +83,11 @@ func (m *MainApp) handleSetPage(ctx app.Context, action app.Action) {
 			log.Info("Setting page to EvalViewer")
 			m.page = &EvalViewer{}
 		}
+	case statsView:
+		if _, ok := m.page.(*StatsViewer); !ok {
+			log.Info("Setting page to StatsViewer")
+			m.page = &StatsViewer{}
+		}
 	}
 	// We need to call update to trigger a re-render of the component.
 	m.Update()
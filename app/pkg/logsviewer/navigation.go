@@ -20,5 +20,10 @@ func (s *navigationBar) Render() app.UI {
 			app.Button().Text("Eval Results")).OnClick(func(ctx app.Context, e app.Event) {
 			ctx.NewActionWithValue(setPage, evalsView)
 		}),
+		app.Div().Body(
+			app.Button().Text("Stats").OnClick(func(ctx app.Context, e app.Event) {
+				ctx.NewActionWithValue(setPage, statsView)
+			}),
+		),
 	)
 }
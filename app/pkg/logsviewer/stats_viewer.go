@@ -0,0 +1,146 @@
+package logsviewer
+
+import (
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+const (
+	statsDimensionID = "statsDimension"
+	statsNotebookID  = "statsNotebook"
+)
+
+// statsMetric is a statistic that is charted for each value of the dimension.
+type statsMetric struct {
+	title  string
+	format string
+	value  func(s *logspb.SessionStats) float64
+}
+
+var statsMetrics = []statsMetric{
+	{title: "Acceptance rate", format: "%.1f%%", value: func(s *logspb.SessionStats) float64 { return 100 * s.GetAcceptanceRate() }},
+	{title: "Suggestion to execution rate", format: "%.1f%%", value: func(s *logspb.SessionStats) float64 { return 100 * s.GetExecutionRate() }},
+	{title: "Execution failure rate", format: "%.1f%%", value: func(s *logspb.SessionStats) float64 { return 100 * s.GetFailureRate() }},
+	{title: "Mean seconds to accept", format: "%.1f", value: func(s *logspb.SessionStats) float64 { return s.GetMeanSecondsToAccept() }},
+	{title: "Tokens per accepted cell", format: "%.0f", value: func(s *logspb.SessionStats) float64 { return s.GetTokensPerAcceptedCell() }},
+}
+
+// StatsViewer is the page that charts the acceptance statistics computed from the sessions.
+//
+// Clicking load fetches the statistics sliced by the selected dimension from the StatsService. There is a bar
+// chart for each metric with one bar for each value of the dimension followed by a table of the counts.
+type StatsViewer struct {
+	app.Compo
+	stats    []*logspb.SessionStats
+	errorMsg string
+}
+
+func (c *StatsViewer) Render() app.UI {
+	dimensions := []logspb.StatsDimension{logspb.StatsDimension_DAY, logspb.StatsDimension_NOTEBOOK, logspb.StatsDimension_MODEL, logspb.StatsDimension_RAG}
+	options := make([]app.UI, 0, len(dimensions))
+	for _, d := range dimensions {
+		options = append(options, app.Option().Value(d.String()).Text(strings.ToLower(d.String())))
+	}
+
+	header := app.Div().Class("header").Body(
+		app.Label().Text("Slice by "),
+		app.Select().ID(statsDimensionID).Body(options...),
+		app.Input().
+			Type("text").
+			ID(statsNotebookID).
+			Placeholder("Notebook URI (optional)"),
+		app.Button().
+			Text("Load").
+			OnClick(func(ctx app.Context, e app.Event) {
+				c.load(ctx)
+			}),
+	)
+
+	if c.errorMsg != "" {
+		return app.Div().Body(header, app.Div().Text(c.errorMsg))
+	}
+
+	charts := make([]app.UI, 0, len(statsMetrics))
+	for _, m := range statsMetrics {
+		charts = append(charts, c.renderChart(m))
+	}
+	return app.Div().Body(header, app.Div().Body(charts...), c.renderTable())
+}
+
+// load fetches the statistics for the selected dimension.
+func (c *StatsViewer) load(ctx app.Context) {
+	dimension := app.Window().GetElementByID(statsDimensionID).Get("value").String()
+	notebook := strings.TrimSpace(app.Window().GetElementByID(statsNotebookID).Get("value").String())
+	req := &logspb.GetSessionStatsRequest{
+		Dimension:   logspb.StatsDimension(logspb.StatsDimension_value[dimension]),
+		NotebookUri: notebook,
+	}
+	resp, err := GetStatsClient().GetSessionStats(ctx, connect.NewRequest(req))
+	if err != nil {
+		c.errorMsg = err.Error()
+		c.stats = nil
+	} else {
+		c.errorMsg = ""
+		c.stats = resp.Msg.GetStats()
+	}
+	c.Update()
+}
+
+// renderChart renders a horizontal bar chart of the metric. The bars are scaled relative to the largest value.
+func (c *StatsViewer) renderChart(m statsMetric) app.UI {
+	maxValue := 0.0
+	for _, s := range c.stats {
+		if v := m.value(s); v > maxValue {
+			maxValue = v
+		}
+	}
+	rows := make([]app.UI, 0, len(c.stats))
+	for _, s := range c.stats {
+		v := m.value(s)
+		width := 0.0
+		if maxValue > 0 {
+			width = 100 * v / maxValue
+		}
+		rows = append(rows, app.Div().Class("chart-row").Body(
+			app.Div().Class("chart-label").Text(s.GetValue()),
+			app.Div().Class("chart-bar-area").Body(
+				app.Div().Class("chart-bar").Style("width", fmt.Sprintf("%.1f%%", width)),
+			),
+			app.Div().Class("chart-value").Text(fmt.Sprintf(m.format, v)),
+		))
+	}
+	return app.Div().Class("chart").Body(
+		app.H3().Text(m.title),
+		app.Div().Body(rows...),
+	)
+}
+
+// renderTable renders the counts the rates are computed from.
+func (c *StatsViewer) renderTable() app.UI {
+	columns := []string{"Value", "Sessions", "Suggested", "Accepted", "Rejected", "Suggested & Executed", "Executed", "Failed", "Input Tokens", "Output Tokens"}
+	header := make([]app.UI, 0, len(columns))
+	for _, col := range columns {
+		header = append(header, app.Th().Text(col))
+	}
+	rows := make([]app.UI, 0, len(c.stats)+1)
+	rows = append(rows, app.Tr().Body(header...))
+	for _, s := range c.stats {
+		rows = append(rows, app.Tr().Body(
+			app.Td().Text(s.GetValue()),
+			app.Td().Text(s.GetNumSessions()),
+			app.Td().Text(s.GetNumSuggested()),
+			app.Td().Text(s.GetNumAccepted()),
+			app.Td().Text(s.GetNumRejected()),
+			app.Td().Text(s.GetNumSuggestedExecuted()),
+			app.Td().Text(s.GetNumExecuted()),
+			app.Td().Text(s.GetNumExecuteFailed()),
+			app.Td().Text(s.GetTotalInputTokens()),
+			app.Td().Text(s.GetTotalOutputTokens()),
+		))
+	}
+	return app.Table().Class("stats-table").Body(rows...)
+}
@@ -0,0 +1,70 @@
+package oai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+type cacheUsageKey struct{}
+
+// cacheUsage is the number of prompt tokens of a request that were read from OpenAI's prompt cache.
+// OpenAI caches the prefix of prompts automatically but the version of go-openai we use doesn't parse
+// prompt_tokens_details so cacheTransport reads it from the response into the cacheUsage in the request's context.
+type cacheUsage struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// withCacheUsage returns a context that tells cacheTransport to report the cache usage of the request.
+func withCacheUsage(ctx context.Context) (context.Context, *cacheUsage) {
+	u := &cacheUsage{}
+	return context.WithValue(ctx, cacheUsageKey{}, u), u
+}
+
+// cacheTransport is an http.RoundTripper that reads the cache usage of requests whose context was created with
+// withCacheUsage.
+type cacheTransport struct {
+	next http.RoundTripper
+}
+
+func newCacheTransport(next http.RoundTripper) *cacheTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cacheTransport{next: next}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, ok := req.Context().Value(cacheUsageKey{}).(*cacheUsage)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read response body")
+	}
+	if err := resp.Body.Close(); err != nil {
+		return nil, errors.Wrapf(err, "Failed to close response body")
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	usage := struct {
+		Usage struct {
+			PromptTokensDetails *cacheUsage `json:"prompt_tokens_details"`
+		} `json:"usage"`
+	}{}
+	usage.Usage.PromptTokensDetails = u
+	// Ignore errors; the usage is best effort and the client reports errors parsing the response.
+	_ = json.Unmarshal(body, &usage)
+	return resp, nil
+}
@@ -0,0 +1,49 @@
+package oai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/config"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/sashabaranov/go-openai"
+)
+
+func Test_CachedTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"hello"},"finish_reason":"stop"}],"usage":{"prompt_tokens":2000,"completion_tokens":5,"total_tokens":2005,"prompt_tokens_details":{"cached_tokens":1024}}}`))
+	}))
+	defer server.Close()
+
+	clientConfig := openai.DefaultConfig("apikey")
+	clientConfig.BaseURL = server.URL + "/v1"
+	clientConfig.HTTPClient = &http.Client{Transport: newCacheTransport(nil)}
+	completer, err := NewCompleter(config.Config{Agent: &api.AgentConfig{Model: "gpt-4o"}}, openai.NewClientWithConfig(clientConfig))
+	if err != nil {
+		t.Fatalf("Failed to create completer: %v", err)
+	}
+
+	var usage api.LLMUsage
+	ctx := llms.WithUsageRecorder(context.Background(), func(u api.LLMUsage) {
+		usage = u
+	})
+	if _, err := completer.Complete(ctx, "system prompt", "document"); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	expected := api.LLMUsage{
+		InputTokens:       2000,
+		CachedInputTokens: 1024,
+		OutputTokens:      5,
+		Model:             "gpt-4o",
+		Provider:          string(api.ModelProviderOpenAI),
+	}
+	if d := cmp.Diff(expected, usage); d != "" {
+		t.Errorf("Unexpected usage:\n%s", d)
+	}
+}
@@ -42,6 +42,9 @@ func NewClient(cfg config.Config) (*openai.Client, error) {
 		httpClient.Transport = otelhttp.NewTransport(httpClient.Transport)
 	}
 
+	// Read the number of prompt tokens read from the cache from responses.
+	httpClient.Transport = newCacheTransport(httpClient.Transport)
+
 	var clientConfig openai.ClientConfig
 	if cfg.AzureOpenAI != nil {
 		var clientErr error
@@ -2,8 +2,7 @@ package oai
 
 import (
 	"context"
-
-	"github.com/jlewi/foyle/app/pkg/logs/matchers"
+	"time"
 
 	"github.com/jlewi/foyle/app/api"
 	"go.opentelemetry.io/otel/attribute"
@@ -23,9 +22,16 @@ const (
 )
 
 func NewCompleter(cfg config.Config, client *openai.Client) (*Completer, error) {
+	provider := api.ModelProviderOpenAI
+	if cfg.Agent != nil && cfg.Agent.ModelProvider == api.ModelProviderReplicate {
+		// Replicate serves models using an OpenAI compatible API.
+		provider = api.ModelProviderReplicate
+	}
 	return &Completer{
-		client: client,
-		config: cfg,
+		client:        client,
+		config:        cfg,
+		provider:      provider,
+		supportsTools: cfg.ModelSupportsTools(provider),
 	}, nil
 }
 
@@ -33,23 +39,37 @@ func NewCompleter(cfg config.Config, client *openai.Client) (*Completer, error)
 type Completer struct {
 	client *openai.Client
 	config config.Config
+	// provider is the provider reported in logs and usage.
+	provider api.ModelProvider
+	// supportsTools is whether the model supports function calling.
+	supportsTools bool
 }
 
 // Complete returns a ContextLengthExceededError if the context is too long
 func (c *Completer) Complete(ctx context.Context, systemPrompt string, message string) ([]*v1alpha1.Block, error) {
+	candidates, err := c.CompleteN(ctx, systemPrompt, message, 1)
+	if err != nil {
+		return nil, err
+	}
+	allBlocks := make([]*v1alpha1.Block, 0, 10)
+	for _, blocks := range candidates {
+		allBlocks = append(allBlocks, blocks...)
+	}
+	return allBlocks, nil
+}
+
+// CompleteN generates n candidate completions in a single request using the n parameter of the chat API.
+func (c *Completer) CompleteN(ctx context.Context, systemPrompt string, message string, n int) ([][]*v1alpha1.Block, error) {
 	tp := tracer()
-	log := logs.FromContext(ctx)
 	// Start a span to record metrics.
-	ctx, span := tp.Start(ctx, "Complete", trace.WithAttributes(attribute.String("llm.model", c.config.GetModel()), attribute.String("llm.provider", string(api.ModelProviderOpenAI))))
+	ctx, span := tp.Start(ctx, "Complete", trace.WithAttributes(attribute.String("llm.model", c.config.GetModel()), attribute.String("llm.provider", string(c.provider))))
 	defer span.End()
 
 	messages := []openai.ChatCompletionMessage{
 		{Role: openai.ChatMessageRoleSystem,
 			Content: systemPrompt,
 		},
-		{Role: openai.ChatMessageRoleUser,
-			Content: message,
-		},
+		userMessage(ctx, message),
 	}
 	request := openai.ChatCompletionRequest{
 		Model:       c.config.GetModel(),
@@ -57,11 +77,24 @@ func (c *Completer) Complete(ctx context.Context, systemPrompt string, message s
 		MaxTokens:   2000,
 		Temperature: temperature,
 	}
+	if n > 1 {
+		request.N = n
+	}
 
-	log.Info("OpenAI:CreateChatCompletion", matchers.RequestField, request)
+	// N.B. OpenAI caches prompt prefixes automatically. The system prompt, instructions and examples come first in
+	// the prompt so they can be cached; we only need to read how many tokens were cached.
+	ctx, cache := withCacheUsage(ctx)
+	call := api.LLMCall{
+		Provider: c.provider,
+		Model:    c.config.GetModel(),
+	}
+	start := time.Now()
 	resp, err := c.client.CreateChatCompletion(ctx, request)
+	call.LatencyMs = time.Since(start).Milliseconds()
 
 	if err != nil {
+		call.Error = err.Error()
+		logs.LogLLMCall(ctx, call, request, nil)
 		apiErr, ok := err.(*openai.APIError)
 		if ok {
 			val, ok := apiErr.Code.(string)
@@ -77,15 +110,16 @@ func (c *Completer) Complete(ctx context.Context, systemPrompt string, message s
 		return nil, errors.Wrapf(err, "CreateChatCompletion failed")
 	}
 
-	log.Info("OpenAI:CreateChatCompletion response", matchers.ResponseField, resp)
 	usage := api.LLMUsage{
-		InputTokens:  resp.Usage.PromptTokens,
-		OutputTokens: resp.Usage.CompletionTokens,
-		Model:        c.config.GetModel(),
-		Provider:     string(api.ModelProviderOpenAI),
+		InputTokens:       resp.Usage.PromptTokens,
+		CachedInputTokens: cache.CachedTokens,
+		OutputTokens:      resp.Usage.CompletionTokens,
+		Model:             c.config.GetModel(),
+		Provider:          string(c.provider),
 	}
-
-	logs.LogLLMUsage(ctx, usage)
+	call.Usage = usage
+	logs.LogLLMCall(ctx, call, request, resp)
+	llms.RecordUsage(ctx, usage)
 	stopReason := ""
 	if len(resp.Choices) > 0 {
 		stopReason = string(resp.Choices[0].FinishReason)
@@ -93,19 +127,47 @@ func (c *Completer) Complete(ctx context.Context, systemPrompt string, message s
 	span.SetAttributes(
 		attribute.Int("llm.input_tokens", resp.Usage.PromptTokens),
 		attribute.Int("llm.output_tokens", resp.Usage.CompletionTokens),
+		attribute.Int("llm.cached_input_tokens", cache.CachedTokens),
 		attribute.String("llm.stop_reason", stopReason),
 	)
 
-	blocks, err := c.parseResponse(ctx, &resp)
+	candidates, err := c.parseResponse(ctx, &resp)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to parse response")
 	}
-	return blocks, nil
+	return candidates, nil
 }
 
-func (c *Completer) parseResponse(ctx context.Context, resp *openai.ChatCompletionResponse) ([]*v1alpha1.Block, error) {
+// userMessage returns the user message. Images in the context are added as image parts of the message.
+func userMessage(ctx context.Context, message string) openai.ChatCompletionMessage {
+	images := llms.Images(ctx)
+	if len(images) == 0 {
+		return openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleUser,
+			Content: message,
+		}
+	}
+	parts := make([]openai.ChatMessagePart, 0, len(images)+1)
+	parts = append(parts, openai.ChatMessagePart{
+		Type: openai.ChatMessagePartTypeText,
+		Text: message,
+	})
+	for _, i := range images {
+		parts = append(parts, openai.ChatMessagePart{
+			Type:     openai.ChatMessagePartTypeImageURL,
+			ImageURL: &openai.ChatMessageImageURL{URL: i.DataURL()},
+		})
+	}
+	return openai.ChatCompletionMessage{
+		Role:         openai.ChatMessageRoleUser,
+		MultiContent: parts,
+	}
+}
+
+// parseResponse parses the blocks for each choice in the response. Choices without any content are skipped.
+func (c *Completer) parseResponse(ctx context.Context, resp *openai.ChatCompletionResponse) ([][]*v1alpha1.Block, error) {
 	log := logs.FromContext(ctx)
-	allBlocks := make([]*v1alpha1.Block, 0, 10)
+	candidates := make([][]*v1alpha1.Block, 0, len(resp.Choices))
 	for _, choice := range resp.Choices {
 		if choice.Message.Content == "" {
 			continue
@@ -118,7 +180,7 @@ func (c *Completer) parseResponse(ctx context.Context, resp *openai.ChatCompleti
 				Kind:     v1alpha1.BlockKind_MARKUP,
 				Contents: choice.Message.Content,
 			}
-			allBlocks = append(allBlocks, b)
+			candidates = append(candidates, []*v1alpha1.Block{b})
 			continue
 		}
 
@@ -127,7 +189,7 @@ func (c *Completer) parseResponse(ctx context.Context, resp *openai.ChatCompleti
 			return nil, errors.Wrapf(err, "Failed to set block ids")
 		}
 
-		allBlocks = append(allBlocks, blocks...)
+		candidates = append(candidates, blocks)
 	}
-	return allBlocks, nil
+	return candidates, nil
 }
@@ -0,0 +1,128 @@
+package oai
+
+import (
+	"context"
+	"time"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	"github.com/pkg/errors"
+	"github.com/sashabaranov/go-openai"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SupportsTools implements llms.ToolCompleter. Whether the model supports function calling depends on the provider
+// and the configuration; see config.ModelSupportsTools.
+func (c *Completer) SupportsTools() bool {
+	return c.supportsTools
+}
+
+// CompleteWithTools implements llms.ToolCompleter using OpenAI function calling.
+func (c *Completer) CompleteWithTools(ctx context.Context, systemPrompt string, messages []llms.Message, tools []llms.Tool) (*llms.Message, error) {
+	tp := tracer()
+	ctx, span := tp.Start(ctx, "CompleteWithTools", trace.WithAttributes(attribute.String("llm.model", c.config.GetModel()), attribute.String("llm.provider", string(c.provider))))
+	defer span.End()
+
+	oaiMessages := make([]openai.ChatCompletionMessage, 0, len(messages)+1)
+	oaiMessages = append(oaiMessages, openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleSystem,
+		Content: systemPrompt,
+	})
+	for _, m := range messages {
+		oaiMessages = append(oaiMessages, toOAIMessage(m))
+	}
+
+	request := openai.ChatCompletionRequest{
+		Model:       c.config.GetModel(),
+		Messages:    oaiMessages,
+		MaxTokens:   2000,
+		Temperature: temperature,
+	}
+	for _, t := range tools {
+		request.Tools = append(request.Tools, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			},
+		})
+	}
+
+	call := api.LLMCall{
+		Provider: c.provider,
+		Model:    c.config.GetModel(),
+	}
+	start := time.Now()
+	resp, err := c.client.CreateChatCompletion(ctx, request)
+	call.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		call.Error = err.Error()
+		logs.LogLLMCall(ctx, call, request, nil)
+		if ErrorIs(err, ContextLengthExceededCode) {
+			return nil, llms.ContextLengthExceededError{Cause: err}
+		}
+		return nil, errors.Wrapf(err, "CreateChatCompletion failed")
+	}
+
+	usage := api.LLMUsage{
+		InputTokens:  resp.Usage.PromptTokens,
+		OutputTokens: resp.Usage.CompletionTokens,
+		Model:        c.config.GetModel(),
+		Provider:     string(c.provider),
+	}
+	call.Usage = usage
+	logs.LogLLMCall(ctx, call, request, resp)
+	llms.RecordUsage(ctx, usage)
+
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("CreateChatCompletion returned no choices")
+	}
+	choice := resp.Choices[0]
+	span.SetAttributes(
+		attribute.Int("llm.input_tokens", resp.Usage.PromptTokens),
+		attribute.Int("llm.output_tokens", resp.Usage.CompletionTokens),
+		attribute.String("llm.stop_reason", string(choice.FinishReason)),
+	)
+
+	reply := &llms.Message{
+		Role:    llms.RoleAssistant,
+		Content: choice.Message.Content,
+	}
+	for _, call := range choice.Message.ToolCalls {
+		reply.ToolCalls = append(reply.ToolCalls, llms.ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
+	}
+	return reply, nil
+}
+
+func toOAIMessage(m llms.Message) openai.ChatCompletionMessage {
+	msg := openai.ChatCompletionMessage{
+		Content:    m.Content,
+		ToolCallID: m.ToolCallID,
+	}
+	switch m.Role {
+	case llms.RoleAssistant:
+		msg.Role = openai.ChatMessageRoleAssistant
+	case llms.RoleTool:
+		msg.Role = openai.ChatMessageRoleTool
+	default:
+		msg.Role = openai.ChatMessageRoleUser
+	}
+	for _, call := range m.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, openai.ToolCall{
+			ID:   call.ID,
+			Type: openai.ToolTypeFunction,
+			Function: openai.FunctionCall{
+				Name:      call.Name,
+				Arguments: call.Arguments,
+			},
+		})
+	}
+	return msg
+}
@@ -0,0 +1,408 @@
+// Package queue implements a durable work queue backed by a dbutil.KV.
+package queue
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	pendingPrefix = "pending/"
+	deadPrefix    = "dead/"
+)
+
+var (
+	depthGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "queue_depth",
+			Help: "Number of items in the queue including items waiting to be retried",
+		},
+		[]string{"queue"},
+	)
+
+	retryCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "queue_retries_total",
+			Help: "Number of times processing an item failed and it was scheduled to be retried",
+		},
+		[]string{"queue"},
+	)
+
+	deadLetterCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "queue_dead_letters_total",
+			Help: "Number of items moved to the dead letter store because they ran out of attempts",
+		},
+		[]string{"queue"},
+	)
+)
+
+// Options configure how failed items are retried.
+type Options struct {
+	// MaxAttempts is the number of times an item is tried before it is moved to the dead letter store.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. It doubles with each attempt up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultOptions returns the options used for any option that isn't set.
+func DefaultOptions() Options {
+	return Options{
+		MaxAttempts: 5,
+		BaseDelay:   time.Second,
+		MaxDelay:    5 * time.Minute,
+	}
+}
+
+// Queue is a durable work queue with at-least-once semantics. Items are strings identifying the work; e.g. a block
+// id. An item is persisted when it is added and only deleted once processing it succeeds so items that are pending
+// when the process stops are processed when the queue is next created.
+//
+// Like a workqueue, an item is only processed by a single worker at a time and adding an item that is already queued
+// is a no-op. If an item is added while it is being processed it is processed again after the worker is done.
+//
+// Workers call Get to get an item and then either Done if processing succeeded or Retry if it failed. Failed items
+// are retried with exponential backoff; once they run out of attempts they are moved to the dead letter store which
+// can be inspected with DeadLetters.
+type Queue struct {
+	db   dbutil.KV
+	name string
+	opts Options
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// items are all the items in the queue including those being processed and waiting to be retried.
+	items map[string]*logspb.QueueItem
+	// ready are the keys ready to be processed in the order they were added.
+	ready []string
+	// queued is the set of keys in ready or waiting to be retried.
+	queued     map[string]bool
+	processing map[string]bool
+	// dirty is the set of keys that were added again while they were being processed.
+	dirty        map[string]bool
+	timers       map[string]*time.Timer
+	shuttingDown bool
+}
+
+// New creates the queue with the given name and loads any items left in the database. Several queues can share a
+// database as long as they have different names. If db is nil the queue is kept in memory; this is intended for
+// tests.
+func New(db dbutil.KV, name string, opts Options) (*Queue, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return nil, errors.Errorf("Invalid queue name %q; names must be non-empty and can't contain /", name)
+	}
+	defaults := DefaultOptions()
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaults.MaxAttempts
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = defaults.BaseDelay
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = defaults.MaxDelay
+	}
+
+	if db == nil {
+		db = dbutil.NewMemKV()
+	}
+
+	q := &Queue{
+		db:         db,
+		name:       name,
+		opts:       opts,
+		items:      make(map[string]*logspb.QueueItem),
+		ready:      make([]string, 0, 10),
+		queued:     make(map[string]bool),
+		processing: make(map[string]bool),
+		dirty:      make(map[string]bool),
+		timers:     make(map[string]*time.Timer),
+	}
+	q.cond = sync.NewCond(&q.mu)
+
+	pending, err := listItems(db, pendingPrefix+name+"/")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to load pending items for queue %s", name)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	for _, item := range pending {
+		q.items[item.Key] = item
+		if next := item.GetNextAttemptTime(); next != nil && next.AsTime().After(now) {
+			q.scheduleLocked(item.Key, next.AsTime().Sub(now))
+		} else {
+			q.readyLocked(item.Key)
+		}
+	}
+	depthGauge.WithLabelValues(name).Set(float64(len(q.items)))
+	return q, nil
+}
+
+// Add adds an item to the queue. The item is persisted before Add returns.
+func (q *Queue) Add(key string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shuttingDown {
+		return errors.Errorf("Queue %s is shutting down; can't add %s", q.name, key)
+	}
+	if q.processing[key] {
+		q.dirty[key] = true
+		return nil
+	}
+	if q.queued[key] {
+		return nil
+	}
+	item := &logspb.QueueItem{
+		Queue:       q.name,
+		Key:         key,
+		EnqueueTime: timestamppb.Now(),
+	}
+	b, err := proto.Marshal(item)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to marshal item %s in queue %s", key, q.name)
+	}
+	// Delete any dead letter for the item so it doesn't linger after the item is added again.
+	batch := dbutil.NewBatch()
+	batch.Set([]byte(q.pendingKey(key)), b)
+	batch.Delete([]byte(deadKey(q.name, key)))
+	if err := q.db.Apply(batch); err != nil {
+		return errors.Wrapf(err, "Failed to persist item %s in queue %s", key, q.name)
+	}
+	q.items[key] = item
+	q.readyLocked(key)
+	depthGauge.WithLabelValues(q.name).Set(float64(len(q.items)))
+	return nil
+}
+
+// Get blocks until an item is ready and returns it. shutdown is true if the queue is shutting down in which case
+// the worker should exit.
+func (q *Queue) Get() (key string, shutdown bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.ready) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.shuttingDown {
+		return "", true
+	}
+	key = q.ready[0]
+	q.ready = q.ready[1:]
+	delete(q.queued, key)
+	q.processing[key] = true
+	return key, false
+}
+
+// Done marks the item as successfully processed and removes it from the queue.
+func (q *Queue) Done(key string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	defer q.cond.Broadcast()
+	delete(q.processing, key)
+
+	if q.dirty[key] {
+		// The item was added again while it was being processed so process it again.
+		delete(q.dirty, key)
+		item := q.items[key]
+		item.Attempts = 0
+		item.LastError = ""
+		item.NextAttemptTime = nil
+		if err := dbutil.SetProto(q.db, q.pendingKey(key), item); err != nil {
+			return errors.Wrapf(err, "Failed to persist item %s in queue %s", key, q.name)
+		}
+		if !q.shuttingDown {
+			q.readyLocked(key)
+		}
+		return nil
+	}
+
+	delete(q.items, key)
+	depthGauge.WithLabelValues(q.name).Set(float64(len(q.items)))
+	if err := q.db.Delete([]byte(q.pendingKey(key))); err != nil {
+		return errors.Wrapf(err, "Failed to delete item %s from queue %s", key, q.name)
+	}
+	return nil
+}
+
+// Retry marks the item as failed. It is retried after a backoff unless it has run out of attempts in which case it
+// is moved to the dead letter store.
+func (q *Queue) Retry(key string, cause error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	defer q.cond.Broadcast()
+	delete(q.processing, key)
+	// The item is going to be processed again anyway.
+	delete(q.dirty, key)
+
+	item, ok := q.items[key]
+	if !ok {
+		return errors.Errorf("Item %s isn't in queue %s", key, q.name)
+	}
+	item.Attempts++
+	if cause != nil {
+		item.LastError = cause.Error()
+	}
+
+	if int(item.Attempts) >= q.opts.MaxAttempts {
+		item.DeadLetterTime = timestamppb.Now()
+		item.NextAttemptTime = nil
+		b, err := proto.Marshal(item)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to marshal item %s in queue %s", key, q.name)
+		}
+		batch := dbutil.NewBatch()
+		batch.Set([]byte(deadKey(q.name, key)), b)
+		batch.Delete([]byte(q.pendingKey(key)))
+		if err := q.db.Apply(batch); err != nil {
+			return errors.Wrapf(err, "Failed to move item %s in queue %s to the dead letters", key, q.name)
+		}
+		// Only drop the item once it is durably in the dead letter store; otherwise it stays in the queue.
+		delete(q.items, key)
+		depthGauge.WithLabelValues(q.name).Set(float64(len(q.items)))
+		deadLetterCounter.WithLabelValues(q.name).Inc()
+		return nil
+	}
+
+	retryCounter.WithLabelValues(q.name).Inc()
+	delay := q.backoff(item.Attempts)
+	item.NextAttemptTime = timestamppb.New(time.Now().Add(delay))
+	if err := dbutil.SetProto(q.db, q.pendingKey(key), item); err != nil {
+		return errors.Wrapf(err, "Failed to persist item %s in queue %s", key, q.name)
+	}
+	if !q.shuttingDown {
+		q.scheduleLocked(key, delay)
+	}
+	return nil
+}
+
+// Len returns the number of items in the queue including items being processed and waiting to be retried.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// ShuttingDown returns true if ShutDown has been called.
+func (q *Queue) ShuttingDown() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.shuttingDown
+}
+
+// ShutDown stops the queue. Workers blocked in Get return immediately. ShutDown waits for the items being processed
+// to be marked Done or Retry; items that haven't been processed stay in the database and are processed when the
+// queue is next created.
+func (q *Queue) ShutDown() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.shuttingDown = true
+	for key, t := range q.timers {
+		t.Stop()
+		delete(q.timers, key)
+	}
+	q.cond.Broadcast()
+	for len(q.processing) > 0 {
+		q.cond.Wait()
+	}
+}
+
+func (q *Queue) readyLocked(key string) {
+	if q.queued[key] {
+		return
+	}
+	q.queued[key] = true
+	q.ready = append(q.ready, key)
+	q.cond.Signal()
+}
+
+func (q *Queue) scheduleLocked(key string, delay time.Duration) {
+	q.queued[key] = true
+	q.timers[key] = time.AfterFunc(delay, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		delete(q.timers, key)
+		if q.shuttingDown {
+			return
+		}
+		// readyLocked skips keys that are already queued.
+		delete(q.queued, key)
+		q.readyLocked(key)
+	})
+}
+
+func (q *Queue) backoff(attempts int32) time.Duration {
+	delay := q.opts.BaseDelay
+	for i := int32(1); i < attempts && delay < q.opts.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > q.opts.MaxDelay {
+		delay = q.opts.MaxDelay
+	}
+	return delay
+}
+
+func (q *Queue) pendingKey(key string) string {
+	return pendingPrefix + q.name + "/" + key
+}
+
+func deadKey(name string, key string) string {
+	return deadPrefix + name + "/" + key
+}
+
+// DeadLetters returns the items in the dead letter store of the named queue. If name is empty the dead letters of
+// all the queues in the database are returned.
+func DeadLetters(db dbutil.KV, name string) ([]*logspb.QueueItem, error) {
+	prefix := deadPrefix
+	if name != "" {
+		prefix = deadPrefix + name + "/"
+	}
+	return listItems(db, prefix)
+}
+
+// Requeue moves an item from the dead letter store back to the queue. Its attempts are reset and it is processed
+// when the queue is next created. The queue must not be running in another process.
+func Requeue(db dbutil.KV, name string, key string) error {
+	item := &logspb.QueueItem{}
+	if err := dbutil.GetProto(db, deadKey(name, key), item); err != nil {
+		return errors.Wrapf(err, "Failed to get dead letter %s in queue %s", key, name)
+	}
+	item.Attempts = 0
+	item.NextAttemptTime = nil
+	item.DeadLetterTime = nil
+	item.EnqueueTime = timestamppb.Now()
+	b, err := proto.Marshal(item)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to marshal item %s in queue %s", key, name)
+	}
+
+	batch := dbutil.NewBatch()
+	batch.Set([]byte(pendingPrefix+name+"/"+key), b)
+	batch.Delete([]byte(deadKey(name, key)))
+	if err := db.Apply(batch); err != nil {
+		return errors.Wrapf(err, "Failed to requeue item %s in queue %s", key, name)
+	}
+	return nil
+}
+
+// listItems returns the items whose keys start with prefix.
+func listItems(db dbutil.KV, prefix string) ([]*logspb.QueueItem, error) {
+	items := make([]*logspb.QueueItem, 0, 10)
+	err := db.Scan([]byte(prefix), func(key []byte, value []byte) error {
+		item := &logspb.QueueItem{}
+		if err := proto.Unmarshal(value, item); err != nil {
+			return errors.Wrapf(err, "Failed to unmarshal queue item %s", string(key))
+		}
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
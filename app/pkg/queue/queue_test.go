@@ -0,0 +1,202 @@
+package queue
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlewi/foyle/app/pkg/dbutil"
+	"github.com/jlewi/monogo/helpers"
+	"github.com/pkg/errors"
+)
+
+func Test_QueueIsDurable(t *testing.T) {
+	db, err := dbutil.OpenPebbleKV(filepath.Join(t.TempDir(), "queues"), false)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer helpers.DeferIgnoreError(db.Close)
+
+	q, err := New(db, "blocks", Options{})
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	for _, k := range []string{"a", "b", "a"} {
+		if err := q.Add(k); err != nil {
+			t.Fatalf("Failed to add %s: %v", k, err)
+		}
+	}
+	if q.Len() != 2 {
+		t.Errorf("Expected 2 items; got %d", q.Len())
+	}
+	key, shutdown := q.Get()
+	if shutdown || key != "a" {
+		t.Fatalf("Expected a; got %q shutdown=%v", key, shutdown)
+	}
+	if err := q.Done(key); err != nil {
+		t.Fatalf("Done failed: %v", err)
+	}
+	q.ShutDown()
+	if _, shutdown := q.Get(); !shutdown {
+		t.Errorf("Expected Get to return shutdown")
+	}
+
+	// Items that weren't processed are loaded when the queue is recreated. Other queues in the database aren't.
+	other, err := New(db, "sessions", Options{})
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	if other.Len() != 0 {
+		t.Errorf("Expected the sessions queue to be empty; got %d", other.Len())
+	}
+	q, err = New(db, "blocks", Options{})
+	if err != nil {
+		t.Fatalf("Failed to recreate queue: %v", err)
+	}
+	key, _ = q.Get()
+	if key != "b" {
+		t.Errorf("Expected b; got %q", key)
+	}
+	if err := q.Done(key); err != nil {
+		t.Fatalf("Done failed: %v", err)
+	}
+	q.ShutDown()
+	other.ShutDown()
+}
+
+func Test_QueueRetryAndDeadLetters(t *testing.T) {
+	db, err := dbutil.OpenPebbleKV(filepath.Join(t.TempDir(), "queues"), false)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer helpers.DeferIgnoreError(db.Close)
+
+	q, err := New(db, "blocks", Options{MaxAttempts: 2, BaseDelay: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	if err := q.Add("a"); err != nil {
+		t.Fatalf("Failed to add: %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		key, _ := q.Get()
+		if key != "a" {
+			t.Fatalf("Attempt %d: expected a; got %q", i, key)
+		}
+		if err := q.Retry(key, errors.New("some error")); err != nil {
+			t.Fatalf("Retry failed: %v", err)
+		}
+	}
+	if d := time.Since(start); d < 10*time.Millisecond {
+		t.Errorf("Expected the retry to be delayed; it took %v", d)
+	}
+	if q.Len() != 0 {
+		t.Errorf("Expected the item to be removed from the queue; got %d items", q.Len())
+	}
+	q.ShutDown()
+
+	dead, err := DeadLetters(db, "")
+	if err != nil {
+		t.Fatalf("Failed to list dead letters: %v", err)
+	}
+	if len(dead) != 1 {
+		t.Fatalf("Expected 1 dead letter; got %d", len(dead))
+	}
+	if dead[0].Key != "a" || dead[0].Queue != "blocks" || dead[0].Attempts != 2 || dead[0].LastError != "some error" || dead[0].DeadLetterTime == nil {
+		t.Errorf("Unexpected dead letter %v", dead[0])
+	}
+
+	if err := Requeue(db, "blocks", "a"); err != nil {
+		t.Fatalf("Requeue failed: %v", err)
+	}
+	dead, err = DeadLetters(db, "blocks")
+	if err != nil {
+		t.Fatalf("Failed to list dead letters: %v", err)
+	}
+	if len(dead) != 0 {
+		t.Errorf("Expected no dead letters after requeue; got %d", len(dead))
+	}
+	q, err = New(db, "blocks", Options{})
+	if err != nil {
+		t.Fatalf("Failed to recreate queue: %v", err)
+	}
+	if key, _ := q.Get(); key != "a" {
+		t.Errorf("Expected the requeued item; got %q", key)
+	}
+	if err := q.Done("a"); err != nil {
+		t.Fatalf("Done failed: %v", err)
+	}
+	q.ShutDown()
+}
+
+func Test_QueueAddDeletesDeadLetter(t *testing.T) {
+	db, err := dbutil.OpenPebbleKV(filepath.Join(t.TempDir(), "queues"), false)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer helpers.DeferIgnoreError(db.Close)
+
+	q, err := New(db, "blocks", Options{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	defer q.ShutDown()
+	if err := q.Add("a"); err != nil {
+		t.Fatalf("Failed to add: %v", err)
+	}
+	key, _ := q.Get()
+	if err := q.Retry(key, errors.New("some error")); err != nil {
+		t.Fatalf("Retry failed: %v", err)
+	}
+	if dead, err := DeadLetters(db, "blocks"); err != nil || len(dead) != 1 {
+		t.Fatalf("Expected 1 dead letter; got %d, err %v", len(dead), err)
+	}
+
+	// Adding the item again should remove the stale dead letter.
+	if err := q.Add("a"); err != nil {
+		t.Fatalf("Failed to add: %v", err)
+	}
+	dead, err := DeadLetters(db, "blocks")
+	if err != nil {
+		t.Fatalf("Failed to list dead letters: %v", err)
+	}
+	if len(dead) != 0 {
+		t.Errorf("Expected no dead letters after the item was added again; got %d", len(dead))
+	}
+	if key, _ := q.Get(); key != "a" {
+		t.Errorf("Expected the re-added item; got %q", key)
+	}
+	if err := q.Done("a"); err != nil {
+		t.Fatalf("Done failed: %v", err)
+	}
+}
+
+func Test_QueueAddWhileProcessing(t *testing.T) {
+	q, err := New(nil, "blocks", Options{})
+	if err != nil {
+		t.Fatalf("Failed to create queue: %v", err)
+	}
+	defer q.ShutDown()
+	if err := q.Add("a"); err != nil {
+		t.Fatalf("Failed to add: %v", err)
+	}
+	key, _ := q.Get()
+	// Adding an item that is being processed means it is processed again once it's done.
+	if err := q.Add(key); err != nil {
+		t.Fatalf("Failed to add: %v", err)
+	}
+	if err := q.Done(key); err != nil {
+		t.Fatalf("Done failed: %v", err)
+	}
+	if key, _ := q.Get(); key != "a" {
+		t.Errorf("Expected a to be processed again; got %q", key)
+	}
+	if err := q.Done("a"); err != nil {
+		t.Fatalf("Done failed: %v", err)
+	}
+	if q.Len() != 0 {
+		t.Errorf("Expected the queue to be empty; got %d", q.Len())
+	}
+}
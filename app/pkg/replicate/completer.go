@@ -15,10 +15,15 @@ const (
 
 func NewCompleter(cfg config.Config, client *openai.Client) (*oai.Completer, error) {
 	log := zapr.NewLogger(zap.L())
-	if cfg.Agent == nil {
-		cfg.Agent = &api.AgentConfig{}
+	// Copy the agent config so setting the defaults doesn't modify the caller's config.
+	agent := api.AgentConfig{}
+	if cfg.Agent != nil {
+		agent = *cfg.Agent
 	}
-	if cfg.Agent == nil || cfg.Agent.Model == "" {
+	cfg.Agent = &agent
+	// The OpenAI completer uses the provider to report usage and to decide whether the model supports tools.
+	cfg.Agent.ModelProvider = api.ModelProviderReplicate
+	if cfg.Agent.Model == "" {
 
 		log.Info("No model specified; using default model", "model", defaultModel)
 		cfg.Agent.Model = "meta/meta-llama-3-8b-instruct"
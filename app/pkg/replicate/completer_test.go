@@ -7,6 +7,7 @@ import (
 
 	"github.com/jlewi/foyle/app/pkg/config"
 	"github.com/jlewi/foyle/app/pkg/docs"
+	"github.com/sashabaranov/go-openai"
 )
 
 func Test_ReplicateCompleter(t *testing.T) {
@@ -43,3 +44,38 @@ func Test_ReplicateCompleter(t *testing.T) {
 
 	t.Logf("Result:\n%v", docs.BlocksToMarkdown(result))
 }
+
+func Test_CompleterSupportsTools(t *testing.T) {
+	type testCase struct {
+		name     string
+		cfg      config.Config
+		expected bool
+	}
+
+	cases := []testCase{
+		{
+			name:     "default",
+			cfg:      config.Config{},
+			expected: false,
+		},
+		{
+			name: "enabled",
+			cfg: config.Config{
+				Replicate: &config.ReplicateConfig{SupportsTools: true},
+			},
+			expected: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			completer, err := NewCompleter(c.cfg, openai.NewClient(""))
+			if err != nil {
+				t.Fatalf("Error creating completer; %v", err)
+			}
+			if completer.SupportsTools() != c.expected {
+				t.Errorf("Expected SupportsTools to be %v; got %v", c.expected, completer.SupportsTools())
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/events"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/app/pkg/logs"
+	"github.com/jlewi/foyle/app/pkg/logs/matchers"
+	logspb "github.com/jlewi/foyle/protos/go/foyle/logs"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// Names of the routes recorded in the LLMSpan
+	RoutePrimary  = "primary"
+	RouteFallback = "fallback"
+	RouteSmall    = "small"
+)
+
+var (
+	attemptsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "router_attempts_total",
+		Help: "Number of calls the router made to each model broken down by route and status"},
+		[]string{"model", "route", "status"},
+	)
+)
+
+// Route is a model the router can send requests to.
+type Route struct {
+	Model     string
+	Provider  api.ModelProvider
+	Completer llms.Completer
+}
+
+type target struct {
+	Route
+	name string
+}
+
+// Completer routes completions across several models. Requests are sent to the small model if they are short
+// enough and to the primary model otherwise. If a model returns an error, including a timeout or exceeding the
+// context length, the request is retried with the primary model and then each fallback in order.
+type Completer struct {
+	primary       target
+	fallbacks     []target
+	small         *target
+	smallMaxChars int
+	timeout       time.Duration
+}
+
+// NewCompleter creates a new router. small is optional.
+func NewCompleter(cfg api.RouterConfig, primary Route, fallbacks []Route, small *Route) (*Completer, error) {
+	if primary.Completer == nil {
+		return nil, errors.New("Primary completer is required")
+	}
+	c := &Completer{
+		primary:       target{Route: primary, name: RoutePrimary},
+		fallbacks:     make([]target, 0, len(fallbacks)),
+		smallMaxChars: cfg.SmallMaxChars,
+		timeout:       time.Duration(cfg.TimeoutSeconds) * time.Second,
+	}
+	for i, f := range fallbacks {
+		if f.Completer == nil {
+			return nil, errors.Errorf("Completer for fallback %d is nil", i)
+		}
+		c.fallbacks = append(c.fallbacks, target{Route: f, name: RouteFallback})
+	}
+	if small != nil {
+		if small.Completer == nil {
+			return nil, errors.New("Completer for the small model is nil")
+		}
+		if cfg.SmallMaxChars <= 0 {
+			return nil, errors.New("SmallMaxChars must be positive when a small model is configured")
+		}
+		c.small = &target{Route: *small, name: RouteSmall}
+	}
+	return c, nil
+}
+
+func (c *Completer) Complete(ctx context.Context, systemPrompt string, message string) ([]*v1alpha1.Block, error) {
+	var blocks []*v1alpha1.Block
+	err := c.route(ctx, len(systemPrompt)+len(message), func(ctx context.Context, r Route) error {
+		var err error
+		blocks, err = r.Completer.Complete(ctx, systemPrompt, message)
+		return err
+	})
+	return blocks, err
+}
+
+// CompleteN generates all the candidates with the same model.
+func (c *Completer) CompleteN(ctx context.Context, systemPrompt string, message string, n int) ([][]*v1alpha1.Block, error) {
+	var candidates [][]*v1alpha1.Block
+	err := c.route(ctx, len(systemPrompt)+len(message), func(ctx context.Context, r Route) error {
+		var err error
+		candidates, err = llms.CompleteN(ctx, r.Completer, systemPrompt, message, n)
+		return err
+	})
+	return candidates, err
+}
+
+// SupportsTools returns true if any of the models supports tools; CompleteWithTools skips the ones that don't.
+func (c *Completer) SupportsTools() bool {
+	targets := append([]target{c.primary}, c.fallbacks...)
+	if c.small != nil {
+		targets = append(targets, *c.small)
+	}
+	for _, t := range targets {
+		if llms.SupportsTools(t.Completer) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompleteWithTools routes a conversation with tools. Models whose completer doesn't support tools are skipped.
+func (c *Completer) CompleteWithTools(ctx context.Context, systemPrompt string, messages []llms.Message, tools []llms.Tool) (*llms.Message, error) {
+	size := len(systemPrompt)
+	for _, m := range messages {
+		size += len(m.Content)
+	}
+	var reply *llms.Message
+	err := c.route(ctx, size, func(ctx context.Context, r Route) error {
+		tc, ok := r.Completer.(llms.ToolCompleter)
+		if !ok || !tc.SupportsTools() {
+			return errors.Wrapf(llms.ErrToolsNotSupported, "Model %s doesn't support tool calling", r.Model)
+		}
+		var err error
+		reply, err = tc.CompleteWithTools(ctx, systemPrompt, messages, tools)
+		return err
+	})
+	return reply, err
+}
+
+// targets returns the models to try in order for a request of the given size.
+func (c *Completer) targets(size int) []target {
+	targets := make([]target, 0, len(c.fallbacks)+2)
+	if c.small != nil && size <= c.smallMaxChars {
+		targets = append(targets, *c.small)
+	}
+	targets = append(targets, c.primary)
+	return append(targets, c.fallbacks...)
+}
+
+// route calls complete with each target until one succeeds. The chosen model is logged as an LLMSpan so the
+// analyzer can add it to the trace. If every model fails the error from the last one is returned.
+func (c *Completer) route(ctx context.Context, size int, complete func(ctx context.Context, r Route) error) error {
+	log := logs.FromContext(ctx)
+	var lastErr error
+	attempts := 0
+	for _, t := range c.targets(size) {
+		attempts++
+		err := c.attempt(ctx, t, complete)
+		if err == nil {
+			attemptsCounter.WithLabelValues(t.Model, t.name, "ok").Inc()
+			span := &logspb.LLMSpan{
+				Model:    t.Model,
+				Route:    t.name,
+				Attempts: int32(attempts),
+			}
+			log.Info("Router completion", "model", t.Model, "provider", t.Provider, "route", t.name, "attempts", attempts, logs.ZapProto(matchers.LLMRouteField, span))
+			events.Publish(ctx, &events.Event{Span: &logspb.Span{Data: &logspb.Span_Llm{Llm: span}}})
+			return nil
+		}
+		attemptsCounter.WithLabelValues(t.Model, t.name, "error").Inc()
+		lastErr = err
+
+		// Don't fall back if the caller cancelled the request; e.g. because a newer request superseded it.
+		if ctx.Err() != nil {
+			return err
+		}
+		log.Info("Model failed; falling back to the next model", "model", t.Model, "provider", t.Provider, "route", t.name, "err", err)
+	}
+
+	log.Error(lastErr, "Every model failed to generate a completion", "attempts", attempts)
+	return lastErr
+}
+
+func (c *Completer) attempt(ctx context.Context, t target, complete func(ctx context.Context, r Route) error) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	return complete(ctx, t.Route)
+}
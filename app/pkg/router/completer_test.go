@@ -0,0 +1,176 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jlewi/foyle/app/api"
+	"github.com/jlewi/foyle/app/pkg/llms"
+	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+// fakeCompleter returns a block containing its name or err. If delay is set it waits that long or until the
+// context is done.
+type fakeCompleter struct {
+	name  string
+	err   error
+	delay time.Duration
+	calls int
+}
+
+func (f *fakeCompleter) Complete(ctx context.Context, systemPrompt string, message string) ([]*v1alpha1.Block, error) {
+	f.calls++
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []*v1alpha1.Block{{Kind: v1alpha1.BlockKind_CODE, Contents: f.name}}, nil
+}
+
+func Test_Complete(t *testing.T) {
+	type testCase struct {
+		name      string
+		cfg       api.RouterConfig
+		primary   *fakeCompleter
+		fallbacks []*fakeCompleter
+		small     *fakeCompleter
+		message   string
+		expected  string
+		// expectedCalls is the number of calls to the primary, the fallbacks and the small model in that order.
+		expectedCalls []int
+	}
+
+	cases := []testCase{
+		{
+			name:          "primary",
+			primary:       &fakeCompleter{name: "primary"},
+			fallbacks:     []*fakeCompleter{{name: "fallback"}},
+			message:       "hello",
+			expected:      "primary",
+			expectedCalls: []int{1, 0},
+		},
+		{
+			name:          "fallback-on-error",
+			primary:       &fakeCompleter{name: "primary", err: errors.New("service unavailable")},
+			fallbacks:     []*fakeCompleter{{name: "fallback1", err: llms.ContextLengthExceededError{Cause: errors.New("too long")}}, {name: "fallback2"}},
+			message:       "hello",
+			expected:      "fallback2",
+			expectedCalls: []int{1, 1, 1},
+		},
+		{
+			name:          "fallback-on-timeout",
+			cfg:           api.RouterConfig{TimeoutSeconds: 1},
+			primary:       &fakeCompleter{name: "primary", delay: time.Minute},
+			fallbacks:     []*fakeCompleter{{name: "fallback"}},
+			message:       "hello",
+			expected:      "fallback",
+			expectedCalls: []int{1, 1},
+		},
+		{
+			name:          "small",
+			cfg:           api.RouterConfig{SmallMaxChars: 10},
+			primary:       &fakeCompleter{name: "primary"},
+			small:         &fakeCompleter{name: "small"},
+			message:       "hello",
+			expected:      "small",
+			expectedCalls: []int{0, 1},
+		},
+		{
+			name:          "large",
+			cfg:           api.RouterConfig{SmallMaxChars: 10},
+			primary:       &fakeCompleter{name: "primary"},
+			small:         &fakeCompleter{name: "small"},
+			message:       "a message that is too long for the small model",
+			expected:      "primary",
+			expectedCalls: []int{1, 0},
+		},
+		{
+			name:          "small-falls-back-to-primary",
+			cfg:           api.RouterConfig{SmallMaxChars: 10},
+			primary:       &fakeCompleter{name: "primary"},
+			small:         &fakeCompleter{name: "small", err: errors.New("service unavailable")},
+			message:       "hello",
+			expected:      "primary",
+			expectedCalls: []int{1, 1},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fakes := []*fakeCompleter{c.primary}
+			fallbacks := make([]Route, 0, len(c.fallbacks))
+			for _, f := range c.fallbacks {
+				fallbacks = append(fallbacks, Route{Model: f.name, Completer: f})
+				fakes = append(fakes, f)
+			}
+			var small *Route
+			if c.small != nil {
+				small = &Route{Model: c.small.name, Completer: c.small}
+				fakes = append(fakes, c.small)
+			}
+
+			r, err := NewCompleter(c.cfg, Route{Model: c.primary.name, Completer: c.primary}, fallbacks, small)
+			if err != nil {
+				t.Fatalf("Failed to create router: %v", err)
+			}
+
+			blocks, err := r.Complete(context.Background(), "", c.message)
+			if err != nil {
+				t.Fatalf("Complete failed: %v", err)
+			}
+			if len(blocks) != 1 || blocks[0].GetContents() != c.expected {
+				t.Errorf("Expected a completion from %s; got %v", c.expected, blocks)
+			}
+
+			calls := make([]int, 0, len(fakes))
+			for _, f := range fakes {
+				calls = append(calls, f.calls)
+			}
+			if d := cmp.Diff(c.expectedCalls, calls); d != "" {
+				t.Errorf("Unexpected calls:\n%s", d)
+			}
+		})
+	}
+}
+
+func Test_CompleteAllFail(t *testing.T) {
+	lastErr := llms.ContextLengthExceededError{Cause: errors.New("too long")}
+	primary := &fakeCompleter{name: "primary", err: errors.New("service unavailable")}
+	fallback := &fakeCompleter{name: "fallback", err: lastErr}
+	r, err := NewCompleter(api.RouterConfig{}, Route{Model: "primary", Completer: primary}, []Route{{Model: "fallback", Completer: fallback}}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	_, err = r.Complete(context.Background(), "", "hello")
+	if !errors.As(err, &llms.ContextLengthExceededError{}) {
+		t.Errorf("Expected the error from the last model; got %v", err)
+	}
+}
+
+func Test_CompleteCancelled(t *testing.T) {
+	primary := &fakeCompleter{name: "primary", delay: time.Minute}
+	fallback := &fakeCompleter{name: "fallback"}
+	r, err := NewCompleter(api.RouterConfig{}, Route{Model: "primary", Completer: primary}, []Route{{Model: "fallback", Completer: fallback}}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.Complete(ctx, "", "hello"); err == nil {
+		t.Fatalf("Expected an error when the request is cancelled")
+	}
+	if fallback.calls != 0 {
+		t.Errorf("The router shouldn't fall back when the request is cancelled")
+	}
+}
@@ -0,0 +1,3 @@
+// Package router routes completions across multiple models; falling back to other models when a model fails
+// and optionally sending short requests to a smaller model.
+package router
@@ -72,9 +72,13 @@ func BlockOutputToCellOutput(output *v1alpha1.BlockOutput) (*parserv1.CellOutput
 	}
 
 	for _, oi := range output.Items {
+		data := oi.Data
+		if len(data) == 0 {
+			data = []byte(oi.TextData)
+		}
 		boi := &parserv1.CellOutputItem{
 			Mime: oi.Mime,
-			Data: []byte(oi.TextData),
+			Data: data,
 		}
 		coutput.Items = append(coutput.Items, boi)
 	}
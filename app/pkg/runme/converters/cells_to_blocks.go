@@ -1,6 +1,9 @@
 package converters
 
 import (
+	"strings"
+	"unicode/utf8"
+
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1"
 	"github.com/pkg/errors"
 	parserv1 "github.com/stateful/runme/v3/pkg/api/gen/proto/go/runme/parser/v1"
@@ -100,6 +103,16 @@ func CellKindToBlockKind(kind parserv1.CellKind) v1alpha1.BlockKind {
 	}
 }
 
+// IsBinaryMime returns true if output items with the mime type contain binary data; e.g. images.
+func IsBinaryMime(mime string) bool {
+	mime = strings.ToLower(mime)
+	return strings.HasPrefix(mime, "image/") && !strings.HasPrefix(mime, "image/svg") ||
+		strings.HasPrefix(mime, "audio/") ||
+		strings.HasPrefix(mime, "video/") ||
+		mime == "application/octet-stream" ||
+		mime == "application/pdf"
+}
+
 func CellOutputToBlockOutput(output *parserv1.CellOutput) (*v1alpha1.BlockOutput, error) {
 	if output == nil {
 		return nil, errors.New("CellOutput is nil")
@@ -111,8 +124,13 @@ func CellOutputToBlockOutput(output *parserv1.CellOutput) (*v1alpha1.BlockOutput
 
 	for _, oi := range output.Items {
 		boi := &v1alpha1.BlockOutputItem{
-			Mime:     oi.Mime,
-			TextData: string(oi.Data),
+			Mime: oi.Mime,
+		}
+		// Proto strings must be valid UTF-8 so binary data such as images is stored as bytes.
+		if IsBinaryMime(oi.Mime) || !utf8.Valid(oi.Data) {
+			boi.Data = oi.Data
+		} else {
+			boi.TextData = string(oi.Data)
 		}
 		boutput.Items = append(boutput.Items, boi)
 	}
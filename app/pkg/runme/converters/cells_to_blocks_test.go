@@ -118,6 +118,55 @@ var (
 				},
 			},
 		},
+		{
+			// Binary outputs such as images are stored as bytes.
+			name: "image",
+			Notebook: &parserv1.Notebook{
+				Cells: []*parserv1.Cell{
+					{
+						Metadata: map[string]string{
+							"id": "1234",
+						},
+						Kind:       parserv1.CellKind_CELL_KIND_CODE,
+						LanguageId: "python",
+						Value:      "plot()",
+						Outputs: []*parserv1.CellOutput{
+							{
+								Items: []*parserv1.CellOutputItem{
+									{
+										Data: []byte{0x89, 'P', 'N', 'G', 0xff},
+										Mime: "image/png",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Doc: &v1alpha1.Doc{
+				Blocks: []*v1alpha1.Block{
+					{
+						Id:       "1234",
+						Language: "python",
+						Contents: "plot()",
+						Metadata: map[string]string{
+							"id": "1234",
+						},
+						Kind: v1alpha1.BlockKind_CODE,
+						Outputs: []*v1alpha1.BlockOutput{
+							{
+								Items: []*v1alpha1.BlockOutputItem{
+									{
+										Data: []byte{0x89, 'P', 'N', 'G', 0xff},
+										Mime: "image/png",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 )
 
@@ -17,12 +17,11 @@ import (
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
-	"github.com/cockroachdb/pebble"
-
 	"github.com/jlewi/foyle/app/pkg/eval"
 	"github.com/jlewi/foyle/protos/go/foyle/v1alpha1/v1alpha1connect"
 
 	"github.com/jlewi/foyle/app/pkg/analyze"
+	"github.com/jlewi/foyle/app/pkg/dbutil"
 	"github.com/jlewi/foyle/app/pkg/logsviewer"
 	"github.com/maxence-charriere/go-app/v9/pkg/app"
 
@@ -64,7 +63,7 @@ type Server struct {
 }
 
 // NewServer creates a new server
-func NewServer(config config.Config, blocksDB *pebble.DB, agent *agent.Agent, tracesDB *pebble.DB, analyzer *analyze.Analyzer, sessManager *analyze.SessionsManager) (*Server, error) {
+func NewServer(config config.Config, blocksDB dbutil.KV, agent *agent.Agent, tracesDB dbutil.KV, analyzer *analyze.Analyzer, sessManager *analyze.SessionsManager) (*Server, error) {
 	e, err := executor.NewExecutor(config)
 	if err != nil {
 		return nil, err
@@ -161,6 +160,14 @@ func (s *Server) createGinEngine() error {
 	log.Info("Setting up sessions service", "path", apiPrefix+"/"+sessSvcPath)
 	router.Any(apiPrefix+"/"+sessSvcPath+"*any", gin.WrapH(http.StripPrefix("/"+apiPrefix, sessSvcHandler)))
 
+	statsSvc, err := analyze.NewStatsService(s.sessManager)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create StatsService")
+	}
+	statsSvcPath, statsSvcHandler := logspbconnect.NewStatsServiceHandler(statsSvc, connect.WithInterceptors(interceptors...))
+	log.Info("Setting up stats service", "path", apiPrefix+"/"+statsSvcPath)
+	router.Any(apiPrefix+"/"+statsSvcPath+"*any", gin.WrapH(http.StripPrefix("/"+apiPrefix, statsSvcHandler)))
+
 	cSvc, err := docs.NewConvertersService()
 	if err != nil {
 		return errors.Wrapf(err, "Failed to create ConvertersService")
@@ -68,5 +68,8 @@ func (m *BlockLog) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncoder) e
 	keyName = "suggestion_status" // field suggestion_status = 10
 	enc.AddString(keyName, m.SuggestionStatus.String())
 
+	keyName = "candidate_rank" // field candidate_rank = 11
+	enc.AddInt32(keyName, m.CandidateRank)
+
 	return nil
 }
@@ -77,6 +77,20 @@ func (m *Session) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncoder) er
 		return nil
 	}))
 
+	keyName = "total_cost_usd" // field total_cost_usd = 9
+	enc.AddFloat64(keyName, m.TotalCostUsd)
+
+	keyName = "model" // field model = 10
+	enc.AddString(keyName, m.Model)
+
+	keyName = "rag_enabled" // field rag_enabled = 11
+	enc.AddBool(keyName, m.RagEnabled)
+
+	keyName = "accept_time" // field accept_time = 12
+	if t, err := github_com_golang_protobuf_ptypes.Timestamp(m.AcceptTime); err == nil {
+		enc.AddTime(keyName, t)
+	}
+
 	return nil
 }
 
@@ -121,6 +135,46 @@ func (m *ListSessionsRequest) MarshalLogObject(enc go_uber_org_zap_zapcore.Objec
 		return nil
 	}
 
+	keyName = "page_size" // field page_size = 1
+	enc.AddInt32(keyName, m.PageSize)
+
+	keyName = "page_token" // field page_token = 2
+	enc.AddString(keyName, m.PageToken)
+
+	keyName = "start_time" // field start_time = 3
+	if t, err := github_com_golang_protobuf_ptypes.Timestamp(m.StartTime); err == nil {
+		enc.AddTime(keyName, t)
+	}
+
+	keyName = "end_time" // field end_time = 4
+	if t, err := github_com_golang_protobuf_ptypes.Timestamp(m.EndTime); err == nil {
+		enc.AddTime(keyName, t)
+	}
+
+	keyName = "notebook_uri" // field notebook_uri = 5
+	enc.AddString(keyName, m.NotebookUri)
+
+	keyName = "suggestion_status" // field suggestion_status = 6
+	enc.AddString(keyName, m.SuggestionStatus.String())
+
+	keyName = "execution" // field execution = 7
+	enc.AddString(keyName, m.Execution.String())
+
+	keyName = "min_input_tokens" // field min_input_tokens = 8
+	enc.AddInt32(keyName, m.MinInputTokens)
+
+	keyName = "max_input_tokens" // field max_input_tokens = 9
+	enc.AddInt32(keyName, m.MaxInputTokens)
+
+	keyName = "min_output_tokens" // field min_output_tokens = 10
+	enc.AddInt32(keyName, m.MinOutputTokens)
+
+	keyName = "max_output_tokens" // field max_output_tokens = 11
+	enc.AddInt32(keyName, m.MaxOutputTokens)
+
+	keyName = "query" // field query = 12
+	enc.AddString(keyName, m.Query)
+
 	return nil
 }
 
@@ -146,6 +200,9 @@ func (m *ListSessionsResponse) MarshalLogObject(enc go_uber_org_zap_zapcore.Obje
 		return nil
 	}))
 
+	keyName = "next_page_token" // field next_page_token = 2
+	enc.AddString(keyName, m.NextPageToken)
+
 	return nil
 }
 
@@ -84,6 +84,18 @@ func (m *Trace) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncoder) erro
 		return nil
 	}))
 
+	keyName = "input_tokens" // field input_tokens = 10
+	enc.AddInt32(keyName, m.InputTokens)
+
+	keyName = "output_tokens" // field output_tokens = 11
+	enc.AddInt32(keyName, m.OutputTokens)
+
+	keyName = "cost_usd" // field cost_usd = 12
+	enc.AddFloat64(keyName, m.CostUsd)
+
+	keyName = "cached_input_tokens" // field cached_input_tokens = 13
+	enc.AddInt32(keyName, m.CachedInputTokens)
+
 	return nil
 }
 
@@ -120,6 +132,28 @@ func (m *Span) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncoder) error
 		}
 	}
 
+	keyName = "tool" // field tool = 4
+	if ov, ok := m.GetData().(*Span_Tool); ok {
+		_ = ov
+		if ov.Tool != nil {
+			var vv interface{} = ov.Tool
+			if marshaler, ok := vv.(go_uber_org_zap_zapcore.ObjectMarshaler); ok {
+				enc.AddObject(keyName, marshaler)
+			}
+		}
+	}
+
+	keyName = "reduction" // field reduction = 5
+	if ov, ok := m.GetData().(*Span_Reduction); ok {
+		_ = ov
+		if ov.Reduction != nil {
+			var vv interface{} = ov.Reduction
+			if marshaler, ok := vv.(go_uber_org_zap_zapcore.ObjectMarshaler); ok {
+				enc.AddObject(keyName, marshaler)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -168,6 +202,86 @@ func (m *LLMSpan) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncoder) er
 	keyName = "response_json" // field response_json = 3
 	enc.AddString(keyName, m.ResponseJson)
 
+	keyName = "model" // field model = 4
+	enc.AddString(keyName, m.Model)
+
+	keyName = "route" // field route = 5
+	enc.AddString(keyName, m.Route)
+
+	keyName = "attempts" // field attempts = 6
+	enc.AddInt32(keyName, m.Attempts)
+
+	keyName = "latency_ms" // field latency_ms = 7
+	enc.AddInt64(keyName, m.LatencyMs)
+
+	return nil
+}
+
+func (m *ToolSpan) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncoder) error {
+	var keyName string
+	_ = keyName
+
+	if m == nil {
+		return nil
+	}
+
+	keyName = "name" // field name = 1
+	enc.AddString(keyName, m.Name)
+
+	keyName = "arguments" // field arguments = 2
+	enc.AddString(keyName, m.Arguments)
+
+	keyName = "command" // field command = 3
+	enc.AddString(keyName, m.Command)
+
+	keyName = "allowed" // field allowed = 4
+	enc.AddBool(keyName, m.Allowed)
+
+	keyName = "exit_code" // field exit_code = 5
+	enc.AddInt32(keyName, m.ExitCode)
+
+	keyName = "output" // field output = 6
+	enc.AddString(keyName, m.Output)
+
+	keyName = "error" // field error = 7
+	enc.AddString(keyName, m.Error)
+
+	keyName = "start_time" // field start_time = 8
+	if t, err := github_com_golang_protobuf_ptypes.Timestamp(m.StartTime); err == nil {
+		enc.AddTime(keyName, t)
+	}
+
+	keyName = "end_time" // field end_time = 9
+	if t, err := github_com_golang_protobuf_ptypes.Timestamp(m.EndTime); err == nil {
+		enc.AddTime(keyName, t)
+	}
+
+	return nil
+}
+
+func (m *OutputReductionSpan) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncoder) error {
+	var keyName string
+	_ = keyName
+
+	if m == nil {
+		return nil
+	}
+
+	keyName = "block_id" // field block_id = 1
+	enc.AddString(keyName, m.BlockId)
+
+	keyName = "strategy" // field strategy = 2
+	enc.AddString(keyName, m.Strategy)
+
+	keyName = "original_length" // field original_length = 3
+	enc.AddInt32(keyName, m.OriginalLength)
+
+	keyName = "reduced_length" // field reduced_length = 4
+	enc.AddInt32(keyName, m.ReducedLength)
+
+	keyName = "error" // field error = 5
+	enc.AddString(keyName, m.Error)
+
 	return nil
 }
 
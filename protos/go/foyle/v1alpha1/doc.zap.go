@@ -136,5 +136,8 @@ func (m *BlockOutputItem) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEnc
 	keyName = "text_data" // field text_data = 2
 	enc.AddString(keyName, m.TextData)
 
+	keyName = "data" // field data = 3
+	enc.AddByteString(keyName, m.Data)
+
 	return nil
 }
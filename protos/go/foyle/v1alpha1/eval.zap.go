@@ -92,6 +92,12 @@ func (m *EvalResult) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncoder)
 	keyName = "block_log_status" // field block_log_status = 15
 	enc.AddString(keyName, m.BlockLogStatus.String())
 
+	keyName = "split" // field split = 16
+	enc.AddString(keyName, m.Split.String())
+
+	keyName = "cost_usd" // field cost_usd = 17
+	enc.AddFloat64(keyName, m.CostUsd)
+
 	return nil
 }
 
@@ -115,6 +121,9 @@ func (m *Assertion) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncoder)
 	keyName = "id" // field id = 4
 	enc.AddString(keyName, m.Id)
 
+	keyName = "custom_name" // field custom_name = 5
+	enc.AddString(keyName, m.CustomName)
+
 	return nil
 }
 
@@ -186,6 +195,16 @@ func (m *AssertionRow) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncode
 	keyName = "ends_with_code_cell" // field ends_with_code_cell = 7
 	enc.AddString(keyName, m.EndsWithCodeCell.String())
 
+	keyName = "results" // field results = 8
+	enc.AddObject(keyName, go_uber_org_zap_zapcore.ObjectMarshalerFunc(func(enc go_uber_org_zap_zapcore.ObjectEncoder) error {
+		for mk, mv := range m.Results {
+			key := mk
+			_ = key
+			enc.AddString(key, mv.String())
+		}
+		return nil
+	}))
+
 	return nil
 }
 
@@ -266,6 +285,15 @@ func (m *AssertionTableResponse) MarshalLogObject(enc go_uber_org_zap_zapcore.Ob
 		return nil
 	}))
 
+	keyName = "columns" // field columns = 2
+	enc.AddArray(keyName, go_uber_org_zap_zapcore.ArrayMarshalerFunc(func(aenc go_uber_org_zap_zapcore.ArrayEncoder) error {
+		for _, rv := range m.Columns {
+			_ = rv
+			aenc.AppendString(rv)
+		}
+		return nil
+	}))
+
 	return nil
 }
 
@@ -352,6 +380,23 @@ func (m *ExperimentReport) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEn
 		return nil
 	}))
 
+	keyName = "total_cost_usd" // field total_cost_usd = 7
+	enc.AddFloat64(keyName, m.TotalCostUsd)
+
+	keyName = "cost_stats" // field cost_stats = 8
+	enc.AddArray(keyName, go_uber_org_zap_zapcore.ArrayMarshalerFunc(func(aenc go_uber_org_zap_zapcore.ArrayEncoder) error {
+		for _, rv := range m.CostStats {
+			_ = rv
+			if rv != nil {
+				var vv interface{} = rv
+				if marshaler, ok := vv.(go_uber_org_zap_zapcore.ObjectMarshaler); ok {
+					aenc.AppendObject(marshaler)
+				}
+			}
+		}
+		return nil
+	}))
+
 	return nil
 }
 
@@ -378,6 +423,9 @@ func (m *AssertionCounts) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEnc
 	keyName = "skipped" // field skipped = 5
 	enc.AddInt32(keyName, m.Skipped)
 
+	keyName = "custom_name" // field custom_name = 6
+	enc.AddString(keyName, m.CustomName)
+
 	return nil
 }
 
@@ -397,3 +445,51 @@ func (m *PercentileStat) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEnco
 
 	return nil
 }
+
+func (m *EvalDatasetManifest) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncoder) error {
+	var keyName string
+	_ = keyName
+
+	if m == nil {
+		return nil
+	}
+
+	keyName = "entries" // field entries = 1
+	enc.AddArray(keyName, go_uber_org_zap_zapcore.ArrayMarshalerFunc(func(aenc go_uber_org_zap_zapcore.ArrayEncoder) error {
+		for _, rv := range m.Entries {
+			_ = rv
+			if rv != nil {
+				var vv interface{} = rv
+				if marshaler, ok := vv.(go_uber_org_zap_zapcore.ObjectMarshaler); ok {
+					aenc.AppendObject(marshaler)
+				}
+			}
+		}
+		return nil
+	}))
+
+	keyName = "spec_hash" // field spec_hash = 2
+	enc.AddString(keyName, m.SpecHash)
+
+	return nil
+}
+
+func (m *DatasetEntry) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncoder) error {
+	var keyName string
+	_ = keyName
+
+	if m == nil {
+		return nil
+	}
+
+	keyName = "id" // field id = 1
+	enc.AddString(keyName, m.Id)
+
+	keyName = "split" // field split = 2
+	enc.AddString(keyName, m.Split.String())
+
+	keyName = "stratum" // field stratum = 3
+	enc.AddString(keyName, m.Stratum)
+
+	return nil
+}
@@ -58,6 +58,17 @@ func (m *Example) MarshalLogObject(enc go_uber_org_zap_zapcore.ObjectEncoder) er
 		return nil
 	}))
 
+	keyName = "suffix" // field suffix = 5
+	if m.Suffix != nil {
+		var vv interface{} = m.Suffix
+		if marshaler, ok := vv.(go_uber_org_zap_zapcore.ObjectMarshaler); ok {
+			enc.AddObject(keyName, marshaler)
+		}
+	}
+
+	keyName = "language" // field language = 6
+	enc.AddString(keyName, m.Language)
+
 	return nil
 }
 